@@ -2,30 +2,69 @@ package authcontroller
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/beka-birhanu/task_manager_final/api"
 	"github.com/beka-birhanu/task_manager_final/api/controllers/auth/dto"
 	basecontroller "github.com/beka-birhanu/task_manager_final/api/controllers/base"
 	errapi "github.com/beka-birhanu/task_manager_final/api/errors"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
 	registercmd "github.com/beka-birhanu/task_manager_final/app/user/auth/command"
 	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	logoutcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/logout"
+	mfacmd "github.com/beka-birhanu/task_manager_final/app/user/auth/mfa"
+	oidccallbackcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/callback"
+	oidcstartcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/start"
 	loginqry "github.com/beka-birhanu/task_manager_final/app/user/auth/query"
+	refreshcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/refresh"
+	resendemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/resend"
+	verifyemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/verify"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	resetcmd "github.com/beka-birhanu/task_manager_final/app/user/password/reset"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	"github.com/gin-gonic/gin"
 )
 
+// Names of the cookies carrying the session tokens, and the state of a single sign-on.
+const (
+	accessTokenCookie  = "accessToken"
+	refreshTokenCookie = "refreshToken"
+	oidcStateCookie    = "oidcState"
+)
+
 // Controller handles HTTP requests related to authentication.
 type Controller struct {
 	basecontroller.BaseHandler
 	registerHandler icmd.IHandler[*registercmd.Command, *authresult.Result]
 	loginHandler    iquery.IHandler[*loginqry.Query, *authresult.Result]
+	mfaHandler      icmd.IHandler[*mfacmd.Command, *authresult.Result]
+	refreshHandler  icmd.IHandler[*refreshcmd.Command, *authresult.Result]
+	logoutHandler   icmd.IHandler[*logoutcmd.Command, bool]
+	forgotHandler   icmd.IHandler[*forgotcmd.Command, bool]
+	resetHandler    icmd.IHandler[*resetcmd.Command, bool]
+	verifyHandler   icmd.IHandler[*verifyemailcmd.Command, bool]
+	resendHandler   icmd.IHandler[*resendemailcmd.Command, bool]
+	oidcStart       icmd.IHandler[*oidcstartcmd.Command, *oidcstartcmd.Result]
+	oidcCallback    icmd.IHandler[*oidccallbackcmd.Command, *authresult.Result]
 }
 
 // Config holds the configuration for the Controller.
 type Config struct {
 	RegisterHandler icmd.IHandler[*registercmd.Command, *authresult.Result]
 	LoginHandler    iquery.IHandler[*loginqry.Query, *authresult.Result]
+	MFAHandler      icmd.IHandler[*mfacmd.Command, *authresult.Result]
+	RefreshHandler  icmd.IHandler[*refreshcmd.Command, *authresult.Result]
+	LogoutHandler   icmd.IHandler[*logoutcmd.Command, bool]
+	ForgotHandler   icmd.IHandler[*forgotcmd.Command, bool]
+	ResetHandler    icmd.IHandler[*resetcmd.Command, bool]
+	VerifyHandler   icmd.IHandler[*verifyemailcmd.Command, bool]
+	ResendHandler   icmd.IHandler[*resendemailcmd.Command, bool]
+
+	// Single sign-on handlers; the single sign-on routes are left out when nil.
+	OIDCStartHandler    icmd.IHandler[*oidcstartcmd.Command, *oidcstartcmd.Result]
+	OIDCCallbackHandler icmd.IHandler[*oidccallbackcmd.Command, *authresult.Result]
 }
 
 // New creates a new AuthController with the given CQRS handlers.
@@ -33,59 +72,67 @@ func New(config Config) *Controller {
 	return &Controller{
 		registerHandler: config.RegisterHandler,
 		loginHandler:    config.LoginHandler,
+		mfaHandler:      config.MFAHandler,
+		refreshHandler:  config.RefreshHandler,
+		logoutHandler:   config.LogoutHandler,
+		forgotHandler:   config.ForgotHandler,
+		resetHandler:    config.ResetHandler,
+		verifyHandler:   config.VerifyHandler,
+		resendHandler:   config.ResendHandler,
+		oidcStart:       config.OIDCStartHandler,
+		oidcCallback:    config.OIDCCallbackHandler,
 	}
 }
 
-// RegisterPublic registers public routes.
-func (c *Controller) RegisterPublic(route *gin.RouterGroup) {
-	auth := route.Group("/auth")
+// Register registers the routes of the controller. Signing in and recovering an
+// account are public; signing out requires an authenticated user.
+func (c *Controller) Register(route *gin.RouterGroup, auth api.IAuthorizer) {
+	group := route.Group("/auth")
 	{
-		auth.POST("/register", c.registerUser)
-		auth.POST("/login", c.login)
-	}
-}
+		group.POST("/register", c.registerUser)
+		group.POST("/login", c.login)
+		group.POST("/login/mfa", c.loginMFA)
+		group.POST("/refresh", c.refresh)
+		group.POST("/password/forgot", c.forgotPassword)
+		group.POST("/password/reset", c.resetPassword)
+		group.POST("/email/verify", c.verifyEmail)
+		group.POST("/email/resend", c.resendVerification)
+		group.POST("/logOut", auth.Authenticated(), c.logOut)
+		group.POST("/logOutAll", auth.Authenticated(), c.logOutAll)
 
-// RegisterProtected registers protected routes.
-func (c *Controller) RegisterProtected(route *gin.RouterGroup) {
-	auth := route.Group("/auth")
-	{
-		auth.POST("/logOut", c.logOut)
+		if c.oidcStart != nil && c.oidcCallback != nil {
+			group.GET("/oidc/login", c.oidcLogin)
+			group.GET("/oidc/callback", c.oidcLoginCallback)
+		}
 	}
 }
 
-// RegisterPrivileged registers privileged routes.
-func (c *Controller) RegisterPrivileged(route *gin.RouterGroup) {}
-
-// registerUser handles user registration.
+// registerUser handles user registration. When the user has to verify their email
+// before signing in, no session is started and 202 Accepted is returned.
 func (c *Controller) registerUser(ctx *gin.Context) {
-	var request dto.AuthRequest
+	var request dto.RegisterRequest
 
 	if err := ctx.ShouldBind(&request); err != nil {
 		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
 		return
 	}
 
-	result, err := c.registerHandler.Handle(registercmd.NewCommand(request.Username, request.Password))
+	result, err := c.registerHandler.Handle(registercmd.NewCommand(request.Username, request.Email, request.Password))
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
 	response := dto.NewAuthResponse(result)
-	c.RespondWithCookies(ctx, http.StatusOK, response, []*http.Cookie{
-		{
-			Name:     "accessToken",
-			Value:    result.Token,
-			Path:     "/",
-			Domain:   ctx.Request.Host,
-			MaxAge:   24 * 60 * 60,
-			HttpOnly: true,
-			Secure:   true,
-		},
-	})
+	if result.Token == "" {
+		c.Respond(ctx, http.StatusAccepted, response)
+		return
+	}
+	c.RespondWithCookies(ctx, http.StatusOK, response, SessionCookies(ctx, result))
 }
 
-// login handles user login.
+// login handles user login. When the user has to prove their second factor, no session
+// is started yet: 202 Accepted is returned with the MFA challenge token.
 func (c *Controller) login(ctx *gin.Context) {
 	var request dto.AuthRequest
 
@@ -94,31 +141,240 @@ func (c *Controller) login(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.loginHandler.Handle(loginqry.NewQuery(request.Username, request.Password))
+	result, err := c.loginHandler.Handle(loginqry.NewQuery(request.Username, request.Password, ctx.ClientIP()))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	response := dto.NewAuthResponse(result)
+	if result.MFAChallenge != "" {
+		c.Respond(ctx, http.StatusAccepted, response)
+		return
+	}
+	c.RespondWithCookies(ctx, http.StatusOK, response, SessionCookies(ctx, result))
+}
+
+// loginMFA completes a login with the MFA challenge token and the code of the second factor.
+func (c *Controller) loginMFA(ctx *gin.Context) {
+	var request dto.MFALoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := c.mfaHandler.Handle(mfacmd.NewCommand(request.MFAToken, request.Code, ctx.ClientIP()))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	response := dto.NewAuthResponse(result)
+	c.RespondWithCookies(ctx, http.StatusOK, response, SessionCookies(ctx, result))
+}
+
+// refresh exchanges a refresh token, taken from the request body or the
+// refreshToken cookie, for a new pair of tokens.
+func (c *Controller) refresh(ctx *gin.Context) {
+	var request dto.RefreshRequest
+
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+			return
+		}
+	}
+
+	if request.RefreshToken == "" {
+		cookie, err := ctx.Cookie(refreshTokenCookie)
+		if err != nil {
+			c.Problem(ctx, errapi.NewAuthentication("refresh token not found"))
+			return
+		}
+		request.RefreshToken = cookie
+	}
+
+	result, err := c.refreshHandler.Handle(refreshcmd.NewCommand(request.RefreshToken))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	response := dto.NewAuthResponse(result)
+	c.RespondWithCookies(ctx, http.StatusOK, response, SessionCookies(ctx, result))
+}
+
+// oidcLogin starts a single sign-on, redirecting the user to the identity provider.
+// The state of the sign in is kept in a cookie until the provider sends them back.
+func (c *Controller) oidcLogin(ctx *gin.Context) {
+	result, err := c.oidcStart.Handle(oidcstartcmd.NewCommand())
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	// Lax, so the cookie comes along when the provider redirects the user back.
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    result.StateToken,
+		Path:     "/",
+		Domain:   ctx.Request.Host,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	ctx.Redirect(http.StatusFound, result.URL)
+}
+
+// oidcLoginCallback completes a single sign-on with the authorization code the identity
+// provider sent the user back with. As with a password login, users with two-factor
+// authentication get 202 Accepted with the MFA challenge token instead of a session.
+func (c *Controller) oidcLoginCallback(ctx *gin.Context) {
+	var request dto.OIDCCallbackRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	// The state cookie is single use, whatever the outcome.
+	stateToken, _ := ctx.Cookie(oidcStateCookie)
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     "/",
+		Domain:   ctx.Request.Host,
+		MaxAge:   -1, // Delete the cookie
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if request.Error != "" {
+		c.Problem(ctx, errapi.FromErrDMN(errdmn.SSOLoginFailed))
+		return
+	}
+	if request.Code == "" {
+		c.Problem(ctx, errapi.NewBadRequest("authorization code is required"))
+		return
+	}
+
+	result, err := c.oidcCallback.Handle(oidccallbackcmd.NewCommand(request.Code, request.State, stateToken))
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
 	response := dto.NewAuthResponse(result)
-	c.RespondWithCookies(ctx, http.StatusOK, response, []*http.Cookie{
+	if result.MFAChallenge != "" {
+		c.Respond(ctx, http.StatusAccepted, response)
+		return
+	}
+	c.RespondWithCookies(ctx, http.StatusOK, response, SessionCookies(ctx, result))
+}
+
+// forgotPassword sends a password reset token to the user. The response is the same
+// whether or not the user exists, so it cannot be used to find out usernames.
+func (c *Controller) forgotPassword(ctx *gin.Context) {
+	var request dto.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.forgotHandler.Handle(forgotcmd.NewCommand(request.Username)); err != nil && err != errdmn.UserNotFound {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusAccepted, nil)
+}
+
+// resetPassword sets a new password with a password reset token.
+func (c *Controller) resetPassword(ctx *gin.Context) {
+	var request dto.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.resetHandler.Handle(resetcmd.NewCommand(request.Token, request.NewPassword)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
+
+// verifyEmail marks the email of a user as verified with the token sent to it.
+func (c *Controller) verifyEmail(ctx *gin.Context) {
+	var request dto.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.verifyHandler.Handle(verifyemailcmd.NewCommand(request.Token)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
+
+// resendVerification sends a new email verification token to the user. The response
+// is the same whether or not the user exists or still has an email to verify.
+func (c *Controller) resendVerification(ctx *gin.Context) {
+	var request dto.ResendVerificationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	_, err := c.resendHandler.Handle(resendemailcmd.NewCommand(request.Username))
+	switch err {
+	case nil, errdmn.UserNotFound, errdmn.EmailRequired, errdmn.EmailAlreadyVerified:
+		c.Respond(ctx, http.StatusAccepted, nil)
+	default:
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+	}
+}
+
+// logOut handles user logout, ending the session of the access token used for the request.
+func (c *Controller) logOut(ctx *gin.Context) {
+	c.endSessions(ctx, false)
+}
+
+// logOutAll logs the user out from every device, ending all of their sessions.
+func (c *Controller) logOutAll(ctx *gin.Context) {
+	c.endSessions(ctx, true)
+}
+
+// endSessions revokes the sessions ended by a logout and clears the session cookies.
+func (c *Controller) endSessions(ctx *gin.Context, allDevices bool) {
+	cmd, err := logoutCommand(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+	cmd.AllDevices = allDevices
+
+	if _, err := c.logoutHandler.Handle(cmd); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.RespondWithCookies(ctx, http.StatusNoContent, nil, []*http.Cookie{
 		{
-			Name:     "accessToken",
-			Value:    result.Token,
+			Name:     accessTokenCookie,
+			Value:    "",
 			Path:     "/",
 			Domain:   ctx.Request.Host,
-			MaxAge:   24 * 60 * 60,
+			MaxAge:   -1, // Delete the cookie
 			HttpOnly: true,
 			Secure:   true,
 		},
-	})
-}
-
-// logOut handles user logout.
-func (c *Controller) logOut(ctx *gin.Context) {
-	c.RespondWithCookies(ctx, http.StatusNoContent, nil, []*http.Cookie{
 		{
-			Name:     "accessToken",
+			Name:     refreshTokenCookie,
 			Value:    "",
 			Path:     "/",
 			Domain:   ctx.Request.Host,
@@ -128,3 +384,38 @@ func (c *Controller) logOut(ctx *gin.Context) {
 		},
 	})
 }
+
+// SessionCookies returns the cookies carrying the tokens of the given authentication result.
+// Other controllers starting a new session respond with them too.
+func SessionCookies(ctx *gin.Context, result *authresult.Result) []*http.Cookie {
+	return []*http.Cookie{
+		{
+			Name:     accessTokenCookie,
+			Value:    result.Token,
+			Path:     "/",
+			Domain:   ctx.Request.Host,
+			MaxAge:   int(time.Until(result.TokenExpiresAt).Seconds()),
+			HttpOnly: true,
+			Secure:   true,
+		},
+		{
+			Name:     refreshTokenCookie,
+			Value:    result.RefreshToken,
+			Path:     "/",
+			Domain:   ctx.Request.Host,
+			MaxAge:   int(time.Until(result.RefreshExpiresAt).Seconds()),
+			HttpOnly: true,
+			Secure:   true,
+		},
+	}
+}
+
+// logoutCommand builds the logout command for the access token whose claims were
+// attached to the request context by the auth middleware.
+func logoutCommand(ctx *gin.Context) (*logoutcmd.Command, error) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return logoutcmd.NewCommand(claims.Subject, claims.TokenID, claims.SessionID, claims.ExpiresAt), nil
+}
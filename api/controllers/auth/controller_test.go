@@ -5,14 +5,29 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	authcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/auth"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	api_mock "github.com/beka-birhanu/task_manager_final/api/mocks"
 	icmd_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command/mocks"
 	iquery_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query/mocks"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	registercmd "github.com/beka-birhanu/task_manager_final/app/user/auth/command"
 	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	logoutcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/logout"
+	mfacmd "github.com/beka-birhanu/task_manager_final/app/user/auth/mfa"
+	oidccallbackcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/callback"
+	oidcstartcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/start"
 	loginqry "github.com/beka-birhanu/task_manager_final/app/user/auth/query"
+	refreshcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/refresh"
+	resendemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/resend"
+	verifyemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/verify"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	resetcmd "github.com/beka-birhanu/task_manager_final/app/user/password/reset"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -22,29 +37,66 @@ type AuthControllerTestSuite struct {
 	controller          *authcontroller.Controller
 	mockRegisterHandler *icmd_mock.IHandler[*registercmd.Command, *authresult.Result]
 	mockLoginHandler    *iquery_mock.IHandler[*loginqry.Query, *authresult.Result]
+	mockMFAHandler      *icmd_mock.IHandler[*mfacmd.Command, *authresult.Result]
+	mockRefreshHandler  *icmd_mock.IHandler[*refreshcmd.Command, *authresult.Result]
+	mockLogoutHandler   *icmd_mock.IHandler[*logoutcmd.Command, bool]
+	mockForgotHandler   *icmd_mock.IHandler[*forgotcmd.Command, bool]
+	mockResetHandler    *icmd_mock.IHandler[*resetcmd.Command, bool]
+	mockVerifyHandler   *icmd_mock.IHandler[*verifyemailcmd.Command, bool]
+	mockResendHandler   *icmd_mock.IHandler[*resendemailcmd.Command, bool]
+	mockOIDCStart       *icmd_mock.IHandler[*oidcstartcmd.Command, *oidcstartcmd.Result]
+	mockOIDCCallback    *icmd_mock.IHandler[*oidccallbackcmd.Command, *authresult.Result]
 	router              *gin.Engine
+	logoutCmd           *logoutcmd.Command
 }
 
 func (suite *AuthControllerTestSuite) SetupTest() {
 	suite.mockRegisterHandler = new(icmd_mock.IHandler[*registercmd.Command, *authresult.Result])
 	suite.mockLoginHandler = new(iquery_mock.IHandler[*loginqry.Query, *authresult.Result])
+	suite.mockMFAHandler = new(icmd_mock.IHandler[*mfacmd.Command, *authresult.Result])
+	suite.mockRefreshHandler = new(icmd_mock.IHandler[*refreshcmd.Command, *authresult.Result])
+	suite.mockLogoutHandler = new(icmd_mock.IHandler[*logoutcmd.Command, bool])
+	suite.mockForgotHandler = new(icmd_mock.IHandler[*forgotcmd.Command, bool])
+	suite.mockResetHandler = new(icmd_mock.IHandler[*resetcmd.Command, bool])
+	suite.mockVerifyHandler = new(icmd_mock.IHandler[*verifyemailcmd.Command, bool])
+	suite.mockResendHandler = new(icmd_mock.IHandler[*resendemailcmd.Command, bool])
+	suite.mockOIDCStart = new(icmd_mock.IHandler[*oidcstartcmd.Command, *oidcstartcmd.Result])
+	suite.mockOIDCCallback = new(icmd_mock.IHandler[*oidccallbackcmd.Command, *authresult.Result])
+	suite.logoutCmd = logoutcmd.NewCommand(uuid.New(), "token-id", uuid.New(), time.Now().Add(time.Hour))
 
 	suite.controller = authcontroller.New(authcontroller.Config{
 		RegisterHandler: suite.mockRegisterHandler,
 		LoginHandler:    suite.mockLoginHandler,
+		MFAHandler:      suite.mockMFAHandler,
+		RefreshHandler:  suite.mockRefreshHandler,
+		LogoutHandler:   suite.mockLogoutHandler,
+		ForgotHandler:   suite.mockForgotHandler,
+		ResetHandler:    suite.mockResetHandler,
+		VerifyHandler:   suite.mockVerifyHandler,
+		ResendHandler:   suite.mockResendHandler,
+
+		OIDCStartHandler:    suite.mockOIDCStart,
+		OIDCCallbackHandler: suite.mockOIDCCallback,
 	})
 
 	suite.router = gin.Default()
-	api := suite.router.Group("/api")
-	suite.controller.RegisterPublic(api)
-	suite.controller.RegisterProtected(api)
+	suite.router.Use(func(ctx *gin.Context) {
+		// Simulate the claims attached by the auth middleware.
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{
+			Subject:   suite.logoutCmd.UserID,
+			TokenID:   suite.logoutCmd.TokenID,
+			SessionID: suite.logoutCmd.SessionID,
+			ExpiresAt: suite.logoutCmd.ExpiresAt,
+		})
+	})
+	suite.controller.Register(suite.router.Group("/api"), api_mock.Authorizer{})
 }
 
 func (suite *AuthControllerTestSuite) TestRegisterUser_Success() {
 	result := &authresult.Result{Token: "testtoken"}
 	suite.mockRegisterHandler.On("Handle", mock.AnythingOfType("*registercmd.Command")).Return(result, nil)
 
-	reqBody := `{"username":"testuser","password":"password123"}`
+	reqBody := `{"username":"testuser","email":"testuser@example.com","password":"password123"}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -55,8 +107,58 @@ func (suite *AuthControllerTestSuite) TestRegisterUser_Success() {
 	suite.mockRegisterHandler.AssertExpectations(suite.T())
 }
 
+func (suite *AuthControllerTestSuite) TestRegisterUser_VerificationRequired() {
+	result := &authresult.Result{Username: "testuser", Email: "testuser@example.com"}
+	suite.mockRegisterHandler.On("Handle", registercmd.NewCommand("testuser", "testuser@example.com", "password123")).Return(result, nil)
+
+	reqBody := `{"username":"testuser","email":"testuser@example.com","password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Empty(w.Header().Values("Set-Cookie"))
+	suite.Contains(w.Body.String(), `"email":"testuser@example.com","emailVerified":false`)
+	suite.NotContains(w.Body.String(), "accessToken")
+	suite.mockRegisterHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_EmailNotVerified() {
+	suite.mockLoginHandler.On("Handle", mock.AnythingOfType("*loginqry.Query")).Return((*authresult.Result)(nil), errdmn.EmailNotVerified)
+
+	reqBody := `{"username":"testuser","password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), "email is not verified")
+}
+
+func (suite *AuthControllerTestSuite) TestLogin_Locked() {
+	suite.mockLoginHandler.On("Handle", loginqry.NewQuery("testuser", "password123", "192.0.2.1")).Return((*authresult.Result)(nil), errdmn.LoginLocked)
+
+	reqBody := `{"username":"testuser","password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.Contains(w.Body.String(), "too many failed sign in attempts")
+	suite.mockLoginHandler.AssertExpectations(suite.T())
+}
+
 func (suite *AuthControllerTestSuite) TestLogin_Success() {
-	result := &authresult.Result{Token: "testtoken"}
+	result := &authresult.Result{
+		Token:            "testtoken",
+		TokenExpiresAt:   time.Now().Add(15 * time.Minute),
+		RefreshToken:     "refreshtoken",
+		RefreshExpiresAt: time.Now().Add(time.Hour),
+	}
 	suite.mockLoginHandler.On("Handle", mock.AnythingOfType("*loginqry.Query")).Return(result, nil)
 
 	reqBody := `{"username":"testuser","password":"password123"}`
@@ -66,12 +168,114 @@ func (suite *AuthControllerTestSuite) TestLogin_Success() {
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusOK, w.Code)
-	suite.Contains(w.Header().Get("Set-Cookie"), "accessToken=testtoken")
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=testtoken")
+	suite.Contains(w.Header().Values("Set-Cookie")[1], "refreshToken=refreshtoken")
+	// Each cookie lasts as long as the token it carries.
+	cookies := w.Result().Cookies()
+	suite.InDelta(15*60, cookies[0].MaxAge, 1)
+	suite.InDelta(60*60, cookies[1].MaxAge, 1)
+	suite.Contains(w.Body.String(), `"accessToken":"testtoken","tokenType":"Bearer","refreshToken":"refreshtoken"`)
 	suite.mockLoginHandler.AssertExpectations(suite.T())
 }
 
+func (suite *AuthControllerTestSuite) TestLogin_MFARequired() {
+	result := &authresult.Result{Username: "testuser", MFAChallenge: "challenge"}
+	suite.mockLoginHandler.On("Handle", mock.AnythingOfType("*loginqry.Query")).Return(result, nil)
+
+	reqBody := `{"username":"testuser","password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Empty(w.Header().Values("Set-Cookie"))
+	suite.Contains(w.Body.String(), `"mfaRequired":true,"mfaToken":"challenge"`)
+	suite.NotContains(w.Body.String(), "accessToken")
+}
+
+func (suite *AuthControllerTestSuite) TestLoginMFA_Success() {
+	result := &authresult.Result{Token: "testtoken", RefreshToken: "refreshtoken", RefreshExpiresAt: time.Now().Add(time.Hour)}
+	suite.mockMFAHandler.On("Handle", mfacmd.NewCommand("challenge", "123456", "192.0.2.1")).Return(result, nil)
+
+	reqBody := `{"mfaToken":"challenge","code":"123456"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/login/mfa", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=testtoken")
+	suite.NotContains(w.Body.String(), "mfaRequired")
+	suite.mockMFAHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestLoginMFA_InvalidChallenge() {
+	suite.mockMFAHandler.On("Handle", mock.AnythingOfType("*mfacmd.Command")).Return((*authresult.Result)(nil), errdmn.InvalidMFAChallenge)
+
+	reqBody := `{"mfaToken":"expired","code":"123456"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/login/mfa", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Empty(w.Header().Values("Set-Cookie"))
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_FromCookie() {
+	result := &authresult.Result{Token: "newtoken", RefreshToken: "newrefreshtoken", RefreshExpiresAt: time.Now().Add(time.Hour)}
+	suite.mockRefreshHandler.On("Handle", refreshcmd.NewCommand("oldrefreshtoken")).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refreshToken", Value: "oldrefreshtoken"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=newtoken")
+	suite.Contains(w.Header().Values("Set-Cookie")[1], "refreshToken=newrefreshtoken")
+	suite.mockRefreshHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_FromBody() {
+	result := &authresult.Result{Token: "newtoken", RefreshToken: "newrefreshtoken", RefreshExpiresAt: time.Now().Add(time.Hour)}
+	suite.mockRefreshHandler.On("Handle", refreshcmd.NewCommand("bodytoken")).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", strings.NewReader(`{"refreshToken":"bodytoken"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "refreshToken", Value: "cookietoken"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockRefreshHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_Missing() {
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockRefreshHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *AuthControllerTestSuite) TestRefresh_Reused() {
+	suite.mockRefreshHandler.On("Handle", mock.Anything).Return((*authresult.Result)(nil), errdmn.RefreshTokenReused)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refreshToken", Value: "usedtoken"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Empty(w.Header().Values("Set-Cookie"))
+}
+
 func (suite *AuthControllerTestSuite) TestRegisterUser_BadRequest() {
-	reqBody := `{"username":"testuser"}`
+	reqBody := `{"username":"testuser","password":"password123"}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -93,13 +297,207 @@ func (suite *AuthControllerTestSuite) TestLogin_BadRequest() {
 }
 
 func (suite *AuthControllerTestSuite) TestLogOut() {
+	suite.mockLogoutHandler.On("Handle", suite.logoutCmd).Return(true, nil)
+
 	req, _ := http.NewRequest(http.MethodPost, "/api/auth/logOut", nil)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusNoContent, w.Code)
-	suite.Contains(w.Header().Get("Set-Cookie"), "accessToken=;")
-	suite.Contains(w.Header().Get("Set-Cookie"), "Max-Age=0")
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=;")
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "Max-Age=0")
+	suite.Contains(w.Header().Values("Set-Cookie")[1], "refreshToken=;")
+	suite.Contains(w.Header().Values("Set-Cookie")[1], "Max-Age=0")
+	suite.mockLogoutHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestLogOutAll() {
+	expected := logoutcmd.NewAllDevicesCommand(suite.logoutCmd.UserID, suite.logoutCmd.TokenID, suite.logoutCmd.SessionID, suite.logoutCmd.ExpiresAt)
+	suite.mockLogoutHandler.On("Handle", expected).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/logOutAll", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=;")
+	suite.mockLogoutHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestLogOut_ClaimsNotFound() {
+	router := gin.Default()
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/logOut", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockLogoutHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *AuthControllerTestSuite) TestForgotPassword() {
+	suite.mockForgotHandler.On("Handle", forgotcmd.NewCommand("testuser")).Return(true, nil)
+	suite.mockForgotHandler.On("Handle", forgotcmd.NewCommand("ghost")).Return(false, errdmn.UserNotFound)
+
+	for _, username := range []string{"testuser", "ghost"} {
+		reqBody := `{"username":"` + username + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/auth/password/forgot", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		// Unknown users get the same response.
+		suite.Equal(http.StatusAccepted, w.Code)
+		suite.Empty(w.Body.String())
+	}
+	suite.mockForgotHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestForgotPassword_BadRequest() {
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/password/forgot", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockForgotHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *AuthControllerTestSuite) TestResetPassword_Success() {
+	suite.mockResetHandler.On("Handle", resetcmd.NewCommand("reset-token", "new-password")).Return(true, nil)
+
+	reqBody := `{"token":"reset-token","newPassword":"new-password"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/password/reset", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.mockResetHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestResetPassword_InvalidToken() {
+	suite.mockResetHandler.On("Handle", mock.AnythingOfType("*resetcmd.Command")).Return(false, errdmn.ResetTokenExpired)
+
+	reqBody := `{"token":"reset-token","newPassword":"new-password"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/password/reset", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "password reset token expired")
+}
+
+func (suite *AuthControllerTestSuite) TestVerifyEmail() {
+	suite.mockVerifyHandler.On("Handle", verifyemailcmd.NewCommand("good-token")).Return(true, nil)
+	suite.mockVerifyHandler.On("Handle", verifyemailcmd.NewCommand("bad-token")).Return(false, errdmn.InvalidVerificationToken)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/email/verify", strings.NewReader(`{"token":"good-token"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/auth/email/verify", strings.NewReader(`{"token":"bad-token"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)
+
+	suite.mockVerifyHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestResendVerification() {
+	suite.mockResendHandler.On("Handle", resendemailcmd.NewCommand("testuser")).Return(true, nil)
+	suite.mockResendHandler.On("Handle", resendemailcmd.NewCommand("ghost")).Return(false, errdmn.UserNotFound)
+	suite.mockResendHandler.On("Handle", resendemailcmd.NewCommand("verified")).Return(false, errdmn.EmailAlreadyVerified)
+
+	for _, username := range []string{"testuser", "ghost", "verified"} {
+		reqBody := `{"username":"` + username + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/auth/email/resend", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		// Whether the user exists or is verified is not revealed.
+		suite.Equal(http.StatusAccepted, w.Code)
+		suite.Empty(w.Body.String())
+	}
+	suite.mockResendHandler.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestOIDCLogin() {
+	result := &oidcstartcmd.Result{URL: "https://idp.example.com/authorize?state=abc", StateToken: "statetoken"}
+	suite.mockOIDCStart.On("Handle", oidcstartcmd.NewCommand()).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusFound, w.Code)
+	suite.Equal(result.URL, w.Header().Get("Location"))
+	suite.Contains(w.Header().Get("Set-Cookie"), "oidcState=statetoken")
+	suite.Contains(w.Header().Get("Set-Cookie"), "SameSite=Lax")
+}
+
+func (suite *AuthControllerTestSuite) TestOIDCCallback_Success() {
+	result := &authresult.Result{Token: "testtoken", RefreshToken: "refreshtoken", RefreshExpiresAt: time.Now().Add(time.Hour)}
+	suite.mockOIDCCallback.On("Handle", oidccallbackcmd.NewCommand("code", "abc", "statetoken")).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/oidc/callback?code=code&state=abc", nil)
+	req.AddCookie(&http.Cookie{Name: "oidcState", Value: "statetoken"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	cookies := w.Header().Values("Set-Cookie")
+	suite.Contains(cookies[0], "oidcState=;")
+	suite.Contains(cookies[1], "accessToken=testtoken")
+	suite.mockOIDCCallback.AssertExpectations(suite.T())
+}
+
+func (suite *AuthControllerTestSuite) TestOIDCCallback_MFARequired() {
+	result := &authresult.Result{Username: "testuser", MFAChallenge: "challenge"}
+	suite.mockOIDCCallback.On("Handle", mock.AnythingOfType("*oidccallbackcmd.Command")).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/oidc/callback?code=code&state=abc", nil)
+	req.AddCookie(&http.Cookie{Name: "oidcState", Value: "statetoken"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.Contains(w.Body.String(), `"mfaRequired":true,"mfaToken":"challenge"`)
+	suite.NotContains(w.Body.String(), "accessToken")
+}
+
+func (suite *AuthControllerTestSuite) TestOIDCCallback_Failed() {
+	suite.mockOIDCCallback.On("Handle", mock.AnythingOfType("*oidccallbackcmd.Command")).Return((*authresult.Result)(nil), errdmn.InvalidSSOState)
+
+	tests := map[string]string{
+		"provider error": "/api/auth/oidc/callback?error=access_denied&state=abc",
+		"invalid state":  "/api/auth/oidc/callback?code=code&state=abc",
+	}
+	for name, url := range tests {
+		suite.Run(name, func() {
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+			suite.router.ServeHTTP(w, req)
+
+			suite.Equal(http.StatusUnauthorized, w.Code)
+			suite.Len(w.Header().Values("Set-Cookie"), 1)
+		})
+	}
+}
+
+func (suite *AuthControllerTestSuite) TestOIDCCallback_MissingCode() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/auth/oidc/callback?state=abc", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockOIDCCallback.AssertNotCalled(suite.T(), "Handle", mock.Anything)
 }
 
 func TestAuthControllerTestSuite(t *testing.T) {
@@ -4,3 +4,15 @@ type AuthRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+// RegisterRequest carries the details of a new user.
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest carries a refresh token. The token may instead be sent in the refreshToken cookie.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
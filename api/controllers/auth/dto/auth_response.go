@@ -3,17 +3,35 @@ package dto
 import authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
 
 type AuthResponse struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	IsAdmin  bool   `json:"isAdmin"`
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"emailVerified"`
+	Role          string `json:"role"`
+	AccessToken   string `json:"accessToken,omitempty"`  // Sent as "Authorization: Bearer <accessToken>" by clients not using cookies.
+	TokenType     string `json:"tokenType,omitempty"`    // Always "Bearer".
+	RefreshToken  string `json:"refreshToken,omitempty"` // Exchanged at the refresh endpoint for a new pair of tokens.
+	MFARequired   bool   `json:"mfaRequired,omitempty"`  // The sign in has to be completed with a second factor.
+	MFAToken      string `json:"mfaToken,omitempty"`     // Sent with the code of the second factor to complete the sign in.
 }
 
 // FromAuthResult extracts the info for the login response from the given
 // auth.Result and map them to new LoginResponse
+// The token fields are omitted when no session was started.
 func NewAuthResponse(authResult *authresult.Result) *AuthResponse {
-	return &AuthResponse{
-		ID:       authResult.ID.String(),
-		Username: authResult.Username,
-		IsAdmin:  authResult.IsAdmin,
+	response := &AuthResponse{
+		ID:            authResult.ID.String(),
+		Username:      authResult.Username,
+		Email:         authResult.Email,
+		EmailVerified: authResult.EmailVerified,
+		Role:          string(authResult.Role),
+		AccessToken:   authResult.Token,
+		RefreshToken:  authResult.RefreshToken,
+		MFARequired:   authResult.MFAChallenge != "",
+		MFAToken:      authResult.MFAChallenge,
 	}
+	if authResult.Token != "" {
+		response.TokenType = "Bearer"
+	}
+	return response
 }
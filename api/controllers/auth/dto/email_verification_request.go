@@ -0,0 +1,11 @@
+package dto
+
+// VerifyEmailRequest carries an email verification token.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest names the user whose email verification token is sent again.
+type ResendVerificationRequest struct {
+	Username string `json:"username" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// MFALoginRequest carries the MFA challenge token returned by the sign in and the code of the second factor.
+type MFALoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// OIDCCallbackRequest carries the query parameters the identity provider sends the user back with.
+// The provider sends an error code instead of an authorization code when the sign in failed.
+type OIDCCallbackRequest struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+	Error string `form:"error"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// ForgotPasswordRequest names the user who forgot their password.
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ResetPasswordRequest carries a password reset token and the password replacing the forgotten one.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
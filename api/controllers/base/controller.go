@@ -15,7 +15,7 @@ type BaseHandler struct{}
 func (h *BaseHandler) Problem(c *gin.Context, err errapi.Error) {
 	var shadowedErr errapi.Error
 	switch err.StatusCode() {
-	case errapi.BadRequest, errapi.Conflict, errapi.NotFound, errapi.Forbidden:
+	case errapi.BadRequest, errapi.Conflict, errapi.NotFound, errapi.Forbidden, errapi.TooManyRequests:
 		shadowedErr = err
 	case errapi.Authentication:
 		shadowedErr = errapi.NewAuthentication("invalid credentials")
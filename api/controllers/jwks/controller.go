@@ -0,0 +1,39 @@
+// Package jwkscontroller publishes the public keys access tokens are signed with,
+// so other services can verify the tokens without holding the signing keys.
+package jwkscontroller
+
+import (
+	"net/http"
+
+	"github.com/beka-birhanu/task_manager_final/api"
+	basecontroller "github.com/beka-birhanu/task_manager_final/api/controllers/base"
+	"github.com/beka-birhanu/task_manager_final/api/controllers/jwks/dto"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// cacheControl lets verifiers cache the key set for a while; keys are published
+// before they start signing, so a cached set never misses a new key.
+const cacheControl = "public, max-age=300"
+
+// Controller handles HTTP requests for the JSON Web Key Set.
+type Controller struct {
+	basecontroller.BaseHandler
+	keys ijwt.KeySet
+}
+
+// New creates a new Controller publishing the given key set.
+func New(keys ijwt.KeySet) *Controller {
+	return &Controller{keys: keys}
+}
+
+// Register registers the public routes. The route is meant to be served from the server root.
+func (c *Controller) Register(route *gin.RouterGroup, auth api.IAuthorizer) {
+	route.GET("/.well-known/jwks.json", c.getKeys)
+}
+
+// getKeys returns the public keys as a JWKS.
+func (c *Controller) getKeys(ctx *gin.Context) {
+	ctx.Header("Cache-Control", cacheControl)
+	c.Respond(ctx, http.StatusOK, dto.NewJWKSResponse(c.keys.PublicKeys()))
+}
@@ -0,0 +1,57 @@
+package jwkscontroller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwkscontroller "github.com/beka-birhanu/task_manager_final/api/controllers/jwks"
+	api_mock "github.com/beka-birhanu/task_manager_final/api/mocks"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type JWKSControllerTestSuite struct {
+	suite.Suite
+	mockKeys *ijwt_mock.MockKeySet
+	router   *gin.Engine
+}
+
+func (suite *JWKSControllerTestSuite) SetupTest() {
+	suite.mockKeys = new(ijwt_mock.MockKeySet)
+
+	suite.router = gin.Default()
+	jwkscontroller.New(suite.mockKeys).Register(suite.router.Group("/"), api_mock.Authorizer{})
+}
+
+func (suite *JWKSControllerTestSuite) TestGetKeys() {
+	suite.mockKeys.On("PublicKeys").Return([]ijwt.JWK{
+		{Kty: "OKP", Kid: "2024-09", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("public, max-age=300", w.Header().Get("Cache-Control"))
+	suite.JSONEq(`{"keys":[{"kty":"OKP","kid":"2024-09","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, w.Body.String())
+	suite.mockKeys.AssertExpectations(suite.T())
+}
+
+func (suite *JWKSControllerTestSuite) TestGetKeys_Empty() {
+	suite.mockKeys.On("PublicKeys").Return([]ijwt.JWK{})
+
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"keys":[]}`, w.Body.String())
+}
+
+func TestJWKSControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(JWKSControllerTestSuite))
+}
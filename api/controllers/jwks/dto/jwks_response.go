@@ -0,0 +1,38 @@
+package dto
+
+import ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+
+// JWKResponse is a public key in JSON Web Key form.
+type JWKResponse struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSResponse is a JSON Web Key Set.
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
+
+// NewJWKSResponse maps the given public keys to a JWKSResponse.
+func NewJWKSResponse(keys []ijwt.JWK) *JWKSResponse {
+	response := &JWKSResponse{Keys: make([]JWKResponse, 0, len(keys))}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, JWKResponse{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return response
+}
@@ -2,12 +2,34 @@ package dto
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // DTOs for task operations
+// Status may be left out, for the initial state of the workflow on creation or the
+// current status on update; priority likewise falls back to medium or the current
+// priority, and tags to none or the current tags. Project, parent and assignees are
+// only read on creation; the parent and assignees of an existing task are changed
+// through their own endpoints.
 type AddTaskRequest struct {
 	Title       string    `json:"title" binding:"required"`
 	Description string    `json:"description" binding:"required"`
 	DueDate     time.Time `json:"dueDate" binding:"required"`
-	Status      string    `json:"status" binding:"required"`
+	Status      string    `json:"status"`
+	Priority    string    `json:"priority"`
+	Tags        []string  `json:"tags"`
+	Project     string    `json:"project"`
+	ParentID    uuid.UUID `json:"parentId"`
+	Assignees   []string  `json:"assignees"` // Usernames of the users the task is assigned to.
+}
+
+// TransitionRequest holds the status a task is moved to.
+type TransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// SetParentRequest holds the task a task is moved under.
+type SetParentRequest struct {
+	ParentID uuid.UUID `json:"parentId" binding:"required"`
 }
@@ -3,13 +3,110 @@ package dto
 import (
 	"time"
 
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	searchqry "github.com/beka-birhanu/task_manager_final/app/task/query/search"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/google/uuid"
 )
 
 type TaskResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	DueDate     time.Time `json:"dueDate"`
-	Status      string    `json:"status"`
+	ID          uuid.UUID         `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	DueDate     time.Time         `json:"dueDate"`
+	Status      string            `json:"status"`
+	Priority    string            `json:"priority"`
+	Tags        []string          `json:"tags"`
+	Project     string            `json:"project,omitempty"`
+	CreatedBy   string            `json:"createdBy,omitempty"` // Username of the creator; omitted once they are deleted.
+	Assignees   []string          `json:"assignees"`           // Usernames of the users the task is assigned to.
+	ParentID    *uuid.UUID        `json:"parentId,omitempty"`
+	Subtasks    *ProgressResponse `json:"subtasks,omitempty"` // Omitted for tasks without subtasks.
+
+	// Set only for search results.
+	Score      float64             `json:"score,omitempty"`
+	Highlights *HighlightsResponse `json:"highlights,omitempty"`
+}
+
+// HighlightsResponse holds the task's text fields with the matched search terms
+// wrapped in <mark> tags. Fields without a match are omitted.
+type HighlightsResponse struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProgressResponse rolls up the subtasks of a task: how many of them are done out of how many.
+type ProgressResponse struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// NewTaskResponse maps the given task to its response representation, naming its creator
+// and assignees with the given usernames by user ID. Users without a username are left out.
+// The progress of its subtasks is looked up in the given progress by task ID.
+func NewTaskResponse(task *taskmodel.Task, usernames map[uuid.UUID]string, progress map[uuid.UUID]taskmodel.Progress) TaskResponse {
+	assignees := make([]string, 0, len(task.AssigneeIDs()))
+	for _, id := range task.AssigneeIDs() {
+		if username, ok := usernames[id]; ok {
+			assignees = append(assignees, username)
+		}
+	}
+	response := TaskResponse{
+		ID:          task.ID(),
+		Title:       task.Title(),
+		Description: task.Description(),
+		DueDate:     task.DueDate(),
+		Status:      task.Status(),
+		Priority:    task.Priority().String(),
+		Tags:        task.Tags(),
+		Project:     task.Project(),
+		CreatedBy:   usernames[task.CreatorID()],
+		Assignees:   assignees,
+	}
+	if parentID := task.ParentID(); parentID != uuid.Nil {
+		response.ParentID = &parentID
+	}
+	if subtasks, ok := progress[task.ID()]; ok && subtasks.Total > 0 {
+		response.Subtasks = &ProgressResponse{Done: subtasks.Done, Total: subtasks.Total}
+	}
+	return response
+}
+
+// TaskPageResponse is a single page of a task listing.
+// NextCursor is omitted on the last page.
+type TaskPageResponse struct {
+	Items      []TaskResponse `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// NewTaskPageResponse maps the given page of tasks to its response representation.
+func NewTaskPageResponse(page *irepo.TaskPage, usernames map[uuid.UUID]string, progress map[uuid.UUID]taskmodel.Progress) TaskPageResponse {
+	response := NewTaskListResponse(page.Tasks, usernames, progress)
+	response.NextCursor = page.NextCursor
+	return response
+}
+
+// NewTaskListResponse maps the given tasks to a single page holding all of them.
+func NewTaskListResponse(tasks []*taskmodel.Task, usernames map[uuid.UUID]string, progress map[uuid.UUID]taskmodel.Progress) TaskPageResponse {
+	items := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, NewTaskResponse(task, usernames, progress))
+	}
+	return TaskPageResponse{Items: items}
+}
+
+// NewTaskSearchResponse maps the given search results to their response representation,
+// keeping the relevance order.
+func NewTaskSearchResponse(results []*searchqry.Result, usernames map[uuid.UUID]string, progress map[uuid.UUID]taskmodel.Progress) TaskPageResponse {
+	items := make([]TaskResponse, 0, len(results))
+	for _, result := range results {
+		item := NewTaskResponse(result.Task, usernames, progress)
+		item.Score = result.Score
+		item.Highlights = &HighlightsResponse{
+			Title:       result.Highlights.Title,
+			Description: result.Highlights.Description,
+		}
+		items = append(items, item)
+	}
+	return TaskPageResponse{Items: items}
 }
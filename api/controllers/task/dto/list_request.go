@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"strings"
+	"time"
+)
+
+// ListTasksRequest holds the query-string parameters of a task listing.
+type ListTasksRequest struct {
+	AssignedToMe bool      `form:"assignedToMe"`
+	Status       string    `form:"status"`
+	Tags         string    `form:"tags"`
+	TagMatch     string    `form:"tagMatch"`
+	DueAfter     time.Time `form:"dueAfter" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueBefore    time.Time `form:"dueBefore" time_format:"2006-01-02T15:04:05Z07:00"`
+	Query        string    `form:"q"`
+	Sort         string    `form:"sort"`
+	Order        string    `form:"order"`
+	Limit        int       `form:"limit"`
+	Cursor       string    `form:"cursor"`
+}
+
+// TagList returns the comma-separated tags of the request, or nil when it has none.
+func (r *ListTasksRequest) TagList() []string {
+	var tags []string
+	for _, tag := range strings.Split(r.Tags, ",") {
+		if strings.TrimSpace(tag) != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
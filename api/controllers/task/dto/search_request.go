@@ -0,0 +1,7 @@
+package dto
+
+// SearchTasksRequest holds the query-string parameters of a task search.
+type SearchTasksRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
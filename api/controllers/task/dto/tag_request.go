@@ -0,0 +1,11 @@
+package dto
+
+// RenameTagRequest holds the new name of a tag.
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MergeTagRequest holds the tag another tag is merged into.
+type MergeTagRequest struct {
+	Into string `json:"into" binding:"required"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+
+// TagResponse is a tag along with the number of tasks that have it.
+type TagResponse struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagListResponse holds the tags of the tasks visible to the requester, most used first.
+type TagListResponse struct {
+	Items []TagResponse `json:"items"`
+}
+
+// NewTagListResponse maps the given tag usages to their response representation.
+func NewTagListResponse(tags []*irepo.TagUsage) TagListResponse {
+	items := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, TagResponse{Tag: tag.Tag, Count: tag.Count})
+	}
+	return TagListResponse{Items: items}
+}
+
+// TagChangeResponse holds the number of tasks a tag was renamed or merged on.
+type TagChangeResponse struct {
+	TasksChanged int `json:"tasksChanged"`
+}
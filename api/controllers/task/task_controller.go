@@ -4,13 +4,33 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/beka-birhanu/task_manager_final/api"
 	basecontroller "github.com/beka-birhanu/task_manager_final/api/controllers/base"
 	"github.com/beka-birhanu/task_manager_final/api/controllers/task/dto"
 	errapi "github.com/beka-birhanu/task_manager_final/api/errors"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	mergetagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/merge"
+	renametagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/rename"
+	listtagsqry "github.com/beka-birhanu/task_manager_final/app/tag/query/list"
 	addcmd "github.com/beka-birhanu/task_manager_final/app/task/command/add"
+	assigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/assign"
+	deletecmd "github.com/beka-birhanu/task_manager_final/app/task/command/delete"
+	parentcmd "github.com/beka-birhanu/task_manager_final/app/task/command/parent"
+	transitioncmd "github.com/beka-birhanu/task_manager_final/app/task/command/transition"
+	unassigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/unassign"
 	updatecmd "github.com/beka-birhanu/task_manager_final/app/task/command/update"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	getqry "github.com/beka-birhanu/task_manager_final/app/task/query/get"
+	getallqry "github.com/beka-birhanu/task_manager_final/app/task/query/get_all"
+	progressqry "github.com/beka-birhanu/task_manager_final/app/task/query/progress"
+	searchqry "github.com/beka-birhanu/task_manager_final/app/task/query/search"
+	subtasksqry "github.com/beka-birhanu/task_manager_final/app/task/query/subtasks"
+	usernamesqry "github.com/beka-birhanu/task_manager_final/app/task/query/usernames"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,17 +41,45 @@ type Controller struct {
 	basecontroller.BaseHandler
 	addHandler    icmd.IHandler[*addcmd.Command, *taskmodel.Task]
 	updateHandler icmd.IHandler[*updatecmd.Command, *taskmodel.Task]
-	deleteHandler icmd.IHandler[uuid.UUID, bool]
-	getAllHandler icmd.IHandler[struct{}, []*taskmodel.Task]
-	getHandler    icmd.IHandler[uuid.UUID, *taskmodel.Task]
+	deleteHandler icmd.IHandler[*deletecmd.Command, bool]
+	getAllHandler icmd.IHandler[*getallqry.Query, *irepo.TaskPage]
+	getHandler    icmd.IHandler[*getqry.Query, *taskmodel.Task]
+	searchHandler icmd.IHandler[*searchqry.Query, []*searchqry.Result]
+
+	assignHandler     icmd.IHandler[*assigncmd.Command, *taskmodel.Task]
+	unassignHandler   icmd.IHandler[*unassigncmd.Command, *taskmodel.Task]
+	transitionHandler icmd.IHandler[*transitioncmd.Command, *taskmodel.Task]
+	usernamesHandler  iquery.IHandler[*usernamesqry.Query, map[uuid.UUID]string]
+
+	listTagsHandler  iquery.IHandler[*listtagsqry.Query, []*irepo.TagUsage]
+	renameTagHandler icmd.IHandler[*renametagcmd.Command, int]
+	mergeTagHandler  icmd.IHandler[*mergetagcmd.Command, int]
+
+	parentHandler   icmd.IHandler[*parentcmd.Command, *taskmodel.Task]
+	subtasksHandler icmd.IHandler[*subtasksqry.Query, []*taskmodel.Task]
+	progressHandler iquery.IHandler[*progressqry.Query, map[uuid.UUID]taskmodel.Progress]
 }
 
 type Config struct {
 	AddHandler    icmd.IHandler[*addcmd.Command, *taskmodel.Task]
 	UpdateHandler icmd.IHandler[*updatecmd.Command, *taskmodel.Task]
-	DeleteHandler icmd.IHandler[uuid.UUID, bool]
-	GetAllHandler icmd.IHandler[struct{}, []*taskmodel.Task]
-	GetHandler    icmd.IHandler[uuid.UUID, *taskmodel.Task]
+	DeleteHandler icmd.IHandler[*deletecmd.Command, bool]
+	GetAllHandler icmd.IHandler[*getallqry.Query, *irepo.TaskPage]
+	GetHandler    icmd.IHandler[*getqry.Query, *taskmodel.Task]
+	SearchHandler icmd.IHandler[*searchqry.Query, []*searchqry.Result]
+
+	AssignHandler     icmd.IHandler[*assigncmd.Command, *taskmodel.Task]
+	UnassignHandler   icmd.IHandler[*unassigncmd.Command, *taskmodel.Task]
+	TransitionHandler icmd.IHandler[*transitioncmd.Command, *taskmodel.Task]
+	UsernamesHandler  iquery.IHandler[*usernamesqry.Query, map[uuid.UUID]string] // Names the creators and assignees of tasks in responses.
+
+	ListTagsHandler  iquery.IHandler[*listtagsqry.Query, []*irepo.TagUsage]
+	RenameTagHandler icmd.IHandler[*renametagcmd.Command, int]
+	MergeTagHandler  icmd.IHandler[*mergetagcmd.Command, int]
+
+	ParentHandler   icmd.IHandler[*parentcmd.Command, *taskmodel.Task]
+	SubtasksHandler icmd.IHandler[*subtasksqry.Query, []*taskmodel.Task]
+	ProgressHandler iquery.IHandler[*progressqry.Query, map[uuid.UUID]taskmodel.Progress] // Rolls up the subtasks of tasks in responses.
 }
 
 // New creates a new TaskController with the given CQRS handlers and task repository.
@@ -42,32 +90,59 @@ func New(config Config) *Controller {
 		deleteHandler: config.DeleteHandler,
 		getAllHandler: config.GetAllHandler,
 		getHandler:    config.GetHandler,
+		searchHandler: config.SearchHandler,
+
+		assignHandler:     config.AssignHandler,
+		unassignHandler:   config.UnassignHandler,
+		transitionHandler: config.TransitionHandler,
+		usernamesHandler:  config.UsernamesHandler,
+
+		listTagsHandler:  config.ListTagsHandler,
+		renameTagHandler: config.RenameTagHandler,
+		mergeTagHandler:  config.MergeTagHandler,
+
+		parentHandler:   config.ParentHandler,
+		subtasksHandler: config.SubtasksHandler,
+		progressHandler: config.ProgressHandler,
 	}
 }
 
-// RegisterPublic registers public routes.
-func (c *Controller) RegisterPublic(route *gin.RouterGroup) {}
-
-// RegisterProtected registers protected routes.
-func (c *Controller) RegisterProtected(route *gin.RouterGroup) {
+// Register registers the routes of the controller, each requiring the permission
+// to perform its action on one's own tasks. Access to individual tasks is decided
+// by the task policy consulted in the handlers. Renaming and merging tags changes
+// them on every task, and requires the permission to manage tags.
+func (c *Controller) Register(route *gin.RouterGroup, auth api.IAuthorizer) {
 	tasks := route.Group("/tasks")
 	{
-		tasks.GET("", c.getAllTasks)
-		tasks.GET("/:id", c.getTask)
+		tasks.GET("", auth.Require(rolemodel.TaskRead), c.getAllTasks)
+		tasks.GET("/search", auth.Require(rolemodel.TaskRead), c.searchTasks)
+		tasks.GET("/:id", auth.Require(rolemodel.TaskRead), c.getTask)
+		tasks.POST("", auth.Require(rolemodel.TaskCreate), c.addTask)
+		tasks.PUT("/:id", auth.Require(rolemodel.TaskUpdate), c.updateTask)
+		tasks.DELETE("/:id", auth.Require(rolemodel.TaskDelete), c.deleteTask)
+		tasks.PUT("/:id/assignees/:username", auth.Require(rolemodel.TaskUpdate), c.assignTask)
+		tasks.DELETE("/:id/assignees/:username", auth.Require(rolemodel.TaskUpdate), c.unassignTask)
+		tasks.POST("/:id/transitions", auth.Require(rolemodel.TaskUpdate), c.transitionTask)
+		tasks.GET("/:id/subtasks", auth.Require(rolemodel.TaskRead), c.getSubtasks)
+		tasks.PUT("/:id/parent", auth.Require(rolemodel.TaskUpdate), c.setParent)
+		tasks.DELETE("/:id/parent", auth.Require(rolemodel.TaskUpdate), c.removeParent)
 	}
-}
 
-// RegisterPrivileged registers privileged routes.
-func (c *Controller) RegisterPrivileged(route *gin.RouterGroup) {
-	tasks := route.Group("/tasks")
+	tags := route.Group("/tags")
 	{
-		tasks.POST("", c.addTask)
-		tasks.PUT("/:id", c.updateTask)
-		tasks.DELETE("/:id", c.deleteTask)
+		tags.GET("", auth.Require(rolemodel.TaskRead), c.listTags)
+		tags.PATCH("/:tag", auth.Require(rolemodel.TagManage), c.renameTag)
+		tags.POST("/:tag/merge", auth.Require(rolemodel.TagManage), c.mergeTag)
 	}
 }
 
 func (c *Controller) addTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
 	var request dto.AddTaskRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -75,20 +150,19 @@ func (c *Controller) addTask(ctx *gin.Context) {
 		return
 	}
 
-	cmd := addcmd.NewCommand(request.Title, request.Description, request.Status, request.DueDate)
+	cmd := addcmd.NewCommand(request.Title, request.Description, request.Status, request.Priority, request.Project, request.Tags, request.ParentID, request.Assignees, request.DueDate, actor)
 	task, err := c.addHandler.Handle(cmd)
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
-	response := dto.TaskResponse{
-		ID:          task.ID(),
-		Title:       task.Title(),
-		Description: task.Description(),
-		DueDate:     task.DueDate(),
-		Status:      task.Status(),
+	usernames, progress, err := c.related(task)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
 	}
+	response := dto.NewTaskResponse(task, usernames, progress)
 
 	baseURL := fmt.Sprintf("http://%s", ctx.Request.Host)
 	resourceLocation := fmt.Sprintf("%s%s/%s", baseURL, ctx.Request.URL.Path, task.ID().String())
@@ -96,6 +170,12 @@ func (c *Controller) addTask(ctx *gin.Context) {
 }
 
 func (c *Controller) updateTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
@@ -109,7 +189,7 @@ func (c *Controller) updateTask(ctx *gin.Context) {
 		return
 	}
 
-	cmd := updatecmd.NewCommand(id, request.Title, request.Description, request.Status, request.DueDate)
+	cmd := updatecmd.NewCommand(id, request.Title, request.Description, request.Status, request.Priority, request.Tags, request.DueDate, actor)
 	_, err = c.updateHandler.Handle(cmd)
 	if err != nil {
 		if err == errdmn.TaskNotFound {
@@ -124,13 +204,19 @@ func (c *Controller) updateTask(ctx *gin.Context) {
 }
 
 func (c *Controller) deleteTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
-	_, err = c.deleteHandler.Handle(id)
+	_, err = c.deleteHandler.Handle(deletecmd.NewCommand(id, actor))
 	if err != nil {
 		if err == errdmn.TaskNotFound {
 			c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
@@ -144,33 +230,92 @@ func (c *Controller) deleteTask(ctx *gin.Context) {
 }
 
 func (c *Controller) getAllTasks(ctx *gin.Context) {
-	tasks, err := c.getAllHandler.Handle(struct{}{})
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.ListTasksRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	page, err := c.getAllHandler.Handle(&getallqry.Query{
+		Actor:        actor,
+		AssignedToMe: request.AssignedToMe,
+		Status:       request.Status,
+		Tags:         request.TagList(),
+		TagMatch:     request.TagMatch,
+		DueAfter:     request.DueAfter,
+		DueBefore:    request.DueBefore,
+		Text:         request.Query,
+		SortBy:       request.Sort,
+		SortOrder:    request.Order,
+		Limit:        request.Limit,
+		Cursor:       request.Cursor,
+	})
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	usernames, progress, err := c.related(page.Tasks...)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewTaskPageResponse(page, usernames, progress))
+}
+
+func (c *Controller) searchTasks(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.SearchTasksRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	results, err := c.searchHandler.Handle(searchqry.NewQuery(request.Query, request.Limit, actor))
 	if err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
-	var response []dto.TaskResponse
-	for _, task := range tasks {
-		response = append(response, dto.TaskResponse{
-			ID:          task.ID(),
-			Title:       task.Title(),
-			Description: task.Description(),
-			DueDate:     task.DueDate(),
-			Status:      task.Status(),
-		})
+	tasks := make([]*taskmodel.Task, 0, len(results))
+	for _, result := range results {
+		tasks = append(tasks, result.Task)
+	}
+	usernames, progress, err := c.related(tasks...)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
 	}
-	c.Respond(ctx, http.StatusOK, response)
+
+	c.Respond(ctx, http.StatusOK, dto.NewTaskSearchResponse(results, usernames, progress))
 }
 
 func (c *Controller) getTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
 		return
 	}
 
-	task, err := c.getHandler.Handle(id)
+	task, err := c.getHandler.Handle(getqry.NewQuery(id, actor))
 	if err != nil {
 		if err == errdmn.TaskNotFound {
 			c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
@@ -180,13 +325,247 @@ func (c *Controller) getTask(ctx *gin.Context) {
 		return
 	}
 
-	response := dto.TaskResponse{
-		ID:          task.ID(),
-		Title:       task.Title(),
-		Description: task.Description(),
-		DueDate:     task.DueDate(),
-		Status:      task.Status(),
+	c.respondWithTask(ctx, task)
+}
+
+// assignTask assigns the task to the user named in the path.
+func (c *Controller) assignTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	task, err := c.assignHandler.Handle(assigncmd.NewCommand(id, ctx.Param("username"), actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.respondWithTask(ctx, task)
+}
+
+// unassignTask removes the user named in the path from the assignees of the task.
+func (c *Controller) unassignTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	task, err := c.unassignHandler.Handle(unassigncmd.NewCommand(id, ctx.Param("username"), actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.respondWithTask(ctx, task)
+}
+
+// transitionTask moves the task to the status in the body, as the workflow of its project allows.
+func (c *Controller) transitionTask(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
 	}
 
-	c.Respond(ctx, http.StatusOK, response)
+	var request dto.TransitionRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	task, err := c.transitionHandler.Handle(transitioncmd.NewCommand(id, request.Status, actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.respondWithTask(ctx, task)
+}
+
+// getSubtasks returns the subtasks of the task visible to the requester.
+func (c *Controller) getSubtasks(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	subtasks, err := c.subtasksHandler.Handle(subtasksqry.NewQuery(id, actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	usernames, progress, err := c.related(subtasks...)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewTaskListResponse(subtasks, usernames, progress))
+}
+
+// setParent makes the task a subtask of the task in the body.
+func (c *Controller) setParent(ctx *gin.Context) {
+	var request dto.SetParentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	c.moveTask(ctx, request.ParentID)
+}
+
+// removeParent detaches the task from its parent, making it a top-level task again.
+func (c *Controller) removeParent(ctx *gin.Context) {
+	c.moveTask(ctx, uuid.Nil)
+}
+
+// moveTask moves the task in the path under the given parent, or to the top level for uuid.Nil.
+func (c *Controller) moveTask(ctx *gin.Context, parentID uuid.UUID) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	task, err := c.parentHandler.Handle(parentcmd.NewCommand(id, parentID, actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.respondWithTask(ctx, task)
+}
+
+// listTags returns the tags of the tasks visible to the requester with their usage counts.
+func (c *Controller) listTags(ctx *gin.Context) {
+	actor, err := requester(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	tags, err := c.listTagsHandler.Handle(listtagsqry.NewQuery(actor))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewTagListResponse(tags))
+}
+
+// renameTag renames a tag on every task.
+func (c *Controller) renameTag(ctx *gin.Context) {
+	var request dto.RenameTagRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	changed, err := c.renameTagHandler.Handle(renametagcmd.NewCommand(ctx.Param("tag"), request.Name))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.TagChangeResponse{TasksChanged: changed})
+}
+
+// mergeTag merges a tag into another on every task.
+func (c *Controller) mergeTag(ctx *gin.Context) {
+	var request dto.MergeTagRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	changed, err := c.mergeTagHandler.Handle(mergetagcmd.NewCommand(ctx.Param("tag"), request.Into))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.TagChangeResponse{TasksChanged: changed})
+}
+
+// respondWithTask responds with the task, naming its creator and assignees and
+// rolling up its subtasks.
+func (c *Controller) respondWithTask(ctx *gin.Context, task *taskmodel.Task) {
+	usernames, progress, err := c.related(task)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewTaskResponse(task, usernames, progress))
+}
+
+// related looks up what responses show of the tasks beyond their own fields: the
+// usernames of their creators and assignees, and the progress of their subtasks.
+func (c *Controller) related(tasks ...*taskmodel.Task) (map[uuid.UUID]string, map[uuid.UUID]taskmodel.Progress, error) {
+	usernames, err := c.usernamesHandler.Handle(usernamesqry.NewQuery(tasks...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress, err := c.progressHandler.Handle(progressqry.NewQuery(tasks...))
+	if err != nil {
+		return nil, nil, err
+	}
+	return usernames, progress, nil
+}
+
+// requester builds the task policy actor for the authenticated user from the
+// claims attached to the request context by the auth middleware.
+func requester(ctx *gin.Context) (taskpolicy.Actor, error) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		return taskpolicy.Actor{}, err
+	}
+
+	roles := make([]rolemodel.Role, 0, len(claims.Roles))
+	for _, role := range claims.Roles {
+		roles = append(roles, rolemodel.Role(role))
+	}
+	actor := taskpolicy.Actor{ID: claims.Subject, Roles: roles}
+
+	// Requests made with an API key are limited to its scopes; an empty list still limits.
+	if claims.IsAPIKey() {
+		actor.Scopes = append([]rolemodel.Permission{}, claims.Scopes...)
+	}
+	return actor, nil
 }
@@ -8,9 +8,31 @@ import (
 	"time"
 
 	taskcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/task"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	api_mock "github.com/beka-birhanu/task_manager_final/api/mocks"
 	icmd_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command/mocks"
+	iquery_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query/mocks"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	mergetagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/merge"
+	renametagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/rename"
+	listtagsqry "github.com/beka-birhanu/task_manager_final/app/tag/query/list"
 	addcmd "github.com/beka-birhanu/task_manager_final/app/task/command/add"
+	assigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/assign"
+	deletecmd "github.com/beka-birhanu/task_manager_final/app/task/command/delete"
+	parentcmd "github.com/beka-birhanu/task_manager_final/app/task/command/parent"
+	transitioncmd "github.com/beka-birhanu/task_manager_final/app/task/command/transition"
+	unassigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/unassign"
 	updatecmd "github.com/beka-birhanu/task_manager_final/app/task/command/update"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	getqry "github.com/beka-birhanu/task_manager_final/app/task/query/get"
+	getallqry "github.com/beka-birhanu/task_manager_final/app/task/query/get_all"
+	progressqry "github.com/beka-birhanu/task_manager_final/app/task/query/progress"
+	searchqry "github.com/beka-birhanu/task_manager_final/app/task/query/search"
+	subtasksqry "github.com/beka-birhanu/task_manager_final/app/task/query/subtasks"
+	usernamesqry "github.com/beka-birhanu/task_manager_final/app/task/query/usernames"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,19 +45,45 @@ type TaskControllerTestSuite struct {
 	controller        *taskcontroller.Controller
 	mockAddHandler    *icmd_mock.IHandler[*addcmd.Command, *taskmodel.Task]
 	mockUpdateHandler *icmd_mock.IHandler[*updatecmd.Command, *taskmodel.Task]
-	mockDeleteHandler *icmd_mock.IHandler[uuid.UUID, bool]
-	mockGetAllHandler *icmd_mock.IHandler[struct{}, []*taskmodel.Task]
-	mockGetHandler    *icmd_mock.IHandler[uuid.UUID, *taskmodel.Task]
+	mockDeleteHandler *icmd_mock.IHandler[*deletecmd.Command, bool]
+	mockGetAllHandler *icmd_mock.IHandler[*getallqry.Query, *irepo.TaskPage]
+	mockGetHandler    *icmd_mock.IHandler[*getqry.Query, *taskmodel.Task]
+	mockSearchHandler *icmd_mock.IHandler[*searchqry.Query, []*searchqry.Result]
+	mockAssign        *icmd_mock.IHandler[*assigncmd.Command, *taskmodel.Task]
+	mockUnassign      *icmd_mock.IHandler[*unassigncmd.Command, *taskmodel.Task]
+	mockTransition    *icmd_mock.IHandler[*transitioncmd.Command, *taskmodel.Task]
+	mockUsernames     *iquery_mock.IHandler[*usernamesqry.Query, map[uuid.UUID]string]
+	mockListTags      *iquery_mock.IHandler[*listtagsqry.Query, []*irepo.TagUsage]
+	mockRenameTag     *icmd_mock.IHandler[*renametagcmd.Command, int]
+	mockMergeTag      *icmd_mock.IHandler[*mergetagcmd.Command, int]
+	mockParent        *icmd_mock.IHandler[*parentcmd.Command, *taskmodel.Task]
+	mockSubtasks      *icmd_mock.IHandler[*subtasksqry.Query, []*taskmodel.Task]
+	mockProgress      *iquery_mock.IHandler[*progressqry.Query, map[uuid.UUID]taskmodel.Progress]
 	router            *gin.Engine
 	testTask          *taskmodel.Task
+	actor             taskpolicy.Actor
 }
 
 func (suite *TaskControllerTestSuite) SetupTest() {
 	suite.mockAddHandler = new(icmd_mock.IHandler[*addcmd.Command, *taskmodel.Task])
 	suite.mockUpdateHandler = new(icmd_mock.IHandler[*updatecmd.Command, *taskmodel.Task])
-	suite.mockDeleteHandler = new(icmd_mock.IHandler[uuid.UUID, bool])
-	suite.mockGetAllHandler = new(icmd_mock.IHandler[struct{}, []*taskmodel.Task])
-	suite.mockGetHandler = new(icmd_mock.IHandler[uuid.UUID, *taskmodel.Task])
+	suite.mockDeleteHandler = new(icmd_mock.IHandler[*deletecmd.Command, bool])
+	suite.mockGetAllHandler = new(icmd_mock.IHandler[*getallqry.Query, *irepo.TaskPage])
+	suite.mockGetHandler = new(icmd_mock.IHandler[*getqry.Query, *taskmodel.Task])
+	suite.mockSearchHandler = new(icmd_mock.IHandler[*searchqry.Query, []*searchqry.Result])
+	suite.mockAssign = new(icmd_mock.IHandler[*assigncmd.Command, *taskmodel.Task])
+	suite.mockUnassign = new(icmd_mock.IHandler[*unassigncmd.Command, *taskmodel.Task])
+	suite.mockTransition = new(icmd_mock.IHandler[*transitioncmd.Command, *taskmodel.Task])
+	suite.mockUsernames = new(iquery_mock.IHandler[*usernamesqry.Query, map[uuid.UUID]string])
+	suite.mockListTags = new(iquery_mock.IHandler[*listtagsqry.Query, []*irepo.TagUsage])
+	suite.mockRenameTag = new(icmd_mock.IHandler[*renametagcmd.Command, int])
+	suite.mockMergeTag = new(icmd_mock.IHandler[*mergetagcmd.Command, int])
+	suite.mockParent = new(icmd_mock.IHandler[*parentcmd.Command, *taskmodel.Task])
+	suite.mockSubtasks = new(icmd_mock.IHandler[*subtasksqry.Query, []*taskmodel.Task])
+	suite.mockProgress = new(iquery_mock.IHandler[*progressqry.Query, map[uuid.UUID]taskmodel.Progress])
+	suite.actor = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	suite.mockUsernames.On("Handle", mock.Anything).Return(map[uuid.UUID]string{suite.actor.ID: "member1"}, nil)
+	suite.mockProgress.On("Handle", mock.Anything).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
 
 	suite.controller = taskcontroller.New(taskcontroller.Config{
 		AddHandler:    suite.mockAddHandler,
@@ -43,12 +91,28 @@ func (suite *TaskControllerTestSuite) SetupTest() {
 		DeleteHandler: suite.mockDeleteHandler,
 		GetAllHandler: suite.mockGetAllHandler,
 		GetHandler:    suite.mockGetHandler,
+		SearchHandler: suite.mockSearchHandler,
+
+		AssignHandler:     suite.mockAssign,
+		UnassignHandler:   suite.mockUnassign,
+		TransitionHandler: suite.mockTransition,
+		UsernamesHandler:  suite.mockUsernames,
+
+		ListTagsHandler:  suite.mockListTags,
+		RenameTagHandler: suite.mockRenameTag,
+		MergeTagHandler:  suite.mockMergeTag,
+
+		ParentHandler:   suite.mockParent,
+		SubtasksHandler: suite.mockSubtasks,
+		ProgressHandler: suite.mockProgress,
 	})
 
 	suite.router = gin.Default()
-	api := suite.router.Group("/api")
-	suite.controller.RegisterProtected(api)
-	suite.controller.RegisterPrivileged(api)
+	suite.router.Use(func(ctx *gin.Context) {
+		// Simulate the claims attached by the auth middleware.
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{Subject: suite.actor.ID, Roles: []string{"member"}})
+	})
+	suite.controller.Register(suite.router.Group("/api"), api_mock.Authorizer{})
 
 	suite.testTask, _ = taskmodel.New(
 		taskmodel.Config{
@@ -56,6 +120,7 @@ func (suite *TaskControllerTestSuite) SetupTest() {
 			Description: "This is a test task.",
 			DueDate:     time.Now(),
 			Status:      "pending",
+			OwnerID:     suite.actor.ID,
 		})
 }
 
@@ -77,6 +142,39 @@ func (suite *TaskControllerTestSuite) TestAddTask_Success() {
 	suite.mockAddHandler.AssertExpectations(suite.T())
 }
 
+func (suite *TaskControllerTestSuite) TestAddTask_InvalidPriority() {
+	suite.mockAddHandler.On("Handle", mock.AnythingOfType("*addcmd.Command")).Return((*taskmodel.Task)(nil), errdmn.InvalidPriority)
+
+	reqBody := `{
+		"title": "Test Task",
+		"description": "This is a test task.",
+		"priority": "whenever",
+		"dueDate": "2024-08-30T00:00:00Z"
+	}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestAddTask_PermissionDenied() {
+	router := gin.Default()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{Subject: suite.actor.ID, Roles: []string{"viewer"}})
+	})
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.mockAddHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
 func (suite *TaskControllerTestSuite) TestUpdateTask_Success() {
 	id := suite.testTask.ID()
 	suite.mockUpdateHandler.On("Handle", mock.AnythingOfType("*updatecmd.Command")).Return(suite.testTask, nil)
@@ -98,7 +196,7 @@ func (suite *TaskControllerTestSuite) TestUpdateTask_Success() {
 
 func (suite *TaskControllerTestSuite) TestDeleteTask_Success() {
 	id := suite.testTask.ID()
-	suite.mockDeleteHandler.On("Handle", id).Return(true, nil)
+	suite.mockDeleteHandler.On("Handle", deletecmd.NewCommand(id, suite.actor)).Return(true, nil)
 
 	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/"+id.String(), nil)
 	w := httptest.NewRecorder()
@@ -109,28 +207,426 @@ func (suite *TaskControllerTestSuite) TestDeleteTask_Success() {
 }
 
 func (suite *TaskControllerTestSuite) TestGetAllTasks_Success() {
-	suite.mockGetAllHandler.On("Handle", mock.Anything).Return([]*taskmodel.Task{suite.testTask}, nil)
+	page := &irepo.TaskPage{Tasks: []*taskmodel.Task{suite.testTask}, NextCursor: "next-page"}
+	suite.mockGetAllHandler.On("Handle", &getallqry.Query{Actor: suite.actor}).Return(page, nil)
 
 	req, _ := http.NewRequest(http.MethodGet, "/api/tasks", nil)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"nextCursor":"next-page"`)
+	suite.Contains(w.Body.String(), suite.testTask.ID().String())
+	suite.mockGetAllHandler.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestDeleteTask_APIKey() {
+	keyID := uuid.New()
+	scopes := []rolemodel.Permission{rolemodel.TaskDelete}
+	router := gin.Default()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{Subject: suite.actor.ID, Roles: []string{"member"}, APIKeyID: keyID, Scopes: scopes})
+	})
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+
+	// The actor is limited to the scopes of the key.
+	actor := suite.actor
+	actor.Scopes = scopes
+	id := suite.testTask.ID()
+	suite.mockDeleteHandler.On("Handle", deletecmd.NewCommand(id, actor)).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockDeleteHandler.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestGetAllTasks_QueryParameters() {
+	dueAfter, _ := time.Parse(time.RFC3339, "2024-08-01T00:00:00Z")
+	expected := &getallqry.Query{
+		Actor:     suite.actor,
+		Status:    "pending",
+		Tags:      []string{"Backend", "bug"},
+		TagMatch:  "all",
+		DueAfter:  dueAfter,
+		Text:      "report",
+		SortBy:    "title",
+		SortOrder: "desc",
+		Limit:     10,
+		Cursor:    "abc",
+	}
+	suite.mockGetAllHandler.On("Handle", expected).Return(&irepo.TaskPage{}, nil)
+
+	url := "/api/tasks?status=pending&tags=Backend,bug&tagMatch=all&dueAfter=2024-08-01T00:00:00Z&q=report&sort=title&order=desc&limit=10&cursor=abc"
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"items":[]`)
+	suite.NotContains(w.Body.String(), "nextCursor")
 	suite.mockGetAllHandler.AssertExpectations(suite.T())
 }
 
+func (suite *TaskControllerTestSuite) TestGetAllTasks_BadQuery() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks?limit=many", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockGetAllHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *TaskControllerTestSuite) TestSearchTasks_Success() {
+	results := []*searchqry.Result{{
+		Task:       suite.testTask,
+		Score:      1.5,
+		Highlights: searchqry.Highlights{Title: "<mark>Test</mark> Task"},
+	}}
+	suite.mockSearchHandler.On("Handle", searchqry.NewQuery("test", 5, suite.actor)).Return(results, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/search?q=test&limit=5", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"score":1.5`)
+	suite.Contains(w.Body.String(), `"highlights":{"title":"\u003cmark\u003eTest\u003c/mark\u003e Task"}`)
+	suite.mockSearchHandler.AssertExpectations(suite.T())
+	suite.mockGetHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *TaskControllerTestSuite) TestSearchTasks_BadQuery() {
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/search?q=test&limit=many", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockSearchHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
 func (suite *TaskControllerTestSuite) TestGetTask_Success() {
 	id := suite.testTask.ID()
-	suite.mockGetHandler.On("Handle", id).Return(suite.testTask, nil)
+	suite.mockGetHandler.On("Handle", getqry.NewQuery(id, suite.actor)).Return(suite.testTask, nil)
 
 	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/"+id.String(), nil)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"createdBy":"member1","assignees":[]`)
 	suite.mockGetHandler.AssertExpectations(suite.T())
 }
 
+func (suite *TaskControllerTestSuite) TestGetAllTasks_AssignedToMe() {
+	suite.mockGetAllHandler.On("Handle", &getallqry.Query{Actor: suite.actor, AssignedToMe: true}).Return(&irepo.TaskPage{}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks?assignedToMe=true", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockGetAllHandler.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestAssignTask_Success() {
+	assignee := uuid.New()
+	suite.Require().NoError(suite.testTask.Assign(assignee))
+	id := suite.testTask.ID()
+	suite.mockAssign.On("Handle", assigncmd.NewCommand(id, "user1", suite.actor)).Return(suite.testTask, nil)
+	suite.mockUsernames.ExpectedCalls = nil
+	suite.mockUsernames.On("Handle", usernamesqry.NewQuery(suite.testTask)).
+		Return(map[uuid.UUID]string{suite.actor.ID: "member1", assignee: "user1"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/tasks/"+id.String()+"/assignees/user1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"createdBy":"member1","assignees":["user1"]`)
+	suite.mockAssign.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestAssignTask_InvalidAssignee() {
+	id := suite.testTask.ID()
+	suite.mockAssign.On("Handle", mock.AnythingOfType("*assigncmd.Command")).Return((*taskmodel.Task)(nil), errdmn.InvalidAssignee)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/tasks/"+id.String()+"/assignees/ghost", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestUnassignTask_Success() {
+	id := suite.testTask.ID()
+	suite.mockUnassign.On("Handle", unassigncmd.NewCommand(id, "user1", suite.actor)).Return(suite.testTask, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/"+id.String()+"/assignees/user1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"assignees":[]`)
+	suite.mockUnassign.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestGetAllTasks_ClaimsNotFound() {
+	router := gin.Default()
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockGetAllHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *TaskControllerTestSuite) TestTransitionTask_Success() {
+	id := suite.testTask.ID()
+	suite.Require().NoError(suite.testTask.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+	suite.mockTransition.On("Handle", transitioncmd.NewCommand(id, taskmodel.StatusInProgress, suite.actor)).Return(suite.testTask, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/"+id.String()+"/transitions", strings.NewReader(`{"status": "inprogress"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"status":"inprogress"`)
+	suite.mockTransition.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestTransitionTask_NotAllowed() {
+	id := suite.testTask.ID()
+	suite.mockTransition.On("Handle", mock.AnythingOfType("*transitioncmd.Command")).Return((*taskmodel.Task)(nil), errdmn.TransitionNotAllowed)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/"+id.String()+"/transitions", strings.NewReader(`{"status": "done"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestTransitionTask_SubtasksOpen() {
+	id := suite.testTask.ID()
+	suite.mockTransition.On("Handle", mock.AnythingOfType("*transitioncmd.Command")).Return((*taskmodel.Task)(nil), errdmn.SubtasksOpen)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/"+id.String()+"/transitions", strings.NewReader(`{"status": "done"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestTransitionTask_MissingStatus() {
+	id := suite.testTask.ID()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tasks/"+id.String()+"/transitions", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockTransition.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+// routerAs returns a router whose requests are made by the suite's actor with the given role.
+func (suite *TaskControllerTestSuite) routerAs(role string) *gin.Engine {
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{Subject: suite.actor.ID, Roles: []string{role}})
+	})
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+	return router
+}
+
+func (suite *TaskControllerTestSuite) TestListTags_Success() {
+	tags := []*irepo.TagUsage{{Tag: "bug", Count: 2}, {Tag: "backend", Count: 1}}
+	suite.mockListTags.On("Handle", listtagsqry.NewQuery(suite.actor)).Return(tags, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tags", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"items":[{"tag":"bug","count":2},{"tag":"backend","count":1}]}`, w.Body.String())
+}
+
+func (suite *TaskControllerTestSuite) TestRenameTag_Success() {
+	suite.mockRenameTag.On("Handle", renametagcmd.NewCommand("bugs", "defect")).Return(3, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/tags/bugs", strings.NewReader(`{"name": "defect"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("manager").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"tasksChanged":3}`, w.Body.String())
+}
+
+func (suite *TaskControllerTestSuite) TestRenameTag_InUse() {
+	suite.mockRenameTag.On("Handle", renametagcmd.NewCommand("bugs", "bug")).Return(0, errdmn.TagInUse)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/tags/bugs", strings.NewReader(`{"name": "bug"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("manager").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestRenameTag_PermissionDenied() {
+	req, _ := http.NewRequest(http.MethodPatch, "/api/tags/bugs", strings.NewReader(`{"name": "defect"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.mockRenameTag.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *TaskControllerTestSuite) TestMergeTag_Success() {
+	suite.mockMergeTag.On("Handle", mergetagcmd.NewCommand("bugs", "bug")).Return(3, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tags/bugs/merge", strings.NewReader(`{"into": "bug"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("admin").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"tasksChanged":3}`, w.Body.String())
+}
+
+func (suite *TaskControllerTestSuite) TestMergeTag_NotFound() {
+	suite.mockMergeTag.On("Handle", mergetagcmd.NewCommand("bugs", "defect")).Return(0, errdmn.TagNotFound)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/tags/bugs/merge", strings.NewReader(`{"into": "defect"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("admin").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestGetTask_SubtaskProgress() {
+	id := suite.testTask.ID()
+	suite.mockGetHandler.On("Handle", getqry.NewQuery(id, suite.actor)).Return(suite.testTask, nil)
+	suite.mockProgress.ExpectedCalls = nil
+	suite.mockProgress.On("Handle", progressqry.NewQuery(suite.testTask)).
+		Return(map[uuid.UUID]taskmodel.Progress{id: {Done: 1, Total: 3}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"subtasks":{"done":1,"total":3}`)
+	suite.mockProgress.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestGetTask_NoSubtasks() {
+	id := suite.testTask.ID()
+	suite.mockGetHandler.On("Handle", getqry.NewQuery(id, suite.actor)).Return(suite.testTask, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.NotContains(w.Body.String(), `"subtasks"`)
+	suite.NotContains(w.Body.String(), `"parentId"`)
+}
+
+func (suite *TaskControllerTestSuite) TestGetSubtasks_Success() {
+	id := suite.testTask.ID()
+	subtask, err := taskmodel.New(taskmodel.Config{
+		Title:       "Subtask",
+		Description: "A part of the test task.",
+		DueDate:     time.Now(),
+		ParentID:    id,
+		OwnerID:     suite.actor.ID,
+	})
+	suite.Require().NoError(err)
+	suite.mockSubtasks.On("Handle", subtasksqry.NewQuery(id, suite.actor)).Return([]*taskmodel.Task{subtask}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/"+id.String()+"/subtasks", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"parentId":"`+id.String()+`"`)
+	suite.mockSubtasks.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestGetSubtasks_TaskNotFound() {
+	id := uuid.New()
+	suite.mockSubtasks.On("Handle", subtasksqry.NewQuery(id, suite.actor)).Return([]*taskmodel.Task(nil), errdmn.TaskNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/tasks/"+id.String()+"/subtasks", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestSetParent_Success() {
+	id, parentID := suite.testTask.ID(), uuid.New()
+	suite.Require().NoError(suite.testTask.SetParent(parentID))
+	suite.mockParent.On("Handle", parentcmd.NewCommand(id, parentID, suite.actor)).Return(suite.testTask, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/tasks/"+id.String()+"/parent", strings.NewReader(`{"parentId": "`+parentID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"parentId":"`+parentID.String()+`"`)
+	suite.mockParent.AssertExpectations(suite.T())
+}
+
+func (suite *TaskControllerTestSuite) TestSetParent_Cycle() {
+	id := suite.testTask.ID()
+	suite.mockParent.On("Handle", mock.AnythingOfType("*parentcmd.Command")).Return((*taskmodel.Task)(nil), errdmn.ParentCycle)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/tasks/"+id.String()+"/parent", strings.NewReader(`{"parentId": "`+uuid.NewString()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *TaskControllerTestSuite) TestSetParent_MissingParent() {
+	id := suite.testTask.ID()
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/tasks/"+id.String()+"/parent", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockParent.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *TaskControllerTestSuite) TestRemoveParent_Success() {
+	id := suite.testTask.ID()
+	suite.mockParent.On("Handle", parentcmd.NewCommand(id, uuid.Nil, suite.actor)).Return(suite.testTask, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/tasks/"+id.String()+"/parent", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.NotContains(w.Body.String(), `"parentId"`)
+	suite.mockParent.AssertExpectations(suite.T())
+}
+
 func TestTaskControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(TaskControllerTestSuite))
 }
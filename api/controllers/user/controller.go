@@ -3,12 +3,41 @@ package usercontroller
 import (
 	"net/http"
 
+	"github.com/beka-birhanu/task_manager_final/api"
+	authcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/auth"
+	authdto "github.com/beka-birhanu/task_manager_final/api/controllers/auth/dto"
 	basecontroller "github.com/beka-birhanu/task_manager_final/api/controllers/base"
+	"github.com/beka-birhanu/task_manager_final/api/controllers/user/dto"
 	errapi "github.com/beka-birhanu/task_manager_final/api/errors"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	promotcmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/command"
+	demotecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/demote"
+	rolecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/role"
+	createapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/create"
+	listapikeysqry "github.com/beka-birhanu/task_manager_final/app/user/apikey/list"
+	revokeapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/revoke"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	lockstatusqry "github.com/beka-birhanu/task_manager_final/app/user/lock/status"
+	unlockcmd "github.com/beka-birhanu/task_manager_final/app/user/lock/unlock"
+	deactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/deactivate"
+	deleteusercmd "github.com/beka-birhanu/task_manager_final/app/user/manage/delete"
+	getuserqry "github.com/beka-birhanu/task_manager_final/app/user/manage/get"
+	listusersqry "github.com/beka-birhanu/task_manager_final/app/user/manage/list"
+	reactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/reactivate"
+	confirmmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/confirm"
+	disablemfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/disable"
+	enrollmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/enroll"
+	passwordcmd "github.com/beka-birhanu/task_manager_final/app/user/password/command"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	profileqry "github.com/beka-birhanu/task_manager_final/app/user/profile/get"
+	profilecmd "github.com/beka-birhanu/task_manager_final/app/user/profile/update"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
-	"github.com/dgrijalva/jwt-go"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -16,33 +45,211 @@ import (
 // Controller handles HTTP requests related to users.
 type Controller struct {
 	basecontroller.BaseHandler
-	promotHandler icmd.IHandler[*promotcmd.Command, bool]
+	promotHandler         icmd.IHandler[*promotcmd.Command, bool]
+	demoteHandler         icmd.IHandler[*demotecmd.Command, bool]
+	roleHandler           icmd.IHandler[*rolecmd.Command, bool]
+	changePasswordHandler icmd.IHandler[*passwordcmd.Command, *authresult.Result]
+	resetPasswordHandler  icmd.IHandler[*forgotcmd.Command, bool]
+	enrollMFAHandler      icmd.IHandler[*enrollmfacmd.Command, *enrollmfacmd.Result]
+	confirmMFAHandler     icmd.IHandler[*confirmmfacmd.Command, []string]
+	disableMFAHandler     icmd.IHandler[*disablemfacmd.Command, bool]
+	lockStatusHandler     iquery.IHandler[*lockstatusqry.Query, *lockstatusqry.Result]
+	unlockHandler         icmd.IHandler[*unlockcmd.Command, bool]
+	listHandler           iquery.IHandler[*listusersqry.Query, *irepo.UserPage]
+	getHandler            iquery.IHandler[*getuserqry.Query, *usermodel.User]
+	deactivateHandler     icmd.IHandler[*deactivatecmd.Command, bool]
+	reactivateHandler     icmd.IHandler[*reactivatecmd.Command, bool]
+	deleteHandler         icmd.IHandler[*deleteusercmd.Command, bool]
+	profileHandler        iquery.IHandler[*profileqry.Query, *usermodel.User]
+	updateProfileHandler  icmd.IHandler[*profilecmd.Command, *usermodel.User]
+	createAPIKeyHandler   icmd.IHandler[*createapikeycmd.Command, *createapikeycmd.Result]
+	listAPIKeysHandler    iquery.IHandler[*listapikeysqry.Query, []*apikeymodel.APIKey]
+	revokeAPIKeyHandler   icmd.IHandler[*revokeapikeycmd.Command, bool]
 }
 
 // Config holds the configuration for the Controller.
 type Config struct {
-	PromotHandler icmd.IHandler[*promotcmd.Command, bool]
+	PromotHandler         icmd.IHandler[*promotcmd.Command, bool]
+	DemoteHandler         icmd.IHandler[*demotecmd.Command, bool]
+	RoleHandler           icmd.IHandler[*rolecmd.Command, bool]
+	ChangePasswordHandler icmd.IHandler[*passwordcmd.Command, *authresult.Result]
+	ResetPasswordHandler  icmd.IHandler[*forgotcmd.Command, bool]
+	EnrollMFAHandler      icmd.IHandler[*enrollmfacmd.Command, *enrollmfacmd.Result]
+	ConfirmMFAHandler     icmd.IHandler[*confirmmfacmd.Command, []string]
+	DisableMFAHandler     icmd.IHandler[*disablemfacmd.Command, bool]
+	LockStatusHandler     iquery.IHandler[*lockstatusqry.Query, *lockstatusqry.Result]
+	UnlockHandler         icmd.IHandler[*unlockcmd.Command, bool]
+	ListHandler           iquery.IHandler[*listusersqry.Query, *irepo.UserPage]
+	GetHandler            iquery.IHandler[*getuserqry.Query, *usermodel.User]
+	DeactivateHandler     icmd.IHandler[*deactivatecmd.Command, bool]
+	ReactivateHandler     icmd.IHandler[*reactivatecmd.Command, bool]
+	DeleteHandler         icmd.IHandler[*deleteusercmd.Command, bool]
+	ProfileHandler        iquery.IHandler[*profileqry.Query, *usermodel.User]
+	UpdateProfileHandler  icmd.IHandler[*profilecmd.Command, *usermodel.User]
+	CreateAPIKeyHandler   icmd.IHandler[*createapikeycmd.Command, *createapikeycmd.Result]
+	ListAPIKeysHandler    iquery.IHandler[*listapikeysqry.Query, []*apikeymodel.APIKey]
+	RevokeAPIKeyHandler   icmd.IHandler[*revokeapikeycmd.Command, bool]
 }
 
-// New creates a new UserController with the given CQRS handler.
+// New creates a new UserController with the given CQRS handlers.
 func New(config Config) *Controller {
 	return &Controller{
-		promotHandler: config.PromotHandler,
+		promotHandler:         config.PromotHandler,
+		demoteHandler:         config.DemoteHandler,
+		roleHandler:           config.RoleHandler,
+		changePasswordHandler: config.ChangePasswordHandler,
+		resetPasswordHandler:  config.ResetPasswordHandler,
+		enrollMFAHandler:      config.EnrollMFAHandler,
+		confirmMFAHandler:     config.ConfirmMFAHandler,
+		disableMFAHandler:     config.DisableMFAHandler,
+		lockStatusHandler:     config.LockStatusHandler,
+		unlockHandler:         config.UnlockHandler,
+		listHandler:           config.ListHandler,
+		getHandler:            config.GetHandler,
+		deactivateHandler:     config.DeactivateHandler,
+		reactivateHandler:     config.ReactivateHandler,
+		deleteHandler:         config.DeleteHandler,
+		profileHandler:        config.ProfileHandler,
+		updateProfileHandler:  config.UpdateProfileHandler,
+		createAPIKeyHandler:   config.CreateAPIKeyHandler,
+		listAPIKeysHandler:    config.ListAPIKeysHandler,
+		revokeAPIKeyHandler:   config.RevokeAPIKeyHandler,
 	}
 }
 
-// RegisterPublic registers public routes.
-func (c *Controller) RegisterPublic(route *gin.RouterGroup) {}
-
-// RegisterProtected registers protected routes.
-func (c *Controller) RegisterProtected(route *gin.RouterGroup) {}
-
-// RegisterPrivileged registers privileged routes.
-func (c *Controller) RegisterPrivileged(route *gin.RouterGroup) {
+// Register registers the routes of the controller. Users manage their own account
+// once authenticated; managing other users requires the matching permission.
+func (c *Controller) Register(route *gin.RouterGroup, auth api.IAuthorizer) {
 	user := route.Group("/users")
 	{
-		user.PATCH("/:username/promot", c.promot)
+		user.GET("/me", auth.Authenticated(), c.profile)
+		user.PATCH("/me", auth.Authenticated(), c.updateProfile)
+		user.PATCH("/me/password", auth.Authenticated(), c.changePassword)
+		user.POST("/me/mfa/totp", auth.Authenticated(), c.enrollMFA)
+		user.POST("/me/mfa/totp/confirm", auth.Authenticated(), c.confirmMFA)
+		user.POST("/me/mfa/disable", auth.Authenticated(), c.disableMFA)
+		user.POST("/me/api-keys", auth.Authenticated(), c.createAPIKey)
+		user.GET("/me/api-keys", auth.Authenticated(), c.listAPIKeys)
+		user.DELETE("/me/api-keys/:id", auth.Authenticated(), c.revokeAPIKey)
+
+		user.GET("", auth.Require(rolemodel.UserRead), c.list)
+		user.GET("/:username", auth.Require(rolemodel.UserRead), c.get)
+		user.PATCH("/:username/deactivate", auth.Require(rolemodel.UserDeactivate), c.deactivate)
+		user.PATCH("/:username/reactivate", auth.Require(rolemodel.UserDeactivate), c.reactivate)
+		user.DELETE("/:username", auth.Require(rolemodel.UserDelete), c.delete)
+		user.PATCH("/:username/promot", auth.Require(rolemodel.UserPromote), c.promot)
+		user.PATCH("/:username/demote", auth.Require(rolemodel.UserDemote), c.demote)
+		user.PUT("/:username/role", auth.Require(rolemodel.UserAssignRole), c.assignRole)
+		user.POST("/:username/password/reset", auth.Require(rolemodel.UserResetPassword), c.resetPassword)
+		user.GET("/:username/lock", auth.Require(rolemodel.UserReadLock), c.lockStatus)
+		user.DELETE("/:username/lock", auth.Require(rolemodel.UserUnlock), c.unlock)
+	}
+}
+
+// list returns a page of the users, optionally searched by username or email and
+// filtered by role.
+func (c *Controller) list(ctx *gin.Context) {
+	var request dto.ListUsersRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	page, err := c.listHandler.Handle(&listusersqry.Query{
+		Text:   request.Query,
+		Role:   request.Role,
+		Limit:  request.Limit,
+		Cursor: request.Cursor,
+	})
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewUserPageResponse(page))
+}
+
+// get returns the account details of a user.
+func (c *Controller) get(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	user, err := c.getHandler.Handle(getuserqry.NewQuery(username))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewUserResponse(user))
+}
+
+// deactivate keeps a user from signing in and ends their sessions.
+func (c *Controller) deactivate(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	if _, err := c.deactivateHandler.Handle(deactivatecmd.NewCommand(username, claims.Subject)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, nil)
+}
+
+// reactivate lets a deactivated user sign in again.
+func (c *Controller) reactivate(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	if _, err := c.reactivateHandler.Handle(reactivatecmd.NewCommand(username)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, nil)
+}
+
+// delete deletes a user for good, reassigning their tasks to another user or deleting them.
+func (c *Controller) delete(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
 	}
+
+	var request dto.DeleteUserRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.deleteHandler.Handle(deleteusercmd.NewCommand(username, request.Tasks, request.To, claims.Subject)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
 }
 
 // promot handles the promotion of a user.
@@ -53,37 +260,334 @@ func (c *Controller) promot(ctx *gin.Context) {
 		return
 	}
 
-	claims, exists := ctx.Get("userClaims")
-	if !exists {
-		c.Problem(ctx, errapi.NewAuthentication("Claims not found"))
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
 		return
 	}
 
-	// Type assertion to jwt.MapClaims
-	jwtClaims, ok := claims.(jwt.MapClaims)
-	if !ok {
-		c.Problem(ctx, errapi.NewAuthentication("Invalid claims"))
+	_, err = c.promotHandler.Handle(promotcmd.NewCommand(username, claims.Subject))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
-	// Extract and parse the user_id claim as a UUID
-	userIDStr, ok := jwtClaims["user_id"].(string)
-	if !ok {
-		c.Problem(ctx, errapi.NewAuthentication("Invalid user_id claim"))
+	c.Respond(ctx, http.StatusOK, nil)
+}
+
+// demote handles the demotion of an admin to a regular user.
+func (c *Controller) demote(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
 		return
 	}
 
-	promoterId, err := uuid.Parse(userIDStr)
+	_, err = c.demoteHandler.Handle(demotecmd.NewCommand(username, claims.Subject))
 	if err != nil {
-		c.Problem(ctx, errapi.NewBadRequest("Invalid user_id format"))
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, nil)
+}
+
+// assignRole gives a user one of the named roles.
+func (c *Controller) assignRole(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
 		return
 	}
 
-	_, err = c.promotHandler.Handle(promotcmd.NewCommand(username, promoterId))
+	claims, err := authmiddleware.Claims(ctx)
 	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.AssignRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.roleHandler.Handle(rolecmd.NewCommand(username, request.Role, claims.Subject)); err != nil {
 		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
 		return
 	}
 
 	c.Respond(ctx, http.StatusOK, nil)
 }
+
+// profile returns the profile of the authenticated user.
+func (c *Controller) profile(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	user, err := c.profileHandler.Handle(profileqry.NewQuery(claims.Subject))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewProfileResponse(user))
+}
+
+// updateProfile changes the given profile fields of the authenticated user and
+// returns the updated profile.
+func (c *Controller) updateProfile(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	user, err := c.updateProfileHandler.Handle(&profilecmd.Command{
+		UserID:      claims.Subject,
+		Username:    request.Username,
+		DisplayName: request.DisplayName,
+		TimeZone:    request.TimeZone,
+		Locale:      request.Locale,
+		AvatarURL:   request.AvatarURL,
+	})
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewProfileResponse(user))
+}
+
+// createAPIKey creates an API key for the authenticated user. The key is only
+// returned in this response.
+func (c *Controller) createAPIKey(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	cmd := &createapikeycmd.Command{
+		UserID: claims.Subject,
+		Name:   request.Name,
+		Scopes: request.Scopes,
+	}
+	if request.ExpiresAt != nil {
+		cmd.ExpiresAt = *request.ExpiresAt
+	}
+
+	result, err := c.createAPIKeyHandler.Handle(cmd)
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusCreated, &dto.CreatedAPIKeyResponse{
+		APIKeyResponse: *dto.NewAPIKeyResponse(result.APIKey),
+		Key:            result.Key,
+	})
+}
+
+// listAPIKeys returns the API keys of the authenticated user, without their secrets.
+func (c *Controller) listAPIKeys(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	keys, err := c.listAPIKeysHandler.Handle(listapikeysqry.NewQuery(claims.Subject))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewAPIKeyListResponse(keys))
+}
+
+// revokeAPIKey revokes one of the API keys of the authenticated user.
+func (c *Controller) revokeAPIKey(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		c.Problem(ctx, errapi.NewBadRequest("invalid api key id"))
+		return
+	}
+
+	if _, err := c.revokeAPIKeyHandler.Handle(revokeapikeycmd.NewCommand(id, claims.Subject)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
+
+// changePassword changes the password of the authenticated user. Their existing
+// sessions end, and the tokens of a new session are returned.
+func (c *Controller) changePassword(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	result, err := c.changePasswordHandler.Handle(passwordcmd.NewCommand(claims.Subject, request.CurrentPassword, request.NewPassword))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	response := authdto.NewAuthResponse(result)
+	c.RespondWithCookies(ctx, http.StatusOK, response, authcontroller.SessionCookies(ctx, result))
+}
+
+// resetPassword sends a password reset token to the given user on behalf of an admin.
+func (c *Controller) resetPassword(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	sent, err := c.resetPasswordHandler.Handle(forgotcmd.NewCommand(username))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+	// Admins know the user exists, so they are told when the token could not be sent.
+	if !sent {
+		c.Problem(ctx, errapi.NewServerError("password reset token could not be sent"))
+		return
+	}
+
+	c.Respond(ctx, http.StatusAccepted, nil)
+}
+
+// lockStatus returns the failed sign in attempts of a user and whether signing in as them is locked.
+func (c *Controller) lockStatus(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	result, err := c.lockStatusHandler.Handle(lockstatusqry.NewQuery(username))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewLockStatusResponse(result))
+}
+
+// unlock lifts the sign in lock of a user and forgets their failed attempts.
+func (c *Controller) unlock(ctx *gin.Context) {
+	username := ctx.Param("username")
+	if username == "" {
+		c.Problem(ctx, errapi.NewBadRequest("username missing"))
+		return
+	}
+
+	if _, err := c.unlockHandler.Handle(unlockcmd.NewCommand(username)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
+
+// enrollMFA starts the enrollment of an authenticator app for the authenticated user.
+func (c *Controller) enrollMFA(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	result, err := c.enrollMFAHandler.Handle(enrollmfacmd.NewCommand(claims.Subject))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewMFAEnrollmentResponse(result))
+}
+
+// confirmMFA enables two-factor authentication for the authenticated user once the
+// code of the enrolled app is confirmed, and returns their recovery codes.
+func (c *Controller) confirmMFA(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.ConfirmMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	recoveryCodes, err := c.confirmMFAHandler.Handle(confirmmfacmd.NewCommand(claims.Subject, request.Code))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, &dto.RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// disableMFA disables two-factor authentication for the authenticated user.
+func (c *Controller) disableMFA(ctx *gin.Context) {
+	claims, err := authmiddleware.Claims(ctx)
+	if err != nil {
+		c.Problem(ctx, errapi.NewAuthentication(err.Error()))
+		return
+	}
+
+	var request dto.DisableMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	if _, err := c.disableMFAHandler.Handle(disablemfacmd.NewCommand(claims.Subject, request.Password, request.Code)); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
@@ -1,16 +1,44 @@
 package usercontroller_test
 
 import (
-	"context"
-	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	usercontroller "github.com/beka-birhanu/task_manager_final/api/controllers/user"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	api_mock "github.com/beka-birhanu/task_manager_final/api/mocks"
 	icmd_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command/mocks"
+	iquery_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query/mocks"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	promotcmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/command"
-	"github.com/dgrijalva/jwt-go"
+	demotecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/demote"
+	rolecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/role"
+	createapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/create"
+	listapikeysqry "github.com/beka-birhanu/task_manager_final/app/user/apikey/list"
+	revokeapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/revoke"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	lockstatusqry "github.com/beka-birhanu/task_manager_final/app/user/lock/status"
+	unlockcmd "github.com/beka-birhanu/task_manager_final/app/user/lock/unlock"
+	deactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/deactivate"
+	deleteusercmd "github.com/beka-birhanu/task_manager_final/app/user/manage/delete"
+	getuserqry "github.com/beka-birhanu/task_manager_final/app/user/manage/get"
+	listusersqry "github.com/beka-birhanu/task_manager_final/app/user/manage/list"
+	reactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/reactivate"
+	confirmmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/confirm"
+	disablemfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/disable"
+	enrollmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/enroll"
+	passwordcmd "github.com/beka-birhanu/task_manager_final/app/user/password/command"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	profileqry "github.com/beka-birhanu/task_manager_final/app/user/profile/get"
+	profilecmd "github.com/beka-birhanu/task_manager_final/app/user/profile/update"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -19,33 +47,99 @@ import (
 
 type UserControllerTestSuite struct {
 	suite.Suite
-	controller        *usercontroller.Controller
-	mockPromotHandler *icmd_mock.IHandler[*promotcmd.Command, bool]
-	router            *gin.Engine
+	controller                *usercontroller.Controller
+	mockPromotHandler         *icmd_mock.IHandler[*promotcmd.Command, bool]
+	mockDemoteHandler         *icmd_mock.IHandler[*demotecmd.Command, bool]
+	mockRoleHandler           *icmd_mock.IHandler[*rolecmd.Command, bool]
+	mockChangePasswordHandler *icmd_mock.IHandler[*passwordcmd.Command, *authresult.Result]
+	mockResetPasswordHandler  *icmd_mock.IHandler[*forgotcmd.Command, bool]
+	mockEnrollMFAHandler      *icmd_mock.IHandler[*enrollmfacmd.Command, *enrollmfacmd.Result]
+	mockConfirmMFAHandler     *icmd_mock.IHandler[*confirmmfacmd.Command, []string]
+	mockDisableMFAHandler     *icmd_mock.IHandler[*disablemfacmd.Command, bool]
+	mockLockStatusHandler     *iquery_mock.IHandler[*lockstatusqry.Query, *lockstatusqry.Result]
+	mockUnlockHandler         *icmd_mock.IHandler[*unlockcmd.Command, bool]
+	mockListHandler           *iquery_mock.IHandler[*listusersqry.Query, *irepo.UserPage]
+	mockGetHandler            *iquery_mock.IHandler[*getuserqry.Query, *usermodel.User]
+	mockDeactivateHandler     *icmd_mock.IHandler[*deactivatecmd.Command, bool]
+	mockReactivateHandler     *icmd_mock.IHandler[*reactivatecmd.Command, bool]
+	mockDeleteHandler         *icmd_mock.IHandler[*deleteusercmd.Command, bool]
+	mockProfileHandler        *iquery_mock.IHandler[*profileqry.Query, *usermodel.User]
+	mockUpdateProfileHandler  *icmd_mock.IHandler[*profilecmd.Command, *usermodel.User]
+	mockCreateAPIKeyHandler   *icmd_mock.IHandler[*createapikeycmd.Command, *createapikeycmd.Result]
+	mockListAPIKeysHandler    *iquery_mock.IHandler[*listapikeysqry.Query, []*apikeymodel.APIKey]
+	mockRevokeAPIKeyHandler   *icmd_mock.IHandler[*revokeapikeycmd.Command, bool]
+	router                    *gin.Engine
+	claims                    interface{} // Claims attached to the request context, if any.
 }
 
 func (suite *UserControllerTestSuite) SetupTest() {
 	suite.mockPromotHandler = new(icmd_mock.IHandler[*promotcmd.Command, bool])
+	suite.mockDemoteHandler = new(icmd_mock.IHandler[*demotecmd.Command, bool])
+	suite.mockRoleHandler = new(icmd_mock.IHandler[*rolecmd.Command, bool])
+	suite.mockChangePasswordHandler = new(icmd_mock.IHandler[*passwordcmd.Command, *authresult.Result])
+	suite.mockResetPasswordHandler = new(icmd_mock.IHandler[*forgotcmd.Command, bool])
+	suite.mockEnrollMFAHandler = new(icmd_mock.IHandler[*enrollmfacmd.Command, *enrollmfacmd.Result])
+	suite.mockConfirmMFAHandler = new(icmd_mock.IHandler[*confirmmfacmd.Command, []string])
+	suite.mockDisableMFAHandler = new(icmd_mock.IHandler[*disablemfacmd.Command, bool])
+	suite.mockLockStatusHandler = new(iquery_mock.IHandler[*lockstatusqry.Query, *lockstatusqry.Result])
+	suite.mockUnlockHandler = new(icmd_mock.IHandler[*unlockcmd.Command, bool])
+	suite.mockListHandler = new(iquery_mock.IHandler[*listusersqry.Query, *irepo.UserPage])
+	suite.mockGetHandler = new(iquery_mock.IHandler[*getuserqry.Query, *usermodel.User])
+	suite.mockDeactivateHandler = new(icmd_mock.IHandler[*deactivatecmd.Command, bool])
+	suite.mockReactivateHandler = new(icmd_mock.IHandler[*reactivatecmd.Command, bool])
+	suite.mockDeleteHandler = new(icmd_mock.IHandler[*deleteusercmd.Command, bool])
+	suite.mockProfileHandler = new(iquery_mock.IHandler[*profileqry.Query, *usermodel.User])
+	suite.mockUpdateProfileHandler = new(icmd_mock.IHandler[*profilecmd.Command, *usermodel.User])
+	suite.mockCreateAPIKeyHandler = new(icmd_mock.IHandler[*createapikeycmd.Command, *createapikeycmd.Result])
+	suite.mockListAPIKeysHandler = new(iquery_mock.IHandler[*listapikeysqry.Query, []*apikeymodel.APIKey])
+	suite.mockRevokeAPIKeyHandler = new(icmd_mock.IHandler[*revokeapikeycmd.Command, bool])
 
 	suite.controller = usercontroller.New(usercontroller.Config{
-		PromotHandler: suite.mockPromotHandler,
+		PromotHandler:         suite.mockPromotHandler,
+		DemoteHandler:         suite.mockDemoteHandler,
+		RoleHandler:           suite.mockRoleHandler,
+		ChangePasswordHandler: suite.mockChangePasswordHandler,
+		ResetPasswordHandler:  suite.mockResetPasswordHandler,
+		EnrollMFAHandler:      suite.mockEnrollMFAHandler,
+		ConfirmMFAHandler:     suite.mockConfirmMFAHandler,
+		DisableMFAHandler:     suite.mockDisableMFAHandler,
+		LockStatusHandler:     suite.mockLockStatusHandler,
+		UnlockHandler:         suite.mockUnlockHandler,
+		ListHandler:           suite.mockListHandler,
+		GetHandler:            suite.mockGetHandler,
+		DeactivateHandler:     suite.mockDeactivateHandler,
+		ReactivateHandler:     suite.mockReactivateHandler,
+		DeleteHandler:         suite.mockDeleteHandler,
+		ProfileHandler:        suite.mockProfileHandler,
+		UpdateProfileHandler:  suite.mockUpdateProfileHandler,
+		CreateAPIKeyHandler:   suite.mockCreateAPIKeyHandler,
+		ListAPIKeysHandler:    suite.mockListAPIKeysHandler,
+		RevokeAPIKeyHandler:   suite.mockRevokeAPIKeyHandler,
 	})
 
+	suite.claims = &ijwt.Claims{Subject: uuid.New(), Roles: []string{"admin"}}
+
 	suite.router = gin.Default()
-	api := suite.router.Group("/api")
-	suite.controller.RegisterPrivileged(api)
+	suite.router.Use(func(ctx *gin.Context) {
+		// Simulate the claims attached by the auth middleware.
+		if suite.claims != nil {
+			ctx.Set(authmiddleware.ContextUserClaims, suite.claims)
+		}
+	})
+	suite.controller.Register(suite.router.Group("/api"), api_mock.Authorizer{})
 }
 
 func (suite *UserControllerTestSuite) TestPromot_UsernameMissing() {
 	req, _ := http.NewRequest(http.MethodPatch, "/api/users//promot", nil)
-	ctx := context.WithValue(req.Context(), "userClaims", jwt.MapClaims{}) //nolint
 	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req.WithContext(ctx))
+	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusBadRequest, w.Code)
 }
 
 func (suite *UserControllerTestSuite) TestPromot_ClaimsNotFound() {
+	suite.claims = nil
+
 	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
 	w := httptest.NewRecorder()
 	suite.router.ServeHTTP(w, req)
@@ -54,45 +148,521 @@ func (suite *UserControllerTestSuite) TestPromot_ClaimsNotFound() {
 }
 
 func (suite *UserControllerTestSuite) TestPromot_InvalidClaims() {
-	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
 	// Set invalid claims
-	ctx := context.WithValue(req.Context(), "userClaims", "invalid_claims") //nolint
+	suite.claims = "invalid_claims"
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
 	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req.WithContext(ctx))
+	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockPromotHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
 }
 
-func (suite *UserControllerTestSuite) TestPromot_InvalidUserIDClaim() {
+func (suite *UserControllerTestSuite) TestPromot_Success() {
+	promoterID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockPromotHandler.On("Handle", promotcmd.NewCommand("testuser", promoterID)).Return(true, nil)
+
 	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
-	ctx := context.WithValue(req.Context(), "userClaims", //nolint
-		jwt.MapClaims{
-			"user_id": "invalid-uuid",
-		})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockPromotHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestPromot_PromotionFailure() {
+	suite.mockPromotHandler.On("Handle", mock.AnythingOfType("*promotcmd.Command")).Return(false, errdmn.UserNotFound)
 
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
 	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req.WithContext(ctx))
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestDemote_Success() {
+	demoterID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockDemoteHandler.On("Handle", demotecmd.NewCommand("testuser", demoterID)).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/demote", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockDemoteHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestDemote_LastAdmin() {
+	suite.mockDemoteHandler.On("Handle", mock.AnythingOfType("*demotecmd.Command")).Return(false, errdmn.LastAdmin)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/demote", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestDemote_ClaimsNotFound() {
+	suite.claims = nil
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/demote", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockDemoteHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
 }
 
-func (suite *UserControllerTestSuite) TestPromot_PromotionFailure() {
-	promoterId := uuid.New()
-	suite.mockPromotHandler.On("Handle", mock.AnythingOfType("*promotcmd.Command")).Return(false, errors.New("promotion failed"))
+func (suite *UserControllerTestSuite) TestPromot_PermissionDenied() {
+	suite.claims = &ijwt.Claims{Subject: uuid.New(), Roles: []string{"manager"}}
 
-	// Create a mock JWT token with user_id claim
 	req, _ := http.NewRequest(http.MethodPatch, "/api/users/testuser/promot", nil)
-	ctx := context.WithValue(req.Context(), "userClaims", //nolint
-		jwt.MapClaims{
-			"user_id": promoterId.String(),
-		})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.mockPromotHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestAssignRole_Success() {
+	assignerID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockRoleHandler.On("Handle", rolecmd.NewCommand("testuser", "manager", assignerID)).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/users/testuser/role", strings.NewReader(`{"role":"manager"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockRoleHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestAssignRole_InvalidRole() {
+	suite.mockRoleHandler.On("Handle", mock.AnythingOfType("*rolecmd.Command")).Return(false, errdmn.InvalidRole)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/users/testuser/role", strings.NewReader(`{"role":"root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestAssignRole_BadRequest() {
+	req, _ := http.NewRequest(http.MethodPut, "/api/users/testuser/role", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockRoleHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestChangePassword_Success() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	result := &authresult.Result{ID: userID, Token: "newtoken", RefreshToken: "newrefresh", RefreshExpiresAt: time.Now().Add(time.Hour)}
+	suite.mockChangePasswordHandler.On("Handle", passwordcmd.NewCommand(userID, "old-password", "new-password")).Return(result, nil)
+
+	reqBody := `{"currentPassword":"old-password","newPassword":"new-password"}`
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me/password", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Header().Values("Set-Cookie")[0], "accessToken=newtoken")
+	suite.Contains(w.Header().Values("Set-Cookie")[1], "refreshToken=newrefresh")
+	suite.Contains(w.Body.String(), `"accessToken":"newtoken"`)
+	suite.mockChangePasswordHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestChangePassword_IncorrectCurrentPassword() {
+	suite.mockChangePasswordHandler.On("Handle", mock.AnythingOfType("*passwordcmd.Command")).Return((*authresult.Result)(nil), errdmn.IncorrectCurrentPassword)
+
+	reqBody := `{"currentPassword":"wrong","newPassword":"new-password"}`
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me/password", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Empty(w.Header().Values("Set-Cookie"))
+}
+
+func (suite *UserControllerTestSuite) TestChangePassword_BadRequest() {
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me/password", strings.NewReader(`{"newPassword":"new-password"}`))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
-	suite.router.ServeHTTP(w, req.WithContext(ctx))
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockChangePasswordHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestChangePassword_ClaimsNotFound() {
+	suite.claims = nil
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me/password", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
 
 	suite.Equal(http.StatusUnauthorized, w.Code)
 }
 
+func (suite *UserControllerTestSuite) TestResetPassword_Success() {
+	suite.mockResetPasswordHandler.On("Handle", forgotcmd.NewCommand("testuser")).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/testuser/password/reset", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusAccepted, w.Code)
+	suite.mockResetPasswordHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestResetPassword_NotSent() {
+	suite.mockResetPasswordHandler.On("Handle", forgotcmd.NewCommand("testuser")).Return(false, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/testuser/password/reset", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusInternalServerError, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestResetPassword_UserNotFound() {
+	suite.mockResetPasswordHandler.On("Handle", forgotcmd.NewCommand("ghost")).Return(false, errdmn.UserNotFound)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/ghost/password/reset", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestEnrollMFA() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	result := &enrollmfacmd.Result{Secret: "SECRET", URI: "otpauth://totp/x"}
+	suite.mockEnrollMFAHandler.On("Handle", enrollmfacmd.NewCommand(userID)).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/mfa/totp", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"secret":"SECRET","uri":"otpauth://totp/x"}`, w.Body.String())
+	suite.mockEnrollMFAHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestConfirmMFA() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockConfirmMFAHandler.On("Handle", confirmmfacmd.NewCommand(userID, "123456")).Return([]string{"abcde-fghij"}, nil)
+	suite.mockConfirmMFAHandler.On("Handle", confirmmfacmd.NewCommand(userID, "654321")).Return([]string(nil), errdmn.InvalidMFACode)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/mfa/totp/confirm", strings.NewReader(`{"code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"recoveryCodes":["abcde-fghij"]}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/api/users/me/mfa/totp/confirm", strings.NewReader(`{"code":"654321"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "invalid verification code")
+}
+
+func (suite *UserControllerTestSuite) TestDisableMFA() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockDisableMFAHandler.On("Handle", disablemfacmd.NewCommand(userID, "password", "123456")).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/mfa/disable", strings.NewReader(`{"password":"password","code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.mockDisableMFAHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestDisableMFA_BadRequest() {
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/mfa/disable", strings.NewReader(`{"password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockDisableMFAHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestLockStatus() {
+	lockedUntil := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	suite.mockLockStatusHandler.On("Handle", lockstatusqry.NewQuery("lockeduser")).Return(&lockstatusqry.Result{
+		Username:    "lockeduser",
+		Failures:    5,
+		Locked:      true,
+		LockedUntil: lockedUntil,
+	}, nil)
+	suite.mockLockStatusHandler.On("Handle", lockstatusqry.NewQuery("ghost")).Return((*lockstatusqry.Result)(nil), errdmn.UserNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users/lockeduser/lock", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"username":"lockeduser","failedAttempts":5,"locked":true,"lockedUntil":"2024-01-01T12:00:00Z"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/users/ghost/lock", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestUnlock() {
+	suite.mockUnlockHandler.On("Handle", unlockcmd.NewCommand("lockeduser")).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/lockeduser/lock", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.mockUnlockHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestList() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	query := &listusersqry.Query{Text: "us", Role: "member", Limit: 1}
+	suite.mockListHandler.On("Handle", query).Return(&irepo.UserPage{Users: []*usermodel.User{user}, NextCursor: "next"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users?q=us&role=member&limit=1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"username":"user1"`)
+	suite.Contains(w.Body.String(), `"nextCursor":"next"`)
+	suite.mockListHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestList_PermissionDenied() {
+	suite.claims = &ijwt.Claims{Subject: uuid.New(), Roles: []string{"manager"}}
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.mockListHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestGet() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member, Deactivated: true})
+	suite.mockGetHandler.On("Handle", getuserqry.NewQuery("user1")).Return(user, nil)
+	suite.mockGetHandler.On("Handle", getuserqry.NewQuery("ghost")).Return((*usermodel.User)(nil), errdmn.UserNotFound)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users/user1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"deactivated":true`)
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/users/ghost", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestDeactivate() {
+	adminID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockDeactivateHandler.On("Handle", deactivatecmd.NewCommand("user1", adminID)).Return(true, nil)
+	suite.mockDeactivateHandler.On("Handle", deactivatecmd.NewCommand("admin1", adminID)).Return(false, errdmn.DeactivateLastAdmin)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/user1/deactivate", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPatch, "/api/users/admin1/deactivate", nil)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestReactivate() {
+	suite.mockReactivateHandler.On("Handle", reactivatecmd.NewCommand("user1")).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/user1/reactivate", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockReactivateHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestDelete() {
+	adminID := suite.claims.(*ijwt.Claims).Subject
+	suite.mockDeleteHandler.On("Handle", deleteusercmd.NewCommand("user1", deleteusercmd.TasksReassign, "user2", adminID)).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/user1?tasks=reassign&to=user2", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.mockDeleteHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestDelete_TasksMissing() {
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/user1", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockDeleteHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestProfile() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: userID, Username: "user1", Role: rolemodel.Member, Locale: "en-US"})
+	suite.mockProfileHandler.On("Handle", profileqry.NewQuery(userID)).Return(user, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users/me", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"id":"`+userID.String()+`","username":"user1","emailVerified":false,"role":"member","mfaEnabled":false,"locale":"en-US"}`, w.Body.String())
+	suite.mockGetHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestUpdateProfile() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: userID, Username: "user_one", DisplayName: "User One"})
+	suite.mockUpdateProfileHandler.On("Handle", mock.MatchedBy(func(cmd *profilecmd.Command) bool {
+		return cmd.UserID == userID && *cmd.Username == "user_one" && *cmd.DisplayName == "User One" && cmd.Locale == nil
+	})).Return(user, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me", strings.NewReader(`{"username":"user_one","displayName":"User One"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"displayName":"User One"`)
+	suite.mockUpdateProfileHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestUpdateProfile_UsernameConflict() {
+	suite.mockUpdateProfileHandler.On("Handle", mock.AnythingOfType("*profilecmd.Command")).Return((*usermodel.User)(nil), errdmn.UsernameConflict)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/api/users/me", strings.NewReader(`{"username":"taken"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestCreateAPIKey() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := apikeymodel.FromBSON(&apikeymodel.APIKeyBSON{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      "ci",
+		Scopes:    []rolemodel.Permission{rolemodel.TaskRead},
+		CreatedAt: time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: expiresAt,
+	})
+	suite.mockCreateAPIKeyHandler.On("Handle", mock.MatchedBy(func(cmd *createapikeycmd.Command) bool {
+		return cmd.UserID == userID && cmd.Name == "ci" && len(cmd.Scopes) == 1 && cmd.ExpiresAt.Equal(expiresAt)
+	})).Return(&createapikeycmd.Result{APIKey: key, Key: "tm_key"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/api-keys", strings.NewReader(`{"name":"ci","scopes":["task:read"],"expiresAt":"2030-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.JSONEq(`{"id":"`+key.ID().String()+`","name":"ci","scopes":["task:read"],"createdAt":"2029-01-01T00:00:00Z","expiresAt":"2030-01-01T00:00:00Z","key":"tm_key"}`, w.Body.String())
+	suite.mockCreateAPIKeyHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestCreateAPIKey_ScopesMissing() {
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/api-keys", strings.NewReader(`{"name":"ci"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockCreateAPIKeyHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestCreateAPIKey_ScopeNotGranted() {
+	suite.mockCreateAPIKeyHandler.On("Handle", mock.AnythingOfType("*createapikeycmd.Command")).Return((*createapikeycmd.Result)(nil), errdmn.APIKeyScopeNotGranted)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/users/me/api-keys", strings.NewReader(`{"name":"ci","scopes":["user:delete"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+}
+
+func (suite *UserControllerTestSuite) TestListAPIKeys() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	key := apikeymodel.FromBSON(&apikeymodel.APIKeyBSON{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       "ci",
+		Scopes:     []rolemodel.Permission{rolemodel.TaskRead},
+		SecretHash: "hashed_secret",
+		CreatedAt:  time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastUsedAt: time.Date(2029, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	suite.mockListAPIKeysHandler.On("Handle", listapikeysqry.NewQuery(userID)).Return([]*apikeymodel.APIKey{key}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/users/me/api-keys", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`[{"id":"`+key.ID().String()+`","name":"ci","scopes":["task:read"],"createdAt":"2029-01-01T00:00:00Z","lastUsedAt":"2029-02-01T00:00:00Z"}]`, w.Body.String())
+}
+
+func (suite *UserControllerTestSuite) TestRevokeAPIKey() {
+	userID := suite.claims.(*ijwt.Claims).Subject
+	id := uuid.New()
+	suite.mockRevokeAPIKeyHandler.On("Handle", revokeapikeycmd.NewCommand(id, userID)).Return(true, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/api-keys/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.mockRevokeAPIKeyHandler.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestRevokeAPIKey_InvalidID() {
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/api-keys/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockRevokeAPIKeyHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestRevokeAPIKey_NotFound() {
+	suite.mockRevokeAPIKeyHandler.On("Handle", mock.AnythingOfType("*revokeapikeycmd.Command")).Return(false, errdmn.APIKeyNotFound)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/users/me/api-keys/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
 func TestUserControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(UserControllerTestSuite))
 }
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest holds the details of a new API key. Without an expiry the key
+// can be used until it is revoked.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
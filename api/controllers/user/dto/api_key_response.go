@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	"github.com/google/uuid"
+)
+
+// APIKeyResponse describes an API key without its secret.
+// The expiry and last use are omitted when the key has none.
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// CreatedAPIKeyResponse describes a new API key along with the key itself, which is
+// only ever shown in this response.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// NewAPIKeyResponse maps the given API key to its response.
+func NewAPIKeyResponse(key *apikeymodel.APIKey) *APIKeyResponse {
+	scopes := make([]string, 0, len(key.Scopes()))
+	for _, scope := range key.Scopes() {
+		scopes = append(scopes, string(scope))
+	}
+
+	return &APIKeyResponse{
+		ID:         key.ID(),
+		Name:       key.Name(),
+		Scopes:     scopes,
+		CreatedAt:  key.CreatedAt(),
+		ExpiresAt:  optionalTime(key.ExpiresAt()),
+		LastUsedAt: optionalTime(key.LastUsedAt()),
+	}
+}
+
+// NewAPIKeyListResponse maps the given API keys to their responses.
+func NewAPIKeyListResponse(keys []*apikeymodel.APIKey) []*APIKeyResponse {
+	responses := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, NewAPIKeyResponse(key))
+	}
+	return responses
+}
+
+// optionalTime returns nil for the zero time, so it is left out of the response.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,7 @@
+package dto
+
+// ChangePasswordRequest carries the current password of the user and the one replacing it.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
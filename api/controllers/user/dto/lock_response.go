@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	lockstatusqry "github.com/beka-birhanu/task_manager_final/app/user/lock/status"
+)
+
+// LockStatusResponse carries the sign in lock status of a user.
+type LockStatusResponse struct {
+	Username       string     `json:"username"`
+	FailedAttempts int        `json:"failedAttempts"`
+	Locked         bool       `json:"locked"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"` // Only set while locked.
+}
+
+// NewLockStatusResponse maps a lock status to its response.
+func NewLockStatusResponse(result *lockstatusqry.Result) *LockStatusResponse {
+	response := &LockStatusResponse{
+		Username:       result.Username,
+		FailedAttempts: result.Failures,
+		Locked:         result.Locked,
+	}
+	if result.Locked {
+		lockedUntil := result.LockedUntil
+		response.LockedUntil = &lockedUntil
+	}
+	return response
+}
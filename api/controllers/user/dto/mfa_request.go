@@ -0,0 +1,13 @@
+package dto
+
+// ConfirmMFARequest carries the code shown by the authenticator app being enrolled.
+type ConfirmMFARequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableMFARequest carries both factors of the user. Users signing in with single
+// sign-on have no password and send the code alone.
+type DisableMFARequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"required"`
+}
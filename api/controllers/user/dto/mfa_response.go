@@ -0,0 +1,22 @@
+package dto
+
+import enrollmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/enroll"
+
+// MFAEnrollmentResponse carries the TOTP secret to enter in the authenticator app.
+type MFAEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// URI, usually shown as a QR code.
+}
+
+// NewMFAEnrollmentResponse maps the result of an enrollment to its response.
+func NewMFAEnrollmentResponse(result *enrollmfacmd.Result) *MFAEnrollmentResponse {
+	return &MFAEnrollmentResponse{
+		Secret: result.Secret,
+		URI:    result.URI,
+	}
+}
+
+// RecoveryCodesResponse carries the recovery codes, shown to the user once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// UpdateProfileRequest holds the profile fields the signed in user changes.
+// Omitted fields are left unchanged; empty strings remove the optional details.
+type UpdateProfileRequest struct {
+	Username    *string `json:"username"`
+	DisplayName *string `json:"displayName"`
+	TimeZone    *string `json:"timeZone"`
+	Locale      *string `json:"locale"`
+	AvatarURL   *string `json:"avatarUrl"`
+}
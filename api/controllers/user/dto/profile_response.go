@@ -0,0 +1,37 @@
+package dto
+
+import (
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// ProfileResponse carries the profile of the signed in user.
+// Optional details the user has not set are omitted.
+type ProfileResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"emailVerified"`
+	Role          string    `json:"role"`
+	MFAEnabled    bool      `json:"mfaEnabled"`
+	DisplayName   string    `json:"displayName,omitempty"`
+	TimeZone      string    `json:"timeZone,omitempty"`
+	Locale        string    `json:"locale,omitempty"`
+	AvatarURL     string    `json:"avatarUrl,omitempty"`
+}
+
+// NewProfileResponse maps the given user to their profile response.
+func NewProfileResponse(user *usermodel.User) *ProfileResponse {
+	return &ProfileResponse{
+		ID:            user.ID(),
+		Username:      user.Username(),
+		Email:         user.Email(),
+		EmailVerified: user.IsEmailVerified(),
+		Role:          string(user.Role()),
+		MFAEnabled:    user.IsMFAEnabled(),
+		DisplayName:   user.DisplayName(),
+		TimeZone:      user.TimeZone(),
+		Locale:        user.Locale(),
+		AvatarURL:     user.AvatarURL(),
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// AssignRoleRequest carries the name of the role to give the user: viewer, member, manager, or admin.
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
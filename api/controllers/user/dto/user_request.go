@@ -0,0 +1,16 @@
+package dto
+
+// ListUsersRequest holds the query-string parameters of a user listing.
+type ListUsersRequest struct {
+	Query  string `form:"q"`
+	Role   string `form:"role"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+// DeleteUserRequest holds the query-string parameters of a user deletion: whether the
+// tasks of the user are reassigned or deleted, and who is given them when reassigned.
+type DeleteUserRequest struct {
+	Tasks string `form:"tasks" binding:"required"`
+	To    string `form:"to"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// UserResponse carries the account details of a user shown to admins.
+type UserResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"emailVerified"`
+	Role          string    `json:"role"`
+	MFAEnabled    bool      `json:"mfaEnabled"`
+	Deactivated   bool      `json:"deactivated"`
+}
+
+// NewUserResponse maps the given user to its response representation.
+func NewUserResponse(user *usermodel.User) UserResponse {
+	return UserResponse{
+		ID:            user.ID(),
+		Username:      user.Username(),
+		Email:         user.Email(),
+		EmailVerified: user.IsEmailVerified(),
+		Role:          string(user.Role()),
+		MFAEnabled:    user.IsMFAEnabled(),
+		Deactivated:   user.IsDeactivated(),
+	}
+}
+
+// UserPageResponse is a single page of a user listing.
+// NextCursor is omitted on the last page.
+type UserPageResponse struct {
+	Items      []UserResponse `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// NewUserPageResponse maps the given page of users to its response representation.
+func NewUserPageResponse(page *irepo.UserPage) UserPageResponse {
+	items := make([]UserResponse, 0, len(page.Users))
+	for _, user := range page.Users {
+		items = append(items, NewUserResponse(user))
+	}
+	return UserPageResponse{Items: items, NextCursor: page.NextCursor}
+}
@@ -0,0 +1,123 @@
+// Package workflowcontroller lets admins define the workflow of each project, the
+// states its tasks go through and the transitions allowed between them, and lets
+// everyone who can read tasks look the workflows up.
+package workflowcontroller
+
+import (
+	"net/http"
+
+	"github.com/beka-birhanu/task_manager_final/api"
+	basecontroller "github.com/beka-birhanu/task_manager_final/api/controllers/base"
+	"github.com/beka-birhanu/task_manager_final/api/controllers/workflow/dto"
+	errapi "github.com/beka-birhanu/task_manager_final/api/errors"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	defineworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/define"
+	deleteworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/delete"
+	getworkflowqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/get"
+	listworkflowsqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/list"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/gin-gonic/gin"
+)
+
+// Controller handles HTTP requests related to workflows.
+type Controller struct {
+	basecontroller.BaseHandler
+	defineHandler icmd.IHandler[*defineworkflowcmd.Command, *workflowmodel.Workflow]
+	deleteHandler icmd.IHandler[*deleteworkflowcmd.Command, bool]
+	getHandler    iquery.IHandler[*getworkflowqry.Query, *workflowmodel.Workflow]
+	listHandler   iquery.IHandler[*listworkflowsqry.Query, []*workflowmodel.Workflow]
+}
+
+// Config holds the handlers of the controller.
+type Config struct {
+	DefineHandler icmd.IHandler[*defineworkflowcmd.Command, *workflowmodel.Workflow]
+	DeleteHandler icmd.IHandler[*deleteworkflowcmd.Command, bool]
+	GetHandler    iquery.IHandler[*getworkflowqry.Query, *workflowmodel.Workflow]
+	ListHandler   iquery.IHandler[*listworkflowsqry.Query, []*workflowmodel.Workflow]
+}
+
+// New creates a new Controller with the given handlers.
+func New(config Config) *Controller {
+	return &Controller{
+		defineHandler: config.DefineHandler,
+		deleteHandler: config.DeleteHandler,
+		getHandler:    config.GetHandler,
+		listHandler:   config.ListHandler,
+	}
+}
+
+// Register registers the routes of the controller. Reading workflows requires the
+// permission to read tasks; changing them is for admins.
+func (c *Controller) Register(route *gin.RouterGroup, auth api.IAuthorizer) {
+	workflows := route.Group("/workflows")
+	{
+		workflows.GET("", auth.Require(rolemodel.TaskRead), c.list)
+		workflows.GET("/:project", auth.Require(rolemodel.TaskRead), c.get)
+		workflows.PUT("/:project", auth.Require(rolemodel.WorkflowManage), c.define)
+		workflows.DELETE("/:project", auth.Require(rolemodel.WorkflowManage), c.delete)
+	}
+}
+
+// list returns the default workflow and the workflows defined for projects.
+func (c *Controller) list(ctx *gin.Context) {
+	defaultWorkflow, err := c.getHandler.Handle(getworkflowqry.NewQuery(""))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	workflows, err := c.listHandler.Handle(&listworkflowsqry.Query{})
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewWorkflowListResponse(defaultWorkflow, workflows))
+}
+
+// get returns the workflow the tasks of the project follow.
+func (c *Controller) get(ctx *gin.Context) {
+	workflow, err := c.getHandler.Handle(getworkflowqry.NewQuery(ctx.Param("project")))
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewWorkflowResponse(workflow))
+}
+
+// define sets the workflow of the project, replacing the one it had.
+func (c *Controller) define(ctx *gin.Context) {
+	var request dto.DefineWorkflowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.Problem(ctx, errapi.NewBadRequest(err.Error()))
+		return
+	}
+
+	workflow, err := c.defineHandler.Handle(&defineworkflowcmd.Command{
+		Project:     ctx.Param("project"),
+		States:      request.States,
+		Initial:     request.Initial,
+		Final:       request.Final,
+		Transitions: request.ToTransitions(),
+	})
+	if err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusOK, dto.NewWorkflowResponse(workflow))
+}
+
+// delete removes the workflow of the project, whose tasks then follow the default workflow.
+func (c *Controller) delete(ctx *gin.Context) {
+	if _, err := c.deleteHandler.Handle(deleteworkflowcmd.NewCommand(ctx.Param("project"))); err != nil {
+		c.Problem(ctx, errapi.FromErrDMN(err.(*errdmn.Error)))
+		return
+	}
+
+	c.Respond(ctx, http.StatusNoContent, nil)
+}
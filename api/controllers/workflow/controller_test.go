@@ -0,0 +1,152 @@
+package workflowcontroller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	workflowcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/workflow"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	api_mock "github.com/beka-birhanu/task_manager_final/api/mocks"
+	icmd_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command/mocks"
+	iquery_mock "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query/mocks"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	defineworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/define"
+	deleteworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/delete"
+	getworkflowqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/get"
+	listworkflowsqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/list"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkflowControllerTestSuite struct {
+	suite.Suite
+	controller        *workflowcontroller.Controller
+	mockDefineHandler *icmd_mock.IHandler[*defineworkflowcmd.Command, *workflowmodel.Workflow]
+	mockDeleteHandler *icmd_mock.IHandler[*deleteworkflowcmd.Command, bool]
+	mockGetHandler    *iquery_mock.IHandler[*getworkflowqry.Query, *workflowmodel.Workflow]
+	mockListHandler   *iquery_mock.IHandler[*listworkflowsqry.Query, []*workflowmodel.Workflow]
+	workflow          *workflowmodel.Workflow
+}
+
+func (suite *WorkflowControllerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockDefineHandler = new(icmd_mock.IHandler[*defineworkflowcmd.Command, *workflowmodel.Workflow])
+	suite.mockDeleteHandler = new(icmd_mock.IHandler[*deleteworkflowcmd.Command, bool])
+	suite.mockGetHandler = new(iquery_mock.IHandler[*getworkflowqry.Query, *workflowmodel.Workflow])
+	suite.mockListHandler = new(iquery_mock.IHandler[*listworkflowsqry.Query, []*workflowmodel.Workflow])
+
+	suite.controller = workflowcontroller.New(workflowcontroller.Config{
+		DefineHandler: suite.mockDefineHandler,
+		DeleteHandler: suite.mockDeleteHandler,
+		GetHandler:    suite.mockGetHandler,
+		ListHandler:   suite.mockListHandler,
+	})
+
+	suite.workflow, _ = workflowmodel.New(workflowmodel.Config{
+		Project:     "website",
+		States:      []string{"todo", "shipped"},
+		Final:       []string{"shipped"},
+		Transitions: []workflowmodel.Transition{{From: "todo", To: "shipped"}},
+	})
+}
+
+// routerAs returns a router whose requests are made by a user with the given role.
+func (suite *WorkflowControllerTestSuite) routerAs(role string) *gin.Engine {
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		ctx.Set(authmiddleware.ContextUserClaims, &ijwt.Claims{Subject: uuid.New(), Roles: []string{role}})
+	})
+	suite.controller.Register(router.Group("/api"), api_mock.Authorizer{})
+	return router
+}
+
+func (suite *WorkflowControllerTestSuite) TestList() {
+	suite.mockGetHandler.On("Handle", getworkflowqry.NewQuery("")).Return(workflowmodel.Default(), nil)
+	suite.mockListHandler.On("Handle", &listworkflowsqry.Query{}).Return([]*workflowmodel.Workflow{suite.workflow}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/workflows", nil)
+	w := httptest.NewRecorder()
+	suite.routerAs("viewer").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"default":{"states":["pending","inprogress","review","done"],"initial":"pending","final":["done"]`)
+	suite.Contains(w.Body.String(), `"items":[{"project":"website","states":["todo","shipped"],"initial":"todo","final":["shipped"],"transitions":[{"from":"todo","to":"shipped"}]}]`)
+}
+
+func (suite *WorkflowControllerTestSuite) TestGet() {
+	suite.mockGetHandler.On("Handle", getworkflowqry.NewQuery("website")).Return(suite.workflow, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/workflows/website", nil)
+	w := httptest.NewRecorder()
+	suite.routerAs("member").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"project":"website","states":["todo","shipped"],"initial":"todo","final":["shipped"],"transitions":[{"from":"todo","to":"shipped"}]}`, w.Body.String())
+}
+
+func (suite *WorkflowControllerTestSuite) TestDefine_Success() {
+	suite.mockDefineHandler.On("Handle", &defineworkflowcmd.Command{
+		Project:     "website",
+		States:      []string{"todo", "shipped"},
+		Final:       []string{"shipped"},
+		Transitions: []workflowmodel.Transition{{From: "todo", To: "shipped"}},
+	}).Return(suite.workflow, nil)
+
+	body := `{"states": ["todo", "shipped"], "final": ["shipped"], "transitions": [{"from": "todo", "to": "shipped"}]}`
+	req, _ := http.NewRequest(http.MethodPut, "/api/workflows/website", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("admin").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockDefineHandler.AssertExpectations(suite.T())
+}
+
+func (suite *WorkflowControllerTestSuite) TestDefine_Invalid() {
+	suite.mockDefineHandler.On("Handle", mock.Anything).Return((*workflowmodel.Workflow)(nil), errdmn.InvalidWorkflowTransition)
+
+	body := `{"states": ["todo"], "transitions": [{"from": "todo", "to": "shipped"}]}`
+	req, _ := http.NewRequest(http.MethodPut, "/api/workflows/website", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("admin").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *WorkflowControllerTestSuite) TestDefine_PermissionDenied() {
+	body := `{"states": ["todo"]}`
+	req, _ := http.NewRequest(http.MethodPut, "/api/workflows/website", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.routerAs("manager").ServeHTTP(w, req)
+
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.mockDefineHandler.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+func (suite *WorkflowControllerTestSuite) TestDelete() {
+	suite.mockDeleteHandler.On("Handle", deleteworkflowcmd.NewCommand("website")).Return(true, nil)
+	suite.mockDeleteHandler.On("Handle", deleteworkflowcmd.NewCommand("mobile")).Return(false, errdmn.WorkflowNotFound)
+	router := suite.routerAs("admin")
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/workflows/website", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNoContent, w.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/workflows/mobile", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func TestWorkflowControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowControllerTestSuite))
+}
@@ -0,0 +1,27 @@
+package dto
+
+import workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+
+// TransitionDTO is a move allowed from one state of a workflow to another.
+type TransitionDTO struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// DefineWorkflowRequest carries the states of a workflow and the transitions allowed
+// between them. Initial may be left out for the first state, and final for done.
+type DefineWorkflowRequest struct {
+	States      []string        `json:"states" binding:"required"`
+	Initial     string          `json:"initial"`
+	Final       []string        `json:"final"`
+	Transitions []TransitionDTO `json:"transitions" binding:"dive"`
+}
+
+// ToTransitions maps the transitions of the request to their domain representation.
+func (r *DefineWorkflowRequest) ToTransitions() []workflowmodel.Transition {
+	transitions := make([]workflowmodel.Transition, 0, len(r.Transitions))
+	for _, t := range r.Transitions {
+		transitions = append(transitions, workflowmodel.Transition{From: t.From, To: t.To})
+	}
+	return transitions
+}
@@ -0,0 +1,43 @@
+package dto
+
+import workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+
+// WorkflowResponse carries the states of a workflow and the transitions allowed between them.
+// Project is omitted for the default workflow.
+type WorkflowResponse struct {
+	Project     string          `json:"project,omitempty"`
+	States      []string        `json:"states"`
+	Initial     string          `json:"initial"`
+	Final       []string        `json:"final"`
+	Transitions []TransitionDTO `json:"transitions"`
+}
+
+// NewWorkflowResponse maps the given workflow to its response representation.
+func NewWorkflowResponse(workflow *workflowmodel.Workflow) WorkflowResponse {
+	transitions := make([]TransitionDTO, 0, len(workflow.Transitions()))
+	for _, t := range workflow.Transitions() {
+		transitions = append(transitions, TransitionDTO{From: t.From, To: t.To})
+	}
+	return WorkflowResponse{
+		Project:     workflow.Project(),
+		States:      workflow.States(),
+		Initial:     workflow.Initial(),
+		Final:       workflow.Final(),
+		Transitions: transitions,
+	}
+}
+
+// WorkflowListResponse holds the default workflow and the workflows defined for projects.
+type WorkflowListResponse struct {
+	Default WorkflowResponse   `json:"default"`
+	Items   []WorkflowResponse `json:"items"`
+}
+
+// NewWorkflowListResponse maps the given workflows to their response representation.
+func NewWorkflowListResponse(defaultWorkflow *workflowmodel.Workflow, workflows []*workflowmodel.Workflow) WorkflowListResponse {
+	items := make([]WorkflowResponse, 0, len(workflows))
+	for _, workflow := range workflows {
+		items = append(items, NewWorkflowResponse(workflow))
+	}
+	return WorkflowListResponse{Default: NewWorkflowResponse(defaultWorkflow), Items: items}
+}
@@ -4,12 +4,13 @@ import errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 
 // HTTP status codes used in the Error type.
 const (
-	BadRequest     = 400 // Bad Request
-	Conflict       = 409 // Conflict
-	ServerError    = 500 // Internal Server Error
-	Authentication = 401 // Unauthorized
-	Forbidden      = 403 // Forbidden
-	NotFound       = 404 // Not Found
+	BadRequest      = 400 // Bad Request
+	Conflict        = 409 // Conflict
+	ServerError     = 500 // Internal Server Error
+	Authentication  = 401 // Unauthorized
+	Forbidden       = 403 // Forbidden
+	NotFound        = 404 // Not Found
+	TooManyRequests = 429 // Too Many Requests
 )
 
 // Error represents an API error with a status code and message.
@@ -54,6 +55,12 @@ func NewForbidden(message string) Error {
 	return Error{statusCode: Forbidden, message: message}
 }
 
+// NewTooManyRequests creates a new Error with a 429 Too Many Requests status code
+// and the provided message.
+func NewTooManyRequests(message string) Error {
+	return Error{statusCode: TooManyRequests, message: message}
+}
+
 // Error returns the error message.
 func (e Error) Error() string {
 	return e.message
@@ -77,6 +84,10 @@ func FromErrDMN(e *errdmn.Error) Error {
 		return NewNotFound(e.Message)
 	case errdmn.Unauthorized:
 		return NewAuthentication(e.Message)
+	case errdmn.Forbidden:
+		return NewForbidden(e.Message)
+	case errdmn.TooManyRequests:
+		return NewTooManyRequests(e.Message)
 	default:
 		return NewServerError("unknown error")
 	}
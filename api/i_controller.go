@@ -1,21 +1,24 @@
-// Package api defines an interface for controllers to register routes
-// with different access levels.
+// Package api defines an interface for controllers to register routes,
+// each declaring the permissions it requires.
 package api
 
-import "github.com/gin-gonic/gin"
+import (
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/gin-gonic/gin"
+)
 
-// IController outlines methods for route registration:
-// - Public: No authentication required.
-// - Protected: Requires authentication.
-// - Privileged: Requires additional role-based authorization.
-type IController interface {
-	// RegisterPublic sets up public routes.
-	RegisterPublic(route *gin.RouterGroup)
-
-	// RegisterProtected sets up routes that require authentication.
-	RegisterProtected(route *gin.RouterGroup)
+// IAuthorizer builds the middleware controllers guard their routes with.
+type IAuthorizer interface {
+	// Authenticated requires an authenticated user.
+	Authenticated() gin.HandlerFunc
 
-	// RegisterPrivileged sets up routes with additional role-based authorization.
-	RegisterPrivileged(route *gin.RouterGroup)
+	// Require requires an authenticated user whose roles grant every given permission.
+	Require(permissions ...rolemodel.Permission) gin.HandlerFunc
 }
 
+// IController outlines route registration. Routes guarded by none of the
+// middleware of the authorizer are public.
+type IController interface {
+	// Register sets up the routes of the controller, guarding each as it requires.
+	Register(route *gin.RouterGroup, auth IAuthorizer)
+}
@@ -0,0 +1,28 @@
+package authmiddleware
+
+import (
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/gin-gonic/gin"
+)
+
+// Authorizer builds the Authoriz middleware guarding individual routes, all sharing
+// the same configuration.
+type Authorizer struct {
+	cfg Config
+}
+
+// NewAuthorizer creates a new Authorizer with the given configuration.
+func NewAuthorizer(cfg Config) *Authorizer {
+	return &Authorizer{cfg: cfg}
+}
+
+// Authenticated returns middleware letting through any authenticated user.
+func (a *Authorizer) Authenticated() gin.HandlerFunc {
+	return Authoriz(a.cfg)
+}
+
+// Require returns middleware letting through authenticated users whose roles grant
+// every given permission.
+func (a *Authorizer) Require(permissions ...rolemodel.Permission) gin.HandlerFunc {
+	return Authoriz(a.cfg, permissions...)
+}
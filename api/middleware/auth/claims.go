@@ -0,0 +1,26 @@
+package authmiddleware
+
+import (
+	"errors"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrClaimsNotFound is returned by Claims when the request did not go through Authoriz.
+var ErrClaimsNotFound = errors.New("claims not found")
+
+// Claims returns the claims of the authenticated user attached to the request
+// context by Authoriz.
+func Claims(c *gin.Context) (*ijwt.Claims, error) {
+	value, exists := c.Get(ContextUserClaims)
+	if !exists {
+		return nil, ErrClaimsNotFound
+	}
+
+	claims, ok := value.(*ijwt.Claims)
+	if !ok || claims == nil {
+		return nil, ErrClaimsNotFound
+	}
+	return claims, nil
+}
@@ -5,52 +5,84 @@ package authmiddleware
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	"github.com/gin-gonic/gin"
 )
 
 // Constants for context keys used in Gin middleware.
 const (
-	// ContextUserClaims is the key used to store user claims in the Gin context.
+	// ContextUserClaims is the key used to store the *ijwt.Claims of the user in the Gin context.
 	ContextUserClaims = "userClaims"
 )
 
+// Where the access token is looked for in a request.
+const (
+	authorizationHeader = "Authorization"
+	bearerScheme        = "Bearer"
+	accessTokenCookie   = "accessToken"
+	apiKeyHeader        = "X-API-Key"
+)
+
+// realm is the protection space advertised in WWW-Authenticate challenges.
+const realm = "task_manager"
+
+// errNoToken is returned when the request carries no access token.
+var errNoToken = errors.New("no access token")
+
+// errMalformedHeader is returned when the Authorization header is not a Bearer credential.
+var errMalformedHeader = errors.New("malformed authorization header")
+
+// Config holds the dependencies and settings of the Authoriz middleware.
+type Config struct {
+	JwtService  ijwt.Service              // Service decoding the access tokens.
+	Revocations irevocation.Store         // Store of revoked access tokens and sessions.
+	AllowCookie bool                      // Also accept the access token from the accessToken cookie.
+	APIKeys     apikeyauth.IAuthenticator // Authenticator of API keys; API keys are not accepted when nil.
+}
+
 // Authoriz returns a Gin middleware handler that performs authentication and
-// optional authorization based on the provided JWT service and admin status requirement.
+// optional authorization based on the provided configuration and required permissions.
+//
+// The JWT is taken from the "Authorization: Bearer" header. When the header is absent
+// and cookies are allowed, it is taken from the "accessToken" cookie instead; a header
+// that is present always wins, even if it is malformed. The token is decoded, rejected
+// if it or its session has been revoked, and the roles of the user are checked to grant
+// every required permission. If the user is authenticated and meets the authorization criteria, their claims
+// are attached to the request context; otherwise, an appropriate HTTP status code is
+// returned and the request is aborted. Every 401 response carries a WWW-Authenticate
+// challenge as described in RFC 6750.
 //
-// It extracts the JWT from the "accessToken" cookie, decodes it, and checks if the user
-// has the required admin status. If the user is authenticated and meets the authorization
-// criteria, their claims are attached to the request context; otherwise, an appropriate
-// HTTP status code is returned and the request is aborted.
-func Authoriz(jwtService ijwt.Service, hasToBeAdmin bool) gin.HandlerFunc {
+// When API keys are accepted, a request carrying an "X-API-Key" header is authenticated
+// with the key instead, whatever other credentials it carries. Each required permission
+// then has to be one of the scopes of the key as well. Routes requiring no permission
+// manage the account of the user and refuse API keys with 403 Forbidden.
+func Authoriz(cfg Config, permissions ...rolemodel.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Retrieve the access token from the cookie.
-		cookie, err := c.Cookie("accessToken")
-		if err != nil {
-			if errors.Is(err, http.ErrNoCookie) {
-				c.Status(http.StatusUnauthorized) // No cookie found.
-			} else {
-				c.Status(http.StatusInternalServerError) // Internal server error.
-			}
-			c.Abort()
-			return
+		var claims *ijwt.Claims
+		var ok bool
+		if key := c.GetHeader(apiKeyHeader); key != "" && cfg.APIKeys != nil {
+			claims, ok = apiKeyClaims(c, cfg, key, permissions)
+		} else {
+			claims, ok = tokenClaims(c, cfg)
 		}
-
-		// Decode the token using the JWT service.
-		claims, err := jwtService.Decode(cookie)
-		if err != nil {
-			c.Status(http.StatusUnauthorized) // Invalid token.
+		if !ok {
 			c.Abort()
 			return
 		}
 
-		// Check if the user meets the required admin status.
-		isAdmin, ok := claims["is_admin"].(bool)
-		if !ok || (!isAdmin && hasToBeAdmin) {
-			c.Status(http.StatusForbidden) // Forbidden if admin status does not match.
-			c.Abort()
-			return
+		// Check if the roles of the user, and the scopes of their API key, grant every required permission.
+		for _, permission := range permissions {
+			if !claims.Can(permission) {
+				c.Status(http.StatusForbidden) // Forbidden if a permission is missing.
+				c.Abort()
+				return
+			}
 		}
 
 		// Attach user claims to the request context for further use.
@@ -58,3 +90,99 @@ func Authoriz(jwtService ijwt.Service, hasToBeAdmin bool) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// tokenClaims authenticates the request with its access token. When it fails, the
+// response is written and false is returned.
+func tokenClaims(c *gin.Context, cfg Config) (*ijwt.Claims, bool) {
+	// Retrieve the access token from the header or the cookie.
+	token, err := accessToken(c, cfg.AllowCookie)
+	if err != nil {
+		switch {
+		case errors.Is(err, errNoToken):
+			challenge(c, "") // No credentials: bare challenge.
+		case errors.Is(err, errMalformedHeader):
+			challenge(c, "invalid_request")
+		default:
+			c.Status(http.StatusInternalServerError) // Internal server error.
+		}
+		return nil, false
+	}
+
+	// Decode the token using the JWT service.
+	claims, err := cfg.JwtService.Decode(token)
+	if err != nil {
+		challenge(c, "invalid_token") // Invalid token.
+		return nil, false
+	}
+
+	// Reject tokens revoked on logout, either by themselves or with their session.
+	revoked, err := cfg.Revocations.IsRevoked(claims.TokenID, claims.SessionID.String())
+	if err != nil {
+		c.Status(http.StatusInternalServerError) // Internal server error.
+		return nil, false
+	}
+	if revoked {
+		challenge(c, "invalid_token") // Revoked token.
+		return nil, false
+	}
+	return claims, true
+}
+
+// apiKeyClaims authenticates the request with the given API key. When it fails, the
+// response is written and false is returned.
+func apiKeyClaims(c *gin.Context, cfg Config, key string, permissions []rolemodel.Permission) (*ijwt.Claims, bool) {
+	claims, err := cfg.APIKeys.Authenticate(key)
+	if err != nil {
+		var dmnErr *errdmn.Error
+		if errors.As(err, &dmnErr) && dmnErr.Type() == errdmn.Unexpected {
+			c.Status(http.StatusInternalServerError) // Internal server error.
+		} else {
+			challenge(c, "invalid_token") // Unknown, expired, or deactivated key.
+		}
+		return nil, false
+	}
+
+	// API keys only reach the routes their scopes can grant access to.
+	if len(permissions) == 0 {
+		c.Status(http.StatusForbidden)
+		return nil, false
+	}
+	return claims, true
+}
+
+// accessToken returns the access token of the request, preferring the Authorization
+// header over the cookie.
+func accessToken(c *gin.Context, allowCookie bool) (string, error) {
+	if header := c.GetHeader(authorizationHeader); header != "" {
+		scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+		token = strings.TrimSpace(token)
+		if !found || !strings.EqualFold(scheme, bearerScheme) || token == "" {
+			return "", errMalformedHeader
+		}
+		return token, nil
+	}
+
+	if !allowCookie {
+		return "", errNoToken
+	}
+
+	cookie, err := c.Cookie(accessTokenCookie)
+	if err != nil {
+		if errors.Is(err, http.ErrNoCookie) {
+			return "", errNoToken
+		}
+		return "", err
+	}
+	return cookie, nil
+}
+
+// challenge responds with 401 Unauthorized and a Bearer WWW-Authenticate challenge.
+// The error code is omitted when the request carried no credentials at all.
+func challenge(c *gin.Context, errorCode string) {
+	value := bearerScheme + ` realm="` + realm + `"`
+	if errorCode != "" {
+		value += `, error="` + errorCode + `"`
+	}
+	c.Header("WWW-Authenticate", value)
+	c.Status(http.StatusUnauthorized)
+}
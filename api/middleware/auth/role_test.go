@@ -6,25 +6,43 @@ import (
 	"testing"
 
 	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
-	"github.com/dgrijalva/jwt-go"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	apikeyauth_mock "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// sessionID is the session of the tokens used in the tests.
+var sessionID = uuid.New()
+
 // AuthMiddlewareTestSuite defines the test suite for the Authoriz middleware.
 type AuthMiddlewareTestSuite struct {
-	mockJwtSvc *ijwt_mock.MockService
-	router     *gin.Engine
+	mockJwtSvc      *ijwt_mock.MockService
+	mockRevocations *irevocation_mock.Store
+	mockAPIKeys     *apikeyauth_mock.IAuthenticator
+	router          *gin.Engine
 }
 
 // SetupTest sets up the test environment.
-func (suite *AuthMiddlewareTestSuite) SetupTest(hasToBeAdmin bool) {
+func (suite *AuthMiddlewareTestSuite) SetupTest(allowCookie bool, permissions ...rolemodel.Permission) {
 	suite.mockJwtSvc = new(ijwt_mock.MockService)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.mockAPIKeys = new(apikeyauth_mock.IAuthenticator)
 
 	// Set up the Gin router with the middleware.
 	suite.router = gin.Default()
-	suite.router.Use(authmiddleware.Authoriz(suite.mockJwtSvc, hasToBeAdmin))
+	suite.router.Use(authmiddleware.Authoriz(authmiddleware.Config{
+		JwtService:  suite.mockJwtSvc,
+		Revocations: suite.mockRevocations,
+		AllowCookie: allowCookie,
+		APIKeys:     suite.mockAPIKeys,
+	}, permissions...))
 
 	// Example endpoint to test the middleware
 	suite.router.GET("/test", func(c *gin.Context) {
@@ -36,11 +54,12 @@ func (suite *AuthMiddlewareTestSuite) SetupTest(hasToBeAdmin bool) {
 // TestValidToken tests the scenario where a valid token is provided.
 func TestValidToken(t *testing.T) {
 	suite := new(AuthMiddlewareTestSuite)
-	suite.SetupTest(false)
+	suite.SetupTest(true)
 
 	// Mock the JWT service to return valid claims.
-	claims := jwt.MapClaims{"username": "testuser", "is_admin": false}
+	claims := &ijwt.Claims{Subject: uuid.New(), TokenID: "token-id", SessionID: sessionID}
 	suite.mockJwtSvc.On("Decode", "valid_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(false, nil)
 
 	// Create a new request with a valid token cookie.
 	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
@@ -52,21 +71,39 @@ func TestValidToken(t *testing.T) {
 
 	// Assert that the response status is OK (200) and claims are returned.
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), `"username":"testuser"`)
-	assert.Contains(t, w.Body.String(), `"is_admin":false`)
+	assert.Contains(t, w.Body.String(), claims.Subject.String())
 
 	// Verify the mock expectations.
 	suite.mockJwtSvc.AssertExpectations(t)
 }
 
-// TestAdminRequired_Failure tests the scenario where admin access is required but the user is not an admin.
-func TestAdminRequired_Failure(t *testing.T) {
+// TestPermissionRequired_Success tests the scenario where the roles of the user grant the required permissions.
+func TestPermissionRequired_Success(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true, rolemodel.UserPromote, rolemodel.TaskDeleteAny)
+
+	// Mock the JWT service to return the claims of an admin.
+	claims := &ijwt.Claims{Subject: uuid.New(), Roles: []string{"admin"}, TokenID: "token-id", SessionID: sessionID}
+	suite.mockJwtSvc.On("Decode", "valid_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(false, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "accessToken", Value: "valid_token"})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestPermissionRequired_Failure tests the scenario where a permission is required but not granted by the roles of the user.
+func TestPermissionRequired_Failure(t *testing.T) {
 	suite := new(AuthMiddlewareTestSuite)
-	suite.SetupTest(true) // Admin access is required
+	suite.SetupTest(true, rolemodel.TaskCreate, rolemodel.UserPromote) // Only the first is granted to members
 
-	// Mock the JWT service to return valid claims but the user is not an admin.
-	claims := jwt.MapClaims{"username": "testuser", "is_admin": false}
+	// Mock the JWT service to return valid claims of a member.
+	claims := &ijwt.Claims{Subject: uuid.New(), Roles: []string{"member"}, TokenID: "token-id", SessionID: sessionID}
 	suite.mockJwtSvc.On("Decode", "valid_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(false, nil)
 
 	// Create a new request with a valid token cookie.
 	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
@@ -82,3 +119,241 @@ func TestAdminRequired_Failure(t *testing.T) {
 	// Verify the mock expectations.
 	suite.mockJwtSvc.AssertExpectations(t)
 }
+
+// TestRevokedToken tests the scenario where the token or its session has been revoked.
+func TestRevokedToken(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true)
+
+	// Mock the JWT service to return valid claims of a revoked token.
+	claims := &ijwt.Claims{Subject: uuid.New(), TokenID: "token-id", SessionID: sessionID}
+	suite.mockJwtSvc.On("Decode", "valid_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(true, nil)
+
+	// Create a new request with a valid token cookie.
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "accessToken", Value: "valid_token"})
+	w := httptest.NewRecorder()
+
+	// Serve the request.
+	suite.router.ServeHTTP(w, req)
+
+	// Assert that the response status is Unauthorized (401) with an invalid_token challenge.
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="task_manager", error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+
+	// Verify the mock expectations.
+	suite.mockJwtSvc.AssertExpectations(t)
+	suite.mockRevocations.AssertExpectations(t)
+}
+
+// TestBearerToken tests the scenario where the token is sent in the Authorization header.
+func TestBearerToken(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(false) // Cookies are not accepted
+
+	// Mock the JWT service to return valid claims.
+	claims := &ijwt.Claims{Subject: uuid.New(), TokenID: "token-id", SessionID: sessionID}
+	suite.mockJwtSvc.On("Decode", "valid_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(false, nil)
+
+	// Create a new request with a bearer token.
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer valid_token")
+	w := httptest.NewRecorder()
+
+	// Serve the request.
+	suite.router.ServeHTTP(w, req)
+
+	// Assert that the response status is OK (200).
+	assert.Equal(t, http.StatusOK, w.Code)
+	suite.mockJwtSvc.AssertExpectations(t)
+}
+
+// TestBearerToken_TakesPrecedence tests that the Authorization header wins over the cookie.
+func TestBearerToken_TakesPrecedence(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true)
+
+	// Mock the JWT service to accept only the header token.
+	claims := &ijwt.Claims{Subject: uuid.New(), TokenID: "token-id", SessionID: sessionID}
+	suite.mockJwtSvc.On("Decode", "header_token").Return(claims, nil)
+	suite.mockRevocations.On("IsRevoked", []string{"token-id", sessionID.String()}).Return(false, nil)
+
+	// Create a new request carrying both a bearer token and a cookie.
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "bearer header_token")
+	req.AddCookie(&http.Cookie{Name: "accessToken", Value: "cookie_token"})
+	w := httptest.NewRecorder()
+
+	// Serve the request.
+	suite.router.ServeHTTP(w, req)
+
+	// Assert that the header token was used.
+	assert.Equal(t, http.StatusOK, w.Code)
+	suite.mockJwtSvc.AssertExpectations(t)
+	suite.mockJwtSvc.AssertNotCalled(t, "Decode", "cookie_token")
+}
+
+// TestMalformedHeader tests that a malformed Authorization header is rejected without falling back to the cookie.
+func TestMalformedHeader(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true)
+
+	for _, header := range []string{"Basic dXNlcjpwYXNz", "Bearer", "Bearer   "} {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", header)
+		req.AddCookie(&http.Cookie{Name: "accessToken", Value: "valid_token"})
+		w := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, header)
+		assert.Equal(t, `Bearer realm="task_manager", error="invalid_request"`, w.Header().Get("WWW-Authenticate"), header)
+	}
+	suite.mockJwtSvc.AssertNotCalled(t, "Decode", mock.Anything)
+}
+
+// TestMissingToken tests the challenge sent when no token is provided.
+func TestMissingToken(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="task_manager"`, w.Header().Get("WWW-Authenticate"))
+}
+
+// TestCookieDisabled tests that the cookie is ignored when cookies are not allowed.
+func TestCookieDisabled(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(false)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "accessToken", Value: "valid_token"})
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer realm="task_manager"`, w.Header().Get("WWW-Authenticate"))
+	suite.mockJwtSvc.AssertNotCalled(t, "Decode", mock.Anything)
+}
+
+// apiKeyClaims returns the claims of a member authenticated with an API key limited to reading tasks.
+func apiKeyClaims() *ijwt.Claims {
+	return &ijwt.Claims{
+		Subject:  uuid.New(),
+		Roles:    []string{"member"},
+		APIKeyID: uuid.New(),
+		Scopes:   []rolemodel.Permission{rolemodel.TaskRead},
+	}
+}
+
+// TestAPIKey tests that a request carrying an API key is authenticated with it, even with a token.
+func TestAPIKey(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true, rolemodel.TaskRead)
+
+	claims := apiKeyClaims()
+	suite.mockAPIKeys.On("Authenticate", "tm_key").Return(claims, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-API-Key", "tm_key")
+	req.Header.Set("Authorization", "Bearer valid_token")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), claims.APIKeyID.String())
+	suite.mockAPIKeys.AssertExpectations(t)
+	suite.mockJwtSvc.AssertNotCalled(t, "Decode", mock.Anything)
+}
+
+// TestAPIKey_OutOfScope tests that a permission granted by the role but not in the scopes of the key is refused.
+func TestAPIKey_OutOfScope(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true, rolemodel.TaskCreate)
+
+	suite.mockAPIKeys.On("Authenticate", "tm_key").Return(apiKeyClaims(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-API-Key", "tm_key")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// TestAPIKey_NoPermissionRequired tests that API keys cannot reach routes requiring no permission.
+func TestAPIKey_NoPermissionRequired(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true)
+
+	suite.mockAPIKeys.On("Authenticate", "tm_key").Return(apiKeyClaims(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-API-Key", "tm_key")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// TestAPIKey_Invalid tests the challenge sent for unknown, expired, or deactivated keys.
+func TestAPIKey_Invalid(t *testing.T) {
+	for _, err := range []error{errdmn.InvalidAPIKey, errdmn.APIKeyExpired, errdmn.UserDeactivated} {
+		suite := new(AuthMiddlewareTestSuite)
+		suite.SetupTest(true, rolemodel.TaskRead)
+		suite.mockAPIKeys.On("Authenticate", "tm_key").Return(nil, err)
+
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("X-API-Key", "tm_key")
+		w := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, err)
+		assert.Equal(t, `Bearer realm="task_manager", error="invalid_token"`, w.Header().Get("WWW-Authenticate"), err)
+	}
+}
+
+// TestAPIKey_Unexpected tests that a failure to verify the key is an internal server error.
+func TestAPIKey_Unexpected(t *testing.T) {
+	suite := new(AuthMiddlewareTestSuite)
+	suite.SetupTest(true, rolemodel.TaskRead)
+	suite.mockAPIKeys.On("Authenticate", "tm_key").Return(nil, errdmn.NewUnexpected("store down"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-API-Key", "tm_key")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+// TestClaims tests the request-context helper used by controllers.
+func TestClaims(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	_, err := authmiddleware.Claims(c)
+	assert.ErrorIs(t, err, authmiddleware.ErrClaimsNotFound)
+
+	c.Set(authmiddleware.ContextUserClaims, "not claims")
+	_, err = authmiddleware.Claims(c)
+	assert.ErrorIs(t, err, authmiddleware.ErrClaimsNotFound)
+
+	claims := &ijwt.Claims{Subject: uuid.New()}
+	c.Set(authmiddleware.ContextUserClaims, claims)
+	got, err := authmiddleware.Claims(c)
+	assert.NoError(t, err)
+	assert.Same(t, claims, got)
+}
@@ -0,0 +1,39 @@
+package api_mock
+
+import (
+	"net/http"
+
+	"github.com/beka-birhanu/task_manager_final/api"
+	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/gin-gonic/gin"
+)
+
+// Authorizer is a fake implementation of the IAuthorizer interface checking the claims
+// tests attach to the request context, instead of decoding access tokens. Requests
+// without claims are let through for the handlers to refuse.
+type Authorizer struct{}
+
+// Ensure Authorizer implements api.IAuthorizer
+var _ api.IAuthorizer = Authorizer{}
+
+// Authenticated lets every request through.
+func (Authorizer) Authenticated() gin.HandlerFunc {
+	return func(ctx *gin.Context) {}
+}
+
+// Require refuses requests whose claims do not grant every given permission.
+func (Authorizer) Require(permissions ...rolemodel.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, err := authmiddleware.Claims(ctx)
+		if err != nil {
+			return
+		}
+		for _, permission := range permissions {
+			if !claims.Can(permission) {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+	}
+}
@@ -1,10 +1,15 @@
 // Package router provides functionality to set up and run the HTTP server,
-// manage routes, and apply middleware based on access levels.
+// and manage routes.
 //
-// It configures and initializes routes with varying access requirements:
-// - Public routes: Accessible without authentication.
-// - Protected routes: Require authentication.
-// - Privileged routes: Require both authentication and admin privileges.
+// Controllers declare the access requirements of each of their routes with the
+// middleware of an authorizer: none for public routes, authentication, or
+// the permissions the roles of the user have to grant.
+//
+// Root controllers register their routes at the server root instead, for
+// well-known URLs such as /.well-known/jwks.json.
+//
+// The client address seen by the handlers is only taken from the X-Forwarded-For
+// header of requests sent by one of the trusted proxies.
 package router
 
 import (
@@ -13,6 +18,8 @@ import (
 	"github.com/beka-birhanu/task_manager_final/api"
 	authmiddleware "github.com/beka-birhanu/task_manager_final/api/middleware/auth"
 	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,65 +29,72 @@ type Router struct {
 	addr        string
 	baseURL     string
 	controllers []api.IController
+	root        []api.IController
 	jwtService  ijwt.Service
+	revocations irevocation.Store
+	allowCookie bool
+	apiKeys     apikeyauth.IAuthenticator
+	proxies     []string
 }
 
 // Config holds configuration settings for creating a new Router instance.
 type Config struct {
-	Addr        string            // Address to listen on
-	BaseURL     string            // Base URL for API routes
-	Controllers []api.IController // List of controllers
-	JwtService  ijwt.Service      // JWT service
+	Addr        string                    // Address to listen on
+	BaseURL     string                    // Base URL for API routes
+	Controllers []api.IController         // List of controllers
+	Root        []api.IController         // Controllers whose routes are served from the server root
+	JwtService  ijwt.Service              // JWT service
+	Revocations irevocation.Store         // Store of revoked access tokens and sessions
+	AllowCookie bool                      // Accept the access token from the accessToken cookie as well as the Authorization header
+	APIKeys     apikeyauth.IAuthenticator // Authenticator of the API keys sent in the X-API-Key header; none accepted when nil
+	Proxies     []string                  // Addresses or CIDR ranges of the trusted reverse proxies; none when empty
 }
 
 // NewRouter creates a new Router instance with the given configuration.
-// It initializes the router with address, base URL, controllers, and JWT service.
+// It initializes the router with address, base URL, controllers, JWT service, revocation store, and API key authenticator.
 func NewRouter(config Config) *Router {
 	return &Router{
 		addr:        config.Addr,
 		baseURL:     config.BaseURL,
 		controllers: config.Controllers,
+		root:        config.Root,
 		jwtService:  config.JwtService,
+		revocations: config.Revocations,
+		allowCookie: config.AllowCookie,
+		apiKeys:     config.APIKeys,
+		proxies:     config.Proxies,
 	}
 }
 
-// Run starts the HTTP server and sets up routes with different access levels.
-//
-// Routes are grouped and managed under the base URL, with the following access levels:
-// - Public routes: No authentication required.
-// - Protected routes: Authentication required.
-// - Privileged routes: Authentication and admin privileges required.
+// authorizer returns the authorizer controllers guard their routes with.
+func (r *Router) authorizer() *authmiddleware.Authorizer {
+	return authmiddleware.NewAuthorizer(authmiddleware.Config{
+		JwtService:  r.jwtService,
+		Revocations: r.revocations,
+		AllowCookie: r.allowCookie,
+		APIKeys:     r.apiKeys,
+	})
+}
+
+// Run starts the HTTP server and sets up the routes of the controllers under the
+// base URL, each controller guarding its routes as they require.
 func (r *Router) Run() error {
 	router := gin.Default()
+	if err := router.SetTrustedProxies(r.proxies); err != nil {
+		return err
+	}
 
-	// Setting up routes under baseURL
-	api := router.Group(r.baseURL)
-	{
-		// Public routes (accessible without authentication)
-		publicRoutes := api.Group("/v1")
-		{
-			for _, c := range r.controllers {
-				c.RegisterPublic(publicRoutes)
-			}
-		}
+	auth := r.authorizer()
 
-		// Protected routes (authentication required)
-		protectedRoutes := api.Group("/v1")
-		protectedRoutes.Use(authmiddleware.Authoriz(r.jwtService, false))
-		{
-			for _, c := range r.controllers {
-				c.RegisterProtected(protectedRoutes)
-			}
-		}
+	// Routes at the server root
+	for _, c := range r.root {
+		c.Register(&router.RouterGroup, auth)
+	}
 
-		// Privileged routes (authentication and admin privileges required)
-		privilegedRoutes := api.Group("/v1")
-		privilegedRoutes.Use(authmiddleware.Authoriz(r.jwtService, true))
-		{
-			for _, c := range r.controllers {
-				c.RegisterPrivileged(privilegedRoutes)
-			}
-		}
+	// Setting up routes under baseURL
+	routes := router.Group(r.baseURL).Group("/v1")
+	for _, c := range r.controllers {
+		c.Register(routes, auth)
 	}
 
 	log.Println("Listening on", r.addr)
@@ -0,0 +1,18 @@
+// Package applog records what happens in the application layer that is worth keeping
+// track of but is not part of any response: actions taken on user accounts, and
+// failures that do not fail the request they happen in.
+//
+// TODO: Implement a proper logging mechanism; records go to the standard logger for now.
+package applog
+
+import "log"
+
+// Warn records a failure that the request it happened in recovered from.
+func Warn(format string, args ...interface{}) {
+	log.Printf("warning: "+format, args...)
+}
+
+// Audit records an action taken on a user account, such as an admin deactivating it.
+func Audit(format string, args ...interface{}) {
+	log.Printf("audit: "+format, args...)
+}
@@ -0,0 +1,16 @@
+package ijwt
+
+import (
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// Challenges issues the short-lived tokens that carry a sign in from the password
+// check to the second factor check. They cannot be used as access tokens.
+type Challenges interface {
+	// GenerateChallenge creates an MFA challenge token for a user whose password was checked.
+	GenerateChallenge(user *usermodel.User) (string, error)
+
+	// DecodeChallenge validates an MFA challenge token and returns the ID of its user.
+	DecodeChallenge(token string) (uuid.UUID, error)
+}
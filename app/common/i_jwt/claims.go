@@ -0,0 +1,69 @@
+package ijwt
+
+import (
+	"time"
+
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/google/uuid"
+)
+
+// Claims are the validated claims of an access token. Requests authenticated with an
+// API key are given the same claims, limited to the scopes of the key.
+type Claims struct {
+	Subject   uuid.UUID // ID of the user the token was issued to (sub).
+	Roles     []string  // Roles of the user when the token was issued (roles).
+	SessionID uuid.UUID // Session the token belongs to (sid).
+	TokenID   string    // Unique ID of the token (jti).
+	Issuer    string    // Issuer of the token (iss).
+	Audience  []string  // Intended recipients of the token (aud).
+	IssuedAt  time.Time // Issue time (iat).
+	ExpiresAt time.Time // Expiry time (exp).
+
+	APIKeyID uuid.UUID              // API key the request was authenticated with; uuid.Nil for access tokens.
+	Scopes   []rolemodel.Permission // Permissions the API key is limited to.
+}
+
+// HasRole reports whether the claims grant the given role.
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether any of the roles of the claims grants the permission and,
+// for API keys, whether it is one of the scopes of the key.
+// Roles unknown to this server grant nothing.
+func (c *Claims) Can(permission rolemodel.Permission) bool {
+	if !c.InScope(permission) {
+		return false
+	}
+	for _, r := range c.Roles {
+		if rolemodel.Role(r).Can(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAPIKey reports whether the request was authenticated with an API key.
+func (c *Claims) IsAPIKey() bool {
+	return c.APIKeyID != uuid.Nil
+}
+
+// InScope reports whether the permission may be used with the claims: always for
+// access tokens, and only when it is one of the scopes of the key for API keys.
+// The roles of the claims still have to grant it.
+func (c *Claims) InScope(permission rolemodel.Permission) bool {
+	if !c.IsAPIKey() {
+		return true
+	}
+	for _, scope := range c.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
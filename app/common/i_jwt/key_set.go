@@ -0,0 +1,21 @@
+package ijwt
+
+// JWK is a public key in JSON Web Key form (RFC 7517). Binary members are base64url encoded.
+type JWK struct {
+	Kty string // Key type: "RSA" or "OKP".
+	Kid string // Key ID, matching the kid header of the tokens signed with the key.
+	Use string // Public key use, always "sig".
+	Alg string // JWS algorithm the key is used with.
+	N   string // RSA modulus.
+	E   string // RSA public exponent.
+	Crv string // OKP curve, "Ed25519".
+	X   string // OKP public key.
+}
+
+// KeySet exposes the public keys tokens can be verified with, so other services
+// can verify them without holding the signing keys.
+type KeySet interface {
+	// PublicKeys returns the keys that currently sign tokens, the keys scheduled to,
+	// and the retired keys whose tokens may still be valid.
+	PublicKeys() []JWK
+}
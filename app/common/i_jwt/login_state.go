@@ -0,0 +1,19 @@
+package ijwt
+
+// LoginState is what a single sign-on remembers from sending the user to the identity
+// provider until they come back with an authorization code.
+type LoginState struct {
+	State        string // Value the provider sends back, tying the callback to the browser that started the sign in.
+	Nonce        string // Value the ID token must carry, tying it to the sign in.
+	CodeVerifier string // PKCE secret proving the code is redeemed by whoever asked for it.
+}
+
+// LoginStates issues the short-lived tokens carrying a LoginState through the browser
+// of the user. They cannot be used as access tokens.
+type LoginStates interface {
+	// GenerateLoginState creates a token carrying the state of a single sign-on.
+	GenerateLoginState(state LoginState) (string, error)
+
+	// DecodeLoginState validates a login state token and returns the state it carries.
+	DecodeLoginState(token string) (*LoginState, error)
+}
@@ -0,0 +1,24 @@
+package ijwt_mock
+
+import (
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockChallenges is a mock implementation of the Challenges interface using testify.
+type MockChallenges struct {
+	mock.Mock
+}
+
+// GenerateChallenge mocks the GenerateChallenge method of the Challenges interface.
+func (m *MockChallenges) GenerateChallenge(user *usermodel.User) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
+// DecodeChallenge mocks the DecodeChallenge method of the Challenges interface.
+func (m *MockChallenges) DecodeChallenge(token string) (uuid.UUID, error) {
+	args := m.Called(token)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,17 @@
+package ijwt_mock
+
+import (
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockKeySet is a mock implementation of the KeySet interface using testify.
+type MockKeySet struct {
+	mock.Mock
+}
+
+// PublicKeys mocks the PublicKeys method of the KeySet interface.
+func (m *MockKeySet) PublicKeys() []ijwt.JWK {
+	args := m.Called()
+	return args.Get(0).([]ijwt.JWK)
+}
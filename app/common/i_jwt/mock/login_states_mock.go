@@ -0,0 +1,26 @@
+package ijwt_mock
+
+import (
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoginStates is a mock implementation of the LoginStates interface using testify.
+type MockLoginStates struct {
+	mock.Mock
+}
+
+// GenerateLoginState mocks the GenerateLoginState method of the LoginStates interface.
+func (m *MockLoginStates) GenerateLoginState(state ijwt.LoginState) (string, error) {
+	args := m.Called(state)
+	return args.String(0), args.Error(1)
+}
+
+// DecodeLoginState mocks the DecodeLoginState method of the LoginStates interface.
+func (m *MockLoginStates) DecodeLoginState(token string) (*ijwt.LoginState, error) {
+	args := m.Called(token)
+	if state, ok := args.Get(0).(*ijwt.LoginState); ok {
+		return state, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
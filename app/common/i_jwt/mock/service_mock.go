@@ -1,8 +1,9 @@
 package ijwt_mock
 
 import (
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -12,16 +13,16 @@ type MockService struct {
 }
 
 // Generate mocks the Generate method of the Service interface.
-func (m *MockService) Generate(user *usermodel.User) (string, error) {
-	args := m.Called(user)
+func (m *MockService) Generate(user *usermodel.User, sessionID uuid.UUID) (string, error) {
+	args := m.Called(user, sessionID)
 	return args.String(0), args.Error(1)
 }
 
 // Decode mocks the Decode method of the Service interface.
-func (m *MockService) Decode(token string) (jwt.MapClaims, error) {
+func (m *MockService) Decode(token string) (*ijwt.Claims, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(jwt.MapClaims), args.Error(1)
+	return args.Get(0).(*ijwt.Claims), args.Error(1)
 }
@@ -3,14 +3,15 @@ package ijwt
 
 import (
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
 // Service defines methods to generate and decode JWTs.
 type Service interface {
-	// Generate creates a JWT for a user.
-	Generate(user *usermodel.User) (string, error)
+	// Generate creates a JWT for a user within the given session.
+	// Every token gets a unique ID so it can be revoked on its own.
+	Generate(user *usermodel.User, sessionID uuid.UUID) (string, error)
 
-	// Decode parses a JWT and returns claims.
-	Decode(token string) (jwt.MapClaims, error)
+	// Decode parses and validates a JWT, including its issuer and audience, and returns its claims.
+	Decode(token string) (*Claims, error)
 }
@@ -0,0 +1,17 @@
+package inotifier_mock
+
+import (
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	"github.com/stretchr/testify/mock"
+)
+
+// Notifier is a mock implementation of the Notifier interface using testify.
+type Notifier struct {
+	mock.Mock
+}
+
+// Notify mocks the Notify method of the Notifier interface.
+func (m *Notifier) Notify(msg *inotifier.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
@@ -0,0 +1,17 @@
+// Package inotifier provides the interface used to send messages to users out of band,
+// such as password reset links.
+package inotifier
+
+// Message is a plain text message addressed to a user.
+type Message struct {
+	Username string // User the message is for.
+	Email    string // Address of the user, if they have one.
+	Subject  string // Short summary of the message.
+	Body     string // Plain text content of the message.
+}
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	// Notify delivers the message to its user.
+	Notify(msg *Message) error
+}
@@ -0,0 +1,26 @@
+package ioidc_mock
+
+import (
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	"github.com/stretchr/testify/mock"
+)
+
+// Provider is a mock implementation of the Provider interface using testify.
+type Provider struct {
+	mock.Mock
+}
+
+// AuthCodeURL mocks the AuthCodeURL method of the Provider interface.
+func (m *Provider) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	args := m.Called(state, nonce, codeChallenge)
+	return args.String(0), args.Error(1)
+}
+
+// Exchange mocks the Exchange method of the Provider interface.
+func (m *Provider) Exchange(code, codeVerifier, nonce string) (*ioidc.Identity, error) {
+	args := m.Called(code, codeVerifier, nonce)
+	if identity, ok := args.Get(0).(*ioidc.Identity); ok {
+		return identity, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
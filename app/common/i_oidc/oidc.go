@@ -0,0 +1,26 @@
+// Package ioidc provides the OpenID Connect identity provider users sign in with
+// through single sign-on.
+package ioidc
+
+// Identity is the identity of a user signed in at the identity provider, taken from
+// the claims of their verified ID token.
+type Identity struct {
+	Issuer            string // Issuer of the ID token (iss).
+	Subject           string // ID of the user at the provider, unique within the issuer (sub).
+	Email             string // Email address of the user, if shared (email).
+	EmailVerified     bool   // Whether the provider verified the email address (email_verified).
+	PreferredUsername string // Name the user likes to be called by (preferred_username).
+	Name              string // Full name of the user (name).
+}
+
+// Provider runs the authorization code flow with PKCE against an identity provider.
+type Provider interface {
+	// AuthCodeURL returns the URL of the authorization endpoint the user signs in at.
+	// The provider sends the state back with the code, puts the nonce in the ID token,
+	// and only redeems the code with the verifier of the S256 code challenge.
+	AuthCodeURL(state, nonce, codeChallenge string) (string, error)
+
+	// Exchange redeems the authorization code with the PKCE code verifier, verifies the
+	// returned ID token, which must carry the nonce, and returns the identity it holds.
+	Exchange(code, codeVerifier, nonce string) (*Identity, error)
+}
@@ -0,0 +1,31 @@
+// Package irepo provides interfaces for API key repository operations.
+package irepo
+
+import (
+	"time"
+
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	"github.com/google/uuid"
+)
+
+// APIKey defines methods to manage API keys in the store.
+type APIKey interface {
+	// Save adds a new API key.
+	Save(key *apikeymodel.APIKey) error
+
+	// ByID returns an API key by ID.
+	ByID(id uuid.UUID) (*apikeymodel.APIKey, error)
+
+	// ByUser returns the API keys of the given user, newest first.
+	ByUser(userID uuid.UUID) ([]*apikeymodel.APIKey, error)
+
+	// MarkUsed records the time the key was last used.
+	MarkUsed(id uuid.UUID, at time.Time) error
+
+	// Delete removes the API key with the given ID owned by the given user.
+	// It fails with errdmn.APIKeyNotFound if the user has no such key.
+	Delete(id, userID uuid.UUID) error
+
+	// DeleteByUser removes every API key of the given user.
+	DeleteByUser(userID uuid.UUID) error
+}
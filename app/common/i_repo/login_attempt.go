@@ -0,0 +1,27 @@
+// Package irepo provides interfaces for failed login attempt repository operations.
+package irepo
+
+import (
+	"time"
+
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+)
+
+// LoginAttempt defines methods to manage the failed login attempts recorded under a key.
+type LoginAttempt interface {
+	// ByKey returns the failed attempts recorded under the key.
+	// It fails with errdmn.LoginAttemptNotFound if there are none.
+	ByKey(key string) (*loginattemptmodel.LoginAttempt, error)
+
+	// AddFailure atomically counts a failed attempt made at the given time, first
+	// forgetting the failures of an expired record, and remembers the failures for at
+	// least the given window. It returns the updated record.
+	AddFailure(key string, now time.Time, window time.Duration) (*loginattemptmodel.LoginAttempt, error)
+
+	// Lock refuses signing in for the key until the given time.
+	// A later lock already in place is kept.
+	Lock(key string, until time.Time) error
+
+	// Delete removes the failures and lock recorded under the key.
+	Delete(key string) error
+}
@@ -0,0 +1,56 @@
+package irepo_mock
+
+import (
+	"time"
+
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// APIKey is a mock implementation of the APIKey interface using testify.
+type APIKey struct {
+	mock.Mock
+}
+
+// Save mocks the Save method of the APIKey interface.
+func (m *APIKey) Save(key *apikeymodel.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+// ByID mocks the ByID method of the APIKey interface.
+func (m *APIKey) ByID(id uuid.UUID) (*apikeymodel.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikeymodel.APIKey), args.Error(1)
+}
+
+// ByUser mocks the ByUser method of the APIKey interface.
+func (m *APIKey) ByUser(userID uuid.UUID) ([]*apikeymodel.APIKey, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apikeymodel.APIKey), args.Error(1)
+}
+
+// MarkUsed mocks the MarkUsed method of the APIKey interface.
+func (m *APIKey) MarkUsed(id uuid.UUID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method of the APIKey interface.
+func (m *APIKey) Delete(id, userID uuid.UUID) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+// DeleteByUser mocks the DeleteByUser method of the APIKey interface.
+func (m *APIKey) DeleteByUser(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
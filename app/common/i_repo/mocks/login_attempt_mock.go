@@ -0,0 +1,43 @@
+package irepo_mock
+
+import (
+	"time"
+
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+	"github.com/stretchr/testify/mock"
+)
+
+// LoginAttempt is a mock implementation of the LoginAttempt interface using testify.
+type LoginAttempt struct {
+	mock.Mock
+}
+
+// ByKey mocks the ByKey method of the LoginAttempt interface.
+func (m *LoginAttempt) ByKey(key string) (*loginattemptmodel.LoginAttempt, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*loginattemptmodel.LoginAttempt), args.Error(1)
+}
+
+// AddFailure mocks the AddFailure method of the LoginAttempt interface.
+func (m *LoginAttempt) AddFailure(key string, now time.Time, window time.Duration) (*loginattemptmodel.LoginAttempt, error) {
+	args := m.Called(key, now, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*loginattemptmodel.LoginAttempt), args.Error(1)
+}
+
+// Lock mocks the Lock method of the LoginAttempt interface.
+func (m *LoginAttempt) Lock(key string, until time.Time) error {
+	args := m.Called(key, until)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method of the LoginAttempt interface.
+func (m *LoginAttempt) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
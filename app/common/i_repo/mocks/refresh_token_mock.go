@@ -0,0 +1,48 @@
+package irepo_mock
+
+import (
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// RefreshToken is a mock implementation of the RefreshToken interface using testify.
+type RefreshToken struct {
+	mock.Mock
+}
+
+// Save mocks the Save method of the RefreshToken interface.
+func (m *RefreshToken) Save(token *refreshtokenmodel.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+// ByID mocks the ByID method of the RefreshToken interface.
+func (m *RefreshToken) ByID(id uuid.UUID) (*refreshtokenmodel.RefreshToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*refreshtokenmodel.RefreshToken), args.Error(1)
+}
+
+// MarkUsed mocks the MarkUsed method of the RefreshToken interface.
+func (m *RefreshToken) MarkUsed(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// RevokeFamily mocks the RevokeFamily method of the RefreshToken interface.
+func (m *RefreshToken) RevokeFamily(familyID uuid.UUID) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+// FamiliesByUser mocks the FamiliesByUser method of the RefreshToken interface.
+func (m *RefreshToken) FamiliesByUser(userID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
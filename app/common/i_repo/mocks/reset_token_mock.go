@@ -0,0 +1,39 @@
+package irepo_mock
+
+import (
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// ResetToken is a mock implementation of the ResetToken interface using testify.
+type ResetToken struct {
+	mock.Mock
+}
+
+// Save mocks the Save method of the ResetToken interface.
+func (m *ResetToken) Save(token *resettokenmodel.ResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+// ByID mocks the ByID method of the ResetToken interface.
+func (m *ResetToken) ByID(id uuid.UUID) (*resettokenmodel.ResetToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*resettokenmodel.ResetToken), args.Error(1)
+}
+
+// MarkUsed mocks the MarkUsed method of the ResetToken interface.
+func (m *ResetToken) MarkUsed(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// DeleteByUser mocks the DeleteByUser method of the ResetToken interface.
+func (m *ResetToken) DeleteByUser(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
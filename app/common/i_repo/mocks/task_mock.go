@@ -1,6 +1,7 @@
 package irepo_mock
 
 import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -23,16 +24,87 @@ func (m *Task) Delete(id uuid.UUID) error {
 	return args.Error(0)
 }
 
-// GetAll mocks the GetAll method of the Task interface.
-func (m *Task) GetAll() ([]*taskmodel.Task, error) {
-	args := m.Called()
-	// Ensure to return a slice of *taskmodel.Task and error
+// ReassignOwner mocks the ReassignOwner method of the Task interface.
+func (m *Task) ReassignOwner(from, to uuid.UUID) error {
+	args := m.Called(from, to)
+	return args.Error(0)
+}
+
+// DeleteByOwner mocks the DeleteByOwner method of the Task interface.
+func (m *Task) DeleteByOwner(ownerID uuid.UUID) error {
+	args := m.Called(ownerID)
+	return args.Error(0)
+}
+
+// UnassignUser mocks the UnassignUser method of the Task interface.
+func (m *Task) UnassignUser(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// List mocks the List method of the Task interface.
+func (m *Task) List(query irepo.ListTasks) (*irepo.TaskPage, error) {
+	args := m.Called(query)
+	if page, ok := args.Get(0).(*irepo.TaskPage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Search mocks the Search method of the Task interface.
+func (m *Task) Search(query irepo.SearchTasks) ([]*irepo.TaskSearchResult, error) {
+	args := m.Called(query)
+	if results, ok := args.Get(0).([]*irepo.TaskSearchResult); ok {
+		return results, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ListTags mocks the ListTags method of the Task interface.
+func (m *Task) ListTags(userID uuid.UUID) ([]*irepo.TagUsage, error) {
+	args := m.Called(userID)
+	if tags, ok := args.Get(0).([]*irepo.TagUsage); ok {
+		return tags, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// CountTagged mocks the CountTagged method of the Task interface.
+func (m *Task) CountTagged(tag string) (int, error) {
+	args := m.Called(tag)
+	return args.Int(0), args.Error(1)
+}
+
+// RenameTag mocks the RenameTag method of the Task interface.
+func (m *Task) RenameTag(from, to string) (int, error) {
+	args := m.Called(from, to)
+	return args.Int(0), args.Error(1)
+}
+
+// Subtasks mocks the Subtasks method of the Task interface.
+func (m *Task) Subtasks(parentID uuid.UUID) ([]*taskmodel.Task, error) {
+	args := m.Called(parentID)
 	if tasks, ok := args.Get(0).([]*taskmodel.Task); ok {
 		return tasks, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+// SubtaskProgress mocks the SubtaskProgress method of the Task interface.
+func (m *Task) SubtaskProgress(parentIDs ...uuid.UUID) (map[uuid.UUID]taskmodel.Progress, error) {
+	args := m.Called(parentIDs)
+	if progress, ok := args.Get(0).(map[uuid.UUID]taskmodel.Progress); ok {
+		return progress, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DetachSubtasks mocks the DetachSubtasks method of the Task interface.
+func (m *Task) DetachSubtasks(parentID uuid.UUID) error {
+	args := m.Called(parentID)
+	return args.Error(0)
+}
+
 // GetSingle mocks the GetSingle method of the Task interface.
 func (m *Task) GetSingle(id uuid.UUID) (*taskmodel.Task, error) {
 	args := m.Called(id)
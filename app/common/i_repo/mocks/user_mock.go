@@ -1,6 +1,7 @@
 package irepo_mock
 
 import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -17,6 +18,30 @@ func (m *User) Save(user *usermodel.User) error {
 	return args.Error(0)
 }
 
+// SaveRole mocks the SaveRole method of the User interface.
+func (m *User) SaveRole(user *usermodel.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// SaveDeactivation mocks the SaveDeactivation method of the User interface.
+func (m *User) SaveDeactivation(user *usermodel.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// SaveRoleKeepingAdmin mocks the SaveRoleKeepingAdmin method of the User interface.
+func (m *User) SaveRoleKeepingAdmin(user *usermodel.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// SaveDeactivationKeepingAdmin mocks the SaveDeactivationKeepingAdmin method of the User interface.
+func (m *User) SaveDeactivationKeepingAdmin(user *usermodel.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
 // ById mocks the ById method of the User interface.
 func (m *User) ById(id uuid.UUID) (*usermodel.User, error) {
 	args := m.Called(id)
@@ -26,6 +51,15 @@ func (m *User) ById(id uuid.UUID) (*usermodel.User, error) {
 	return args.Get(0).(*usermodel.User), args.Error(1)
 }
 
+// ByIDs mocks the ByIDs method of the User interface.
+func (m *User) ByIDs(ids []uuid.UUID) ([]*usermodel.User, error) {
+	args := m.Called(ids)
+	if users, ok := args.Get(0).([]*usermodel.User); ok {
+		return users, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // ByUsername mocks the ByUsername method of the User interface.
 func (m *User) ByUsername(username string) (*usermodel.User, error) {
 	args := m.Called(username)
@@ -35,8 +69,47 @@ func (m *User) ByUsername(username string) (*usermodel.User, error) {
 	return args.Get(0).(*usermodel.User), args.Error(1)
 }
 
+// ByEmail mocks the ByEmail method of the User interface.
+func (m *User) ByEmail(email string) (*usermodel.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usermodel.User), args.Error(1)
+}
+
+// ByExternalIdentity mocks the ByExternalIdentity method of the User interface.
+func (m *User) ByExternalIdentity(identity usermodel.ExternalIdentity) (*usermodel.User, error) {
+	args := m.Called(identity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usermodel.User), args.Error(1)
+}
+
+// List mocks the List method of the User interface.
+func (m *User) List(query irepo.ListUsers) (*irepo.UserPage, error) {
+	args := m.Called(query)
+	if page, ok := args.Get(0).(*irepo.UserPage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Delete mocks the Delete method of the User interface.
+func (m *User) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 // Count mocks the Count method of the User interface.
 func (m *User) Count() (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
+
+// CountAdmins mocks the CountAdmins method of the User interface.
+func (m *User) CountAdmins() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
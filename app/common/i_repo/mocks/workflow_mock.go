@@ -0,0 +1,41 @@
+package irepo_mock
+
+import (
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/stretchr/testify/mock"
+)
+
+// Workflow is a mock implementation of the Workflow interface using testify.
+type Workflow struct {
+	mock.Mock
+}
+
+// Save mocks the Save method of the Workflow interface.
+func (m *Workflow) Save(workflow *workflowmodel.Workflow) error {
+	args := m.Called(workflow)
+	return args.Error(0)
+}
+
+// ForProject mocks the ForProject method of the Workflow interface.
+func (m *Workflow) ForProject(project string) (*workflowmodel.Workflow, error) {
+	args := m.Called(project)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*workflowmodel.Workflow), args.Error(1)
+}
+
+// List mocks the List method of the Workflow interface.
+func (m *Workflow) List() ([]*workflowmodel.Workflow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*workflowmodel.Workflow), args.Error(1)
+}
+
+// Delete mocks the Delete method of the Workflow interface.
+func (m *Workflow) Delete(project string) error {
+	args := m.Called(project)
+	return args.Error(0)
+}
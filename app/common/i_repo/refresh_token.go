@@ -0,0 +1,26 @@
+// Package irepo provides interfaces for refresh token repository operations.
+package irepo
+
+import (
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	"github.com/google/uuid"
+)
+
+// RefreshToken defines methods to manage refresh tokens in the store.
+type RefreshToken interface {
+	// Save adds a new refresh token.
+	Save(token *refreshtokenmodel.RefreshToken) error
+
+	// ByID returns a refresh token by ID.
+	ByID(id uuid.UUID) (*refreshtokenmodel.RefreshToken, error)
+
+	// MarkUsed atomically records that the token was exchanged.
+	// It fails with errdmn.RefreshTokenReused if the token was already used.
+	MarkUsed(id uuid.UUID) error
+
+	// RevokeFamily removes every token of the given family.
+	RevokeFamily(familyID uuid.UUID) error
+
+	// FamiliesByUser returns the IDs of the token families issued to the given user.
+	FamiliesByUser(userID uuid.UUID) ([]uuid.UUID, error)
+}
@@ -0,0 +1,24 @@
+// Package irepo provides interfaces for password reset token repository operations.
+package irepo
+
+import (
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	"github.com/google/uuid"
+)
+
+// ResetToken defines methods to manage password reset tokens in the store.
+type ResetToken interface {
+	// Save adds a new password reset token.
+	Save(token *resettokenmodel.ResetToken) error
+
+	// ByID returns a password reset token by ID.
+	// It fails with errdmn.InvalidResetToken if the token does not exist.
+	ByID(id uuid.UUID) (*resettokenmodel.ResetToken, error)
+
+	// MarkUsed atomically records that the token was used.
+	// It fails with errdmn.ResetTokenUsed if the token was already used.
+	MarkUsed(id uuid.UUID) error
+
+	// DeleteByUser removes every password reset token of the given user.
+	DeleteByUser(userID uuid.UUID) error
+}
@@ -2,10 +2,63 @@
 package irepo
 
 import (
+	"time"
+
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/google/uuid"
 )
 
+// Fields a task listing can be sorted by.
+// Tasks sorted by priority are sorted by due date within each priority.
+const (
+	TaskSortByPriority = "priority"
+	TaskSortByDueDate  = "dueDate"
+	TaskSortByTitle    = "title"
+	TaskSortByStatus   = "status"
+)
+
+// ListTasks describes a filtered, sorted, and paginated listing of tasks.
+// Zero values leave the corresponding filter unset.
+type ListTasks struct {
+	VisibleTo  uuid.UUID // Only tasks owned by or assigned to this user; uuid.Nil for every task.
+	AssigneeID uuid.UUID // Only tasks assigned to this user; uuid.Nil for every task.
+	Status     string    // Only tasks with this status.
+	Tags       []string  // Only tasks with any of these tags, or all of them when AllTags is set.
+	AllTags    bool      // Only tasks with every one of Tags.
+	DueAfter   time.Time // Only tasks due at or after this time.
+	DueBefore  time.Time // Only tasks due at or before this time.
+	Text       string    // Case-insensitive match against the title or description.
+	SortBy     string    // One of the TaskSortBy fields.
+	SortDesc   bool      // Sort in descending order.
+	Limit      int       // Maximum number of tasks in the page.
+	Cursor     string    // Opaque token from a previous page's NextCursor.
+}
+
+// TaskPage is a single page of a task listing.
+type TaskPage struct {
+	Tasks      []*taskmodel.Task
+	NextCursor string // Token for the following page; empty on the last page.
+}
+
+// SearchTasks describes a full-text search over task titles and descriptions.
+type SearchTasks struct {
+	VisibleTo uuid.UUID // Only tasks owned by or assigned to this user; uuid.Nil for every task.
+	Text      string    // Search terms, matched against the text index.
+	Limit     int       // Maximum number of results.
+}
+
+// TaskSearchResult is a task matched by a search along with its relevance.
+type TaskSearchResult struct {
+	Task  *taskmodel.Task
+	Score float64 // Relevance score; higher is a better match.
+}
+
+// TagUsage is a tag along with the number of tasks that have it.
+type TagUsage struct {
+	Tag   string
+	Count int
+}
+
 // Task defines methods to manage tasks in the store.
 type Task interface {
 
@@ -15,8 +68,42 @@ type Task interface {
 	// Delete removes a task by ID.
 	Delete(id uuid.UUID) error
 
-	// GetAll retrieves all tasks.
-	GetAll() ([]*taskmodel.Task, error)
+	// ReassignOwner gives every task owned by one user to another.
+	ReassignOwner(from, to uuid.UUID) error
+
+	// DeleteByOwner removes every task owned by the user, detaching the subtasks of
+	// those tasks that other users own.
+	DeleteByOwner(ownerID uuid.UUID) error
+
+	// UnassignUser removes the user from the assignees of every task.
+	UnassignUser(userID uuid.UUID) error
+
+	// List retrieves a page of the tasks matching the query.
+	List(query ListTasks) (*TaskPage, error)
+
+	// Search returns the tasks matching the search terms, most relevant first.
+	Search(query SearchTasks) ([]*TaskSearchResult, error)
+
+	// ListTags returns the tags of the tasks owned by or assigned to the user, or of
+	// every task for uuid.Nil, along with how many tasks have each, most used first.
+	ListTags(userID uuid.UUID) ([]*TagUsage, error)
+
+	// CountTagged returns the number of tasks with the tag.
+	CountTagged(tag string) (int, error)
+
+	// RenameTag replaces a tag with another on every task, merging the two on tasks
+	// that have both, and returns the number of tasks changed.
+	RenameTag(from, to string) (int, error)
+
+	// Subtasks returns the subtasks of a task by priority, then due date.
+	Subtasks(parentID uuid.UUID) ([]*taskmodel.Task, error)
+
+	// SubtaskProgress returns how many subtasks each of the tasks has and how many of
+	// them are done, by task ID. Tasks without subtasks are left out.
+	SubtaskProgress(parentIDs ...uuid.UUID) (map[uuid.UUID]taskmodel.Progress, error)
+
+	// DetachSubtasks removes the subtasks of a task from it, leaving them without a parent.
+	DetachSubtasks(parentID uuid.UUID) error
 
 	// GetSingle returns a task by ID.
 	GetSingle(id uuid.UUID) (*taskmodel.Task, error)
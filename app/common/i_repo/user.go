@@ -2,14 +2,51 @@
 package irepo
 
 import (
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/google/uuid"
 )
 
+// ListUsers describes a filtered and paginated listing of users, sorted by username.
+// Zero values leave the corresponding filter unset.
+type ListUsers struct {
+	Text   string         // Case-insensitive match against the username or email.
+	Role   rolemodel.Role // Only users with this role.
+	Limit  int            // Maximum number of users in the page.
+	Cursor string         // Opaque token from a previous page's NextCursor.
+}
+
+// UserPage is a single page of a user listing.
+type UserPage struct {
+	Users      []*usermodel.User
+	NextCursor string // Token for the following page; empty on the last page.
+}
+
 // User defines methods to manage users in the store.
 type User interface {
+	// Save adds the user or updates them, except for the role and whether they are
+	// deactivated, which are only written when the user is added.
 	Save(user *usermodel.User) error
+
+	// SaveRole and SaveDeactivation write the role of the user, or whether they are
+	// deactivated, on its own, so a save of an older copy of the user cannot undo it.
+	SaveRole(user *usermodel.User) error
+	SaveDeactivation(user *usermodel.User) error
+
+	// SaveRoleKeepingAdmin and SaveDeactivationKeepingAdmin do the same for a user who
+	// may have lost the admin role or been deactivated, failing with errdmn.LastAdmin if
+	// no active admin would be left. The admins are counted atomically with the write,
+	// so concurrent changes cannot both pass.
+	SaveRoleKeepingAdmin(user *usermodel.User) error
+	SaveDeactivationKeepingAdmin(user *usermodel.User) error
+
 	ById(id uuid.UUID) (*usermodel.User, error)
+	ByIDs(ids []uuid.UUID) ([]*usermodel.User, error)
 	ByUsername(username string) (*usermodel.User, error)
+	ByEmail(email string) (*usermodel.User, error)
+	ByExternalIdentity(identity usermodel.ExternalIdentity) (*usermodel.User, error)
+	List(query ListUsers) (*UserPage, error)
+	Delete(id uuid.UUID) error
 	Count() (int64, error)
+	CountAdmins() (int64, error)
 }
@@ -0,0 +1,23 @@
+// Package irepo provides interfaces for workflow repository operations.
+package irepo
+
+import (
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+)
+
+// Workflow defines methods to manage the workflows of projects in the store.
+type Workflow interface {
+	// Save adds the workflow of a project, replacing the one it had.
+	Save(workflow *workflowmodel.Workflow) error
+
+	// ForProject returns the workflow of the given project, or the default workflow
+	// when the project has none of its own.
+	ForProject(project string) (*workflowmodel.Workflow, error)
+
+	// List returns the workflows defined for projects, sorted by project.
+	List() ([]*workflowmodel.Workflow, error)
+
+	// Delete removes the workflow of the given project, whose tasks then follow the default workflow.
+	// It fails with errdmn.WorkflowNotFound if the project has none.
+	Delete(project string) error
+}
@@ -0,0 +1,24 @@
+package irevocation_mock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Store is a mock implementation of the Store interface using testify.
+type Store struct {
+	mock.Mock
+}
+
+// Revoke mocks the Revoke method of the Store interface.
+func (m *Store) Revoke(id string, until time.Time) error {
+	args := m.Called(id, until)
+	return args.Error(0)
+}
+
+// IsRevoked mocks the IsRevoked method of the Store interface.
+func (m *Store) IsRevoked(ids ...string) (bool, error) {
+	args := m.Called(ids)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,16 @@
+// Package irevocation provides the store used to revoke access tokens before they expire.
+package irevocation
+
+import "time"
+
+// Store keeps track of revoked identifiers. An identifier is either the ID of a single
+// access token (its jti claim) or the ID of a session (its sid claim), which revokes
+// every access token issued within that session.
+type Store interface {
+	// Revoke marks the identifier as revoked until the given time, after which
+	// no token carrying it can still be valid.
+	Revoke(id string, until time.Time) error
+
+	// IsRevoked reports whether any of the given identifiers is revoked.
+	IsRevoked(ids ...string) (bool, error)
+}
@@ -0,0 +1,28 @@
+package itotp_mock
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// Service is a mock implementation of the Service interface using testify.
+type Service struct {
+	mock.Mock
+}
+
+// NewSecret mocks the NewSecret method of the Service interface.
+func (m *Service) NewSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+// URI mocks the URI method of the Service interface.
+func (m *Service) URI(account, secret string) string {
+	args := m.Called(account, secret)
+	return args.String(0)
+}
+
+// Validate mocks the Validate method of the Service interface.
+func (m *Service) Validate(secret, code string) (int64, bool) {
+	args := m.Called(secret, code)
+	return args.Get(0).(int64), args.Bool(1)
+}
@@ -0,0 +1,17 @@
+// Package itotp provides the time-based one-time passwords (RFC 6238) users sign in
+// with as a second factor.
+package itotp
+
+// Service generates TOTP secrets and checks the codes derived from them.
+type Service interface {
+	// NewSecret returns a new random secret, base32 encoded.
+	NewSecret() (string, error)
+
+	// URI returns the otpauth:// URI authenticator apps enroll the secret with,
+	// labelled with the given account name.
+	URI(account, secret string) string
+
+	// Validate checks a code against the secret at the current time, allowing for
+	// clock skew, and returns the time step the code was generated for.
+	Validate(secret, code string) (step int64, ok bool)
+}
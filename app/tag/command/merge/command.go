@@ -0,0 +1,12 @@
+package mergetagcmd
+
+// Command represents the data required to merge one tag into another on every task.
+type Command struct {
+	From string // Tag merged away.
+	Into string // Tag the tasks keep, which some task must already have.
+}
+
+// NewCommand creates a new Command instance merging one tag into another.
+func NewCommand(from, into string) *Command {
+	return &Command{From: from, Into: into}
+}
@@ -0,0 +1,49 @@
+// Package mergetagcmd provides the command and handler merging one tag into another,
+// so that every task with the first tag has the second one instead.
+package mergetagcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler handles tag merging commands.
+type Handler struct {
+	repo irepo.Task // Repository for the tasks the tags are merged on.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, int] = &Handler{}
+
+// New creates a new Handler with the given task repository.
+func New(repo irepo.Task) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle merges the tags of the command and returns the number of tasks changed.
+// Both tags must be in use; a tag is given a new name with renametagcmd instead.
+func (h *Handler) Handle(cmd *Command) (int, error) {
+	from, err := taskmodel.NormalizeTag(cmd.From)
+	if err != nil {
+		return 0, err
+	}
+	into, err := taskmodel.NormalizeTag(cmd.Into)
+	if err != nil {
+		return 0, err
+	}
+	if from == into {
+		return 0, errdmn.TagUnchanged
+	}
+
+	for _, tag := range []string{from, into} {
+		if count, err := h.repo.CountTagged(tag); err != nil {
+			return 0, err
+		} else if count == 0 {
+			return 0, errdmn.TagNotFound
+		}
+	}
+
+	return h.repo.RenameTag(from, into)
+}
@@ -0,0 +1,64 @@
+package mergetagcmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	mergetagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/merge"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// MergeTagHandlerTestSuite defines the test suite for the merge tag handler.
+type MergeTagHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Task
+	handler  *mergetagcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *MergeTagHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.handler = mergetagcmd.New(suite.mockRepo)
+}
+
+// TestHandle_Success tests that a tag is merged into another tag in use.
+func (suite *MergeTagHandlerTestSuite) TestHandle_Success() {
+	suite.mockRepo.On("CountTagged", "bugs").Return(3, nil)
+	suite.mockRepo.On("CountTagged", "bug").Return(5, nil)
+	suite.mockRepo.On("RenameTag", "bugs", "bug").Return(3, nil)
+
+	changed, err := suite.handler.Handle(mergetagcmd.NewCommand("Bugs", "bug"))
+
+	suite.NoError(err)
+	suite.Equal(3, changed)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_NotFound tests that tags no task has are neither merged nor merged into.
+func (suite *MergeTagHandlerTestSuite) TestHandle_NotFound() {
+	suite.mockRepo.On("CountTagged", "bugs").Return(3, nil)
+	suite.mockRepo.On("CountTagged", "defect").Return(0, nil)
+
+	_, err := suite.handler.Handle(mergetagcmd.NewCommand("bugs", "defect"))
+	suite.Equal(errdmn.TagNotFound, err)
+
+	_, err = suite.handler.Handle(mergetagcmd.NewCommand("defect", "bugs"))
+	suite.Equal(errdmn.TagNotFound, err)
+
+	suite.mockRepo.AssertNotCalled(suite.T(), "RenameTag", mock.Anything, mock.Anything)
+}
+
+// TestHandle_Unchanged tests that a tag is not merged into itself.
+func (suite *MergeTagHandlerTestSuite) TestHandle_Unchanged() {
+	_, err := suite.handler.Handle(mergetagcmd.NewCommand("bug", " BUG "))
+
+	suite.Equal(errdmn.TagUnchanged, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "CountTagged", mock.Anything)
+}
+
+// Run the test suite
+func TestMergeTagHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(MergeTagHandlerTestSuite))
+}
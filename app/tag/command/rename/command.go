@@ -0,0 +1,12 @@
+package renametagcmd
+
+// Command represents the data required to rename a tag on every task.
+type Command struct {
+	From string // Tag to rename.
+	To   string // New name of the tag, which no task may have yet.
+}
+
+// NewCommand creates a new Command instance renaming one tag to another.
+func NewCommand(from, to string) *Command {
+	return &Command{From: from, To: to}
+}
@@ -0,0 +1,53 @@
+// Package renametagcmd provides the command and handler renaming a tag on every task.
+//
+// Renaming refuses a name some task already has, so two tags are never merged by
+// mistake; merging is left to mergetagcmd.
+package renametagcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler handles tag renaming commands.
+type Handler struct {
+	repo irepo.Task // Repository for the tasks the tag is renamed on.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, int] = &Handler{}
+
+// New creates a new Handler with the given task repository.
+func New(repo irepo.Task) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle renames the tag of the command and returns the number of tasks changed.
+func (h *Handler) Handle(cmd *Command) (int, error) {
+	from, err := taskmodel.NormalizeTag(cmd.From)
+	if err != nil {
+		return 0, err
+	}
+	to, err := taskmodel.NormalizeTag(cmd.To)
+	if err != nil {
+		return 0, err
+	}
+	if from == to {
+		return 0, errdmn.TagUnchanged
+	}
+
+	if count, err := h.repo.CountTagged(from); err != nil {
+		return 0, err
+	} else if count == 0 {
+		return 0, errdmn.TagNotFound
+	}
+	if count, err := h.repo.CountTagged(to); err != nil {
+		return 0, err
+	} else if count > 0 {
+		return 0, errdmn.TagInUse
+	}
+
+	return h.repo.RenameTag(from, to)
+}
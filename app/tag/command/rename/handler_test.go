@@ -0,0 +1,69 @@
+package renametagcmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	renametagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/rename"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// RenameTagHandlerTestSuite defines the test suite for the rename tag handler.
+type RenameTagHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Task
+	handler  *renametagcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *RenameTagHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.handler = renametagcmd.New(suite.mockRepo)
+}
+
+// TestHandle_Success tests that a tag is renamed to its normalized new name.
+func (suite *RenameTagHandlerTestSuite) TestHandle_Success() {
+	suite.mockRepo.On("CountTagged", "bugs").Return(3, nil)
+	suite.mockRepo.On("CountTagged", "defect").Return(0, nil)
+	suite.mockRepo.On("RenameTag", "bugs", "defect").Return(3, nil)
+
+	changed, err := suite.handler.Handle(renametagcmd.NewCommand("bugs", "Defect"))
+
+	suite.NoError(err)
+	suite.Equal(3, changed)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_InUse tests that a tag is not renamed to a tag tasks already have.
+func (suite *RenameTagHandlerTestSuite) TestHandle_InUse() {
+	suite.mockRepo.On("CountTagged", "bugs").Return(3, nil)
+	suite.mockRepo.On("CountTagged", "bug").Return(1, nil)
+
+	_, err := suite.handler.Handle(renametagcmd.NewCommand("bugs", "bug"))
+
+	suite.Equal(errdmn.TagInUse, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "RenameTag", mock.Anything, mock.Anything)
+}
+
+// TestHandle_Invalid tests that unknown, invalid, and unchanged tags are refused.
+func (suite *RenameTagHandlerTestSuite) TestHandle_Invalid() {
+	suite.mockRepo.On("CountTagged", "unused").Return(0, nil)
+
+	cases := map[*renametagcmd.Command]error{
+		renametagcmd.NewCommand("unused", "bug"): errdmn.TagNotFound,
+		renametagcmd.NewCommand("bug", "c++"):    errdmn.InvalidTag,
+		renametagcmd.NewCommand("Bug", "bug"):    errdmn.TagUnchanged,
+	}
+	for cmd, expectedErr := range cases {
+		_, err := suite.handler.Handle(cmd)
+		suite.Equal(expectedErr, err)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "RenameTag", mock.Anything, mock.Anything)
+}
+
+// Run the test suite
+func TestRenameTagHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RenameTagHandlerTestSuite))
+}
@@ -0,0 +1,33 @@
+// Package listtagsqry provides the query and handler listing the tags of the tasks
+// visible to a user along with how many of those tasks have each.
+package listtagsqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/google/uuid"
+)
+
+// Handler handles tag listing queries.
+type Handler struct {
+	repo irepo.Task // Repository for the tasks the tags are counted over.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, []*irepo.TagUsage] = &Handler{}
+
+// New creates a new Handler with the given task repository.
+func New(repo irepo.Task) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle returns the tags of the tasks the actor owns or is assigned to, or of every
+// task when the actor may read every task, most used first.
+func (h *Handler) Handle(qry *Query) ([]*irepo.TagUsage, error) {
+	userID := qry.Actor.ID
+	if qry.Actor.Can(rolemodel.TaskReadAny) {
+		userID = uuid.Nil
+	}
+	return h.repo.ListTags(userID)
+}
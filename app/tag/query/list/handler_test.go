@@ -0,0 +1,57 @@
+package listtagsqry_test
+
+import (
+	"testing"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	listtagsqry "github.com/beka-birhanu/task_manager_final/app/tag/query/list"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// ListTagsHandlerTestSuite defines the test suite for the list tags handler.
+type ListTagsHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Task
+	handler  *listtagsqry.Handler
+	tags     []*irepo.TagUsage
+}
+
+// SetupTest sets up the test environment.
+func (suite *ListTagsHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.handler = listtagsqry.New(suite.mockRepo)
+	suite.tags = []*irepo.TagUsage{{Tag: "bug", Count: 2}}
+}
+
+// TestHandle_Owner tests that members only see the tags of the tasks they own or are assigned to.
+func (suite *ListTagsHandlerTestSuite) TestHandle_Owner() {
+	actor := taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	suite.mockRepo.On("ListTags", actor.ID).Return(suite.tags, nil)
+
+	tags, err := suite.handler.Handle(listtagsqry.NewQuery(actor))
+
+	suite.NoError(err)
+	suite.Equal(suite.tags, tags)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_ReadAny tests that users who can read every task see the tags of every task.
+func (suite *ListTagsHandlerTestSuite) TestHandle_ReadAny() {
+	actor := taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Viewer}}
+	suite.mockRepo.On("ListTags", uuid.Nil).Return(suite.tags, nil)
+
+	tags, err := suite.handler.Handle(listtagsqry.NewQuery(actor))
+
+	suite.NoError(err)
+	suite.Equal(suite.tags, tags)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// Run the test suite
+func TestListTagsHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ListTagsHandlerTestSuite))
+}
@@ -0,0 +1,13 @@
+package listtagsqry
+
+import taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+
+// Query represents the request to list the tags of the tasks visible to a user.
+type Query struct {
+	Actor taskpolicy.Actor // User listing the tags; of their own tasks unless granted task:read:any.
+}
+
+// NewQuery creates a new Query instance for the given actor.
+func NewQuery(actor taskpolicy.Actor) *Query {
+	return &Query{Actor: actor}
+}
@@ -0,0 +1,26 @@
+// Package taskassignee looks up the users tasks are assigned to. Handlers that assign
+// tasks resolve assignees through it, so that a task is only ever assigned to users
+// who exist and are active.
+package taskassignee
+
+import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/google/uuid"
+)
+
+// Find returns the ID of the user with the given username, or errdmn.InvalidAssignee
+// when there is no such user or the user is deactivated.
+func Find(users irepo.User, username string) (uuid.UUID, error) {
+	user, err := users.ByUsername(username)
+	if err == errdmn.UserNotFound {
+		return uuid.Nil, errdmn.InvalidAssignee
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if user.IsDeactivated() {
+		return uuid.Nil, errdmn.InvalidAssignee
+	}
+	return user.ID(), nil
+}
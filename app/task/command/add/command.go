@@ -1,27 +1,49 @@
 package addcmd
 
-import "time"
+import (
+	"time"
+
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
 
 // Command represents the data required to add a new task.
 // Fields:
 // - title: The title of the task.
 // - description: A detailed description of the task.
-// - status: The current status of the task.
+// - status: The status the task starts in; the initial state of the workflow when empty.
+// - priority: The priority of the task; the default priority when empty.
+// - project: The project the task belongs to; none when empty.
+// - tags: The tags of the task.
+// - parentID: The task the new task is a subtask of; none when uuid.Nil.
+// - assignees: The usernames of the users the task is assigned to.
 // - dueDate: The due date for the task.
+// - actor: The user creating, and therefore owning, the task.
 type Command struct {
 	title       string
 	description string
 	status      string
+	priority    string
+	project     string
+	tags        []string
+	parentID    uuid.UUID
+	assignees   []string
 	dueDate     time.Time
+	actor       taskpolicy.Actor
 }
 
 // NewCommand creates a new Command instance with the specified details.
-func NewCommand(title, description, status string, dueDate time.Time) *Command {
+func NewCommand(title, description, status, priority, project string, tags []string, parentID uuid.UUID, assignees []string, dueDate time.Time, actor taskpolicy.Actor) *Command {
 	return &Command{
 		title:       title,
 		description: description,
 		status:      status,
+		priority:    priority,
+		project:     project,
+		tags:        tags,
+		parentID:    parentID,
+		assignees:   assignees,
 		dueDate:     dueDate,
+		actor:       actor,
 	}
 }
-
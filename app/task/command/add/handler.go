@@ -1,37 +1,86 @@
 // Package addcmd provides the logic for adding new tasks.
 // It includes the command structure and the handler to process the add task command.
+//
+// A task created as a subtask needs a parent the creator may update, and cannot be
+// open when the parent is done. A task may be assigned
+// on creation, only to existing, active users.
 package addcmd
 
 import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskassignee "github.com/beka-birhanu/task_manager_final/app/task/assignee"
+	taskhierarchy "github.com/beka-birhanu/task_manager_final/app/task/hierarchy"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
 )
 
 // Handler handles the logic for adding a new task to the repository.
 type Handler struct {
-	repo irepo.Task
+	repo      irepo.Task
+	workflows irepo.Workflow
+	users     irepo.User
+	policy    taskpolicy.IPolicy
+	hierarchy *taskhierarchy.Checker
 }
 
 // Ensure Handler implements icmd.IHandler
 var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
 
-// NewHandler creates a new instance of Handler with the given task repository.
-func NewHandler(repo irepo.Task) *Handler {
-	return &Handler{repo: repo}
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo      irepo.Task         // Repository for task-related operations.
+	Workflows irepo.Workflow     // Repository for the workflows of projects.
+	UserRepo  irepo.User         // Repository the assignees are looked up in.
+	Policy    taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		repo:      cfg.Repo,
+		workflows: cfg.Workflows,
+		users:     cfg.UserRepo,
+		policy:    cfg.Policy,
+		hierarchy: taskhierarchy.New(cfg.Repo, cfg.Workflows, cfg.Policy),
+	}
 }
 
 // Handle processes the command to add a new task to the repository.
 func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionCreate, nil); err != nil {
+		return nil, err
+	}
+
+	workflow, err := h.workflows.ForProject(cmd.project)
+	if err != nil {
+		return nil, err
+	}
+
 	task, err := taskmodel.New(taskmodel.Config{
 		Title:       cmd.title,
 		Description: cmd.description,
 		DueDate:     cmd.dueDate,
 		Status:      cmd.status,
+		Priority:    cmd.priority,
+		Tags:        cmd.tags,
+		Project:     cmd.project,
+		ParentID:    cmd.parentID,
+		OwnerID:     cmd.actor.ID,
+		Workflow:    workflow,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if cmd.parentID != uuid.Nil {
+		if err := h.hierarchy.CheckParent(cmd.actor, task, cmd.parentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.assign(cmd, task); err != nil {
+		return nil, err
+	}
 
 	err = h.repo.Save(task)
 	if err != nil {
@@ -40,3 +89,25 @@ func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
 
 	return task, nil
 }
+
+// assign assigns the new task to the assignees of the command, which the actor must be
+// allowed to choose.
+func (h *Handler) assign(cmd *Command, task *taskmodel.Task) error {
+	if len(cmd.assignees) == 0 {
+		return nil
+	}
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionAssign, task); err != nil {
+		return err
+	}
+
+	for _, username := range cmd.assignees {
+		assigneeID, err := taskassignee.Find(h.users, username)
+		if err != nil {
+			return err
+		}
+		if err := task.Assign(assigneeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
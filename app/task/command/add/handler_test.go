@@ -8,7 +8,14 @@ import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	"github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
 	addcmd "github.com/beka-birhanu/task_manager_final/app/task/command/add"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -16,35 +23,50 @@ import (
 // HandlerTestSuite defines the test suite for the addcmd.Handler.
 type HandlerTestSuite struct {
 	suite.Suite
-	mockRepo   *irepo_mock.Task
-	handler    icmd.IHandler[*addcmd.Command, *taskmodel.Task]
-	cmdTitle   string
-	cmdDesc    string
-	cmdStatus  string
-	cmdDueDate time.Time
+	mockRepo      *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	mockUserRepo  *irepo_mock.User
+	mockPolicy    *taskpolicy_mock.IPolicy
+	handler       icmd.IHandler[*addcmd.Command, *taskmodel.Task]
+	cmdTitle      string
+	cmdDesc       string
+	cmdStatus     string
+	cmdDueDate    time.Time
+	cmdActor      taskpolicy.Actor
 }
 
 // SetupTest sets up the test environment.
 func (suite *HandlerTestSuite) SetupTest() {
 	// Initialize the mock repository
 	suite.mockRepo = new(irepo_mock.Task)
-
-	// Initialize the handler with the mock repository
-	suite.handler = addcmd.NewHandler(suite.mockRepo)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.mockWorkflows.On("ForProject", "").Return(workflowmodel.Default(), nil).Maybe()
+
+	// Initialize the handler with the mock repositories and policy
+	suite.handler = addcmd.NewHandler(addcmd.Config{
+		Repo:      suite.mockRepo,
+		Workflows: suite.mockWorkflows,
+		UserRepo:  suite.mockUserRepo,
+		Policy:    suite.mockPolicy,
+	})
 
 	// Initialize the command properties
 	suite.cmdTitle = "Test Task"
 	suite.cmdDesc = "This is a test task"
 	suite.cmdStatus = taskmodel.StatusPending
 	suite.cmdDueDate = time.Now().Add(24 * time.Hour)
+	suite.cmdActor = taskpolicy.Actor{ID: uuid.New()}
 }
 
 // TestHandle tests the Handle method of the addcmd.Handler.
 func (suite *HandlerTestSuite) TestHandle() {
 	// Create the command using the properties stored in the suite
-	cmd := addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", "", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor)
 
-	// Set up expected behavior for the mock repository
+	// Set up expected behavior for the mocks
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
 	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(nil)
 
 	// Execute the Handle method
@@ -57,16 +79,71 @@ func (suite *HandlerTestSuite) TestHandle() {
 	suite.Equal(suite.cmdDesc, result.Description())
 	suite.Equal(suite.cmdDueDate, result.DueDate())
 	suite.Equal(suite.cmdStatus, result.Status())
+	suite.Equal(suite.cmdActor.ID, result.OwnerID())
 
 	// Verify that the Save method was called on the repository with the expected task
 	suite.mockRepo.AssertCalled(suite.T(), "Save", mock.AnythingOfType("*taskmodel.Task"))
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestHandle_ProjectWorkflow tests that tasks of a project start in the states of its workflow.
+func (suite *HandlerTestSuite) TestHandle_ProjectWorkflow() {
+	workflow, err := workflowmodel.New(workflowmodel.Config{Project: "website", States: []string{"todo", "shipped"}})
+	suite.Require().NoError(err)
+	suite.mockWorkflows.On("ForProject", "website").Return(workflow, nil)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
+	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(nil)
+
+	result, err := suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, "", "", "website", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor))
+	suite.NoError(err)
+	suite.Equal("todo", result.Status())
+	suite.Equal("website", result.Project())
+
+	result, err = suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", "website", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor))
+	suite.Equal(errdmn.InvalidStatus, err)
+	suite.Nil(result)
+}
+
+// TestHandle_Subtask tests that a task is created under a parent the actor can see.
+func (suite *HandlerTestSuite) TestHandle_Subtask() {
+	parent, err := taskmodel.New(taskmodel.Config{
+		Title:       "Parent",
+		Description: "A task to break down",
+		DueDate:     suite.cmdDueDate,
+		OwnerID:     suite.cmdActor.ID,
+	})
+	suite.Require().NoError(err)
+	suite.mockRepo.On("GetSingle", parent.ID()).Return(parent, nil)
+	suite.mockRepo.On("SubtaskProgress", []uuid.UUID{parent.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionUpdate, parent).Return(nil)
+	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(nil)
+
+	result, err := suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, "", "", "", nil, parent.ID(), nil, suite.cmdDueDate, suite.cmdActor))
+
+	suite.NoError(err)
+	suite.Equal(parent.ID(), result.ParentID())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_ParentNotFound tests that no task is created under a missing parent.
+func (suite *HandlerTestSuite) TestHandle_ParentNotFound() {
+	parentID := uuid.New()
+	suite.mockRepo.On("GetSingle", parentID).Return(nil, errdmn.TaskNotFound)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
+
+	result, err := suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, "", "", "", nil, parentID, nil, suite.cmdDueDate, suite.cmdActor))
+
+	suite.Equal(errdmn.ParentNotFound, err)
+	suite.Nil(result)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
 // TestHandle_ErrorCreatingTask tests the Handle method when creating a task fails.
 func (suite *HandlerTestSuite) TestHandle_ErrorCreatingTask() {
 	// Create a command with properties
-	cmd := addcmd.NewCommand("", suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := addcmd.NewCommand("", suite.cmdDesc, suite.cmdStatus, "", "", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
 
 	// Execute the Handle method
 	result, err := suite.handler.Handle(cmd)
@@ -79,8 +156,9 @@ func (suite *HandlerTestSuite) TestHandle_ErrorCreatingTask() {
 // TestHandle_ErrorSavingTask tests the Handle method when saving a task fails.
 func (suite *HandlerTestSuite) TestHandle_ErrorSavingTask() {
 	// Create the command using the properties stored in the suite
-	cmd := addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", "", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor)
 
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
 	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(errors.New("failed to save task"))
 	// Execute the Handle method
 	result, err := suite.handler.Handle(cmd)
@@ -90,6 +168,48 @@ func (suite *HandlerTestSuite) TestHandle_ErrorSavingTask() {
 	suite.Nil(result)
 }
 
+// TestHandle_Unauthorized tests the Handle method when the policy denies creation.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	cmd := addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", "", nil, uuid.Nil, nil, suite.cmdDueDate, suite.cmdActor)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(errdmn.TaskNotFound)
+
+	// Execute the Handle method
+	result, err := suite.handler.Handle(cmd)
+
+	// Assertions
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(result)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Assignees tests that the new task is assigned to the given users.
+func (suite *HandlerTestSuite) TestHandle_Assignees() {
+	assignee := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionAssign, mock.AnythingOfType("*taskmodel.Task")).Return(nil)
+	suite.mockUserRepo.On("ByUsername", "user1").Return(assignee, nil)
+	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(nil)
+
+	result, err := suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, "", "", "", nil, uuid.Nil, []string{"user1"}, suite.cmdDueDate, suite.cmdActor))
+
+	suite.NoError(err)
+	suite.Equal([]uuid.UUID{assignee.ID()}, result.AssigneeIDs())
+	suite.True(result.IsOwnedBy(suite.cmdActor.ID))
+}
+
+// TestHandle_InvalidAssignee tests that the task is not created when an assignee does not exist.
+func (suite *HandlerTestSuite) TestHandle_InvalidAssignee() {
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionCreate, (*taskmodel.Task)(nil)).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.cmdActor, taskpolicy.ActionAssign, mock.AnythingOfType("*taskmodel.Task")).Return(nil)
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(addcmd.NewCommand(suite.cmdTitle, suite.cmdDesc, "", "", "", nil, uuid.Nil, []string{"ghost"}, suite.cmdDueDate, suite.cmdActor))
+
+	suite.Equal(errdmn.InvalidAssignee, err)
+	suite.Nil(result)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
 // Run the test suite
 func TestHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(HandlerTestSuite))
@@ -0,0 +1,23 @@
+package assigncmd
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Command represents the data needed to assign a task to a user.
+type Command struct {
+	id       uuid.UUID
+	username string
+	actor    taskpolicy.Actor
+}
+
+// NewCommand creates a new Command assigning the task with the given ID to the user
+// with the given username on behalf of the given actor.
+func NewCommand(id uuid.UUID, username string, actor taskpolicy.Actor) *Command {
+	return &Command{
+		id:       id,
+		username: username,
+		actor:    actor,
+	}
+}
@@ -0,0 +1,63 @@
+// Package assigncmd provides the logic to assign a task to a user.
+// It includes a command structure and a handler to process the assign command.
+//
+// Only the owner of the task, or users who may update any task, can assign it, and
+// only to existing, active users.
+// Assigning a task to a user it is already assigned to changes nothing.
+package assigncmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskassignee "github.com/beka-birhanu/task_manager_final/app/task/assignee"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler is responsible for handling the assign task command.
+type Handler struct {
+	taskRepo irepo.Task         // Repository for task-related operations.
+	userRepo irepo.User         // Repository the assignees are looked up in.
+	policy   taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	TaskRepo irepo.Task         // Repository for task-related operations.
+	UserRepo irepo.User         // Repository the assignees are looked up in.
+	Policy   taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the provided configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{taskRepo: cfg.TaskRepo, userRepo: cfg.UserRepo, policy: cfg.Policy}
+}
+
+// Handle assigns the task to the user and returns the updated task.
+func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
+	task, err := h.taskRepo.GetSingle(cmd.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionAssign, task); err != nil {
+		return nil, err
+	}
+
+	assigneeID, err := taskassignee.Find(h.userRepo, cmd.username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := task.Assign(assigneeID); err != nil {
+		return nil, err
+	}
+
+	if err := h.taskRepo.Save(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
@@ -0,0 +1,114 @@
+package assigncmd_test
+
+import (
+	"testing"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	assigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/assign"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite defines the test suite for the assigncmd.Handler.
+type HandlerTestSuite struct {
+	suite.Suite
+	mockTaskRepo *irepo_mock.Task
+	mockUserRepo *irepo_mock.User
+	mockPolicy   *taskpolicy_mock.IPolicy
+	handler      icmd.IHandler[*assigncmd.Command, *taskmodel.Task]
+	actor        taskpolicy.Actor
+	task         *taskmodel.Task
+	assignee     *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *HandlerTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(irepo_mock.Task)
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.handler = assigncmd.NewHandler(assigncmd.Config{
+		TaskRepo: suite.mockTaskRepo,
+		UserRepo: suite.mockUserRepo,
+		Policy:   suite.mockPolicy,
+	})
+
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
+	suite.task, _ = taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task to assign",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
+	})
+	suite.assignee = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.mockTaskRepo.On("GetSingle", suite.task.ID()).Return(suite.task, nil)
+}
+
+// TestHandle tests that the task is assigned to the user and saved.
+func (suite *HandlerTestSuite) TestHandle() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionAssign, suite.task).Return(nil)
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.assignee, nil)
+	suite.mockTaskRepo.On("Save", suite.task).Return(nil)
+
+	task, err := suite.handler.Handle(assigncmd.NewCommand(suite.task.ID(), "user1", suite.actor))
+
+	suite.NoError(err)
+	suite.Equal([]uuid.UUID{suite.assignee.ID()}, task.AssigneeIDs())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Unauthorized tests that the task is not assigned when the policy denies the update.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionAssign, suite.task).Return(errdmn.TaskNotFound)
+
+	task, err := suite.handler.Handle(assigncmd.NewCommand(suite.task.ID(), "user1", suite.actor))
+
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(task)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByUsername", mock.Anything)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_InvalidAssignee tests that tasks are only assigned to existing, active users.
+func (suite *HandlerTestSuite) TestHandle_InvalidAssignee() {
+	deactivated := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user2", Role: rolemodel.Member, Deactivated: true})
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionAssign, suite.task).Return(nil)
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByUsername", "user2").Return(deactivated, nil)
+
+	for _, username := range []string{"ghost", "user2"} {
+		task, err := suite.handler.Handle(assigncmd.NewCommand(suite.task.ID(), username, suite.actor))
+
+		suite.Equal(errdmn.InvalidAssignee, err, username)
+		suite.Nil(task)
+	}
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_TooManyAssignees tests that a task is not assigned to more users than allowed.
+func (suite *HandlerTestSuite) TestHandle_TooManyAssignees() {
+	for i := 0; i < taskmodel.MaxAssignees; i++ {
+		suite.Require().NoError(suite.task.Assign(uuid.New()))
+	}
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionAssign, suite.task).Return(nil)
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.assignee, nil)
+
+	_, err := suite.handler.Handle(assigncmd.NewCommand(suite.task.ID(), "user1", suite.actor))
+
+	suite.Equal(errdmn.TooManyAssignees, err)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
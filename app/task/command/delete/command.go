@@ -0,0 +1,21 @@
+package deletecmd
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Command represents the data needed to delete a task.
+type Command struct {
+	id    uuid.UUID
+	actor taskpolicy.Actor
+}
+
+// NewCommand creates a new Command instance for the task with the given ID
+// on behalf of the given actor.
+func NewCommand(id uuid.UUID, actor taskpolicy.Actor) *Command {
+	return &Command{
+		id:    id,
+		actor: actor,
+	}
+}
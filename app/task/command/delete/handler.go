@@ -1,32 +1,54 @@
 // Package deletecmd provides the logic to delete a task.
 // It includes the handler to process the delete command.
+//
+// The subtasks of a deleted task are kept, without a parent.
 package deletecmd
 
 import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
-	"github.com/google/uuid"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 )
 
 // Handler is responsible for handling the delete task command.
 type Handler struct {
-	repo irepo.Task // Repository for task-related operations.
+	repo   irepo.Task         // Repository for task-related operations.
+	policy taskpolicy.IPolicy // Authorization policy for tasks.
 }
 
 // Ensure Handler implements the IHandler interface
-var _ icmd.IHandler[uuid.UUID, bool] = &Handler{}
+var _ icmd.IHandler[*Command, bool] = &Handler{}
 
-// New creates a new instance of Handler with the provided task repository.
-func New(taskRepo irepo.Task) *Handler {
-	return &Handler{repo: taskRepo}
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo   irepo.Task         // Repository for task-related operations.
+	Policy taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// New creates a new instance of Handler with the provided configuration.
+func New(cfg Config) *Handler {
+	return &Handler{repo: cfg.Repo, policy: cfg.Policy}
 }
 
 // Handle processes the delete command and removes the task from the repository.
-func (h *Handler) Handle(id uuid.UUID) (bool, error) {
-	err := h.repo.Delete(id)
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	task, err := h.repo.GetSingle(cmd.id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionDelete, task); err != nil {
+		return false, err
+	}
+
+	err = h.repo.Delete(cmd.id)
 	if err != nil {
 		return false, err
 	}
 
+	if err := h.repo.DetachSubtasks(cmd.id); err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
@@ -3,65 +3,116 @@ package deletecmd_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
 	deletecmd "github.com/beka-birhanu/task_manager_final/app/task/command/delete"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 // HandlerTestSuite defines the test suite for the deletecmd.Handler.
 type HandlerTestSuite struct {
 	suite.Suite
-	mockRepo *irepo_mock.Task
-	handler  icmd.IHandler[uuid.UUID, bool]
-	taskID   uuid.UUID
+	mockRepo   *irepo_mock.Task
+	mockPolicy *taskpolicy_mock.IPolicy
+	handler    icmd.IHandler[*deletecmd.Command, bool]
+	taskID     uuid.UUID
+	actor      taskpolicy.Actor
+	task       *taskmodel.Task
 }
 
 // SetupTest sets up the test environment.
 func (suite *HandlerTestSuite) SetupTest() {
 	// Initialize the mock repository
 	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
 
-	// Initialize the handler with the mock repository
-	suite.handler = deletecmd.New(suite.mockRepo)
+	// Initialize the handler with the mock repository and policy
+	suite.handler = deletecmd.New(deletecmd.Config{Repo: suite.mockRepo, Policy: suite.mockPolicy})
 
-	// Initialize a task ID for testing
+	// Initialize a task ID and an owned task for testing
 	suite.taskID = uuid.New()
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
+	suite.task, _ = taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task to delete",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
+	})
 }
 
 // TestHandle tests the Handle method of the deletecmd.Handler.
 func (suite *HandlerTestSuite) TestHandle() {
 	// Set up expected behavior for the mock repository
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(suite.task, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionDelete, suite.task).Return(nil)
 	suite.mockRepo.On("Delete", suite.taskID).Return(nil)
+	suite.mockRepo.On("DetachSubtasks", suite.taskID).Return(nil)
 
 	// Execute the Handle method
-	result, err := suite.handler.Handle(suite.taskID)
+	result, err := suite.handler.Handle(deletecmd.NewCommand(suite.taskID, suite.actor))
 
 	// Assertions
 	suite.NoError(err)
 	suite.True(result)
 
-	// Verify that the Delete method was called on the repository with the expected task ID
+	// Verify that the task was deleted and its subtasks detached from it
 	suite.mockRepo.AssertCalled(suite.T(), "Delete", suite.taskID)
+	suite.mockRepo.AssertCalled(suite.T(), "DetachSubtasks", suite.taskID)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestHandle_Unauthorized tests the Handle method when the policy denies the deletion.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(suite.task, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionDelete, suite.task).Return(errdmn.TaskNotFound)
+
+	// Execute the Handle method
+	result, err := suite.handler.Handle(deletecmd.NewCommand(suite.taskID, suite.actor))
+
+	// Assertions
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.False(result)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
 // TestHandle_ErrorNotFound tests the Handle method when the task to delete is not found.
 func (suite *HandlerTestSuite) TestHandle_ErrorNotFound() {
 	// Set up expected behavior for the mock repository to return an error indicating task not found
-	suite.mockRepo.On("Delete", suite.taskID).Return(errors.New("task not found"))
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, errors.New("task not found"))
 
 	// Execute the Handle method
-	result, err := suite.handler.Handle(suite.taskID)
+	result, err := suite.handler.Handle(deletecmd.NewCommand(suite.taskID, suite.actor))
 
 	// Assertions
 	suite.Error(err)
 	suite.False(result)
 
-	// Verify that the Delete method was called on the repository with the expected task ID
-	suite.mockRepo.AssertCalled(suite.T(), "Delete", suite.taskID)
+	// Verify that the repository was queried and nothing was deleted
+	suite.mockRepo.AssertCalled(suite.T(), "GetSingle", suite.taskID)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// TestHandle_ErrorDeleting tests the Handle method when removing the task fails.
+func (suite *HandlerTestSuite) TestHandle_ErrorDeleting() {
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(suite.task, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionDelete, suite.task).Return(nil)
+	suite.mockRepo.On("Delete", suite.taskID).Return(errors.New("failed to delete task"))
+
+	// Execute the Handle method
+	result, err := suite.handler.Handle(deletecmd.NewCommand(suite.taskID, suite.actor))
+
+	// Assertions
+	suite.Error(err)
+	suite.False(result)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
@@ -0,0 +1,23 @@
+package parentcmd
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Command represents the data needed to move a task under another one.
+type Command struct {
+	id       uuid.UUID
+	parentID uuid.UUID
+	actor    taskpolicy.Actor
+}
+
+// NewCommand creates a new Command making the task with the given ID a subtask of the
+// parent on behalf of the given actor. A uuid.Nil parent detaches the task from its parent.
+func NewCommand(id, parentID uuid.UUID, actor taskpolicy.Actor) *Command {
+	return &Command{
+		id:       id,
+		parentID: parentID,
+		actor:    actor,
+	}
+}
@@ -0,0 +1,69 @@
+// Package parentcmd provides the logic to move a task under another one, or to
+// detach it from its parent. It includes a command structure and a handler to process
+// the parent command.
+//
+// Only users who may update the task can move it, and only under a task they may update
+// that is neither the task itself nor one of its subtasks. An open task cannot be moved
+// under a task that is done.
+package parentcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskhierarchy "github.com/beka-birhanu/task_manager_final/app/task/hierarchy"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+)
+
+// Handler is responsible for handling the parent command.
+type Handler struct {
+	repo      irepo.Task             // Repository for task-related operations.
+	policy    taskpolicy.IPolicy     // Authorization policy for tasks.
+	hierarchy *taskhierarchy.Checker // Checks the parent the task is moved under.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo      irepo.Task         // Repository for task-related operations.
+	Workflows irepo.Workflow     // Repository for the workflows of projects.
+	Policy    taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the provided configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{repo: cfg.Repo, policy: cfg.Policy, hierarchy: taskhierarchy.New(cfg.Repo, cfg.Workflows, cfg.Policy)}
+}
+
+// Handle moves the task under the parent of the command and returns the updated task.
+// Moving a task under its current parent changes nothing.
+func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
+	task, err := h.repo.GetSingle(cmd.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionUpdate, task); err != nil {
+		return nil, err
+	}
+
+	if cmd.parentID == task.ParentID() {
+		return task, nil
+	}
+	if cmd.parentID != uuid.Nil {
+		if err := h.hierarchy.CheckParent(cmd.actor, task, cmd.parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := task.SetParent(cmd.parentID); err != nil {
+		return nil, err
+	}
+	if err := h.repo.Save(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
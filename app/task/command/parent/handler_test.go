@@ -0,0 +1,118 @@
+package parentcmd_test
+
+import (
+	"testing"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	parentcmd "github.com/beka-birhanu/task_manager_final/app/task/command/parent"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite defines the test suite for the parentcmd.Handler.
+type HandlerTestSuite struct {
+	suite.Suite
+	mockRepo      *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	mockPolicy    *taskpolicy_mock.IPolicy
+	handler       icmd.IHandler[*parentcmd.Command, *taskmodel.Task]
+	actor         taskpolicy.Actor
+	task          *taskmodel.Task
+	parent        *taskmodel.Task
+}
+
+// SetupTest sets up the test environment.
+func (suite *HandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.mockWorkflows.On("ForProject", "").Return(workflowmodel.Default(), nil).Maybe()
+	suite.handler = parentcmd.NewHandler(parentcmd.Config{
+		Repo:      suite.mockRepo,
+		Workflows: suite.mockWorkflows,
+		Policy:    suite.mockPolicy,
+	})
+
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
+	suite.task = suite.newTask("Task")
+	suite.parent = suite.newTask("Parent")
+	suite.mockRepo.On("GetSingle", suite.task.ID()).Return(suite.task, nil)
+	suite.mockRepo.On("GetSingle", suite.parent.ID()).Return(suite.parent, nil).Maybe()
+}
+
+// newTask creates a top-level task of the actor with the given title.
+func (suite *HandlerTestSuite) newTask(title string) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       title,
+		Description: "A task in a hierarchy",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		OwnerID:     suite.actor.ID,
+	})
+	suite.Require().NoError(err)
+	return task
+}
+
+// TestHandle tests that the task is moved under the parent and saved.
+func (suite *HandlerTestSuite) TestHandle() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.parent).Return(nil)
+	suite.mockRepo.On("SubtaskProgress", []uuid.UUID{suite.parent.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+	suite.mockRepo.On("Save", suite.task).Return(nil)
+
+	task, err := suite.handler.Handle(parentcmd.NewCommand(suite.task.ID(), suite.parent.ID(), suite.actor))
+
+	suite.NoError(err)
+	suite.Equal(suite.parent.ID(), task.ParentID())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Detach tests that the task is detached from its parent.
+func (suite *HandlerTestSuite) TestHandle_Detach() {
+	suite.Require().NoError(suite.task.SetParent(suite.parent.ID()))
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	suite.mockRepo.On("Save", suite.task).Return(nil)
+
+	task, err := suite.handler.Handle(parentcmd.NewCommand(suite.task.ID(), uuid.Nil, suite.actor))
+
+	suite.NoError(err)
+	suite.Equal(uuid.Nil, task.ParentID())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Cycle tests that a task is not moved under one of its subtasks.
+func (suite *HandlerTestSuite) TestHandle_Cycle() {
+	suite.Require().NoError(suite.parent.SetParent(suite.task.ID()))
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.parent).Return(nil)
+
+	task, err := suite.handler.Handle(parentcmd.NewCommand(suite.task.ID(), suite.parent.ID(), suite.actor))
+
+	suite.Equal(errdmn.ParentCycle, err)
+	suite.Nil(task)
+	suite.Equal(uuid.Nil, suite.task.ParentID())
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Unauthorized tests that the task is not moved when the policy denies the update.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(errdmn.TaskNotFound)
+
+	task, err := suite.handler.Handle(parentcmd.NewCommand(suite.task.ID(), suite.parent.ID(), suite.actor))
+
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(task)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,23 @@
+package transitioncmd
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Command represents the data needed to move a task to another status.
+type Command struct {
+	id     uuid.UUID
+	status string
+	actor  taskpolicy.Actor
+}
+
+// NewCommand creates a new Command moving the task with the given ID to the given
+// status on behalf of the given actor.
+func NewCommand(id uuid.UUID, status string, actor taskpolicy.Actor) *Command {
+	return &Command{
+		id:     id,
+		status: status,
+		actor:  actor,
+	}
+}
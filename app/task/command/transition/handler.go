@@ -0,0 +1,77 @@
+// Package transitioncmd provides the logic to move a task to another status.
+// It includes a command structure and a handler to process the transition command.
+//
+// Only users who may update the task can move it, and only along the transitions
+// the workflow of its project allows. A task is only done once its subtasks are, and
+// cannot be reopened while its parent is done.
+package transitioncmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskhierarchy "github.com/beka-birhanu/task_manager_final/app/task/hierarchy"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler is responsible for handling the transition command.
+type Handler struct {
+	taskRepo  irepo.Task             // Repository for task-related operations.
+	workflows irepo.Workflow         // Repository for the workflows of projects.
+	policy    taskpolicy.IPolicy     // Authorization policy for tasks.
+	hierarchy *taskhierarchy.Checker // Checks the task against its parent.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	TaskRepo  irepo.Task         // Repository for task-related operations.
+	Workflows irepo.Workflow     // Repository for the workflows of projects.
+	Policy    taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the provided configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		taskRepo:  cfg.TaskRepo,
+		workflows: cfg.Workflows,
+		policy:    cfg.Policy,
+		hierarchy: taskhierarchy.New(cfg.TaskRepo, cfg.Workflows, cfg.Policy),
+	}
+}
+
+// Handle moves the task to the status of the command and returns the updated task.
+func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
+	task, err := h.taskRepo.GetSingle(cmd.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionUpdate, task); err != nil {
+		return nil, err
+	}
+
+	workflow, err := h.workflows.ForProject(task.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := h.taskRepo.SubtaskProgress(task.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := task.Transition(cmd.status, workflow, progress[task.ID()]); err != nil {
+		return nil, err
+	}
+	if err := h.hierarchy.CheckStatus(task); err != nil {
+		return nil, err
+	}
+
+	if err := h.taskRepo.Save(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
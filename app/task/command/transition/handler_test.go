@@ -0,0 +1,165 @@
+package transitioncmd_test
+
+import (
+	"testing"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	transitioncmd "github.com/beka-birhanu/task_manager_final/app/task/command/transition"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite defines the test suite for the transitioncmd.Handler.
+type HandlerTestSuite struct {
+	suite.Suite
+	mockTaskRepo  *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	mockPolicy    *taskpolicy_mock.IPolicy
+	handler       icmd.IHandler[*transitioncmd.Command, *taskmodel.Task]
+	actor         taskpolicy.Actor
+	task          *taskmodel.Task
+}
+
+// SetupTest sets up the test environment.
+func (suite *HandlerTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(irepo_mock.Task)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.handler = transitioncmd.NewHandler(transitioncmd.Config{
+		TaskRepo:  suite.mockTaskRepo,
+		Workflows: suite.mockWorkflows,
+		Policy:    suite.mockPolicy,
+	})
+
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
+	suite.task, _ = taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task to move along",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
+	})
+	suite.mockTaskRepo.On("GetSingle", suite.task.ID()).Return(suite.task, nil)
+	suite.mockWorkflows.On("ForProject", "").Return(workflowmodel.Default(), nil)
+}
+
+// TestHandle tests that the task is moved to the status and saved.
+func (suite *HandlerTestSuite) TestHandle() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{suite.task.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+	suite.mockTaskRepo.On("Save", suite.task).Return(nil)
+
+	task, err := suite.handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusInProgress, suite.actor))
+
+	suite.NoError(err)
+	suite.Equal(taskmodel.StatusInProgress, task.Status())
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_NotAllowed tests that transitions the workflow does not allow are refused.
+func (suite *HandlerTestSuite) TestHandle_NotAllowed() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{suite.task.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+
+	task, err := suite.handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusDone, suite.actor))
+
+	suite.Equal(errdmn.TransitionNotAllowed, err)
+	suite.Nil(task)
+	suite.Equal(taskmodel.StatusPending, suite.task.Status())
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_SubtasksOpen tests that a task is not done while some of its subtasks are open.
+func (suite *HandlerTestSuite) TestHandle_SubtasksOpen() {
+	suite.Require().NoError(suite.task.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+	suite.Require().NoError(suite.task.Transition(taskmodel.StatusReview, nil, taskmodel.Progress{}))
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(nil)
+	progress := map[uuid.UUID]taskmodel.Progress{suite.task.ID(): {Done: 1, Total: 2}}
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{suite.task.ID()}).Return(progress, nil)
+
+	task, err := suite.handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusDone, suite.actor))
+
+	suite.Equal(errdmn.SubtasksOpen, err)
+	suite.Nil(task)
+	suite.Equal(taskmodel.StatusReview, suite.task.Status())
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_ParentDone tests that a subtask is not reopened while its parent is done.
+func (suite *HandlerTestSuite) TestHandle_ParentDone() {
+	parent, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Parent",
+		Description: "A task that is done",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusDone,
+		OwnerID:     suite.actor.ID,
+	})
+	child, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Child",
+		Description: "A subtask that is done",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusDone,
+		ParentID:    parent.ID(),
+		OwnerID:     suite.actor.ID,
+	})
+	suite.mockTaskRepo.On("GetSingle", parent.ID()).Return(parent, nil)
+	suite.mockTaskRepo.On("GetSingle", child.ID()).Return(child, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, child).Return(nil)
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{child.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+
+	task, err := suite.handler.Handle(transitioncmd.NewCommand(child.ID(), taskmodel.StatusInProgress, suite.actor))
+
+	suite.Equal(errdmn.ParentDone, err)
+	suite.Nil(task)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Unauthorized tests that the task is not moved when the policy denies the update.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, suite.task).Return(errdmn.TaskNotFound)
+
+	task, err := suite.handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusInProgress, suite.actor))
+
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(task)
+	suite.mockWorkflows.AssertNotCalled(suite.T(), "ForProject", mock.Anything)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Assignee tests that a member the task is assigned to may move it along
+// under the role based policy, while other members may not.
+func (suite *HandlerTestSuite) TestHandle_Assignee() {
+	handler := transitioncmd.NewHandler(transitioncmd.Config{
+		TaskRepo:  suite.mockTaskRepo,
+		Workflows: suite.mockWorkflows,
+		Policy:    taskpolicy.RoleBased{},
+	})
+	assignee := taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	other := taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	suite.Require().NoError(suite.task.Assign(assignee.ID))
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{suite.task.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+	suite.mockTaskRepo.On("Save", suite.task).Return(nil)
+
+	task, err := handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusInProgress, other))
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(task)
+
+	task, err = handler.Handle(transitioncmd.NewCommand(suite.task.ID(), taskmodel.StatusInProgress, assignee))
+	suite.NoError(err)
+	suite.Equal(taskmodel.StatusInProgress, task.Status())
+	suite.mockTaskRepo.AssertNumberOfCalls(suite.T(), "Save", 1)
+}
+
+// Run the test suite
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,23 @@
+package unassigncmd
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Command represents the data needed to remove a user from the assignees of a task.
+type Command struct {
+	id       uuid.UUID
+	username string
+	actor    taskpolicy.Actor
+}
+
+// NewCommand creates a new Command removing the user with the given username from the
+// assignees of the task with the given ID on behalf of the given actor.
+func NewCommand(id uuid.UUID, username string, actor taskpolicy.Actor) *Command {
+	return &Command{
+		id:       id,
+		username: username,
+		actor:    actor,
+	}
+}
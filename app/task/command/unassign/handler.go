@@ -0,0 +1,66 @@
+// Package unassigncmd provides the logic to remove a user from the assignees of a task.
+// It includes a command structure and a handler to process the unassign command.
+//
+// Only the owner of the task, or users who may update any task, can unassign it.
+// Unassigning a user the task is not assigned to, or who does not exist, changes nothing.
+package unassigncmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler is responsible for handling the unassign task command.
+type Handler struct {
+	taskRepo irepo.Task         // Repository for task-related operations.
+	userRepo irepo.User         // Repository the assignees are looked up in.
+	policy   taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	TaskRepo irepo.Task         // Repository for task-related operations.
+	UserRepo irepo.User         // Repository the assignees are looked up in.
+	Policy   taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the provided configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{taskRepo: cfg.TaskRepo, userRepo: cfg.UserRepo, policy: cfg.Policy}
+}
+
+// Handle removes the user from the assignees of the task and returns the updated task.
+func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
+	task, err := h.taskRepo.GetSingle(cmd.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionAssign, task); err != nil {
+		return nil, err
+	}
+
+	// Deleted users were removed from every task already.
+	assignee, err := h.userRepo.ByUsername(cmd.username)
+	if err == errdmn.UserNotFound {
+		return task, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsAssignedTo(assignee.ID()) {
+		return task, nil
+	}
+
+	task.Unassign(assignee.ID())
+	if err := h.taskRepo.Save(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
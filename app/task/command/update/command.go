@@ -3,6 +3,7 @@ package updatecmd
 import (
 	"time"
 
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	"github.com/google/uuid"
 )
 
@@ -12,16 +13,23 @@ type Command struct {
 	title       string
 	description string
 	status      string
+	priority    string
+	tags        []string
 	dueDate     time.Time
+	actor       taskpolicy.Actor
 }
 
-// NewCommand creates a new Command instance with the provided task details.
-func NewCommand(id uuid.UUID, title, description, status string, dueDate time.Time) *Command {
+// NewCommand creates a new Command instance with the provided task details
+// on behalf of the given actor.
+func NewCommand(id uuid.UUID, title, description, status, priority string, tags []string, dueDate time.Time, actor taskpolicy.Actor) *Command {
 	return &Command{
 		id:          id,
 		title:       title,
 		description: description,
 		status:      status,
+		priority:    priority,
+		tags:        tags,
 		dueDate:     dueDate,
+		actor:       actor,
 	}
 }
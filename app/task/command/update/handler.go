@@ -5,19 +5,38 @@ package updatecmd
 import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskhierarchy "github.com/beka-birhanu/task_manager_final/app/task/hierarchy"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 )
 
+// Handler handles the logic for updating a task, whose status changes as its workflow
+// allows and as long as it is not reopened under a parent that is done.
 type Handler struct {
-	repo irepo.Task
+	repo      irepo.Task
+	workflows irepo.Workflow
+	policy    taskpolicy.IPolicy
+	hierarchy *taskhierarchy.Checker
 }
 
 // Ensure Handler implements icmd.IHandler
 var _ icmd.IHandler[*Command, *taskmodel.Task] = &Handler{}
 
-// NewHandler creates a new instance of Handler with the provided task repository.
-func NewHandler(taskRepo irepo.Task) *Handler {
-	return &Handler{repo: taskRepo}
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo      irepo.Task         // Repository for task-related operations.
+	Workflows irepo.Workflow     // Repository for the workflows of projects.
+	Policy    taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// NewHandler creates a new instance of Handler with the provided configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		repo:      cfg.Repo,
+		workflows: cfg.Workflows,
+		policy:    cfg.Policy,
+		hierarchy: taskhierarchy.New(cfg.Repo, cfg.Workflows, cfg.Policy),
+	}
 }
 
 // HandleUpdate handles updating an existing task.
@@ -27,15 +46,36 @@ func (h *Handler) Handle(cmd *Command) (*taskmodel.Task, error) {
 		return nil, err
 	}
 
+	if err := h.policy.Authorize(cmd.actor, taskpolicy.ActionUpdate, task); err != nil {
+		return nil, err
+	}
+
+	workflow, err := h.workflows.ForProject(task.Project())
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := h.repo.SubtaskProgress(task.ID())
+	if err != nil {
+		return nil, err
+	}
+
 	err = task.Update(taskmodel.Config{
 		Title:       cmd.title,
 		Description: cmd.description,
 		DueDate:     cmd.dueDate,
 		Status:      cmd.status,
+		Priority:    cmd.priority,
+		Tags:        cmd.tags,
+		Workflow:    workflow,
+		Subtasks:    progress[task.ID()],
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := h.hierarchy.CheckStatus(task); err != nil {
+		return nil, err
+	}
 
 	err = h.repo.Save(task)
 	if err != nil {
@@ -7,7 +7,11 @@ import (
 
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -16,29 +20,37 @@ import (
 // HandlerTestSuite defines the test suite for the updatecmd.Handler.
 type HandlerTestSuite struct {
 	suite.Suite
-	mockRepo   *irepo_mock.Task
-	handler    icmd.IHandler[*Command, *taskmodel.Task]
-	taskID     uuid.UUID
-	cmdTitle   string
-	cmdDesc    string
-	cmdStatus  string
-	cmdDueDate time.Time
+	mockRepo      *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	mockPolicy    *taskpolicy_mock.IPolicy
+	handler       icmd.IHandler[*Command, *taskmodel.Task]
+	taskID        uuid.UUID
+	cmdTitle      string
+	cmdDesc       string
+	cmdStatus     string
+	cmdDueDate    time.Time
+	actor         taskpolicy.Actor
 }
 
 // SetupTest sets up the test environment.
 func (suite *HandlerTestSuite) SetupTest() {
 	// Initialize the mock repository
 	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.mockWorkflows.On("ForProject", "").Return(workflowmodel.Default(), nil).Maybe()
+	suite.mockRepo.On("SubtaskProgress", mock.Anything).Return(map[uuid.UUID]taskmodel.Progress{}, nil).Maybe()
 
-	// Initialize the handler with the mock repository
-	suite.handler = NewHandler(suite.mockRepo)
+	// Initialize the handler with the mock repositories and policy
+	suite.handler = NewHandler(Config{Repo: suite.mockRepo, Workflows: suite.mockWorkflows, Policy: suite.mockPolicy})
 
 	// Initialize command properties
 	suite.taskID = uuid.New()
 	suite.cmdTitle = "Updated Task"
 	suite.cmdDesc = "This is an updated task"
-	suite.cmdStatus = taskmodel.StatusDone
+	suite.cmdStatus = taskmodel.StatusInProgress
 	suite.cmdDueDate = time.Now().Add(48 * time.Hour)
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
 }
 
 // TestHandle tests the Handle method of the updatecmd.Handler.
@@ -49,14 +61,16 @@ func (suite *HandlerTestSuite) TestHandle() {
 		Description: "This is an old task",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
 	})
 
 	// Set up mock repository behavior
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(existingTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, existingTask).Return(nil)
 	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(nil)
 
 	// Create the command using the properties stored in the suite
-	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", nil, suite.cmdDueDate, suite.actor)
 
 	// Execute the Handle method
 	updatedTask, err := suite.handler.Handle(cmd)
@@ -74,13 +88,37 @@ func (suite *HandlerTestSuite) TestHandle() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestHandle_TransitionNotAllowed tests that the status only changes as the workflow allows.
+func (suite *HandlerTestSuite) TestHandle_TransitionNotAllowed() {
+	existingTask, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Old Task",
+		Description: "This is an old task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
+	})
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(existingTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, existingTask).Return(nil)
+
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, taskmodel.StatusDone, "", nil, suite.cmdDueDate, suite.actor)
+
+	// Execute the Handle method
+	updatedTask, err := suite.handler.Handle(cmd)
+
+	// Assertions
+	suite.Equal(errdmn.TransitionNotAllowed, err)
+	suite.Nil(updatedTask)
+	suite.Equal(taskmodel.StatusPending, existingTask.Status())
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
 // TestHandle_TaskNotFound tests the Handle method when the task to update is not found.
 func (suite *HandlerTestSuite) TestHandle_TaskNotFound() {
 	// Set up mock repository behavior for a non-existent task
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, errors.New("task not found"))
 
 	// Create the command using the properties stored in the suite
-	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", nil, suite.cmdDueDate, suite.actor)
 
 	// Execute the Handle method
 	updatedTask, err := suite.handler.Handle(cmd)
@@ -90,6 +128,30 @@ func (suite *HandlerTestSuite) TestHandle_TaskNotFound() {
 	suite.Nil(updatedTask)
 }
 
+// TestHandle_Unauthorized tests the Handle method when the policy denies the update.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	existingTask, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Old Task",
+		Description: "This is an old task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     uuid.New(),
+	})
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(existingTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, existingTask).Return(errdmn.TaskNotFound)
+
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", nil, suite.cmdDueDate, suite.actor)
+
+	// Execute the Handle method
+	updatedTask, err := suite.handler.Handle(cmd)
+
+	// Assertions
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(updatedTask)
+	suite.Equal("Old Task", existingTask.Title())
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
 // TestHandle_ErrorSavingTask tests the Handle method when saving the updated task fails.
 func (suite *HandlerTestSuite) TestHandle_ErrorSavingTask() {
 	// Create an existing task using the New method
@@ -98,14 +160,16 @@ func (suite *HandlerTestSuite) TestHandle_ErrorSavingTask() {
 		Description: "This is an old task",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
 	})
 
 	// Set up mock repository behavior
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(existingTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionUpdate, existingTask).Return(nil)
 	suite.mockRepo.On("Save", mock.AnythingOfType("*taskmodel.Task")).Return(errors.New("failed to save task"))
 
 	// Create the command using the properties stored in the suite
-	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", nil, suite.cmdDueDate, suite.actor)
 
 	// Execute the Handle method
 	updatedTask, err := suite.handler.Handle(cmd)
@@ -121,7 +185,7 @@ func (suite *HandlerTestSuite) TestHandle_ErrorRetrievingTask() {
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, errors.New("failed to retrieve task"))
 
 	// Create the command using the properties stored in the suite
-	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, suite.cmdDueDate)
+	cmd := NewCommand(suite.taskID, suite.cmdTitle, suite.cmdDesc, suite.cmdStatus, "", nil, suite.cmdDueDate, suite.actor)
 
 	// Execute the Handle method
 	updatedTask, err := suite.handler.Handle(cmd)
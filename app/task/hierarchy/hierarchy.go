@@ -0,0 +1,129 @@
+// Package taskhierarchy checks where a task may be placed in the hierarchy of tasks
+// and subtasks. Handlers consult a Checker before giving a task a parent, so that the
+// parent exists, may be updated by the actor, has room for another subtask, and is not
+// the task itself or one of its subtasks. A parent that is done only takes subtasks
+// that are done too, and its subtasks stay done, so that no task is done while one of
+// its subtasks is open.
+package taskhierarchy
+
+import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+)
+
+// Checker checks parents against the tasks in the repository.
+type Checker struct {
+	repo      irepo.Task         // Repository the parent and its ancestors are looked up in.
+	workflows irepo.Workflow     // Repository for the workflows deciding whether the tasks are done.
+	policy    taskpolicy.IPolicy // Authorization policy deciding whether the actor may update the parent.
+}
+
+// New creates a new Checker with the given task and workflow repositories and policy.
+func New(repo irepo.Task, workflows irepo.Workflow, policy taskpolicy.IPolicy) *Checker {
+	return &Checker{repo: repo, workflows: workflows, policy: policy}
+}
+
+// CheckParent returns nil if the actor may make the task a subtask of the parent, or a
+// domain error describing why not. The task may be yet to be created, in which case
+// it cannot be an ancestor of the parent.
+func (c *Checker) CheckParent(actor taskpolicy.Actor, task *taskmodel.Task, parentID uuid.UUID) error {
+	if parentID == task.ID() {
+		return errdmn.InvalidParent
+	}
+
+	parent, err := c.repo.GetSingle(parentID)
+	if err == errdmn.TaskNotFound {
+		return errdmn.ParentNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := c.policy.Authorize(actor, taskpolicy.ActionUpdate, parent); err == errdmn.TaskNotFound {
+		return errdmn.ParentNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if err := c.checkAncestors(task.ID(), parent); err != nil {
+		return err
+	}
+	if err := c.checkDone(task, parent); err != nil {
+		return err
+	}
+
+	progress, err := c.repo.SubtaskProgress(parentID)
+	if err != nil {
+		return err
+	}
+	if progress[parentID].Total >= taskmodel.MaxSubtasks {
+		return errdmn.TooManySubtasks
+	}
+	return nil
+}
+
+// CheckStatus returns nil if the task may keep its status under its parent, or
+// errdmn.ParentDone when the task is open while its parent is done. Handlers check
+// tasks whose status they changed before saving them.
+func (c *Checker) CheckStatus(task *taskmodel.Task) error {
+	if task.ParentID() == uuid.Nil {
+		return nil
+	}
+
+	parent, err := c.repo.GetSingle(task.ParentID())
+	if err == errdmn.TaskNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return c.checkDone(task, parent)
+}
+
+// checkDone returns an error if the parent is done while the task is not.
+func (c *Checker) checkDone(task, parent *taskmodel.Task) error {
+	done, err := c.isDone(parent)
+	if err != nil || !done {
+		return err
+	}
+	done, err = c.isDone(task)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errdmn.ParentDone
+	}
+	return nil
+}
+
+// isDone reports whether the task is in a final state of the workflow of its project.
+func (c *Checker) isDone(task *taskmodel.Task) (bool, error) {
+	workflow, err := c.workflows.ForProject(task.Project())
+	if err != nil {
+		return false, err
+	}
+	return workflow.IsFinal(task.Status()), nil
+}
+
+// checkAncestors walks up from the parent and returns an error if the task is found
+// among its ancestors, where the task would become a subtask of itself.
+func (c *Checker) checkAncestors(taskID uuid.UUID, parent *taskmodel.Task) error {
+	seen := map[uuid.UUID]bool{parent.ID(): true}
+	for id := parent.ParentID(); id != uuid.Nil; {
+		// A task met twice is a cycle the tasks already form, which only a task
+		// among them could have closed.
+		if id == taskID || seen[id] {
+			return errdmn.ParentCycle
+		}
+		seen[id] = true
+
+		ancestor, err := c.repo.GetSingle(id)
+		if err == errdmn.TaskNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		id = ancestor.ParentID()
+	}
+	return nil
+}
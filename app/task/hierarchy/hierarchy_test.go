@@ -0,0 +1,138 @@
+package taskhierarchy_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskhierarchy "github.com/beka-birhanu/task_manager_final/app/task/hierarchy"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// CheckerTestSuite defines the test suite for the hierarchy Checker.
+type CheckerTestSuite struct {
+	suite.Suite
+	mockRepo      *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	checker       *taskhierarchy.Checker
+	actor         taskpolicy.Actor
+	task          *taskmodel.Task // Task of the actor yet to be created.
+	root          *taskmodel.Task // Top-level task of the actor.
+	child         *taskmodel.Task // Subtask of root.
+}
+
+// SetupTest sets up the test environment.
+func (suite *CheckerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
+	suite.mockWorkflows.On("ForProject", "").Return(workflowmodel.Default(), nil).Maybe()
+	suite.checker = taskhierarchy.New(suite.mockRepo, suite.mockWorkflows, taskpolicy.RoleBased{})
+	suite.actor = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+
+	suite.task = suite.newTask(uuid.Nil, suite.actor.ID)
+	suite.root = suite.newTask(uuid.Nil, suite.actor.ID)
+	suite.child = suite.newTask(suite.root.ID(), suite.actor.ID)
+	suite.mockRepo.On("GetSingle", suite.root.ID()).Return(suite.root, nil).Maybe()
+	suite.mockRepo.On("GetSingle", suite.child.ID()).Return(suite.child, nil).Maybe()
+}
+
+// newTask creates a task of the owner under the parent.
+func (suite *CheckerTestSuite) newTask(parentID, ownerID uuid.UUID) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task in a hierarchy",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		ParentID:    parentID,
+		OwnerID:     ownerID,
+	})
+	suite.Require().NoError(err)
+	return task
+}
+
+// TestCheckParent_Success tests that tasks can be placed under tasks the actor may
+// update with room for them.
+func (suite *CheckerTestSuite) TestCheckParent_Success() {
+	suite.mockRepo.On("SubtaskProgress", []uuid.UUID{suite.child.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+
+	suite.NoError(suite.checker.CheckParent(suite.actor, suite.task, suite.child.ID()))
+
+	other := suite.newTask(uuid.Nil, suite.actor.ID)
+	suite.NoError(suite.checker.CheckParent(suite.actor, other, suite.child.ID()))
+}
+
+// TestCheckParent_Cycle tests that a task cannot be placed under itself or its subtasks.
+func (suite *CheckerTestSuite) TestCheckParent_Cycle() {
+	suite.Equal(errdmn.InvalidParent, suite.checker.CheckParent(suite.actor, suite.root, suite.root.ID()))
+	suite.Equal(errdmn.ParentCycle, suite.checker.CheckParent(suite.actor, suite.root, suite.child.ID()))
+
+	grandchild := suite.newTask(suite.child.ID(), suite.actor.ID)
+	suite.mockRepo.On("GetSingle", grandchild.ID()).Return(grandchild, nil)
+	suite.Equal(errdmn.ParentCycle, suite.checker.CheckParent(suite.actor, suite.root, grandchild.ID()))
+	suite.mockRepo.AssertNotCalled(suite.T(), "SubtaskProgress")
+}
+
+// TestCheckParent_NotFound tests that missing parents and parents the actor cannot see are refused.
+func (suite *CheckerTestSuite) TestCheckParent_NotFound() {
+	missing := uuid.New()
+	suite.mockRepo.On("GetSingle", missing).Return(nil, errdmn.TaskNotFound)
+	suite.Equal(errdmn.ParentNotFound, suite.checker.CheckParent(suite.actor, suite.task, missing))
+
+	hidden := suite.newTask(uuid.Nil, uuid.New())
+	suite.mockRepo.On("GetSingle", hidden.ID()).Return(hidden, nil)
+	suite.Equal(errdmn.ParentNotFound, suite.checker.CheckParent(suite.actor, suite.task, hidden.ID()))
+}
+
+// TestCheckParent_ReadOnly tests that parents the actor can see but not update are refused.
+func (suite *CheckerTestSuite) TestCheckParent_ReadOnly() {
+	viewer := taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Viewer}}
+	suite.Equal(errdmn.PermissionDenied, suite.checker.CheckParent(viewer, suite.task, suite.root.ID()))
+}
+
+// TestCheckParent_Done tests that a parent that is done only takes subtasks that are done.
+func (suite *CheckerTestSuite) TestCheckParent_Done() {
+	for _, status := range []string{taskmodel.StatusInProgress, taskmodel.StatusReview, taskmodel.StatusDone} {
+		suite.Require().NoError(suite.root.Transition(status, nil, taskmodel.Progress{}))
+	}
+	suite.mockRepo.On("SubtaskProgress", []uuid.UUID{suite.root.ID()}).Return(map[uuid.UUID]taskmodel.Progress{}, nil)
+
+	suite.Equal(errdmn.ParentDone, suite.checker.CheckParent(suite.actor, suite.task, suite.root.ID()))
+
+	for _, status := range []string{taskmodel.StatusInProgress, taskmodel.StatusReview, taskmodel.StatusDone} {
+		suite.Require().NoError(suite.task.Transition(status, nil, taskmodel.Progress{}))
+	}
+	suite.NoError(suite.checker.CheckParent(suite.actor, suite.task, suite.root.ID()))
+}
+
+// TestCheckParent_Full tests that a task cannot have more subtasks than allowed.
+func (suite *CheckerTestSuite) TestCheckParent_Full() {
+	full := map[uuid.UUID]taskmodel.Progress{suite.root.ID(): {Total: taskmodel.MaxSubtasks}}
+	suite.mockRepo.On("SubtaskProgress", []uuid.UUID{suite.root.ID()}).Return(full, nil)
+
+	suite.Equal(errdmn.TooManySubtasks, suite.checker.CheckParent(suite.actor, suite.task, suite.root.ID()))
+}
+
+// TestCheckStatus tests that a subtask cannot be reopened while its parent is done.
+func (suite *CheckerTestSuite) TestCheckStatus() {
+	suite.NoError(suite.checker.CheckStatus(suite.root))
+	suite.NoError(suite.checker.CheckStatus(suite.child))
+
+	for _, status := range []string{taskmodel.StatusInProgress, taskmodel.StatusReview, taskmodel.StatusDone} {
+		suite.Require().NoError(suite.child.Transition(status, nil, taskmodel.Progress{}))
+		suite.Require().NoError(suite.root.Transition(status, nil, taskmodel.Progress{}))
+	}
+	suite.NoError(suite.checker.CheckStatus(suite.child))
+
+	suite.Require().NoError(suite.child.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+	suite.Equal(errdmn.ParentDone, suite.checker.CheckStatus(suite.child))
+}
+
+// Run the test suite
+func TestCheckerTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckerTestSuite))
+}
@@ -0,0 +1,18 @@
+package taskpolicy_mock
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/stretchr/testify/mock"
+)
+
+// IPolicy is a mock implementation of the IPolicy interface using testify.
+type IPolicy struct {
+	mock.Mock
+}
+
+// Authorize mocks the Authorize method of the IPolicy interface.
+func (m *IPolicy) Authorize(actor taskpolicy.Actor, action taskpolicy.Action, task *taskmodel.Task) error {
+	args := m.Called(actor, action, task)
+	return args.Error(0)
+}
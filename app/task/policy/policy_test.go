@@ -0,0 +1,134 @@
+package taskpolicy_test
+
+import (
+	"testing"
+	"time"
+
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// actions are the actions performed on existing tasks.
+var actions = []taskpolicy.Action{taskpolicy.ActionView, taskpolicy.ActionUpdate, taskpolicy.ActionDelete, taskpolicy.ActionAssign}
+
+// RoleBasedTestSuite defines the test suite for the RoleBased policy.
+type RoleBasedTestSuite struct {
+	suite.Suite
+	policy  taskpolicy.RoleBased
+	owner   taskpolicy.Actor
+	other   taskpolicy.Actor
+	viewer  taskpolicy.Actor
+	manager taskpolicy.Actor
+	admin   taskpolicy.Actor
+	task    *taskmodel.Task
+}
+
+// SetupTest sets up the test environment.
+func (suite *RoleBasedTestSuite) SetupTest() {
+	suite.owner = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	suite.other = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Member}}
+	suite.viewer = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Viewer}}
+	suite.manager = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Manager}}
+	suite.admin = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Admin}}
+
+	var err error
+	suite.task, err = taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.owner.ID,
+	})
+	suite.Require().NoError(err)
+}
+
+// TestAuthorize_Create tests that only roles granting task:create may create tasks.
+func (suite *RoleBasedTestSuite) TestAuthorize_Create() {
+	suite.NoError(suite.policy.Authorize(suite.other, taskpolicy.ActionCreate, nil))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.viewer, taskpolicy.ActionCreate, nil))
+}
+
+// TestAuthorize_Owner tests that the owner may perform every action on their task.
+func (suite *RoleBasedTestSuite) TestAuthorize_Owner() {
+	for _, action := range actions {
+		suite.NoError(suite.policy.Authorize(suite.owner, action, suite.task), action)
+	}
+}
+
+// TestAuthorize_Any tests that managers and admins may perform every action on any task.
+func (suite *RoleBasedTestSuite) TestAuthorize_Any() {
+	for _, actor := range []taskpolicy.Actor{suite.manager, suite.admin} {
+		for _, action := range actions {
+			suite.NoError(suite.policy.Authorize(actor, action, suite.task), action)
+		}
+	}
+}
+
+// TestAuthorize_Viewer tests that viewers may see any task but not touch it.
+func (suite *RoleBasedTestSuite) TestAuthorize_Viewer() {
+	suite.NoError(suite.policy.Authorize(suite.viewer, taskpolicy.ActionView, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.viewer, taskpolicy.ActionUpdate, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.viewer, taskpolicy.ActionDelete, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.viewer, taskpolicy.ActionAssign, suite.task))
+}
+
+// TestAuthorize_Other tests that other users cannot see or touch the task.
+func (suite *RoleBasedTestSuite) TestAuthorize_Other() {
+	for _, action := range actions {
+		suite.Equal(errdmn.TaskNotFound, suite.policy.Authorize(suite.other, action, suite.task), action)
+	}
+}
+
+// TestAuthorize_Assignee tests that users the task is assigned to may see and update it,
+// but neither reassign nor delete it.
+func (suite *RoleBasedTestSuite) TestAuthorize_Assignee() {
+	suite.Require().NoError(suite.task.Assign(suite.other.ID))
+
+	suite.NoError(suite.policy.Authorize(suite.other, taskpolicy.ActionView, suite.task))
+	suite.NoError(suite.policy.Authorize(suite.other, taskpolicy.ActionUpdate, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.other, taskpolicy.ActionAssign, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.other, taskpolicy.ActionDelete, suite.task))
+}
+
+// TestAuthorize_AssignedViewer tests that a viewer the task is assigned to may see it
+// but, lacking task:update, not update it.
+func (suite *RoleBasedTestSuite) TestAuthorize_AssignedViewer() {
+	suite.Require().NoError(suite.task.Assign(suite.viewer.ID))
+
+	suite.NoError(suite.policy.Authorize(suite.viewer, taskpolicy.ActionView, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(suite.viewer, taskpolicy.ActionUpdate, suite.task))
+}
+
+// TestAuthorize_NoRole tests that an actor without a known role may do nothing.
+func (suite *RoleBasedTestSuite) TestAuthorize_NoRole() {
+	actor := taskpolicy.Actor{ID: suite.owner.ID, Roles: []rolemodel.Role{"root"}}
+
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(actor, taskpolicy.ActionCreate, nil))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(actor, taskpolicy.ActionView, suite.task))
+}
+
+// TestAuthorize_Scopes tests that an actor limited to scopes may only use the
+// permissions both granted by their roles and in scope.
+func (suite *RoleBasedTestSuite) TestAuthorize_Scopes() {
+	manager := suite.manager
+	manager.ID = suite.owner.ID
+	manager.Scopes = []rolemodel.Permission{rolemodel.TaskRead, rolemodel.TaskUpdate}
+
+	suite.NoError(suite.policy.Authorize(manager, taskpolicy.ActionView, suite.task))
+	suite.NoError(suite.policy.Authorize(manager, taskpolicy.ActionUpdate, suite.task))
+	suite.Equal(errdmn.PermissionDenied, suite.policy.Authorize(manager, taskpolicy.ActionDelete, suite.task))
+
+	// task:update:any is granted by the role but not in scope.
+	other := suite.manager
+	other.Scopes = manager.Scopes
+	suite.Equal(errdmn.TaskNotFound, suite.policy.Authorize(other, taskpolicy.ActionUpdate, suite.task))
+}
+
+// Run the test suite
+func TestRoleBasedTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleBasedTestSuite))
+}
@@ -7,30 +7,38 @@ import (
 
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
 	getqry "github.com/beka-birhanu/task_manager_final/app/task/query/get"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 // HandlerTestSuite defines the test suite for the getqry.Handler.
 type HandlerTestSuite struct {
 	suite.Suite
-	mockRepo *irepo_mock.Task
-	handler  icmd.IHandler[uuid.UUID, *taskmodel.Task]
-	taskID   uuid.UUID
+	mockRepo   *irepo_mock.Task
+	mockPolicy *taskpolicy_mock.IPolicy
+	handler    icmd.IHandler[*getqry.Query, *taskmodel.Task]
+	taskID     uuid.UUID
+	actor      taskpolicy.Actor
 }
 
 // SetupTest sets up the test environment.
 func (suite *HandlerTestSuite) SetupTest() {
 	// Initialize the mock repository
 	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
 
-	// Initialize the handler with the mock repository
-	suite.handler = getqry.New(suite.mockRepo)
+	// Initialize the handler with the mock repository and policy
+	suite.handler = getqry.New(getqry.Config{Repo: suite.mockRepo, Policy: suite.mockPolicy})
 
 	// Initialize a task ID for testing
 	suite.taskID = uuid.New()
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
 }
 
 // TestHandle tests the Handle method of the getqry.Handler for successful retrieval.
@@ -41,13 +49,15 @@ func (suite *HandlerTestSuite) TestHandle_Success() {
 		Description: "This is a mock task",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.actor.ID,
 	})
 
 	// Set up expected behavior for the mock repository
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(expectedTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, expectedTask).Return(nil)
 
 	// Execute the Handle method
-	task, err := suite.handler.Handle(suite.taskID)
+	task, err := suite.handler.Handle(getqry.NewQuery(suite.taskID, suite.actor))
 
 	// Assertions
 	suite.NoError(err)
@@ -68,7 +78,7 @@ func (suite *HandlerTestSuite) TestHandle_ErrorRetrievingTask() {
 	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, errors.New("failed to retrieve task"))
 
 	// Execute the Handle method
-	task, err := suite.handler.Handle(suite.taskID)
+	task, err := suite.handler.Handle(getqry.NewQuery(suite.taskID, suite.actor))
 
 	// Assertions
 	suite.Error(err)
@@ -82,20 +92,41 @@ func (suite *HandlerTestSuite) TestHandle_ErrorRetrievingTask() {
 // TestHandle_TaskNotFound tests the Handle method when the task is not found in the repository.
 func (suite *HandlerTestSuite) TestHandle_TaskNotFound() {
 	// Set up expected behavior for the mock repository
-	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, nil)
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(nil, errdmn.TaskNotFound)
 
 	// Execute the Handle method
-	task, err := suite.handler.Handle(suite.taskID)
+	task, err := suite.handler.Handle(getqry.NewQuery(suite.taskID, suite.actor))
 
 	// Assertions
-	suite.NoError(err)
+	suite.Equal(errdmn.TaskNotFound, err)
 	suite.Nil(task)
+	suite.mockPolicy.AssertNotCalled(suite.T(), "Authorize", mock.Anything, mock.Anything, mock.Anything)
 
 	// Verify that the GetSingle method was called on the repository with the expected ID
 	suite.mockRepo.AssertCalled(suite.T(), "GetSingle", suite.taskID)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestHandle_Unauthorized tests the Handle method when the policy hides the task from the actor.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	foreignTask, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Foreign Task",
+		Description: "This task belongs to someone else",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     uuid.New(),
+	})
+	suite.mockRepo.On("GetSingle", suite.taskID).Return(foreignTask, nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, foreignTask).Return(errdmn.TaskNotFound)
+
+	// Execute the Handle method
+	task, err := suite.handler.Handle(getqry.NewQuery(suite.taskID, suite.actor))
+
+	// Assertions
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(task)
+}
+
 // Run the test suite
 func TestHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(HandlerTestSuite))
@@ -5,24 +5,39 @@ package getqry
 import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
-	"github.com/google/uuid"
 )
 
 // Handler is responsible for handling the Get task query by its ID.
 type Handler struct {
-	repo irepo.Task // Repository for task-related operations.
+	repo   irepo.Task         // Repository for task-related operations.
+	policy taskpolicy.IPolicy // Authorization policy for tasks.
 }
 
 // Ensure Handler implements the IHandler interface
-var _ icmd.IHandler[uuid.UUID, *taskmodel.Task] = &Handler{}
+var _ icmd.IHandler[*Query, *taskmodel.Task] = &Handler{}
 
-// New creates a new instance of Handler with the provided task repository.
-func New(taskRepo irepo.Task) *Handler {
-	return &Handler{repo: taskRepo}
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo   irepo.Task         // Repository for task-related operations.
+	Policy taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// New creates a new instance of Handler with the provided configuration.
+func New(cfg Config) *Handler {
+	return &Handler{repo: cfg.Repo, policy: cfg.Policy}
 }
 
 // Handle processes the Get query by its ID and returns the corresponding task.
-func (h *Handler) Handle(id uuid.UUID) (*taskmodel.Task, error) {
-	return h.repo.GetSingle(id)
+func (h *Handler) Handle(qry *Query) (*taskmodel.Task, error) {
+	task, err := h.repo.GetSingle(qry.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(qry.actor, taskpolicy.ActionView, task); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
@@ -1,27 +1,139 @@
-// Package getallqry provides the logic to retrieve all tasks from the repository.
-// It includes a handler that processes the GetAll query and returns a list of tasks.
+// Package getallqry provides the logic to list tasks from the repository.
+// It includes a handler that processes the GetAll query and returns a page of the tasks
+// visible to the requester, filtered and sorted as requested.
 package getallqry
 
 import (
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+)
+
+const (
+	// DefaultLimit is the page size used when the query does not specify one.
+	DefaultLimit = 20
+
+	// MaxLimit is the largest page size a query may request.
+	MaxLimit = 100
 )
 
 // Handler is responsible for handling the GetAll tasks query.
 type Handler struct {
-	repo irepo.Task
+	repo      irepo.Task     // Repository for task-related operations.
+	workflows irepo.Workflow // Repository for the workflows of projects.
 }
 
 // Ensure Handler implements the IHandler interface
-var _ icmd.IHandler[struct{}, []*taskmodel.Task] = &Handler{}
+var _ icmd.IHandler[*Query, *irepo.TaskPage] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo      irepo.Task     // Repository for task-related operations.
+	Workflows irepo.Workflow // Repository for the workflows of projects.
+}
+
+// New creates a new instance of Handler with the provided configuration.
+func New(cfg Config) *Handler {
+	return &Handler{repo: cfg.Repo, workflows: cfg.Workflows}
+}
+
+// Handle processes the GetAll query and returns a page of the tasks visible to the requester.
+func (h *Handler) Handle(qry *Query) (*irepo.TaskPage, error) {
+	listing, err := toListTasks(qry)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != "" {
+		if err := h.checkStatus(listing.Status); err != nil {
+			return nil, err
+		}
+	}
+	return h.repo.List(listing)
+}
 
-// New creates a new instance of Handler with the provided task repository.
-func New(taskRepo irepo.Task) *Handler {
-	return &Handler{repo: taskRepo}
+// checkStatus rejects a status that is a state of neither the default workflow nor
+// the workflow of any project, which no task can have.
+func (h *Handler) checkStatus(status string) error {
+	if workflowmodel.Default().HasState(status) {
+		return nil
+	}
+
+	workflows, err := h.workflows.List()
+	if err != nil {
+		return err
+	}
+	for _, workflow := range workflows {
+		if workflow.HasState(status) {
+			return nil
+		}
+	}
+	return errdmn.InvalidStatus
 }
 
-// Handle processes the GetAll command and returns a list of tasks.
-func (h *Handler) Handle(_ struct{}) ([]*taskmodel.Task, error) {
-	return h.repo.GetAll()
+// toListTasks validates the query, applies defaults, and scopes it to the tasks
+// the requester is allowed to see.
+func toListTasks(qry *Query) (irepo.ListTasks, error) {
+	listing := irepo.ListTasks{
+		Status:    qry.Status,
+		DueAfter:  qry.DueAfter,
+		DueBefore: qry.DueBefore,
+		Text:      qry.Text,
+		SortBy:    qry.SortBy,
+		Limit:     qry.Limit,
+		Cursor:    qry.Cursor,
+	}
+
+	// Tasks assigned to the actor are visible to them, whoever owns them.
+	if qry.AssignedToMe {
+		listing.AssigneeID = qry.Actor.ID
+	} else if !qry.Actor.Can(rolemodel.TaskReadAny) {
+		listing.VisibleTo = qry.Actor.ID
+	}
+
+	switch listing.SortBy {
+	case "":
+		listing.SortBy = irepo.TaskSortByPriority
+	case irepo.TaskSortByPriority, irepo.TaskSortByDueDate, irepo.TaskSortByTitle, irepo.TaskSortByStatus:
+	default:
+		return irepo.ListTasks{}, errdmn.InvalidSortField
+	}
+
+	switch qry.SortOrder {
+	case "", SortAsc:
+	case SortDesc:
+		listing.SortDesc = true
+	default:
+		return irepo.ListTasks{}, errdmn.InvalidSortOrder
+	}
+
+	if len(qry.Tags) > 0 {
+		tags, err := taskmodel.NormalizeTags(qry.Tags)
+		if err != nil {
+			return irepo.ListTasks{}, err
+		}
+		listing.Tags = tags
+	}
+
+	switch qry.TagMatch {
+	case "", TagMatchAny:
+	case TagMatchAll:
+		listing.AllTags = true
+	default:
+		return irepo.ListTasks{}, errdmn.InvalidTagMatch
+	}
+
+	if listing.Limit == 0 {
+		listing.Limit = DefaultLimit
+	} else if listing.Limit < 0 || listing.Limit > MaxLimit {
+		return irepo.ListTasks{}, errdmn.InvalidPageLimit
+	}
+
+	if !listing.DueAfter.IsZero() && !listing.DueBefore.IsZero() && listing.DueBefore.Before(listing.DueAfter) {
+		return irepo.ListTasks{}, errdmn.InvalidDueDateRange
+	}
+
+	return listing, nil
 }
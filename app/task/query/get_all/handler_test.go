@@ -6,29 +6,43 @@ import (
 	"time"
 
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	getallqry "github.com/beka-birhanu/task_manager_final/app/task/query/get_all"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 // HandlerTestSuite defines the test suite for the getallqry.Handler.
 type HandlerTestSuite struct {
 	suite.Suite
-	mockRepo *irepo_mock.Task
-	handler  icmd.IHandler[struct{}, []*taskmodel.Task]
+	mockRepo      *irepo_mock.Task
+	mockWorkflows *irepo_mock.Workflow
+	handler       icmd.IHandler[*getallqry.Query, *irepo.TaskPage]
+	owner         taskpolicy.Actor
+	admin         taskpolicy.Actor
 }
 
 // SetupTest sets up the test environment.
 func (suite *HandlerTestSuite) SetupTest() {
 	// Initialize the mock repository
 	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockWorkflows = new(irepo_mock.Workflow)
 
-	// Initialize the handler with the mock repository
-	suite.handler = getallqry.New(suite.mockRepo)
+	// Initialize the handler with the mock repositories
+	suite.handler = getallqry.New(getallqry.Config{Repo: suite.mockRepo, Workflows: suite.mockWorkflows})
+
+	suite.owner = taskpolicy.Actor{ID: uuid.New()}
+	suite.admin = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Admin}}
 }
 
-// TestHandle_Success tests the Handle method of the getallqry.Handler for successful retrieval.
+// TestHandle_Success tests the Handle method of the getallqry.Handler for successful retrieval by an admin.
 func (suite *HandlerTestSuite) TestHandle_Success() {
 	// Create mock tasks
 	task1, _ := taskmodel.New(taskmodel.Config{
@@ -36,45 +50,205 @@ func (suite *HandlerTestSuite) TestHandle_Success() {
 		Description: "First task",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.owner.ID,
 	})
 	task2, _ := taskmodel.New(taskmodel.Config{
 		Title:       "Task 2",
 		Description: "Second task",
 		DueDate:     time.Now().Add(48 * time.Hour),
 		Status:      taskmodel.StatusInProgress,
+		OwnerID:     suite.owner.ID,
 	})
 
-	// Set up expected behavior for the mock repository
-	suite.mockRepo.On("GetAll").Return([]*taskmodel.Task{task1, task2}, nil)
+	// An admin listing with no parameters is unscoped and uses the defaults
+	expected := irepo.ListTasks{
+		SortBy: irepo.TaskSortByPriority,
+		Limit:  getallqry.DefaultLimit,
+	}
+	suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{Tasks: []*taskmodel.Task{task1, task2}}, nil)
 
 	// Execute the Handle method
-	tasks, err := suite.handler.Handle(struct{}{})
+	page, err := suite.handler.Handle(&getallqry.Query{Actor: suite.admin})
+
+	// Assertions
+	suite.NoError(err)
+	suite.Len(page.Tasks, 2)
+	suite.Equal(task1.Title(), page.Tasks[0].Title())
+	suite.Equal(task2.Title(), page.Tasks[1].Title())
+	suite.Empty(page.NextCursor)
+
+	// Verify that the List method was called on the repository
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Owner tests that a non-admin listing is scoped to the tasks they can see.
+func (suite *HandlerTestSuite) TestHandle_Owner() {
+	dueAfter := time.Now()
+	dueBefore := dueAfter.Add(72 * time.Hour)
+
+	expected := irepo.ListTasks{
+		VisibleTo: suite.owner.ID,
+		Status:    taskmodel.StatusPending,
+		DueAfter:  dueAfter,
+		DueBefore: dueBefore,
+		Text:      "report",
+		SortBy:    irepo.TaskSortByTitle,
+		SortDesc:  true,
+		Limit:     5,
+		Cursor:    "next",
+	}
+	suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{NextCursor: "after"}, nil)
+
+	// Execute the Handle method
+	page, err := suite.handler.Handle(&getallqry.Query{
+		Actor:     suite.owner,
+		Status:    taskmodel.StatusPending,
+		DueAfter:  dueAfter,
+		DueBefore: dueBefore,
+		Text:      "report",
+		SortBy:    irepo.TaskSortByTitle,
+		SortOrder: getallqry.SortDesc,
+		Limit:     5,
+		Cursor:    "next",
+	})
 
 	// Assertions
 	suite.NoError(err)
-	suite.Len(tasks, 2)
-	suite.Equal(task1.Title(), tasks[0].Title())
-	suite.Equal(task2.Title(), tasks[1].Title())
+	suite.Equal("after", page.NextCursor)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Assignee tests that the default listing of a user includes the tasks
+// assigned to them by others, whose owner they are not.
+func (suite *HandlerTestSuite) TestHandle_Assignee() {
+	assignee := taskpolicy.Actor{ID: uuid.New()}
+	task, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Task 1",
+		Description: "First task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.owner.ID,
+	})
+	suite.Require().NoError(task.Assign(assignee.ID))
+
+	expected := irepo.ListTasks{
+		VisibleTo: assignee.ID,
+		SortBy:    irepo.TaskSortByPriority,
+		Limit:     getallqry.DefaultLimit,
+	}
+	suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{Tasks: []*taskmodel.Task{task}}, nil)
+
+	page, err := suite.handler.Handle(&getallqry.Query{Actor: assignee})
+
+	suite.NoError(err)
+	suite.Equal([]*taskmodel.Task{task}, page.Tasks)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_AssignedToMe tests that a listing of the tasks assigned to the requester
+// is scoped to those tasks rather than to the ones they own.
+func (suite *HandlerTestSuite) TestHandle_AssignedToMe() {
+	for _, actor := range []taskpolicy.Actor{suite.owner, suite.admin} {
+		expected := irepo.ListTasks{
+			AssigneeID: actor.ID,
+			SortBy:     irepo.TaskSortByPriority,
+			Limit:      getallqry.DefaultLimit,
+		}
+		suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{}, nil).Once()
+
+		_, err := suite.handler.Handle(&getallqry.Query{Actor: actor, AssignedToMe: true})
 
-	// Verify that the GetAll method was called on the repository
-	suite.mockRepo.AssertCalled(suite.T(), "GetAll")
+		suite.NoError(err)
+	}
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// TestHandle_Tags tests that the tags of a listing are normalized and matched as requested.
+func (suite *HandlerTestSuite) TestHandle_Tags() {
+	expected := irepo.ListTasks{
+		VisibleTo: suite.owner.ID,
+		Tags:      []string{"back-end", "bug"},
+		AllTags:   true,
+		SortBy:    irepo.TaskSortByPriority,
+		Limit:     getallqry.DefaultLimit,
+	}
+	suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{}, nil)
+
+	_, err := suite.handler.Handle(&getallqry.Query{
+		Actor:    suite.owner,
+		Tags:     []string{"Bug", "Back End", "bug"},
+		TagMatch: getallqry.TagMatchAll,
+	})
+
+	suite.NoError(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_ProjectStatus tests that a status only the workflow of some project has is accepted.
+func (suite *HandlerTestSuite) TestHandle_ProjectStatus() {
+	workflow, _ := workflowmodel.New(workflowmodel.Config{Project: "support", States: []string{"open", "closed"}})
+	suite.mockWorkflows.On("List").Return([]*workflowmodel.Workflow{workflow}, nil)
+
+	expected := irepo.ListTasks{
+		VisibleTo: suite.owner.ID,
+		Status:    "closed",
+		SortBy:    irepo.TaskSortByPriority,
+		Limit:     getallqry.DefaultLimit,
+	}
+	suite.mockRepo.On("List", expected).Return(&irepo.TaskPage{}, nil)
+
+	_, err := suite.handler.Handle(&getallqry.Query{Actor: suite.owner, Status: "closed"})
+
+	suite.NoError(err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_UnknownStatus tests that a status no workflow has is rejected rather than matching no task.
+func (suite *HandlerTestSuite) TestHandle_UnknownStatus() {
+	workflow, _ := workflowmodel.New(workflowmodel.Config{Project: "support", States: []string{"open", "closed"}})
+	suite.mockWorkflows.On("List").Return([]*workflowmodel.Workflow{workflow}, nil)
+
+	page, err := suite.handler.Handle(&getallqry.Query{Actor: suite.owner, Status: "pendng"})
+
+	suite.Equal(errdmn.InvalidStatus, err)
+	suite.Nil(page)
+	suite.mockRepo.AssertNotCalled(suite.T(), "List", mock.Anything)
+}
+
+// TestHandle_InvalidQuery tests that malformed queries are rejected before reaching the repository.
+func (suite *HandlerTestSuite) TestHandle_InvalidQuery() {
+	now := time.Now()
+	cases := map[*getallqry.Query]error{
+		{Actor: suite.owner, SortBy: "owner"}:                                   errdmn.InvalidSortField,
+		{Actor: suite.owner, SortOrder: "sideways"}:                             errdmn.InvalidSortOrder,
+		{Actor: suite.owner, Limit: getallqry.MaxLimit + 1}:                     errdmn.InvalidPageLimit,
+		{Actor: suite.owner, Limit: -1}:                                         errdmn.InvalidPageLimit,
+		{Actor: suite.owner, DueAfter: now, DueBefore: now.Add(-1 * time.Hour)}: errdmn.InvalidDueDateRange,
+		{Actor: suite.owner, Tags: []string{"c++"}}:                             errdmn.InvalidTag,
+		{Actor: suite.owner, TagMatch: "none"}:                                  errdmn.InvalidTagMatch,
+	}
+
+	for qry, expectedErr := range cases {
+		page, err := suite.handler.Handle(qry)
+		suite.Equal(expectedErr, err)
+		suite.Nil(page)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "List", mock.Anything)
+}
+
 // TestHandle_ErrorRetrievingTasks tests the Handle method when retrieving tasks fails.
 func (suite *HandlerTestSuite) TestHandle_ErrorRetrievingTasks() {
 	// Set up expected behavior for the mock repository
-	suite.mockRepo.On("GetAll").Return(nil, errors.New("failed to retrieve tasks"))
+	suite.mockRepo.On("List", mock.Anything).Return(nil, errors.New("failed to retrieve tasks"))
 
 	// Execute the Handle method
-	tasks, err := suite.handler.Handle(struct{}{})
+	page, err := suite.handler.Handle(&getallqry.Query{Actor: suite.admin})
 
 	// Assertions
 	suite.Error(err)
-	suite.Nil(tasks)
+	suite.Nil(page)
 
-	// Verify that the GetAll method was called on the repository
-	suite.mockRepo.AssertCalled(suite.T(), "GetAll")
+	// Verify that the List method was called on the repository
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
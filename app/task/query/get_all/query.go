@@ -0,0 +1,36 @@
+package getallqry
+
+import (
+	"time"
+
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+)
+
+// Sort orders accepted by the query.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Tag matches accepted by the query.
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// Query represents a filtered, sorted, and paginated listing of the tasks visible to a user.
+// Zero values leave the corresponding filter unset or fall back to a default.
+type Query struct {
+	Actor        taskpolicy.Actor // User listing the tasks; only their own unless granted task:read:any.
+	AssignedToMe bool             // Only tasks assigned to the actor, whoever owns them.
+	Status       string           // Only tasks with this status, which must be a state of some workflow.
+	Tags         []string         // Only tasks with these tags, normalized before matching.
+	TagMatch     string           // TagMatchAny or TagMatchAll tasks with every tag; defaults to TagMatchAny.
+	DueAfter     time.Time        // Only tasks due at or after this time.
+	DueBefore    time.Time        // Only tasks due at or before this time.
+	Text         string           // Case-insensitive match against the title or description.
+	SortBy       string           // Field to sort by; defaults to the priority, then the due date.
+	SortOrder    string           // SortAsc or SortDesc; defaults to SortAsc.
+	Limit        int              // Page size; defaults to DefaultLimit.
+	Cursor       string           // Token from a previous page's NextCursor.
+}
@@ -0,0 +1,33 @@
+// Package progressqry provides the query and handler rolling up the subtasks of tasks,
+// how many each has and how many of them are done, so responses can show the progress
+// of the tasks that were broken down.
+package progressqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+)
+
+// Handler handles subtask progress queries.
+type Handler struct {
+	taskRepo irepo.Task // Repository the subtasks are counted in.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, map[uuid.UUID]taskmodel.Progress] = &Handler{}
+
+// New creates a new Handler with the given task repository.
+func New(taskRepo irepo.Task) *Handler {
+	return &Handler{taskRepo: taskRepo}
+}
+
+// Handle returns the progress of the subtasks of the tasks of the query by task ID.
+// Tasks without subtasks are left out.
+func (h *Handler) Handle(qry *Query) (map[uuid.UUID]taskmodel.Progress, error) {
+	if len(qry.IDs) == 0 {
+		return map[uuid.UUID]taskmodel.Progress{}, nil
+	}
+	return h.taskRepo.SubtaskProgress(qry.IDs...)
+}
@@ -0,0 +1,58 @@
+package progressqry_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	progressqry "github.com/beka-birhanu/task_manager_final/app/task/query/progress"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ProgressHandlerTestSuite defines the test suite for the progress handler.
+type ProgressHandlerTestSuite struct {
+	suite.Suite
+	mockTaskRepo *irepo_mock.Task
+	handler      *progressqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ProgressHandlerTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(irepo_mock.Task)
+	suite.handler = progressqry.New(suite.mockTaskRepo)
+}
+
+// TestHandle tests that the subtasks of the tasks of the query are rolled up by task ID.
+func (suite *ProgressHandlerTestSuite) TestHandle() {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task broken down",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		OwnerID:     uuid.New(),
+	})
+	suite.Require().NoError(err)
+	expected := map[uuid.UUID]taskmodel.Progress{task.ID(): {Done: 1, Total: 3}}
+	suite.mockTaskRepo.On("SubtaskProgress", []uuid.UUID{task.ID()}).Return(expected, nil)
+
+	progress, err := suite.handler.Handle(progressqry.NewQuery(task))
+
+	suite.NoError(err)
+	suite.Equal(expected, progress)
+}
+
+// TestHandle_NoTasks tests that no subtasks are counted for no tasks.
+func (suite *ProgressHandlerTestSuite) TestHandle_NoTasks() {
+	progress, err := suite.handler.Handle(progressqry.NewQuery())
+
+	suite.NoError(err)
+	suite.Empty(progress)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "SubtaskProgress", mock.Anything)
+}
+
+// Run the test suite
+func TestProgressHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressHandlerTestSuite))
+}
@@ -0,0 +1,20 @@
+package progressqry
+
+import (
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+)
+
+// Query represents the data required to roll up the subtasks of tasks.
+type Query struct {
+	IDs []uuid.UUID // IDs of the tasks whose subtasks are rolled up.
+}
+
+// NewQuery creates a Query for the given tasks.
+func NewQuery(tasks ...*taskmodel.Task) *Query {
+	qry := &Query{}
+	for _, task := range tasks {
+		qry.IDs = append(qry.IDs, task.ID())
+	}
+	return qry
+}
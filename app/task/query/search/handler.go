@@ -0,0 +1,77 @@
+// Package searchqry provides the logic to search tasks by keyword.
+// It includes a handler that processes the Search query and returns the matching
+// tasks visible to the requester, most relevant first, with the matched terms highlighted.
+package searchqry
+
+import (
+	"strings"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+const (
+	// DefaultLimit is the number of results returned when the query does not specify one.
+	DefaultLimit = 20
+
+	// MaxLimit is the largest number of results a query may request.
+	MaxLimit = 100
+)
+
+// Result is a task matched by a search.
+type Result struct {
+	Task       *taskmodel.Task
+	Score      float64    // Relevance score; higher is a better match.
+	Highlights Highlights // Matched terms marked up in the task's text fields.
+}
+
+// Handler is responsible for handling the Search tasks query.
+type Handler struct {
+	repo irepo.Task
+}
+
+// Ensure Handler implements the IHandler interface
+var _ icmd.IHandler[*Query, []*Result] = &Handler{}
+
+// New creates a new instance of Handler with the provided task repository.
+func New(taskRepo irepo.Task) *Handler {
+	return &Handler{repo: taskRepo}
+}
+
+// Handle processes the Search query and returns the matching tasks visible to the requester.
+func (h *Handler) Handle(qry *Query) ([]*Result, error) {
+	text := strings.TrimSpace(qry.text)
+	if text == "" {
+		return nil, errdmn.SearchQueryEmpty
+	}
+
+	search := irepo.SearchTasks{Text: text, Limit: qry.limit}
+	if search.Limit == 0 {
+		search.Limit = DefaultLimit
+	} else if search.Limit < 0 || search.Limit > MaxLimit {
+		return nil, errdmn.InvalidPageLimit
+	}
+
+	if !qry.actor.Can(rolemodel.TaskReadAny) {
+		search.VisibleTo = qry.actor.ID
+	}
+
+	matches, err := h.repo.Search(search)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(text)
+	results := make([]*Result, 0, len(matches))
+	for _, match := range matches {
+		results = append(results, &Result{
+			Task:       match.Task,
+			Score:      match.Score,
+			Highlights: highlight(match.Task, terms),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,148 @@
+package searchqry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	searchqry "github.com/beka-birhanu/task_manager_final/app/task/query/search"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite defines the test suite for the searchqry.Handler.
+type HandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Task
+	handler  icmd.IHandler[*searchqry.Query, []*searchqry.Result]
+	owner    taskpolicy.Actor
+	admin    taskpolicy.Actor
+	task     *taskmodel.Task
+}
+
+// SetupTest sets up the test environment.
+func (suite *HandlerTestSuite) SetupTest() {
+	// Initialize the mock repository
+	suite.mockRepo = new(irepo_mock.Task)
+
+	// Initialize the handler with the mock repository
+	suite.handler = searchqry.New(suite.mockRepo)
+
+	suite.owner = taskpolicy.Actor{ID: uuid.New()}
+	suite.admin = taskpolicy.Actor{ID: uuid.New(), Roles: []rolemodel.Role{rolemodel.Admin}}
+	suite.task, _ = taskmodel.New(taskmodel.Config{
+		Title:       "Quarterly Reports",
+		Description: "Send the <draft> report to finance",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     suite.owner.ID,
+	})
+}
+
+// TestHandle_Success tests that results keep their score and carry highlighted fields.
+func (suite *HandlerTestSuite) TestHandle_Success() {
+	expected := irepo.SearchTasks{VisibleTo: suite.owner.ID, Text: "report", Limit: searchqry.DefaultLimit}
+	suite.mockRepo.On("Search", expected).Return([]*irepo.TaskSearchResult{{Task: suite.task, Score: 2.5}}, nil)
+
+	// Execute the Handle method
+	results, err := suite.handler.Handle(searchqry.NewQuery("  report ", 0, suite.owner))
+
+	// Assertions
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal(suite.task, results[0].Task)
+	suite.Equal(2.5, results[0].Score)
+	suite.Equal("Quarterly <mark>Reports</mark>", results[0].Highlights.Title)
+	suite.Equal("Send the &lt;draft&gt; <mark>report</mark> to finance", results[0].Highlights.Description)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Highlights tests how the search terms are matched against the task's text.
+func (suite *HandlerTestSuite) TestHandle_Highlights() {
+	cases := map[string]searchqry.Highlights{
+		// Phrases are matched whole and negated terms are not highlighted.
+		`"to finance" -quarterly`: {Description: "Send the &lt;draft&gt; report <mark>to finance</mark>"},
+		// Terms only match at the start of a word.
+		"port": {},
+		// Every matching word is highlighted.
+		"quarter send": {Title: "<mark>Quarterly</mark> Reports", Description: "<mark>Send</mark> the &lt;draft&gt; report to finance"},
+	}
+
+	for text, expected := range cases {
+		suite.mockRepo.On("Search", mock.MatchedBy(func(search irepo.SearchTasks) bool { return search.Text == text })).
+			Return([]*irepo.TaskSearchResult{{Task: suite.task}}, nil)
+
+		results, err := suite.handler.Handle(searchqry.NewQuery(text, 0, suite.admin))
+		suite.NoError(err)
+		suite.Equal(expected, results[0].Highlights, text)
+	}
+}
+
+// TestHandle_Assignee tests that the search of a user covers the tasks assigned to them
+// by others, whose owner they are not.
+func (suite *HandlerTestSuite) TestHandle_Assignee() {
+	assignee := taskpolicy.Actor{ID: uuid.New()}
+	suite.Require().NoError(suite.task.Assign(assignee.ID))
+
+	expected := irepo.SearchTasks{VisibleTo: assignee.ID, Text: "report", Limit: searchqry.DefaultLimit}
+	suite.mockRepo.On("Search", expected).Return([]*irepo.TaskSearchResult{{Task: suite.task, Score: 1}}, nil)
+
+	results, err := suite.handler.Handle(searchqry.NewQuery("report", 0, assignee))
+
+	suite.NoError(err)
+	suite.Len(results, 1)
+	suite.Equal(suite.task, results[0].Task)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Admin tests that an admin search is not scoped to the tasks they can see.
+func (suite *HandlerTestSuite) TestHandle_Admin() {
+	expected := irepo.SearchTasks{Text: "report", Limit: 5}
+	suite.mockRepo.On("Search", expected).Return([]*irepo.TaskSearchResult{}, nil)
+
+	results, err := suite.handler.Handle(searchqry.NewQuery("report", 5, suite.admin))
+
+	suite.NoError(err)
+	suite.Empty(results)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_InvalidQuery tests that malformed queries are rejected before reaching the repository.
+func (suite *HandlerTestSuite) TestHandle_InvalidQuery() {
+	cases := map[*searchqry.Query]error{
+		searchqry.NewQuery("   ", 0, suite.owner):                       errdmn.SearchQueryEmpty,
+		searchqry.NewQuery("report", -1, suite.owner):                   errdmn.InvalidPageLimit,
+		searchqry.NewQuery("report", searchqry.MaxLimit+1, suite.owner): errdmn.InvalidPageLimit,
+	}
+
+	for qry, expectedErr := range cases {
+		results, err := suite.handler.Handle(qry)
+		suite.Equal(expectedErr, err)
+		suite.Nil(results)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "Search", mock.Anything)
+}
+
+// TestHandle_ErrorSearching tests the Handle method when the search fails.
+func (suite *HandlerTestSuite) TestHandle_ErrorSearching() {
+	suite.mockRepo.On("Search", mock.Anything).Return(nil, errors.New("failed to search tasks"))
+
+	results, err := suite.handler.Handle(searchqry.NewQuery("report", 0, suite.owner))
+
+	suite.Error(err)
+	suite.Nil(results)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// Run the test suite
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,110 @@
+package searchqry
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Markers wrapped around each highlighted term.
+const (
+	MarkOpen  = "<mark>"
+	MarkClose = "</mark>"
+)
+
+// Highlights holds the task's text fields with the matched terms wrapped in
+// MarkOpen and MarkClose. The rest of the text is HTML-escaped. A field without
+// any match is left empty.
+type Highlights struct {
+	Title       string
+	Description string
+}
+
+// highlight marks the given search terms in the task's title and description.
+func highlight(task *taskmodel.Task, terms [][]rune) Highlights {
+	return Highlights{
+		Title:       markTerms(task.Title(), terms),
+		Description: markTerms(task.Description(), terms),
+	}
+}
+
+// searchTerms splits the search text the way the text index does: quoted phrases
+// are kept whole and negated terms (prefixed with '-') are dropped.
+func searchTerms(text string) [][]rune {
+	var terms [][]rune
+	for i, part := range strings.Split(text, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.TrimSpace(part); phrase != "" {
+				terms = append(terms, []rune(phrase))
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if strings.HasPrefix(word, "-") {
+				continue
+			}
+			terms = append(terms, []rune(word))
+		}
+	}
+	return terms
+}
+
+// markTerms wraps every word of text starting with one of the terms, ignoring case.
+// The whole word is marked so stemmed matches such as "reports" for "report" are covered.
+// It returns an empty string when nothing matches.
+func markTerms(text string, terms [][]rune) string {
+	runes := []rune(text)
+
+	var b strings.Builder
+	matched := false
+	plain := 0
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && isWordRune(runes[i-1]) {
+			continue
+		}
+
+		end := matchAt(runes, i, terms)
+		if end < 0 {
+			continue
+		}
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+
+		b.WriteString(html.EscapeString(string(runes[plain:i])))
+		b.WriteString(MarkOpen)
+		b.WriteString(html.EscapeString(string(runes[i:end])))
+		b.WriteString(MarkClose)
+		matched = true
+		plain = end
+		i = end - 1
+	}
+
+	if !matched {
+		return ""
+	}
+	b.WriteString(html.EscapeString(string(runes[plain:])))
+	return b.String()
+}
+
+// matchAt returns the end of the first term found at position start of runes,
+// or -1 if none of the terms starts there.
+func matchAt(runes []rune, start int, terms [][]rune) int {
+	for _, term := range terms {
+		end := start + len(term)
+		if len(term) == 0 || end > len(runes) {
+			continue
+		}
+		if strings.EqualFold(string(runes[start:end]), string(term)) {
+			return end
+		}
+	}
+	return -1
+}
+
+// isWordRune reports whether r is part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,22 @@
+package searchqry
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+)
+
+// Query represents a full-text search over the tasks visible to a user.
+type Query struct {
+	text  string
+	limit int
+	actor taskpolicy.Actor
+}
+
+// NewQuery creates a new Query instance searching for the given text on behalf
+// of the given actor. A zero limit falls back to DefaultLimit.
+func NewQuery(text string, limit int, actor taskpolicy.Actor) *Query {
+	return &Query{
+		text:  text,
+		limit: limit,
+		actor: actor,
+	}
+}
@@ -0,0 +1,56 @@
+// Package subtasksqry provides the logic to list the subtasks of a task.
+// It includes a handler that processes the Subtasks query and returns the subtasks
+// of a task the requester can see, leaving out the subtasks they cannot.
+package subtasksqry
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+)
+
+// Handler is responsible for handling the Subtasks query.
+type Handler struct {
+	repo   irepo.Task         // Repository for task-related operations.
+	policy taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// Ensure Handler implements the IHandler interface
+var _ icmd.IHandler[*Query, []*taskmodel.Task] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Repo   irepo.Task         // Repository for task-related operations.
+	Policy taskpolicy.IPolicy // Authorization policy for tasks.
+}
+
+// New creates a new instance of Handler with the provided configuration.
+func New(cfg Config) *Handler {
+	return &Handler{repo: cfg.Repo, policy: cfg.Policy}
+}
+
+// Handle returns the subtasks of the task the requester can see, by priority, then due date.
+func (h *Handler) Handle(qry *Query) ([]*taskmodel.Task, error) {
+	parent, err := h.repo.GetSingle(qry.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policy.Authorize(qry.actor, taskpolicy.ActionView, parent); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := h.repo.Subtasks(parent.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*taskmodel.Task, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		if h.policy.Authorize(qry.actor, taskpolicy.ActionView, subtask) == nil {
+			visible = append(visible, subtask)
+		}
+	}
+	return visible, nil
+}
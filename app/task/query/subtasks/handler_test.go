@@ -0,0 +1,80 @@
+package subtasksqry_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	taskpolicy_mock "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+	subtasksqry "github.com/beka-birhanu/task_manager_final/app/task/query/subtasks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite defines the test suite for the subtasksqry.Handler.
+type HandlerTestSuite struct {
+	suite.Suite
+	mockRepo   *irepo_mock.Task
+	mockPolicy *taskpolicy_mock.IPolicy
+	handler    *subtasksqry.Handler
+	actor      taskpolicy.Actor
+	parent     *taskmodel.Task
+}
+
+// SetupTest sets up the test environment.
+func (suite *HandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Task)
+	suite.mockPolicy = new(taskpolicy_mock.IPolicy)
+	suite.handler = subtasksqry.New(subtasksqry.Config{Repo: suite.mockRepo, Policy: suite.mockPolicy})
+
+	suite.actor = taskpolicy.Actor{ID: uuid.New()}
+	suite.parent = suite.newTask(uuid.Nil)
+	suite.mockRepo.On("GetSingle", suite.parent.ID()).Return(suite.parent, nil)
+}
+
+// newTask creates a task of the actor under the parent.
+func (suite *HandlerTestSuite) newTask(parentID uuid.UUID) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task in a hierarchy",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		ParentID:    parentID,
+		OwnerID:     suite.actor.ID,
+	})
+	suite.Require().NoError(err)
+	return task
+}
+
+// TestHandle tests that the subtasks the requester can see are returned.
+func (suite *HandlerTestSuite) TestHandle() {
+	visible, hidden := suite.newTask(suite.parent.ID()), suite.newTask(suite.parent.ID())
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, suite.parent).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, visible).Return(nil)
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, hidden).Return(errdmn.TaskNotFound)
+	suite.mockRepo.On("Subtasks", suite.parent.ID()).Return([]*taskmodel.Task{visible, hidden}, nil)
+
+	subtasks, err := suite.handler.Handle(subtasksqry.NewQuery(suite.parent.ID(), suite.actor))
+
+	suite.NoError(err)
+	suite.Equal([]*taskmodel.Task{visible}, subtasks)
+}
+
+// TestHandle_Unauthorized tests that the subtasks of a task the requester cannot see are not listed.
+func (suite *HandlerTestSuite) TestHandle_Unauthorized() {
+	suite.mockPolicy.On("Authorize", suite.actor, taskpolicy.ActionView, suite.parent).Return(errdmn.TaskNotFound)
+
+	subtasks, err := suite.handler.Handle(subtasksqry.NewQuery(suite.parent.ID(), suite.actor))
+
+	suite.Equal(errdmn.TaskNotFound, err)
+	suite.Nil(subtasks)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Subtasks", mock.Anything)
+}
+
+// Run the test suite
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,21 @@
+package subtasksqry
+
+import (
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
+	"github.com/google/uuid"
+)
+
+// Query represents the data needed to list the subtasks of a task.
+type Query struct {
+	id    uuid.UUID
+	actor taskpolicy.Actor
+}
+
+// NewQuery creates a new Query instance for the subtasks of the task with the given ID
+// on behalf of the given actor.
+func NewQuery(id uuid.UUID, actor taskpolicy.Actor) *Query {
+	return &Query{
+		id:    id,
+		actor: actor,
+	}
+}
@@ -0,0 +1,41 @@
+// Package usernamesqry provides the query and handler looking up the usernames of the
+// users tasks refer to by ID, such as their creators and assignees, so responses can
+// show who they are.
+package usernamesqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	"github.com/google/uuid"
+)
+
+// Handler handles username lookup queries.
+type Handler struct {
+	userRepo irepo.User // Repository the users are looked up in.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, map[uuid.UUID]string] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle returns the usernames of the users of the query by their IDs.
+// Users that no longer exist are left out.
+func (h *Handler) Handle(qry *Query) (map[uuid.UUID]string, error) {
+	usernames := make(map[uuid.UUID]string, len(qry.IDs))
+	if len(qry.IDs) == 0 {
+		return usernames, nil
+	}
+
+	users, err := h.userRepo.ByIDs(qry.IDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		usernames[user.ID()] = user.Username()
+	}
+	return usernames, nil
+}
@@ -0,0 +1,86 @@
+package usernamesqry_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	usernamesqry "github.com/beka-birhanu/task_manager_final/app/task/query/usernames"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// UsernamesHandlerTestSuite defines the test suite for the usernames handler.
+type UsernamesHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *usernamesqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *UsernamesHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = usernamesqry.New(suite.mockUserRepo)
+}
+
+// newTask creates a task created by the given user.
+func (suite *UsernamesHandlerTestSuite) newTask(creatorID uuid.UUID) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Task",
+		Description: "A task",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		Status:      taskmodel.StatusPending,
+		OwnerID:     creatorID,
+	})
+	suite.Require().NoError(err)
+	return task
+}
+
+// TestNewQuery tests that the creators and assignees of the tasks are each named once.
+func (suite *UsernamesHandlerTestSuite) TestNewQuery() {
+	creator, assignee := uuid.New(), uuid.New()
+	first, second := suite.newTask(creator), suite.newTask(assignee)
+	suite.Require().NoError(first.Assign(assignee))
+	suite.Require().NoError(second.Assign(creator))
+
+	suite.Equal([]uuid.UUID{creator, assignee}, usernamesqry.NewQuery(first, second).IDs)
+	suite.Empty(usernamesqry.NewQuery().IDs)
+}
+
+// TestHandle tests that the usernames of the existing users are returned by ID.
+func (suite *UsernamesHandlerTestSuite) TestHandle() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1"})
+	deleted := uuid.New()
+	suite.mockUserRepo.On("ByIDs", []uuid.UUID{user.ID(), deleted}).Return([]*usermodel.User{user}, nil)
+
+	usernames, err := suite.handler.Handle(&usernamesqry.Query{IDs: []uuid.UUID{user.ID(), deleted}})
+
+	suite.NoError(err)
+	suite.Equal(map[uuid.UUID]string{user.ID(): "user1"}, usernames)
+}
+
+// TestHandle_NoUsers tests that the repository is not queried for no users.
+func (suite *UsernamesHandlerTestSuite) TestHandle_NoUsers() {
+	usernames, err := suite.handler.Handle(usernamesqry.NewQuery())
+
+	suite.NoError(err)
+	suite.Empty(usernames)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByIDs", mock.Anything)
+}
+
+// TestHandle_Error tests that repository errors are returned.
+func (suite *UsernamesHandlerTestSuite) TestHandle_Error() {
+	suite.mockUserRepo.On("ByIDs", mock.Anything).Return(nil, errdmn.NewUnexpected("db down"))
+
+	_, err := suite.handler.Handle(&usernamesqry.Query{IDs: []uuid.UUID{uuid.New()}})
+
+	suite.Error(err)
+}
+
+func TestUsernamesHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(UsernamesHandlerTestSuite))
+}
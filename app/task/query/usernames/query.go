@@ -0,0 +1,31 @@
+package usernamesqry
+
+import (
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	"github.com/google/uuid"
+)
+
+// Query represents the data required to look up usernames.
+type Query struct {
+	IDs []uuid.UUID // IDs of the users to look up.
+}
+
+// NewQuery creates a Query for the creators and assignees of the given tasks,
+// each user named once.
+func NewQuery(tasks ...*taskmodel.Task) *Query {
+	seen := make(map[uuid.UUID]bool)
+	qry := &Query{}
+	add := func(id uuid.UUID) {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			qry.IDs = append(qry.IDs, id)
+		}
+	}
+	for _, task := range tasks {
+		add(task.CreatorID())
+		for _, id := range task.AssigneeIDs() {
+			add(id)
+		}
+	}
+	return qry
+}
@@ -1,12 +1,12 @@
-// Package promotcmd provides the logic for promoting a user to admin status.
+// Package promotcmd provides the logic for promoting a user to the admin role.
 // It includes the necessary command structure and a handler to execute the promotion.
 package promotcmd
 
 import (
-	"log"
-
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 )
 
 // Handler handles the promote command logic.
@@ -22,7 +22,7 @@ func New(userRepo irepo.User) *Handler {
 	return &Handler{userRepo: userRepo}
 }
 
-// Promote promotes a user to admin status based on the provided command.
+// Handle gives the user the admin role based on the provided command.
 func (h *Handler) Handle(cmd *Command) (bool, error) {
 	user, err := h.userRepo.ByUsername(cmd.Username)
 	if err != nil {
@@ -34,12 +34,13 @@ func (h *Handler) Handle(cmd *Command) (bool, error) {
 		return false, err
 	}
 
-	user.UpdateAdminStatus(true)
-	if err := h.userRepo.Save(user); err != nil {
+	if err := user.UpdateRole(rolemodel.Admin); err != nil {
+		return false, err
+	}
+	if err := h.userRepo.SaveRole(user); err != nil {
 		return false, err
 	}
 
-	// TODO: Implement a proper logging mechanism.
-	log.Printf("Admin %v promoted user %v", admin.Username(), user.Username())
+	applog.Audit("Admin %v promoted user %v", admin.Username(), user.Username())
 	return true, nil
 }
@@ -7,6 +7,7 @@ import (
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
 	"github.com/beka-birhanu/task_manager_final/app/user/admin_status/command"
 	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -35,7 +36,7 @@ func (suite *PromoteCommandHandlerTestSuite) SetupTest() {
 	suite.user, err = usermodel.New(usermodel.Config{
 		Username:       "user1",
 		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
-		IsAdmin:        false,
+		Role:           rolemodel.Member,
 		PasswordHasher: suite.passwordHasher,
 	})
 	suite.Require().NoError(err)
@@ -43,7 +44,7 @@ func (suite *PromoteCommandHandlerTestSuite) SetupTest() {
 	suite.admin, err = usermodel.New(usermodel.Config{
 		Username:       "admin1",
 		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
-		IsAdmin:        true,
+		Role:           rolemodel.Admin,
 		PasswordHasher: suite.passwordHasher,
 	})
 	suite.Require().NoError(err)
@@ -61,7 +62,7 @@ func (suite *PromoteCommandHandlerTestSuite) TestHandle_Success() {
 	// Setup the user repository to return the correct users.
 	suite.mockUserRepo.On("ByUsername", suite.user.Username()).Return(suite.user, nil)
 	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
-	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockUserRepo.On("SaveRole", suite.user).Return(nil)
 
 	// Execute the Handle method with the command.
 	result, err := suite.handler.Handle(cmd)
@@ -69,6 +70,7 @@ func (suite *PromoteCommandHandlerTestSuite) TestHandle_Success() {
 	// Verify the results.
 	suite.Assert().True(result)
 	suite.Assert().NoError(err)
+	suite.Assert().True(suite.user.IsAdmin())
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
@@ -126,7 +128,7 @@ func (suite *PromoteCommandHandlerTestSuite) TestHandle_SaveError() {
 	// Setup the user repository to return the correct users and an error when saving.
 	suite.mockUserRepo.On("ByUsername", suite.user.Username()).Return(suite.user, nil)
 	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
-	suite.mockUserRepo.On("Save", suite.user).Return(errors.New("save error"))
+	suite.mockUserRepo.On("SaveRole", suite.user).Return(errors.New("save error"))
 
 	// Execute the Handle method with the command.
 	result, err := suite.handler.Handle(cmd)
@@ -0,0 +1,17 @@
+package demotecmd
+
+import "github.com/google/uuid"
+
+// Command represents a demote command with necessary data.
+type Command struct {
+	Username  string    // Username of the admin to demote.
+	DemoterID uuid.UUID // ID of the admin performing the demotion.
+}
+
+// NewCommand creates a new Command instance with the given username and demoter ID.
+func NewCommand(username string, demoterID uuid.UUID) *Command {
+	return &Command{
+		Username:  username,
+		DemoterID: demoterID,
+	}
+}
@@ -0,0 +1,84 @@
+// Package demotecmd provides the logic for revoking the admin status of a user.
+//
+// A demoted admin becomes a member. The last remaining admin cannot be demoted, so an
+// admin can only demote themselves while another admin exists. The sessions of a demoted
+// user are ended, as their access tokens still carry the admin role.
+package demotecmd
+
+import (
+	"time"
+
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	"github.com/beka-birhanu/task_manager_final/app/user/admin_status/lastadmin"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+)
+
+// Handler handles the demote command logic.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle revokes the admin status of the user and ends their sessions.
+// Demoting a user who is not an admin changes nothing.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	demoter, err := h.userRepo.ById(cmd.DemoterID)
+	if err != nil {
+		return false, err
+	}
+
+	if !user.IsAdmin() {
+		return true, nil
+	}
+
+	if err := lastadmin.Check(h.userRepo, user, demoter.ID()); err != nil {
+		return false, err
+	}
+
+	if err := user.UpdateRole(rolemodel.Member); err != nil {
+		return false, err
+	}
+	// Another admin may have been demoted since the check; the save checks again.
+	if err := lastadmin.Save(h.userRepo.SaveRoleKeepingAdmin, user, lastadmin.DemotionError(user, demoter.ID())); err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return false, err
+	}
+
+	applog.Audit("Admin %v demoted user %v", demoter.Username(), user.Username())
+	return true, nil
+}
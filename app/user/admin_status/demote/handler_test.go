@@ -0,0 +1,172 @@
+package demotecmd_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	demotecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/demote"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DemoteCommandHandlerTestSuite defines the test suite for the DemoteCommand handler.
+type DemoteCommandHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	handler         *demotecmd.Handler
+	admin           *usermodel.User
+	otherAdmin      *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *DemoteCommandHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.handler = demotecmd.New(demotecmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   time.Minute,
+	})
+
+	suite.admin = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin1", Role: rolemodel.Admin})
+	suite.otherAdmin = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
+}
+
+// TestHandle_Success tests that an admin is demoted and their sessions ended.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_Success() {
+	sessionID := uuid.New()
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(suite.otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", suite.otherAdmin).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.otherAdmin.ID()).Return([]uuid.UUID{sessionID}, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+	suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.False(suite.otherAdmin.IsAdmin())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+}
+
+// TestHandle_Self tests that an admin can demote themselves while another admin exists.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_Self() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", suite.admin).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.admin.ID()).Return([]uuid.UUID{}, nil)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin1", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.False(suite.admin.IsAdmin())
+}
+
+// TestHandle_SelfLastAdmin tests that the only admin cannot demote themselves.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_SelfLastAdmin() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(1), nil)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin1", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.SelfDemotionLastAdmin, err)
+	suite.True(suite.admin.IsAdmin())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRoleKeepingAdmin", mock.Anything)
+}
+
+// TestHandle_LastAdmin tests that the last remaining admin cannot be demoted.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_LastAdmin() {
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(suite.otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(1), nil)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.LastAdmin, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRoleKeepingAdmin", mock.Anything)
+}
+
+// TestHandle_ConcurrentDemotion tests that a demotion fails when another admin was demoted
+// between the check and the save, leaving the user the last admin.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_ConcurrentDemotion() {
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(suite.otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", suite.otherAdmin).Return(errdmn.LastAdmin)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.LastAdmin, err)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_ConcurrentSelfDemotion tests that an admin demoting themselves is told so when
+// the other admin was demoted meanwhile.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_ConcurrentSelfDemotion() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", suite.admin).Return(errdmn.LastAdmin)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin1", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.SelfDemotionLastAdmin, err)
+}
+
+// TestHandle_NotAdmin tests that demoting a user who is not an admin changes nothing.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_NotAdmin() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.mockUserRepo.On("ByUsername", "user1").Return(user, nil)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("user1", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CountAdmins")
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRoleKeepingAdmin", mock.Anything)
+}
+
+// TestHandle_UserNotFound tests the scenario where the user to be demoted is not found.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("ghost", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// TestHandle_SaveError tests the scenario where there is an error saving the updated user.
+func (suite *DemoteCommandHandlerTestSuite) TestHandle_SaveError() {
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(suite.otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", suite.otherAdmin).Return(errors.New("save error"))
+
+	result, err := suite.handler.Handle(demotecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.False(result)
+	suite.EqualError(err, "save error")
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// Run the test suite
+func TestDemoteCommandHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DemoteCommandHandlerTestSuite))
+}
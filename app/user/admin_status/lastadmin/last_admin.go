@@ -0,0 +1,62 @@
+// Package lastadmin guards against taking the admin role from the last remaining active
+// admin, or deactivating or deleting them, which would leave nobody able to manage users.
+//
+// Check and IsLast tell early whether a change is allowed; Save makes the change and
+// checks again along with the write, as another admin may lose the role meanwhile.
+package lastadmin
+
+import (
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// Check returns an error if the admin role may not be taken from the user by the actor
+// with the given ID: an admin can only lose the role while another admin exists.
+// Users who are not admins pass.
+func Check(userRepo irepo.User, user *usermodel.User, actorID uuid.UUID) error {
+	last, err := IsLast(userRepo, user)
+	if err != nil {
+		return err
+	}
+	if last {
+		return DemotionError(user, actorID)
+	}
+	return nil
+}
+
+// DemotionError returns the error for the actor with the given ID taking the admin role
+// from the user while no other admin exists.
+func DemotionError(user *usermodel.User, actorID uuid.UUID) error {
+	if user.ID() == actorID {
+		return errdmn.SelfDemotionLastAdmin
+	}
+	return errdmn.LastAdmin
+}
+
+// IsLast reports whether the user is an active admin and no other active admin exists.
+func IsLast(userRepo irepo.User, user *usermodel.User) (bool, error) {
+	if !user.IsAdmin() || user.IsDeactivated() {
+		return false, nil
+	}
+
+	admins, err := userRepo.CountAdmins()
+	if err != nil {
+		return false, err
+	}
+	return admins <= 1, nil
+}
+
+// Save saves the change to the user, an active admin before the change, with one of the
+// keeping-admin saves of irepo.User, such as SaveRoleKeepingAdmin. If no active admin
+// would be left afterwards, it fails with lastErr.
+func Save(save func(user *usermodel.User) error, user *usermodel.User, lastErr error) error {
+	if err := save(user); err != nil {
+		if err == errdmn.LastAdmin {
+			return lastErr
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package rolecmd
+
+import "github.com/google/uuid"
+
+// Command represents a command giving a user another role.
+type Command struct {
+	Username   string    // Username of the user to give the role to.
+	Role       string    // Name of the role to give.
+	AssignerID uuid.UUID // ID of the admin assigning the role.
+}
+
+// NewCommand creates a new Command instance with the given username, role, and assigner ID.
+func NewCommand(username, role string, assignerID uuid.UUID) *Command {
+	return &Command{
+		Username:   username,
+		Role:       role,
+		AssignerID: assignerID,
+	}
+}
@@ -0,0 +1,96 @@
+// Package rolecmd provides the logic for giving a user one of the named roles.
+//
+// The admin role is subject to the same rule as a demotion: the last remaining admin
+// cannot be given another role. The sessions of a user whose role changed are ended,
+// as their access tokens still carry the previous role.
+package rolecmd
+
+import (
+	"time"
+
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	"github.com/beka-birhanu/task_manager_final/app/user/admin_status/lastadmin"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+)
+
+// Handler handles the role command logic.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle gives the user the role and ends their sessions.
+// Giving a user the role they already have changes nothing.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	role, err := rolemodel.Parse(cmd.Role)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	assigner, err := h.userRepo.ById(cmd.AssignerID)
+	if err != nil {
+		return false, err
+	}
+
+	if user.Role() == role {
+		return true, nil
+	}
+
+	if err := lastadmin.Check(h.userRepo, user, assigner.ID()); err != nil {
+		return false, err
+	}
+
+	// An active admin losing the role is checked again along with the save, as
+	// another admin may have lost it since the check.
+	losesAdmin := user.IsAdmin() && !user.IsDeactivated()
+	if err := user.UpdateRole(role); err != nil {
+		return false, err
+	}
+	if losesAdmin {
+		err = lastadmin.Save(h.userRepo.SaveRoleKeepingAdmin, user, lastadmin.DemotionError(user, assigner.ID()))
+	} else {
+		err = h.userRepo.SaveRole(user)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return false, err
+	}
+
+	applog.Audit("Admin %v gave user %v the %v role", assigner.Username(), user.Username(), role)
+	return true, nil
+}
@@ -0,0 +1,143 @@
+package rolecmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	rolecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/role"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// RoleCommandHandlerTestSuite defines the test suite for the role command handler.
+type RoleCommandHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	handler         *rolecmd.Handler
+	admin           *usermodel.User
+	member          *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *RoleCommandHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.handler = rolecmd.New(rolecmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   time.Minute,
+	})
+
+	suite.admin = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin1", Role: rolemodel.Admin})
+	suite.member = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
+}
+
+// TestHandle_Success tests that the role is given and the sessions of the user ended.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_Success() {
+	sessionID := uuid.New()
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.member, nil)
+	suite.mockUserRepo.On("SaveRole", suite.member).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.member.ID()).Return([]uuid.UUID{sessionID}, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+	suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("user1", "manager", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.Equal(rolemodel.Manager, suite.member.Role())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CountAdmins")
+}
+
+// TestHandle_SameRole tests that giving a user the role they have changes nothing.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_SameRole() {
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.member, nil)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("user1", "member", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRole", mock.Anything)
+}
+
+// TestHandle_InvalidRole tests that unknown roles are refused before anything is loaded.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_InvalidRole() {
+	result, err := suite.handler.Handle(rolecmd.NewCommand("user1", "root", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.InvalidRole, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByUsername", mock.Anything)
+}
+
+// TestHandle_LastAdmin tests that the only admin cannot give themselves another role.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_LastAdmin() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(1), nil)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("admin1", "viewer", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.SelfDemotionLastAdmin, err)
+	suite.True(suite.admin.IsAdmin())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRole", mock.Anything)
+}
+
+// TestHandle_Demotion tests that an admin given another role is saved only while another admin is left.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_Demotion() {
+	otherAdmin := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", otherAdmin).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", otherAdmin.ID()).Return([]uuid.UUID{}, nil)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("admin2", "manager", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveRole", mock.Anything)
+}
+
+// TestHandle_ConcurrentDemotion tests that an admin is not given another role when the
+// other admin lost theirs between the check and the save.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_ConcurrentDemotion() {
+	otherAdmin := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(otherAdmin, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveRoleKeepingAdmin", otherAdmin).Return(errdmn.LastAdmin)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("admin2", "manager", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.LastAdmin, err)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_UserNotFound tests the scenario where the user is not found.
+func (suite *RoleCommandHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(rolecmd.NewCommand("ghost", "viewer", suite.admin.ID()))
+
+	suite.False(result)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// Run the test suite
+func TestRoleCommandHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleCommandHandlerTestSuite))
+}
@@ -0,0 +1,142 @@
+// Package apikeyauth authenticates the requests made with the API keys users create
+// for scripts and CI.
+//
+// An API key has the form "tm_<key id>.<secret>". Only a hash of the secret is stored,
+// so a leaked key store cannot be used to call the API. A key authenticates its owner
+// as long as it has not expired and the owner is active, limited to those of its scopes
+// the current role of the owner still grants.
+package apikeyauth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// Prefix starts every API key, so keys are easy to recognize, e.g. by secret scanners.
+const Prefix = "tm_"
+
+// IAuthenticator authenticates requests made with API keys.
+type IAuthenticator interface {
+	// Authenticate verifies the API key and returns the claims of its owner, limited
+	// to the scopes of the key.
+	Authenticate(key string) (*ijwt.Claims, error)
+}
+
+// Authenticator verifies API keys against a repository.
+type Authenticator struct {
+	apiKeyRepo irepo.APIKey  // Repository for API keys.
+	userRepo   irepo.User    // Repository for user data operations.
+	hashSvc    ihash.Service // Service for matching API key secrets.
+}
+
+// Ensure Authenticator implements IAuthenticator.
+var _ IAuthenticator = &Authenticator{}
+
+// Config holds the dependencies for creating a new Authenticator.
+type Config struct {
+	APIKeyRepo irepo.APIKey  // Repository for API keys.
+	UserRepo   irepo.User    // Repository for user data operations.
+	HashSvc    ihash.Service // Service for matching API key secrets.
+}
+
+// NewAuthenticator creates a new Authenticator with the given configuration.
+func NewAuthenticator(cfg Config) *Authenticator {
+	return &Authenticator{
+		apiKeyRepo: cfg.APIKeyRepo,
+		userRepo:   cfg.UserRepo,
+		hashSvc:    cfg.HashSvc,
+	}
+}
+
+// Authenticate verifies the API key, records that it was used, and returns the claims
+// of its owner. It fails with errdmn.InvalidAPIKey when the key is malformed, unknown,
+// does not match, or its owner no longer exists.
+func (a *Authenticator) Authenticate(rawKey string) (*ijwt.Claims, error) {
+	id, secret, err := Parse(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := a.apiKeyRepo.ByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := key.MatchesSecret(secret, a.hashSvc)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to validate api key, %v", err))
+	}
+	if !matches {
+		return nil, errdmn.InvalidAPIKey
+	}
+
+	now := time.Now().UTC()
+	if key.IsExpired(now) {
+		return nil, errdmn.APIKeyExpired
+	}
+
+	user, err := a.userRepo.ById(key.UserID())
+	if err != nil {
+		if err == errdmn.UserNotFound {
+			return nil, errdmn.InvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if user.IsDeactivated() {
+		return nil, errdmn.UserDeactivated
+	}
+
+	if err := a.apiKeyRepo.MarkUsed(key.ID(), now); err != nil {
+		return nil, err
+	}
+
+	return &ijwt.Claims{
+		Subject:   user.ID(),
+		Roles:     roles(user),
+		IssuedAt:  key.CreatedAt(),
+		ExpiresAt: key.ExpiresAt(),
+		APIKeyID:  key.ID(),
+		Scopes:    key.Scopes(),
+	}, nil
+}
+
+// roles returns the roles the claims of the user carry.
+func roles(user *usermodel.User) []string {
+	if user.Role() == "" {
+		return nil
+	}
+	return []string{string(user.Role())}
+}
+
+// Format builds the API key handed to its owner.
+func Format(id uuid.UUID, secret string) string {
+	return Prefix + id.String() + "." + secret
+}
+
+// Parse splits an API key into its ID and secret.
+func Parse(key string) (uuid.UUID, string, error) {
+	rest, found := strings.CutPrefix(key, Prefix)
+	if !found {
+		return uuid.Nil, "", errdmn.InvalidAPIKey
+	}
+
+	rawID, secret, found := strings.Cut(rest, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", errdmn.InvalidAPIKey
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", errdmn.InvalidAPIKey
+	}
+	return id, secret, nil
+}
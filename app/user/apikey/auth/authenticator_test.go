@@ -0,0 +1,151 @@
+package apikeyauth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuthenticatorTestSuite defines the test suite for the API key authenticator.
+type AuthenticatorTestSuite struct {
+	suite.Suite
+	mockAPIKeyRepo *irepo_mock.APIKey
+	mockUserRepo   *irepo_mock.User
+	mockHashSvc    *ihash_mocks.Service
+	authenticator  *apikeyauth.Authenticator
+	user           *usermodel.User
+	key            *apikeymodel.APIKey
+	rawKey         string
+}
+
+// SetupTest sets up the test environment.
+func (suite *AuthenticatorTestSuite) SetupTest() {
+	suite.mockAPIKeyRepo = new(irepo_mock.APIKey)
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.authenticator = apikeyauth.NewAuthenticator(apikeyauth.Config{
+		APIKeyRepo: suite.mockAPIKeyRepo,
+		UserRepo:   suite.mockUserRepo,
+		HashSvc:    suite.mockHashSvc,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:       uuid.New(),
+		Username: "user1",
+		Role:     rolemodel.Member,
+	})
+	suite.key = suite.newKey(time.Time{})
+	suite.rawKey = apikeyauth.Format(suite.key.ID(), "secret")
+
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "guess").Return(false, nil)
+}
+
+// newKey returns a stored API key of the user expiring at the given time.
+func (suite *AuthenticatorTestSuite) newKey(expiresAt time.Time) *apikeymodel.APIKey {
+	return apikeymodel.FromBSON(&apikeymodel.APIKeyBSON{
+		ID:         uuid.New(),
+		UserID:     suite.user.ID(),
+		Name:       "ci",
+		Scopes:     []rolemodel.Permission{rolemodel.TaskRead},
+		SecretHash: "hashed_secret",
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+		ExpiresAt:  expiresAt,
+	})
+}
+
+// TestAuthenticate_Success tests that a valid key authenticates its owner, limited to its scopes.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_Success() {
+	suite.mockAPIKeyRepo.On("ByID", suite.key.ID()).Return(suite.key, nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockAPIKeyRepo.On("MarkUsed", suite.key.ID(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	claims, err := suite.authenticator.Authenticate(suite.rawKey)
+
+	suite.NoError(err)
+	suite.Equal(suite.user.ID(), claims.Subject)
+	suite.Equal([]string{"member"}, claims.Roles)
+	suite.Equal(suite.key.ID(), claims.APIKeyID)
+	suite.True(claims.Can(rolemodel.TaskRead))
+	suite.False(claims.Can(rolemodel.TaskCreate))
+	suite.mockAPIKeyRepo.AssertExpectations(suite.T())
+}
+
+// TestAuthenticate_Malformed tests that malformed keys are refused without a lookup.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_Malformed() {
+	for _, rawKey := range []string{"", suite.key.ID().String() + ".secret", "tm_not-a-uuid.secret", "tm_" + suite.key.ID().String()} {
+		_, err := suite.authenticator.Authenticate(rawKey)
+		suite.Equal(errdmn.InvalidAPIKey, err, rawKey)
+	}
+	suite.mockAPIKeyRepo.AssertNotCalled(suite.T(), "ByID", mock.Anything)
+}
+
+// TestAuthenticate_WrongSecret tests that a key with the wrong secret is refused.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_WrongSecret() {
+	suite.mockAPIKeyRepo.On("ByID", suite.key.ID()).Return(suite.key, nil)
+
+	_, err := suite.authenticator.Authenticate(apikeyauth.Format(suite.key.ID(), "guess"))
+
+	suite.Equal(errdmn.InvalidAPIKey, err)
+	suite.mockAPIKeyRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+// TestAuthenticate_MatchError tests that a failure to match the secret is unexpected.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_MatchError() {
+	suite.mockAPIKeyRepo.On("ByID", suite.key.ID()).Return(suite.key, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "broken").Return(false, errors.New("hash error"))
+
+	_, err := suite.authenticator.Authenticate(apikeyauth.Format(suite.key.ID(), "broken"))
+
+	suite.Error(err)
+	suite.Equal(errdmn.Unexpected, err.(*errdmn.Error).Type())
+}
+
+// TestAuthenticate_Expired tests that an expired key is refused.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_Expired() {
+	key := suite.newKey(time.Now().UTC().Add(-time.Minute))
+	suite.mockAPIKeyRepo.On("ByID", key.ID()).Return(key, nil)
+
+	_, err := suite.authenticator.Authenticate(apikeyauth.Format(key.ID(), "secret"))
+
+	suite.Equal(errdmn.APIKeyExpired, err)
+}
+
+// TestAuthenticate_UserGone tests that a key whose owner was deleted is refused.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_UserGone() {
+	suite.mockAPIKeyRepo.On("ByID", suite.key.ID()).Return(suite.key, nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(nil, errdmn.UserNotFound)
+
+	_, err := suite.authenticator.Authenticate(suite.rawKey)
+
+	suite.Equal(errdmn.InvalidAPIKey, err)
+}
+
+// TestAuthenticate_Deactivated tests that the keys of a deactivated user are refused.
+func (suite *AuthenticatorTestSuite) TestAuthenticate_Deactivated() {
+	suite.user.Deactivate()
+	suite.mockAPIKeyRepo.On("ByID", suite.key.ID()).Return(suite.key, nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+
+	_, err := suite.authenticator.Authenticate(suite.rawKey)
+
+	suite.Equal(errdmn.UserDeactivated, err)
+	suite.mockAPIKeyRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+// TestAuthenticatorTestSuite runs the test suite.
+func TestAuthenticatorTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthenticatorTestSuite))
+}
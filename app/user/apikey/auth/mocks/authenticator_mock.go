@@ -0,0 +1,20 @@
+package apikeyauth_mock
+
+import (
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/stretchr/testify/mock"
+)
+
+// IAuthenticator is a mock implementation of the IAuthenticator interface using testify.
+type IAuthenticator struct {
+	mock.Mock
+}
+
+// Authenticate mocks the Authenticate method of the IAuthenticator interface.
+func (m *IAuthenticator) Authenticate(key string) (*ijwt.Claims, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ijwt.Claims), args.Error(1)
+}
@@ -0,0 +1,15 @@
+package createapikeycmd
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Command represents the data required to create an API key for the signed in user.
+type Command struct {
+	UserID    uuid.UUID // ID of the signed in user.
+	Name      string    // Name telling the keys of the user apart.
+	Scopes    []string  // Permissions the key is limited to.
+	ExpiresAt time.Time // Time after which the key can no longer be used; the zero time never expires.
+}
@@ -0,0 +1,95 @@
+// Package createapikeycmd provides the command and handler letting the signed in user
+// create an API key for scripts and CI.
+//
+// The scopes of a key are permissions, each of which the role of the user has to grant.
+// The key is returned once, on creation; only a hash of its secret is stored.
+package createapikeycmd
+
+import (
+	"fmt"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+)
+
+// Result holds the created API key.
+type Result struct {
+	APIKey *apikeymodel.APIKey // Created key.
+	Key    string              // Key to send in the X-API-Key header; it cannot be shown again.
+}
+
+// Handler handles API key creation commands.
+type Handler struct {
+	userRepo   irepo.User    // Repository for user data operations.
+	apiKeyRepo irepo.APIKey  // Repository for API keys.
+	hashSvc    ihash.Service // Service for hashing API key secrets.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo   irepo.User    // Repository for user data operations.
+	APIKeyRepo irepo.APIKey  // Repository for API keys.
+	HashSvc    ihash.Service // Service for hashing API key secrets.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:   cfg.UserRepo,
+		apiKeyRepo: cfg.APIKeyRepo,
+		hashSvc:    cfg.HashSvc,
+	}
+}
+
+// Handle creates and stores a new API key of the user, failing with
+// errdmn.APIKeyScopeNotGranted if the role of the user does not grant one of its scopes.
+func (h *Handler) Handle(cmd *Command) (*Result, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]rolemodel.Permission, 0, len(cmd.Scopes))
+	for _, name := range cmd.Scopes {
+		scope, err := rolemodel.ParsePermission(name)
+		if err != nil {
+			return nil, err
+		}
+		if !user.Role().Can(scope) {
+			return nil, errdmn.APIKeyScopeNotGranted
+		}
+		scopes = append(scopes, scope)
+	}
+
+	secret, err := authtoken.NewSecret()
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate api key, %v", err))
+	}
+
+	key, err := apikeymodel.New(apikeymodel.Config{
+		UserID:       user.ID(),
+		Name:         cmd.Name,
+		Scopes:       scopes,
+		ExpiresAt:    cmd.ExpiresAt,
+		Secret:       secret,
+		SecretHasher: h.hashSvc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.apiKeyRepo.Save(key); err != nil {
+		return nil, err
+	}
+
+	return &Result{APIKey: key, Key: apikeyauth.Format(key.ID(), secret)}, nil
+}
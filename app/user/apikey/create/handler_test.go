@@ -0,0 +1,141 @@
+package createapikeycmd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
+	createapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/create"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// CreateAPIKeyHandlerTestSuite defines the test suite for the API key creation handler.
+type CreateAPIKeyHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo   *irepo_mock.User
+	mockAPIKeyRepo *irepo_mock.APIKey
+	mockHashSvc    *ihash_mocks.Service
+	handler        *createapikeycmd.Handler
+	user           *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *CreateAPIKeyHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockAPIKeyRepo = new(irepo_mock.APIKey)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = createapikeycmd.NewHandler(createapikeycmd.Config{
+		UserRepo:   suite.mockUserRepo,
+		APIKeyRepo: suite.mockAPIKeyRepo,
+		HashSvc:    suite.mockHashSvc,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:       uuid.New(),
+		Username: "user1",
+		Role:     rolemodel.Member,
+	})
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+}
+
+// TestHandle_Success tests that a key is stored and returned once with its secret.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_Success() {
+	suite.mockAPIKeyRepo.On("Save", mock.AnythingOfType("*apikeymodel.APIKey")).Return(nil)
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+
+	result, err := suite.handler.Handle(&createapikeycmd.Command{
+		UserID:    suite.user.ID(),
+		Name:      "ci",
+		Scopes:    []string{"task:read", "task:create"},
+		ExpiresAt: expiresAt,
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.ID(), result.APIKey.UserID())
+	suite.Equal("ci", result.APIKey.Name())
+	suite.Equal([]rolemodel.Permission{rolemodel.TaskRead, rolemodel.TaskCreate}, result.APIKey.Scopes())
+	suite.Equal(expiresAt, result.APIKey.ExpiresAt())
+	suite.True(strings.HasPrefix(result.Key, apikeyauth.Prefix))
+
+	id, secret, err := apikeyauth.Parse(result.Key)
+	suite.NoError(err)
+	suite.Equal(result.APIKey.ID(), id)
+	suite.NotEmpty(secret)
+	suite.mockHashSvc.AssertCalled(suite.T(), "Hash", secret)
+	suite.mockAPIKeyRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_ScopeNotGranted tests that a key cannot be given a permission the role does not grant.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_ScopeNotGranted() {
+	result, err := suite.handler.Handle(&createapikeycmd.Command{
+		UserID: suite.user.ID(),
+		Name:   "ci",
+		Scopes: []string{"task:read", "task:delete:any"},
+	})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.APIKeyScopeNotGranted, err)
+	suite.mockAPIKeyRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_InvalidScope tests that unknown scopes are refused.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_InvalidScope() {
+	result, err := suite.handler.Handle(&createapikeycmd.Command{
+		UserID: suite.user.ID(),
+		Name:   "ci",
+		Scopes: []string{"task:*"},
+	})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidPermission, err)
+}
+
+// TestHandle_InvalidKey tests that the key is validated before it is stored.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_InvalidKey() {
+	result, err := suite.handler.Handle(&createapikeycmd.Command{
+		UserID: suite.user.ID(),
+		Name:   "",
+		Scopes: []string{"task:read"},
+	})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.APIKeyNameEmpty, err)
+	suite.mockAPIKeyRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_UserNotFound tests the scenario where the user no longer exists.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_UserNotFound() {
+	userID := uuid.New()
+	suite.mockUserRepo.On("ById", userID).Return((*usermodel.User)(nil), errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(&createapikeycmd.Command{UserID: userID, Name: "ci", Scopes: []string{"task:read"}})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// TestHandle_SaveError tests that a failure to store the key is returned.
+func (suite *CreateAPIKeyHandlerTestSuite) TestHandle_SaveError() {
+	saveErr := errdmn.NewUnexpected("save failed")
+	suite.mockAPIKeyRepo.On("Save", mock.AnythingOfType("*apikeymodel.APIKey")).Return(saveErr)
+
+	result, err := suite.handler.Handle(&createapikeycmd.Command{UserID: suite.user.ID(), Name: "ci", Scopes: []string{"task:read"}})
+
+	suite.Nil(result)
+	suite.Equal(saveErr, err)
+}
+
+// Run the test suite
+func TestCreateAPIKeyHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(CreateAPIKeyHandlerTestSuite))
+}
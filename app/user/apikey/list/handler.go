@@ -0,0 +1,27 @@
+// Package listapikeysqry provides the query and handler returning the API keys of the
+// signed in user. Keys are listed without their secret, which is only shown on creation.
+package listapikeysqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+)
+
+// Handler handles API key list queries.
+type Handler struct {
+	apiKeyRepo irepo.APIKey // Repository for API keys.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, []*apikeymodel.APIKey] = &Handler{}
+
+// New creates a new Handler with the given API key repository.
+func New(apiKeyRepo irepo.APIKey) *Handler {
+	return &Handler{apiKeyRepo: apiKeyRepo}
+}
+
+// Handle returns the API keys of the user, newest first.
+func (h *Handler) Handle(qry *Query) ([]*apikeymodel.APIKey, error) {
+	return h.apiKeyRepo.ByUser(qry.UserID)
+}
@@ -0,0 +1,56 @@
+package listapikeysqry_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	listapikeysqry "github.com/beka-birhanu/task_manager_final/app/user/apikey/list"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// ListAPIKeysHandlerTestSuite defines the test suite for the API key list handler.
+type ListAPIKeysHandlerTestSuite struct {
+	suite.Suite
+	mockAPIKeyRepo *irepo_mock.APIKey
+	handler        *listapikeysqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ListAPIKeysHandlerTestSuite) SetupTest() {
+	suite.mockAPIKeyRepo = new(irepo_mock.APIKey)
+	suite.handler = listapikeysqry.New(suite.mockAPIKeyRepo)
+}
+
+// TestHandle_Success tests that the keys of the user are returned.
+func (suite *ListAPIKeysHandlerTestSuite) TestHandle_Success() {
+	userID := uuid.New()
+	keys := []*apikeymodel.APIKey{
+		apikeymodel.FromBSON(&apikeymodel.APIKeyBSON{ID: uuid.New(), UserID: userID, Name: "ci"}),
+	}
+	suite.mockAPIKeyRepo.On("ByUser", userID).Return(keys, nil)
+
+	result, err := suite.handler.Handle(listapikeysqry.NewQuery(userID))
+
+	suite.NoError(err)
+	suite.Equal(keys, result)
+}
+
+// TestHandle_Error tests that a failure to list the keys is returned.
+func (suite *ListAPIKeysHandlerTestSuite) TestHandle_Error() {
+	userID := uuid.New()
+	listErr := errdmn.NewUnexpected("list failed")
+	suite.mockAPIKeyRepo.On("ByUser", userID).Return(nil, listErr)
+
+	result, err := suite.handler.Handle(listapikeysqry.NewQuery(userID))
+
+	suite.Nil(result)
+	suite.Equal(listErr, err)
+}
+
+// Run the test suite
+func TestListAPIKeysHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ListAPIKeysHandlerTestSuite))
+}
@@ -0,0 +1,13 @@
+package listapikeysqry
+
+import "github.com/google/uuid"
+
+// Query represents the data required to list the API keys of the signed in user.
+type Query struct {
+	UserID uuid.UUID // ID of the signed in user.
+}
+
+// NewQuery creates a new Query instance with the given user ID.
+func NewQuery(userID uuid.UUID) *Query {
+	return &Query{UserID: userID}
+}
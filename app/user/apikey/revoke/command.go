@@ -0,0 +1,14 @@
+package revokeapikeycmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to revoke an API key of the signed in user.
+type Command struct {
+	ID     uuid.UUID // ID of the key to revoke.
+	UserID uuid.UUID // ID of the signed in user.
+}
+
+// NewCommand creates a new Command instance with the given key and user IDs.
+func NewCommand(id, userID uuid.UUID) *Command {
+	return &Command{ID: id, UserID: userID}
+}
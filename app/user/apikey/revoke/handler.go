@@ -0,0 +1,29 @@
+// Package revokeapikeycmd provides the command and handler letting the signed in user
+// revoke one of their API keys, which cannot be used from then on.
+package revokeapikeycmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+)
+
+// Handler handles API key revocation commands.
+type Handler struct {
+	apiKeyRepo irepo.APIKey // Repository for API keys.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// New creates a new Handler with the given API key repository.
+func New(apiKeyRepo irepo.APIKey) *Handler {
+	return &Handler{apiKeyRepo: apiKeyRepo}
+}
+
+// Handle deletes the key. Keys of other users are reported as errdmn.APIKeyNotFound.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	if err := h.apiKeyRepo.Delete(cmd.ID, cmd.UserID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,52 @@
+package revokeapikeycmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	revokeapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/revoke"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// RevokeAPIKeyHandlerTestSuite defines the test suite for the API key revocation handler.
+type RevokeAPIKeyHandlerTestSuite struct {
+	suite.Suite
+	mockAPIKeyRepo *irepo_mock.APIKey
+	handler        *revokeapikeycmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *RevokeAPIKeyHandlerTestSuite) SetupTest() {
+	suite.mockAPIKeyRepo = new(irepo_mock.APIKey)
+	suite.handler = revokeapikeycmd.New(suite.mockAPIKeyRepo)
+}
+
+// TestHandle_Success tests that the key of the user is deleted.
+func (suite *RevokeAPIKeyHandlerTestSuite) TestHandle_Success() {
+	cmd := revokeapikeycmd.NewCommand(uuid.New(), uuid.New())
+	suite.mockAPIKeyRepo.On("Delete", cmd.ID, cmd.UserID).Return(nil)
+
+	result, err := suite.handler.Handle(cmd)
+
+	suite.NoError(err)
+	suite.True(result)
+	suite.mockAPIKeyRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_NotFound tests that a key the user does not own is reported as missing.
+func (suite *RevokeAPIKeyHandlerTestSuite) TestHandle_NotFound() {
+	cmd := revokeapikeycmd.NewCommand(uuid.New(), uuid.New())
+	suite.mockAPIKeyRepo.On("Delete", cmd.ID, cmd.UserID).Return(errdmn.APIKeyNotFound)
+
+	result, err := suite.handler.Handle(cmd)
+
+	suite.False(result)
+	suite.Equal(errdmn.APIKeyNotFound, err)
+}
+
+// Run the test suite
+func TestRevokeAPIKeyHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RevokeAPIKeyHandlerTestSuite))
+}
@@ -1,21 +1,31 @@
 // Package registercmd provides the command, handler structure and factory function
 // for user registration.
+//
+// A verification token is sent to the email address of every new user. When verified
+// emails are required, no session is started until the address is verified.
 package registercmd
 
 import (
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
 	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
-	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
 )
 
 // Handler handles the user registration process.
 type Handler struct {
-	userRepo irepo.User    // Repository for user data operations.
-	jwtSvc   ijwt.Service  // Service for JWT operations.
-	hashSvc  ihash.Service // Service for password hashing.
+	userRepo             irepo.User                // Repository for user data operations.
+	tokens               authtoken.IIssuer         // Issuer of access and refresh tokens.
+	hashSvc              ihash.Service             // Service for password hashing.
+	verification         *emailverification.Sender // Starts the email verification and sends its token.
+	requireVerifiedEmail bool                      // Start no session until the email is verified.
 }
 
 // Ensure Handler implementes icmd.Handler
@@ -23,34 +33,47 @@ var _ icmd.IHandler[*Command, *authresult.Result] = &Handler{}
 
 // Config holds the dependencies for creating a new Handler.
 type Config struct {
-	UserRepo irepo.User    // Repository for user data operations.
-	JwtSvc   ijwt.Service  // Service for JWT operations.
-	HashSvc  ihash.Service // Service for password hashing.
+	UserRepo             irepo.User                // Repository for user data operations.
+	Tokens               authtoken.IIssuer         // Issuer of access and refresh tokens.
+	HashSvc              ihash.Service             // Service for password hashing.
+	Verification         *emailverification.Sender // Starts the email verification and sends its token.
+	RequireVerifiedEmail bool                      // Start no session until the email is verified.
 }
 
 // NewHandler creates a new Handler with the given configuration.
 func NewHandler(cfg Config) *Handler {
 	return &Handler{
-		userRepo: cfg.UserRepo,
-		jwtSvc:   cfg.JwtSvc,
-		hashSvc:  cfg.HashSvc,
+		userRepo:             cfg.UserRepo,
+		tokens:               cfg.Tokens,
+		hashSvc:              cfg.HashSvc,
+		verification:         cfg.Verification,
+		requireVerifiedEmail: cfg.RequireVerifiedEmail,
 	}
 }
 
-// Handle processes a registration command, creating and saving a new user,
-// and generating a JWT for the user.
+// Handle processes a registration command, creating and saving a new user, sending
+// the verification token of their email, and issuing their tokens.
 func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
-	var isAdmin bool
+	role := rolemodel.Default
+
+	if cmd.Email == "" {
+		return nil, errdmn.EmailRequired
+	}
 
 	// Check if there are any users to determine if the new user should be an admin.
 	count, err := h.userRepo.Count()
 	if err != nil {
 		return nil, err
 	} else if count == 0 {
-		isAdmin = true
+		role = rolemodel.Admin
+	}
+
+	user, err := createUser(cmd, h.hashSvc, role)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := createUser(cmd, h.hashSvc, isAdmin)
+	msg, err := h.verification.Start(user)
 	if err != nil {
 		return nil, err
 	}
@@ -59,20 +82,31 @@ func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
 		return nil, err
 	}
 
-	token, err := h.jwtSvc.Generate(user)
+	// The account exists at this point: a lost message is not worth failing the
+	// registration over, as the user can ask for a new one.
+	if err := h.verification.Send(msg); err != nil {
+		applog.Warn("verification email of new user %v not sent: %v", user.Username(), err)
+	}
+
+	if h.requireVerifiedEmail {
+		return authresult.WithoutSession(user), nil
+	}
+
+	tokens, err := h.tokens.Issue(user, uuid.Nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return authresult.New(user, token), nil
+	return authresult.New(user, tokens), nil
 }
 
 // createUser creates a new user with the provided command data and password hashing service.
-func createUser(cmd *Command, hashSvc ihash.Service, isAdmin bool) (*usermodel.User, error) {
+func createUser(cmd *Command, hashSvc ihash.Service, role rolemodel.Role) (*usermodel.User, error) {
 	cfg := usermodel.Config{
 		Username:       cmd.Username,
+		Email:          cmd.Email,
 		PlainPassword:  cmd.Password,
-		IsAdmin:        isAdmin,
+		Role:           role,
 		PasswordHasher: hashSvc,
 	}
 	return usermodel.New(cfg)
@@ -3,12 +3,20 @@ package registercmd_test
 import (
 	"errors"
 	"testing"
+	"time"
 
-	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	inotifier_mock "github.com/beka-birhanu/task_manager_final/app/common/i_notifier/mocks"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
 	registercmd "github.com/beka-birhanu/task_manager_final/app/user/auth/command"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -17,8 +25,10 @@ import (
 type RegisterCommandHandlerTestSuite struct {
 	suite.Suite
 	mockUserRepo *irepo_mock.User
-	mockJwtSvc   *ijwt_mock.MockService
+	mockTokens   *authtoken_mock.IIssuer
 	mockHashSvc  *ihash_mocks.Service
+	mockNotifier *inotifier_mock.Notifier
+	verification *emailverification.Sender
 	handler      *registercmd.Handler
 	adminUser    *usermodel.User
 	normalUser   *usermodel.User
@@ -28,13 +38,20 @@ type RegisterCommandHandlerTestSuite struct {
 // SetupTest sets up the test environment.
 func (suite *RegisterCommandHandlerTestSuite) SetupTest() {
 	suite.mockUserRepo = new(irepo_mock.User)
-	suite.mockJwtSvc = new(ijwt_mock.MockService)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
 	suite.mockHashSvc = new(ihash_mocks.Service)
+	suite.mockNotifier = new(inotifier_mock.Notifier)
+	suite.verification = emailverification.NewSender(emailverification.Config{
+		HashSvc:  suite.mockHashSvc,
+		Notifier: suite.mockNotifier,
+		TTL:      time.Hour,
+	})
 
 	suite.handler = registercmd.NewHandler(registercmd.Config{
-		UserRepo: suite.mockUserRepo,
-		JwtSvc:   suite.mockJwtSvc,
-		HashSvc:  suite.mockHashSvc,
+		UserRepo:     suite.mockUserRepo,
+		Tokens:       suite.mockTokens,
+		HashSvc:      suite.mockHashSvc,
+		Verification: suite.verification,
 	})
 
 	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_password", nil)
@@ -44,7 +61,7 @@ func (suite *RegisterCommandHandlerTestSuite) SetupTest() {
 	suite.adminUser, err = usermodel.New(usermodel.Config{
 		Username:       "adminuser",
 		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
-		IsAdmin:        true,
+		Role:           rolemodel.Admin,
 		PasswordHasher: suite.mockHashSvc,
 	})
 	suite.Require().NoError(err)
@@ -53,7 +70,6 @@ func (suite *RegisterCommandHandlerTestSuite) SetupTest() {
 	suite.normalUser, err = usermodel.New(usermodel.Config{
 		Username:       "normaluser",
 		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
-		IsAdmin:        false,
 		PasswordHasher: suite.mockHashSvc,
 	})
 	suite.Require().NoError(err)
@@ -61,6 +77,7 @@ func (suite *RegisterCommandHandlerTestSuite) SetupTest() {
 	// Initialize the command object
 	suite.cmd = &registercmd.Command{
 		Username: "normaluser",
+		Email:    "normaluser@example.com",
 		Password: "&&^_str0ngp@ssw0rd!@d$",
 	}
 }
@@ -71,7 +88,8 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_Success() {
 	suite.mockHashSvc.On("Hash", suite.cmd.Password).Return("hashed_password", nil)
 	suite.mockUserRepo.On("Count").Return(int64(0), nil)
 	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
-	suite.mockJwtSvc.On("Generate", mock.AnythingOfType("*usermodel.User")).Return("jwt_token", nil)
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).Return(nil)
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
 
 	// Execute the Handle method with the command.
 	result, err := suite.handler.Handle(suite.cmd)
@@ -79,11 +97,13 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_Success() {
 	// Verify the results.
 	suite.Assert().NotNil(result)
 	suite.Assert().Equal("jwt_token", result.Token)
+	suite.Assert().Equal("refresh_token", result.RefreshToken)
+	suite.Assert().Equal(rolemodel.Admin, result.Role)
 	suite.Assert().NoError(err)
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
@@ -100,7 +120,7 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_UserRepoCountError() {
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
@@ -120,16 +140,17 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_UserCreationError() {
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
-// TestHandle_JwtGenerationError tests the scenario where JWT generation fails.
-func (suite *RegisterCommandHandlerTestSuite) TestHandle_JwtGenerationError() {
+// TestHandle_TokenIssueError tests the scenario where issuing the tokens fails.
+func (suite *RegisterCommandHandlerTestSuite) TestHandle_TokenIssueError() {
 	suite.mockHashSvc.On("Hash", suite.cmd.Password).Return("hashed_password", nil)
 	suite.mockUserRepo.On("Count").Return(int64(0), nil)
 	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
-	suite.mockJwtSvc.On("Generate", mock.AnythingOfType("*usermodel.User")).Return("", errors.New("jwt error"))
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).Return(nil)
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(nil, errors.New("jwt error"))
 
 	// Execute the Handle method with the command.
 	result, err := suite.handler.Handle(suite.cmd)
@@ -140,7 +161,7 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_JwtGenerationError() {
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
@@ -148,9 +169,10 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_JwtGenerationError() {
 func (suite *RegisterCommandHandlerTestSuite) TestHandle_NormalUser_Success() {
 	// Mock expected behavior for a normal user
 	suite.mockHashSvc.On("Hash", suite.cmd.Password).Return("hashed_password", nil)
-	suite.mockUserRepo.On("Count").Return(int64(0), nil)
+	suite.mockUserRepo.On("Count").Return(int64(1), nil)
 	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
-	suite.mockJwtSvc.On("Generate", mock.AnythingOfType("*usermodel.User")).Return("jwt_token", nil)
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).Return(nil)
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
 
 	// Execute the Handle method with the command.
 	result, err := suite.handler.Handle(suite.cmd)
@@ -158,14 +180,67 @@ func (suite *RegisterCommandHandlerTestSuite) TestHandle_NormalUser_Success() {
 	// Verify the results.
 	suite.Assert().NotNil(result)
 	suite.Assert().Equal("jwt_token", result.Token)
+	suite.Assert().Equal("refresh_token", result.RefreshToken)
+	suite.Assert().Equal(rolemodel.Member, result.Role)
 	suite.Assert().NoError(err)
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
+// TestHandle_EmailRequired tests that a user cannot register without an email address.
+func (suite *RegisterCommandHandlerTestSuite) TestHandle_EmailRequired() {
+	cmd := suite.cmd
+	cmd.Email = ""
+	result, err := suite.handler.Handle(cmd)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.EmailRequired, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_NotifyError tests that a failure to send the verification token does not fail the registration.
+func (suite *RegisterCommandHandlerTestSuite) TestHandle_NotifyError() {
+	suite.mockUserRepo.On("Count").Return(int64(1), nil)
+	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).Return(errors.New("smtp error"))
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("jwt_token", result.Token)
+	suite.Assert().False(result.EmailVerified)
+	suite.mockNotifier.AssertExpectations(suite.T())
+}
+
+// TestHandle_RequireVerifiedEmail tests that no session is started when verified emails are required.
+func (suite *RegisterCommandHandlerTestSuite) TestHandle_RequireVerifiedEmail() {
+	handler := registercmd.NewHandler(registercmd.Config{
+		UserRepo:             suite.mockUserRepo,
+		Tokens:               suite.mockTokens,
+		HashSvc:              suite.mockHashSvc,
+		Verification:         suite.verification,
+		RequireVerifiedEmail: true,
+	})
+	suite.mockUserRepo.On("Count").Return(int64(1), nil)
+	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
+	suite.mockNotifier.On("Notify", mock.MatchedBy(func(msg *inotifier.Message) bool {
+		return msg.Email == "normaluser@example.com"
+	})).Return(nil)
+
+	result, err := handler.Handle(suite.cmd)
+
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("normaluser@example.com", result.Email)
+	suite.Assert().Empty(result.Token)
+	suite.Assert().Empty(result.RefreshToken)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+	suite.mockNotifier.AssertExpectations(suite.T())
+}
+
 // Run the test suite
 func TestRegisterCommandHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(RegisterCommandHandlerTestSuite))
@@ -1,27 +1,55 @@
 // Package authresult defines the structure for authentication results,
-// including user details and an authentication token.
+// including user details and the issued tokens.
 package authresult
 
 import (
+	"time"
+
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/google/uuid"
 )
 
 // Result represents the outcome of an authentication process,
-// including the user's ID, username, and an authentication token.
+// including the user's ID, username, and the issued tokens.
 type Result struct {
-	ID       uuid.UUID // Unique identifier for the user.
-	Username string    // Username of the authenticated user.
-	Token    string    // Authentication token.
-	IsAdmin  bool
+	ID               uuid.UUID      // Unique identifier for the user.
+	Username         string         // Username of the authenticated user.
+	Email            string         // Email address of the user, if any.
+	EmailVerified    bool           // Whether the user verified their email address.
+	Token            string         // Access token; empty when no session was started.
+	TokenExpiresAt   time.Time      // Expiry of the access token.
+	RefreshToken     string         // Refresh token used to obtain a new access token.
+	RefreshExpiresAt time.Time      // Expiry of the refresh token.
+	MFAChallenge     string         // Token to complete the sign in with a second factor; set instead of the session tokens.
+	Role             rolemodel.Role // Role of the user.
 }
 
-// New creates a new authentication result with the given user and token.
-func New(user *usermodel.User, token string) *Result {
+// New creates a new authentication result with the given user and tokens.
+func New(user *usermodel.User, tokens *authtoken.Pair) *Result {
 	return &Result{
-		ID:       user.ID(),
-		Username: user.Username(),
-		Token:    token,
-		IsAdmin:  user.IsAdmin(),
+		ID:               user.ID(),
+		Username:         user.Username(),
+		Email:            user.Email(),
+		EmailVerified:    user.IsEmailVerified(),
+		Token:            tokens.AccessToken,
+		TokenExpiresAt:   tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+		Role:             user.Role(),
 	}
 }
+
+// WithoutSession creates a new result for a user who is not signed in yet.
+func WithoutSession(user *usermodel.User) *Result {
+	return New(user, &authtoken.Pair{})
+}
+
+// WithChallenge creates a new result for a user who still has to prove their second
+// factor with the given challenge token.
+func WithChallenge(user *usermodel.User, challenge string) *Result {
+	result := WithoutSession(user)
+	result.MFAChallenge = challenge
+	return result
+}
@@ -0,0 +1,125 @@
+// Package lockout protects sign in against guessing passwords and second factor codes.
+//
+// Failed attempts are counted both per username and per client IP address. Once either
+// reaches the failures allowed by its policy, signing in is refused with
+// errdmn.LoginLocked for a lockout that doubles with each further failure. Usernames are
+// tracked whether or not a user has them, so a lockout does not tell they exist.
+package lockout
+
+import (
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+)
+
+// IGuard tracks failed sign in attempts and refuses locked ones.
+type IGuard interface {
+	// Check fails with errdmn.LoginLocked if signing in as the user or from the IP address is locked.
+	Check(username, ip string) error
+
+	// Fail records a failed attempt to sign in as the user from the IP address.
+	Fail(username, ip string) error
+
+	// Succeed forgets the failed attempts to sign in as the user.
+	Succeed(username string) error
+}
+
+// Guard tracks failed sign in attempts in a repository.
+type Guard struct {
+	attemptRepo irepo.LoginAttempt       // Repository for failed login attempts.
+	userPolicy  loginattemptmodel.Policy // Thresholds applied per username.
+	ipPolicy    loginattemptmodel.Policy // Thresholds applied per client IP address.
+}
+
+// Ensure Guard implements IGuard.
+var _ IGuard = &Guard{}
+
+// Config holds the dependencies for creating a new Guard.
+type Config struct {
+	AttemptRepo irepo.LoginAttempt       // Repository for failed login attempts.
+	UserPolicy  loginattemptmodel.Policy // Thresholds applied per username.
+	IPPolicy    loginattemptmodel.Policy // Thresholds applied per client IP address.
+}
+
+// NewGuard creates a new Guard with the given configuration.
+func NewGuard(cfg Config) *Guard {
+	return &Guard{
+		attemptRepo: cfg.AttemptRepo,
+		userPolicy:  cfg.UserPolicy,
+		ipPolicy:    cfg.IPPolicy,
+	}
+}
+
+// UserKey returns the key the failed attempts to sign in as the user are recorded under.
+func UserKey(username string) string {
+	return "user:" + username
+}
+
+// IPKey returns the key the failed attempts from the IP address are recorded under.
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check fails with errdmn.LoginLocked if either key is locked.
+func (g *Guard) Check(username, ip string) error {
+	now := time.Now().UTC()
+	for _, key := range g.keys(username, ip) {
+		attempt, err := g.attemptRepo.ByKey(key)
+		if err == errdmn.LoginAttemptNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if attempt.IsLocked(now) {
+			return errdmn.LoginLocked
+		}
+	}
+	return nil
+}
+
+// Fail counts the failed attempt under both keys, locking each that reaches its threshold.
+func (g *Guard) Fail(username, ip string) error {
+	if err := g.fail(UserKey(username), g.userPolicy); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.fail(IPKey(ip), g.ipPolicy)
+}
+
+// Succeed forgets the failed attempts to sign in as the user. The failures of the IP
+// address are kept, so one known password cannot be used to guess others from it.
+func (g *Guard) Succeed(username string) error {
+	return g.attemptRepo.Delete(UserKey(username))
+}
+
+// fail counts a failed attempt under the key and locks it as the policy requires.
+func (g *Guard) fail(key string, policy loginattemptmodel.Policy) error {
+	if policy.MaxFailures <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	attempt, err := g.attemptRepo.AddFailure(key, now, policy.Window)
+	if err != nil {
+		return err
+	}
+
+	if lockout := policy.Lockout(attempt.Failures(now)); lockout > 0 {
+		return g.attemptRepo.Lock(key, now.Add(lockout))
+	}
+	return nil
+}
+
+// keys returns the keys the attempt to sign in as the user from the IP address is checked against.
+func (g *Guard) keys(username, ip string) []string {
+	keys := []string{UserKey(username)}
+	if ip != "" {
+		keys = append(keys, IPKey(ip))
+	}
+	return keys
+}
@@ -0,0 +1,128 @@
+package lockout_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// GuardTestSuite defines the test suite for the lockout Guard.
+type GuardTestSuite struct {
+	suite.Suite
+	mockAttemptRepo *irepo_mock.LoginAttempt
+	guard           *lockout.Guard
+}
+
+// SetupTest sets up the test environment.
+func (suite *GuardTestSuite) SetupTest() {
+	suite.mockAttemptRepo = new(irepo_mock.LoginAttempt)
+
+	suite.guard = lockout.NewGuard(lockout.Config{
+		AttemptRepo: suite.mockAttemptRepo,
+		UserPolicy: loginattemptmodel.Policy{
+			MaxFailures: 3,
+			Window:      15 * time.Minute,
+			BaseLockout: time.Minute,
+			MaxLockout:  time.Hour,
+		},
+		IPPolicy: loginattemptmodel.Policy{
+			MaxFailures: 10,
+			Window:      time.Hour,
+			BaseLockout: time.Minute,
+			MaxLockout:  time.Hour,
+		},
+	})
+}
+
+// attempt returns a record of the given failures locked until the given time.
+func attempt(key string, failures int, lockedUntil time.Time) *loginattemptmodel.LoginAttempt {
+	return loginattemptmodel.FromBSON(&loginattemptmodel.LoginAttemptBSON{
+		Key:         key,
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+}
+
+// TestCheck_NotLocked tests that unknown and unlocked keys are let through.
+func (suite *GuardTestSuite) TestCheck_NotLocked() {
+	suite.mockAttemptRepo.On("ByKey", "user:testuser").Return(attempt("user:testuser", 2, time.Time{}), nil)
+	suite.mockAttemptRepo.On("ByKey", "ip:10.0.0.1").Return(nil, errdmn.LoginAttemptNotFound)
+
+	suite.NoError(suite.guard.Check("testuser", "10.0.0.1"))
+	suite.mockAttemptRepo.AssertExpectations(suite.T())
+}
+
+// TestCheck_UserLocked tests that a locked username is refused.
+func (suite *GuardTestSuite) TestCheck_UserLocked() {
+	suite.mockAttemptRepo.On("ByKey", "user:testuser").Return(attempt("user:testuser", 3, time.Now().Add(time.Minute)), nil)
+
+	suite.Equal(errdmn.LoginLocked, suite.guard.Check("testuser", "10.0.0.1"))
+}
+
+// TestCheck_IPLocked tests that a locked IP address is refused whatever the username.
+func (suite *GuardTestSuite) TestCheck_IPLocked() {
+	suite.mockAttemptRepo.On("ByKey", "user:testuser").Return(nil, errdmn.LoginAttemptNotFound)
+	suite.mockAttemptRepo.On("ByKey", "ip:10.0.0.1").Return(attempt("ip:10.0.0.1", 10, time.Now().Add(time.Minute)), nil)
+
+	suite.Equal(errdmn.LoginLocked, suite.guard.Check("testuser", "10.0.0.1"))
+}
+
+// TestCheck_RepoError tests that repository errors are returned.
+func (suite *GuardTestSuite) TestCheck_RepoError() {
+	repoErr := errors.New("repo error")
+	suite.mockAttemptRepo.On("ByKey", "user:testuser").Return(nil, repoErr)
+
+	suite.Equal(repoErr, suite.guard.Check("testuser", "10.0.0.1"))
+}
+
+// TestFail_BelowThreshold tests that failures below the thresholds lock nothing.
+func (suite *GuardTestSuite) TestFail_BelowThreshold() {
+	suite.mockAttemptRepo.On("AddFailure", "user:testuser", mock.AnythingOfType("time.Time"), 15*time.Minute).Return(attempt("user:testuser", 2, time.Time{}), nil)
+	suite.mockAttemptRepo.On("AddFailure", "ip:10.0.0.1", mock.AnythingOfType("time.Time"), time.Hour).Return(attempt("ip:10.0.0.1", 2, time.Time{}), nil)
+
+	suite.NoError(suite.guard.Fail("testuser", "10.0.0.1"))
+	suite.mockAttemptRepo.AssertExpectations(suite.T())
+	suite.mockAttemptRepo.AssertNotCalled(suite.T(), "Lock", mock.Anything, mock.Anything)
+}
+
+// TestFail_Locks tests that reaching the threshold locks the key, doubling with each further failure.
+func (suite *GuardTestSuite) TestFail_Locks() {
+	suite.mockAttemptRepo.On("AddFailure", "user:testuser", mock.AnythingOfType("time.Time"), 15*time.Minute).Return(attempt("user:testuser", 4, time.Time{}), nil)
+	suite.mockAttemptRepo.On("AddFailure", "ip:10.0.0.1", mock.AnythingOfType("time.Time"), time.Hour).Return(attempt("ip:10.0.0.1", 4, time.Time{}), nil)
+	suite.mockAttemptRepo.On("Lock", "user:testuser", mock.MatchedBy(func(until time.Time) bool {
+		lockout := time.Until(until)
+		return lockout > time.Minute && lockout <= 2*time.Minute
+	})).Return(nil)
+
+	suite.NoError(suite.guard.Fail("testuser", "10.0.0.1"))
+	suite.mockAttemptRepo.AssertExpectations(suite.T())
+}
+
+// TestFail_WithoutIP tests that only the username is tracked when the IP address is unknown.
+func (suite *GuardTestSuite) TestFail_WithoutIP() {
+	suite.mockAttemptRepo.On("AddFailure", "user:testuser", mock.AnythingOfType("time.Time"), 15*time.Minute).Return(attempt("user:testuser", 1, time.Time{}), nil)
+
+	suite.NoError(suite.guard.Fail("testuser", ""))
+	suite.mockAttemptRepo.AssertNumberOfCalls(suite.T(), "AddFailure", 1)
+}
+
+// TestSucceed tests that the failures of the username are forgotten.
+func (suite *GuardTestSuite) TestSucceed() {
+	suite.mockAttemptRepo.On("Delete", "user:testuser").Return(nil)
+
+	suite.NoError(suite.guard.Succeed("testuser"))
+	suite.mockAttemptRepo.AssertExpectations(suite.T())
+}
+
+// TestGuardTestSuite runs the test suite.
+func TestGuardTestSuite(t *testing.T) {
+	suite.Run(t, new(GuardTestSuite))
+}
@@ -0,0 +1,28 @@
+package lockout_mock
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// IGuard is a mock implementation of the IGuard interface using testify.
+type IGuard struct {
+	mock.Mock
+}
+
+// Check mocks the Check method of the IGuard interface.
+func (m *IGuard) Check(username, ip string) error {
+	args := m.Called(username, ip)
+	return args.Error(0)
+}
+
+// Fail mocks the Fail method of the IGuard interface.
+func (m *IGuard) Fail(username, ip string) error {
+	args := m.Called(username, ip)
+	return args.Error(0)
+}
+
+// Succeed mocks the Succeed method of the IGuard interface.
+func (m *IGuard) Succeed(username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
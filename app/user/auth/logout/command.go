@@ -0,0 +1,34 @@
+package logoutcmd
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Command represents the data required to end one or all sessions of a user.
+// The token fields come from the access token used for the request.
+type Command struct {
+	UserID     uuid.UUID // User logging out.
+	TokenID    string    // ID of the access token (jti claim).
+	SessionID  uuid.UUID // Session of the access token (sid claim).
+	ExpiresAt  time.Time // Expiry of the access token.
+	AllDevices bool      // End every session of the user, not only the current one.
+}
+
+// NewCommand creates a new Command instance ending the session of the given access token.
+func NewCommand(userID uuid.UUID, tokenID string, sessionID uuid.UUID, expiresAt time.Time) *Command {
+	return &Command{
+		UserID:    userID,
+		TokenID:   tokenID,
+		SessionID: sessionID,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// NewAllDevicesCommand creates a new Command instance ending every session of the user.
+func NewAllDevicesCommand(userID uuid.UUID, tokenID string, sessionID uuid.UUID, expiresAt time.Time) *Command {
+	cmd := NewCommand(userID, tokenID, sessionID, expiresAt)
+	cmd.AllDevices = true
+	return cmd
+}
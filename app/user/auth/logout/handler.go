@@ -0,0 +1,60 @@
+// Package logoutcmd provides the command and handler for logging a user out.
+//
+// Logging out revokes the access token used for the request and the session it belongs to:
+// the session's refresh tokens are removed and its other access tokens are rejected until
+// they would have expired anyway. Logging out from all devices does the same for every
+// session of the user.
+package logoutcmd
+
+import (
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+)
+
+// Handler handles the logout process.
+type Handler struct {
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle revokes the access token of the command and the sessions it ends.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	if err := h.revocations.Revoke(cmd.TokenID, cmd.ExpiresAt); err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeSession(h.refreshRepo, h.revocations, cmd.SessionID, h.accessTTL); err != nil {
+		return false, err
+	}
+
+	if cmd.AllDevices {
+		if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, cmd.UserID, h.accessTTL); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
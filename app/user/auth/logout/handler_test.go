@@ -0,0 +1,88 @@
+package logoutcmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	logoutcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/logout"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// LogoutHandlerTestSuite defines the test suite for the logout handler.
+type LogoutHandlerTestSuite struct {
+	suite.Suite
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	handler         *logoutcmd.Handler
+	cmd             *logoutcmd.Command
+}
+
+// SetupTest sets up the test environment.
+func (suite *LogoutHandlerTestSuite) SetupTest() {
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+
+	suite.handler = logoutcmd.NewHandler(logoutcmd.Config{
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   15 * time.Minute,
+	})
+
+	suite.cmd = logoutcmd.NewCommand(uuid.New(), "token-id", uuid.New(), time.Now().Add(10*time.Minute))
+}
+
+// TestHandle_Success tests that logging out revokes the access token and its session.
+func (suite *LogoutHandlerTestSuite) TestHandle_Success() {
+	suite.mockRevocations.On("Revoke", suite.cmd.TokenID, suite.cmd.ExpiresAt).Return(nil)
+	suite.mockRefreshRepo.On("RevokeFamily", suite.cmd.SessionID).Return(nil)
+	suite.mockRevocations.On("Revoke", suite.cmd.SessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	ok, err := suite.handler.Handle(suite.cmd)
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_AllDevices tests that logging out from all devices revokes every session of the user.
+func (suite *LogoutHandlerTestSuite) TestHandle_AllDevices() {
+	cmd := logoutcmd.NewAllDevicesCommand(suite.cmd.UserID, suite.cmd.TokenID, suite.cmd.SessionID, suite.cmd.ExpiresAt)
+	otherSession := uuid.New()
+
+	suite.mockRevocations.On("Revoke", cmd.TokenID, cmd.ExpiresAt).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", cmd.UserID).Return([]uuid.UUID{otherSession}, nil)
+	for _, sessionID := range []uuid.UUID{cmd.SessionID, otherSession} {
+		suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+		suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+	}
+
+	ok, err := suite.handler.Handle(cmd)
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_RevokeError tests that a failure to revoke the access token is returned.
+func (suite *LogoutHandlerTestSuite) TestHandle_RevokeError() {
+	suite.mockRevocations.On("Revoke", suite.cmd.TokenID, suite.cmd.ExpiresAt).Return(errdmn.NewUnexpected("store down"))
+
+	ok, err := suite.handler.Handle(suite.cmd)
+
+	suite.Error(err)
+	suite.False(ok)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "RevokeFamily", mock.Anything)
+}
+
+// Run the test suite
+func TestLogoutHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(LogoutHandlerTestSuite))
+}
@@ -0,0 +1,17 @@
+package mfacmd
+
+// Command represents the data required to complete a sign in with a second factor.
+type Command struct {
+	Challenge string // MFA challenge token returned by the password check.
+	Code      string // TOTP code or recovery code of the user.
+	IP        string // Address of the client signing in.
+}
+
+// NewCommand creates a new Command instance with the given challenge token, code, and client address.
+func NewCommand(challenge, code, ip string) *Command {
+	return &Command{
+		Challenge: challenge,
+		Code:      code,
+		IP:        ip,
+	}
+}
@@ -0,0 +1,110 @@
+// Package mfacmd provides the command and handler for the second step of signing in
+// with two-factor authentication: exchanging the MFA challenge token and a code for
+// the user's tokens.
+//
+// Wrong codes count as failed sign in attempts, so codes cannot be guessed any faster
+// than passwords.
+package mfacmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	mfacode "github.com/beka-birhanu/task_manager_final/app/user/mfa/code"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	"github.com/google/uuid"
+)
+
+// Handler handles the second factor step of signing in.
+type Handler struct {
+	userRepo   irepo.User        // Repository for user data operations.
+	challenges ijwt.Challenges   // Validates MFA challenge tokens.
+	totp       itotp.Service     // Checks TOTP codes.
+	hashSvc    ihash.Service     // Service for matching recovery codes.
+	tokens     authtoken.IIssuer // Issuer of access and refresh tokens.
+	guard      lockout.IGuard    // Tracks failed attempts and refuses locked ones.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *authresult.Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo   irepo.User        // Repository for user data operations.
+	Challenges ijwt.Challenges   // Validates MFA challenge tokens.
+	TOTP       itotp.Service     // Checks TOTP codes.
+	HashSvc    ihash.Service     // Service for matching recovery codes.
+	Tokens     authtoken.IIssuer // Issuer of access and refresh tokens.
+	Guard      lockout.IGuard    // Tracks failed attempts and refuses locked ones.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:   cfg.UserRepo,
+		challenges: cfg.Challenges,
+		totp:       cfg.TOTP,
+		hashSvc:    cfg.HashSvc,
+		tokens:     cfg.Tokens,
+		guard:      cfg.Guard,
+	}
+}
+
+// Handle validates the challenge token and the code, and issues the user's tokens.
+func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
+	userID, err := h.challenges.DecodeChallenge(cmd.Challenge)
+	if err != nil {
+		return nil, errdmn.InvalidMFAChallenge
+	}
+
+	user, err := h.userRepo.ById(userID)
+	if err != nil {
+		if err == errdmn.UserNotFound {
+			return nil, errdmn.InvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	// Two-factor authentication may have been disabled since the challenge was issued.
+	if !user.IsMFAEnabled() {
+		return nil, errdmn.InvalidMFAChallenge
+	}
+
+	// The user may have been deactivated since the challenge was issued.
+	if user.IsDeactivated() {
+		return nil, errdmn.UserDeactivated
+	}
+
+	if err := h.guard.Check(user.Username(), cmd.IP); err != nil {
+		return nil, err
+	}
+
+	if err := mfacode.Check(user, cmd.Code, h.totp, h.hashSvc); err != nil {
+		if err == errdmn.InvalidMFACode {
+			if err := h.guard.Fail(user.Username(), cmd.IP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	// Records the used code.
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+
+	if err := h.guard.Succeed(user.Username()); err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.tokens.Issue(user, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	return authresult.New(user, tokens), nil
+}
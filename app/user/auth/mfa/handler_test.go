@@ -0,0 +1,158 @@
+package mfacmd_test
+
+import (
+	"errors"
+	"testing"
+
+	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	itotp_mock "github.com/beka-birhanu/task_manager_final/app/common/i_totp/mocks"
+	lockout_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/lockout/mocks"
+	mfacmd "github.com/beka-birhanu/task_manager_final/app/user/auth/mfa"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// MFALoginHandlerTestSuite defines the test suite for the MFA sign in handler.
+type MFALoginHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo   *irepo_mock.User
+	mockChallenges *ijwt_mock.MockChallenges
+	mockTOTP       *itotp_mock.Service
+	mockHashSvc    *ihash_mocks.Service
+	mockTokens     *authtoken_mock.IIssuer
+	mockGuard      *lockout_mock.IGuard
+	handler        *mfacmd.Handler
+	user           *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *MFALoginHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockChallenges = new(ijwt_mock.MockChallenges)
+	suite.mockTOTP = new(itotp_mock.Service)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
+	suite.mockGuard = new(lockout_mock.IGuard)
+
+	suite.handler = mfacmd.NewHandler(mfacmd.Config{
+		UserRepo:   suite.mockUserRepo,
+		Challenges: suite.mockChallenges,
+		TOTP:       suite.mockTOTP,
+		HashSvc:    suite.mockHashSvc,
+		Tokens:     suite.mockTokens,
+		Guard:      suite.mockGuard,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                 uuid.New(),
+		Username:           "normaluser",
+		TOTPSecret:         "SECRET",
+		TOTPLastStep:       100,
+		RecoveryCodeHashes: []string{"hashed_recovery_code"},
+	})
+	suite.mockChallenges.On("DecodeChallenge", "challenge").Return(suite.user.ID(), nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockGuard.On("Check", "normaluser", "10.0.0.1").Return(nil)
+}
+
+// TestHandle_TOTP tests that a valid TOTP code completes the sign in.
+func (suite *MFALoginHandlerTestSuite) TestHandle_TOTP() {
+	suite.mockTOTP.On("Validate", "SECRET", "123456").Return(int64(101), true)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockGuard.On("Succeed", "normaluser").Return(nil)
+	suite.mockTokens.On("Issue", suite.user, uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
+
+	result, err := suite.handler.Handle(mfacmd.NewCommand("challenge", "123456", "10.0.0.1"))
+
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+	suite.Equal(int64(101), suite.user.TOTPLastStep())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
+	suite.mockGuard.AssertExpectations(suite.T())
+}
+
+// TestHandle_TOTPReused tests that a TOTP code cannot be used twice.
+func (suite *MFALoginHandlerTestSuite) TestHandle_TOTPReused() {
+	suite.mockTOTP.On("Validate", "SECRET", "123456").Return(int64(100), true)
+	suite.mockGuard.On("Fail", "normaluser", "10.0.0.1").Return(nil)
+
+	result, err := suite.handler.Handle(mfacmd.NewCommand("challenge", "123456", "10.0.0.1"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidMFACode, err)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_RecoveryCode tests that a recovery code completes the sign in and is used up.
+func (suite *MFALoginHandlerTestSuite) TestHandle_RecoveryCode() {
+	suite.mockTOTP.On("Validate", "SECRET", "ABCDE-fghij").Return(int64(0), false)
+	suite.mockHashSvc.On("Match", "hashed_recovery_code", "abcdefghij").Return(true, nil)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockGuard.On("Succeed", "normaluser").Return(nil)
+	suite.mockTokens.On("Issue", suite.user, uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
+
+	result, err := suite.handler.Handle(mfacmd.NewCommand("challenge", "ABCDE-fghij", "10.0.0.1"))
+
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+	suite.Empty(suite.user.RecoveryCodeHashes())
+}
+
+// TestHandle_InvalidCode tests that a wrong code is rejected and counted as a failed attempt.
+func (suite *MFALoginHandlerTestSuite) TestHandle_InvalidCode() {
+	suite.mockTOTP.On("Validate", "SECRET", "654321").Return(int64(0), false)
+	suite.mockGuard.On("Fail", "normaluser", "10.0.0.1").Return(nil)
+
+	result, err := suite.handler.Handle(mfacmd.NewCommand("challenge", "654321", "10.0.0.1"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidMFACode, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+	suite.mockGuard.AssertExpectations(suite.T())
+}
+
+// TestHandle_Locked tests that no code is checked while signing in is locked.
+func (suite *MFALoginHandlerTestSuite) TestHandle_Locked() {
+	suite.mockGuard = new(lockout_mock.IGuard)
+	suite.mockGuard.On("Check", "normaluser", "10.0.0.1").Return(errdmn.LoginLocked)
+	handler := mfacmd.NewHandler(mfacmd.Config{
+		UserRepo:   suite.mockUserRepo,
+		Challenges: suite.mockChallenges,
+		TOTP:       suite.mockTOTP,
+		HashSvc:    suite.mockHashSvc,
+		Tokens:     suite.mockTokens,
+		Guard:      suite.mockGuard,
+	})
+
+	result, err := handler.Handle(mfacmd.NewCommand("challenge", "123456", "10.0.0.1"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.LoginLocked, err)
+	suite.mockTOTP.AssertNotCalled(suite.T(), "Validate", mock.Anything, mock.Anything)
+}
+
+// TestHandle_InvalidChallenge tests that invalid challenges and users without two-factor authentication are rejected.
+func (suite *MFALoginHandlerTestSuite) TestHandle_InvalidChallenge() {
+	suite.mockChallenges.On("DecodeChallenge", "forged").Return(uuid.Nil, errors.New("signature is invalid"))
+	result, err := suite.handler.Handle(mfacmd.NewCommand("forged", "123456", "10.0.0.1"))
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidMFAChallenge, err)
+
+	suite.Require().NoError(suite.user.DisableMFA())
+	result, err = suite.handler.Handle(mfacmd.NewCommand("challenge", "123456", "10.0.0.1"))
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidMFAChallenge, err)
+}
+
+// Run the test suite
+func TestMFALoginHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(MFALoginHandlerTestSuite))
+}
@@ -0,0 +1,17 @@
+package oidccallbackcmd
+
+// Command represents the data the identity provider sends the user back with.
+type Command struct {
+	Code       string // Authorization code issued by the provider.
+	State      string // State the provider sent back.
+	StateToken string // Login state token returned when the sign in started.
+}
+
+// NewCommand creates a new Command instance with the given authorization code, state, and login state token.
+func NewCommand(code, state, stateToken string) *Command {
+	return &Command{
+		Code:       code,
+		State:      state,
+		StateToken: stateToken,
+	}
+}
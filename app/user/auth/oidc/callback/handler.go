@@ -0,0 +1,107 @@
+// Package oidccallbackcmd provides the command and handler completing a single sign-on:
+// redeeming the authorization code the OpenID Connect identity provider sent the user
+// back with, and issuing the tokens of the user it identifies.
+//
+// Users are found by the account at the provider linked to them. Users signing in for
+// the first time are provisioned just in time: an existing user is linked when both the
+// provider and the user verified the same email address, and a new user without a
+// password is created otherwise.
+//
+// Users with two-factor authentication enabled get an MFA challenge token instead of
+// their tokens, as with a password sign in.
+package oidccallbackcmd
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/google/uuid"
+)
+
+// Handler handles the completion of single sign-ons.
+type Handler struct {
+	userRepo             irepo.User        // Repository for user data operations.
+	provider             ioidc.Provider    // Identity provider the users sign in at.
+	states               ijwt.LoginStates  // Validates login state tokens.
+	tokens               authtoken.IIssuer // Issuer of access and refresh tokens.
+	challenges           ijwt.Challenges   // Issuer of MFA challenge tokens.
+	requireVerifiedEmail bool              // Refuse users whose email is not verified.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *authresult.Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo             irepo.User        // Repository for user data operations.
+	Provider             ioidc.Provider    // Identity provider the users sign in at.
+	States               ijwt.LoginStates  // Validates login state tokens.
+	Tokens               authtoken.IIssuer // Issuer of access and refresh tokens.
+	Challenges           ijwt.Challenges   // Issuer of MFA challenge tokens.
+	RequireVerifiedEmail bool              // Refuse users whose email is not verified.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:             cfg.UserRepo,
+		provider:             cfg.Provider,
+		states:               cfg.States,
+		tokens:               cfg.Tokens,
+		challenges:           cfg.Challenges,
+		requireVerifiedEmail: cfg.RequireVerifiedEmail,
+	}
+}
+
+// Handle checks the state, redeems the authorization code, provisions the user if they
+// sign in for the first time, and issues their tokens or an MFA challenge token.
+func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
+	loginState, err := h.states.DecodeLoginState(cmd.StateToken)
+	if err != nil {
+		return nil, errdmn.InvalidSSOState
+	}
+	// The state ties the callback to the browser that started the sign in.
+	if subtle.ConstantTimeCompare([]byte(cmd.State), []byte(loginState.State)) != 1 {
+		return nil, errdmn.InvalidSSOState
+	}
+
+	identity, err := h.provider.Exchange(cmd.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, errdmn.SSOLoginFailed
+	}
+
+	user, err := h.provision(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDeactivated() {
+		return nil, errdmn.UserDeactivated
+	}
+
+	if h.requireVerifiedEmail && user.Email() != "" && !user.IsEmailVerified() {
+		return nil, errdmn.EmailNotVerified
+	}
+
+	if user.IsMFAEnabled() {
+		challenge, err := h.challenges.GenerateChallenge(user)
+		if err != nil {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate mfa challenge, %v", err))
+		}
+		return authresult.WithChallenge(user, challenge), nil
+	}
+
+	// Issue tokens for the authenticated user, starting a new refresh token family.
+	tokens, err := h.tokens.Issue(user, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	return authresult.New(user, tokens), nil
+}
@@ -0,0 +1,270 @@
+package oidccallbackcmd_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	ioidc_mock "github.com/beka-birhanu/task_manager_final/app/common/i_oidc/mocks"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	oidccallbackcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/callback"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// CallbackHandlerTestSuite defines the test suite for the single sign-on callback handler.
+type CallbackHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo   *irepo_mock.User
+	mockProvider   *ioidc_mock.Provider
+	mockStates     *ijwt_mock.MockLoginStates
+	mockTokens     *authtoken_mock.IIssuer
+	mockChallenges *ijwt_mock.MockChallenges
+	handler        *oidccallbackcmd.Handler
+	command        *oidccallbackcmd.Command
+	identity       *ioidc.Identity
+	external       usermodel.ExternalIdentity
+	tokens         *authtoken.Pair
+}
+
+// SetupTest sets up the test environment.
+func (suite *CallbackHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockProvider = new(ioidc_mock.Provider)
+	suite.mockStates = new(ijwt_mock.MockLoginStates)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
+	suite.mockChallenges = new(ijwt_mock.MockChallenges)
+
+	suite.handler = oidccallbackcmd.NewHandler(oidccallbackcmd.Config{
+		UserRepo:   suite.mockUserRepo,
+		Provider:   suite.mockProvider,
+		States:     suite.mockStates,
+		Tokens:     suite.mockTokens,
+		Challenges: suite.mockChallenges,
+	})
+
+	suite.command = oidccallbackcmd.NewCommand("auth_code", "state", "state_token")
+	suite.mockStates.On("DecodeLoginState", "state_token").
+		Return(&ijwt.LoginState{State: "state", Nonce: "nonce", CodeVerifier: "verifier"}, nil)
+
+	suite.identity = &ioidc.Identity{
+		Issuer:            "https://sso.example.com",
+		Subject:           "248289761001",
+		Email:             "Jane@Example.com",
+		EmailVerified:     true,
+		PreferredUsername: "jane.doe",
+		Name:              "Jane Doe",
+	}
+	suite.external = usermodel.ExternalIdentity{Issuer: suite.identity.Issuer, Subject: suite.identity.Subject}
+	suite.tokens = &authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}
+}
+
+// exchanges makes the provider redeem the code for the identity of the suite.
+func (suite *CallbackHandlerTestSuite) exchanges() {
+	suite.mockProvider.On("Exchange", "auth_code", "verifier", "nonce").Return(suite.identity, nil)
+}
+
+// TestHandle_LinkedUser tests that a user signing in again is found by their identity.
+func (suite *CallbackHandlerTestSuite) TestHandle_LinkedUser() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "jane"})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(user, nil)
+	suite.mockTokens.On("Issue", user, uuid.Nil).Return(suite.tokens, nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.NoError(err)
+	suite.Equal(user.ID(), result.ID)
+	suite.Equal("jwt_token", result.Token)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_NewUser tests that a user signing in for the first time is created without a password.
+func (suite *CallbackHandlerTestSuite) TestHandle_NewUser() {
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByEmail", "jane@example.com").Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByUsername", "jane_doe").Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(suite.tokens, nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+
+	user := suite.mockUserRepo.Calls[len(suite.mockUserRepo.Calls)-1].Arguments.Get(0).(*usermodel.User)
+	suite.Equal("jane_doe", user.Username())
+	suite.Equal("jane@example.com", user.Email())
+	suite.True(user.IsEmailVerified())
+	suite.Equal("Jane Doe", user.DisplayName())
+	suite.False(user.HasPassword())
+	suite.Equal([]usermodel.ExternalIdentity{suite.external}, user.ExternalIdentities())
+}
+
+// TestHandle_NewUser_UsernameTaken tests that a taken username is numbered.
+func (suite *CallbackHandlerTestSuite) TestHandle_NewUser_UsernameTaken() {
+	taken := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "jane_doe"})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByEmail", "jane@example.com").Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByUsername", "jane_doe").Return(taken, nil)
+	suite.mockUserRepo.On("ByUsername", mock.AnythingOfType("string")).Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
+	suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(suite.tokens, nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.NoError(err)
+	suite.Regexp(regexp.MustCompile(`^jane_doe_\d{4}$`), result.Username)
+}
+
+// TestHandle_NewUser_Usernames tests how usernames are derived from what the provider shares.
+func (suite *CallbackHandlerTestSuite) TestHandle_NewUser_Usernames() {
+	tests := []struct {
+		preferred string
+		email     string
+		username  string
+	}{
+		{preferred: "jdoe", email: "jane@example.com", username: "jdoe"},
+		{preferred: "", email: "jane.doe@example.com", username: "jane_doe"},
+		{preferred: "Jöhn Dœ", email: "", username: "J_hn_D"},
+		{preferred: "a-very-long-preferred-username", email: "", username: "a_very_long_preferre"},
+		{preferred: "李", email: "", username: "user"},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.username, func() {
+			suite.SetupTest()
+			suite.identity.PreferredUsername = tt.preferred
+			suite.identity.Email = tt.email
+			suite.identity.EmailVerified = false
+			suite.exchanges()
+			suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(nil, errdmn.UserNotFound)
+			suite.mockUserRepo.On("ByUsername", mock.AnythingOfType("string")).Return(nil, errdmn.UserNotFound)
+			suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(nil)
+			suite.mockTokens.On("Issue", mock.AnythingOfType("*usermodel.User"), uuid.Nil).Return(suite.tokens, nil)
+
+			result, err := suite.handler.Handle(suite.command)
+
+			suite.NoError(err)
+			suite.Equal(tt.username, result.Username)
+			suite.mockUserRepo.AssertNotCalled(suite.T(), "ByEmail", mock.Anything)
+		})
+	}
+}
+
+// TestHandle_LinkByVerifiedEmail tests that a user who verified the email the provider vouches for is linked.
+func (suite *CallbackHandlerTestSuite) TestHandle_LinkByVerifiedEmail() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:            uuid.New(),
+		Username:      "jane",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		PasswordHash:  "hashed_password",
+	})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByEmail", "jane@example.com").Return(user, nil)
+	suite.mockUserRepo.On("Save", user).Return(nil)
+	suite.mockTokens.On("Issue", user, uuid.Nil).Return(suite.tokens, nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.NoError(err)
+	suite.Equal(user.ID(), result.ID)
+	suite.Equal([]usermodel.ExternalIdentity{suite.external}, user.ExternalIdentities())
+	suite.True(user.HasPassword())
+}
+
+// TestHandle_UnverifiedEmailNotLinked tests that an account whose email was never verified is not taken over.
+func (suite *CallbackHandlerTestSuite) TestHandle_UnverifiedEmailNotLinked() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:           uuid.New(),
+		Username:     "jane",
+		Email:        "jane@example.com",
+		PasswordHash: "hashed_password",
+	})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("ByEmail", "jane@example.com").Return(user, nil)
+	suite.mockUserRepo.On("ByUsername", "jane_doe").Return(nil, errdmn.UserNotFound)
+	suite.mockUserRepo.On("Save", mock.AnythingOfType("*usermodel.User")).Return(errdmn.EmailConflict)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.EmailConflict, err)
+	suite.Empty(user.ExternalIdentities())
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_InvalidStateToken tests that a missing or forged login state token is refused.
+func (suite *CallbackHandlerTestSuite) TestHandle_InvalidStateToken() {
+	suite.mockStates.On("DecodeLoginState", "").Return(nil, errors.New("token is malformed"))
+
+	result, err := suite.handler.Handle(oidccallbackcmd.NewCommand("auth_code", "state", ""))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidSSOState, err)
+	suite.mockProvider.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestHandle_StateMismatch tests that a callback not started from the same browser is refused.
+func (suite *CallbackHandlerTestSuite) TestHandle_StateMismatch() {
+	result, err := suite.handler.Handle(oidccallbackcmd.NewCommand("auth_code", "other_state", "state_token"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidSSOState, err)
+	suite.mockProvider.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestHandle_ExchangeFailed tests that a code the provider refuses fails the sign in.
+func (suite *CallbackHandlerTestSuite) TestHandle_ExchangeFailed() {
+	suite.mockProvider.On("Exchange", "auth_code", "verifier", "nonce").Return(nil, errors.New("invalid_grant"))
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.SSOLoginFailed, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByExternalIdentity", mock.Anything)
+}
+
+// TestHandle_Deactivated tests that deactivated users cannot sign in with single sign-on either.
+func (suite *CallbackHandlerTestSuite) TestHandle_Deactivated() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "jane", Deactivated: true})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(user, nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserDeactivated, err)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_MFAChallenge tests that users with two-factor authentication get a challenge instead of their tokens.
+func (suite *CallbackHandlerTestSuite) TestHandle_MFAChallenge() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "jane", TOTPSecret: "SECRET"})
+	suite.exchanges()
+	suite.mockUserRepo.On("ByExternalIdentity", suite.external).Return(user, nil)
+	suite.mockChallenges.On("GenerateChallenge", user).Return("challenge_token", nil)
+
+	result, err := suite.handler.Handle(suite.command)
+
+	suite.NoError(err)
+	suite.Equal("challenge_token", result.MFAChallenge)
+	suite.Empty(result.Token)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+func TestCallbackHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(CallbackHandlerTestSuite))
+}
@@ -0,0 +1,133 @@
+package oidccallbackcmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+const (
+	// Length limits of the usernames usermodel accepts.
+	minUsernameLength = 3
+	maxUsernameLength = 20
+
+	// usernameAttempts is how many numbered variants of a taken username are tried.
+	usernameAttempts = 5
+
+	// fallbackUsername is used when the provider shares no name that makes a username.
+	fallbackUsername = "user"
+)
+
+// provision returns the user linked to the identity, linking or creating one when the
+// identity signs in for the first time.
+func (h *Handler) provision(identity *ioidc.Identity) (*usermodel.User, error) {
+	external := usermodel.ExternalIdentity{Issuer: identity.Issuer, Subject: identity.Subject}
+
+	user, err := h.userRepo.ByExternalIdentity(external)
+	if err != errdmn.UserNotFound {
+		return user, err
+	}
+
+	// An email address proven to both the provider and the task manager belongs to the
+	// same person. Unverified addresses are never trusted to take over an account.
+	if identity.EmailVerified && identity.Email != "" {
+		user, err := h.userRepo.ByEmail(strings.ToLower(strings.TrimSpace(identity.Email)))
+		switch {
+		case err == nil && user.IsEmailVerified():
+			if err := user.LinkExternalIdentity(external); err != nil {
+				return nil, err
+			}
+			if err := h.userRepo.Save(user); err != nil {
+				return nil, err
+			}
+			return user, nil
+		case err != nil && err != errdmn.UserNotFound:
+			return nil, err
+		}
+	}
+
+	username, err := h.availableUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = usermodel.NewExternal(usermodel.ExternalConfig{
+		Username:      username,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Identity:      external,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A name too long to show is left out rather than failing the sign in.
+	_ = user.UpdateDisplayName(identity.Name)
+
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// availableUsername derives a username nobody has yet from the names the provider
+// shares, numbering it when it is taken.
+func (h *Handler) availableUsername(identity *ioidc.Identity) (string, error) {
+	base := usernameBase(identity)
+	candidate := base
+	for attempt := 0; attempt <= usernameAttempts; attempt++ {
+		if attempt > 0 {
+			n, err := rand.Int(rand.Reader, big.NewInt(10000))
+			if err != nil {
+				return "", errdmn.NewUnexpected(fmt.Sprintf("failed to generate username, %v", err))
+			}
+			suffix := fmt.Sprintf("_%04d", n.Int64())
+			candidate = strings.TrimRight(truncate(base, maxUsernameLength-len(suffix)), "_") + suffix
+		}
+
+		_, err := h.userRepo.ByUsername(candidate)
+		if err == errdmn.UserNotFound {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errdmn.UsernameConflict
+}
+
+// usernameBase turns the preferred username, or else the local part of the email
+// address, into a valid username: characters usernames cannot have become underscores.
+func usernameBase(identity *ioidc.Identity) string {
+	name := identity.PreferredUsername
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range name {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	base := strings.Trim(truncate(b.String(), maxUsernameLength), "_")
+	if len(base) < minUsernameLength {
+		return fallbackUsername
+	}
+	return base
+}
+
+// truncate cuts the ASCII string s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,10 @@
+package oidcstartcmd
+
+// Command represents a request to start a single sign-on. The identity provider asks
+// the user for everything it needs, so the command carries no data.
+type Command struct{}
+
+// NewCommand creates a new Command instance.
+func NewCommand() *Command {
+	return &Command{}
+}
@@ -0,0 +1,79 @@
+// Package oidcstartcmd provides the command and handler starting a single sign-on at the
+// OpenID Connect identity provider, with the authorization code flow and PKCE.
+//
+// The random state, nonce and PKCE code verifier of the sign in are returned in a signed
+// login state token, which the browser of the user keeps until the provider sends them
+// back with the authorization code. Nothing is stored on the server.
+package oidcstartcmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// Result holds where to send the user and what to remember until they come back.
+type Result struct {
+	URL        string // Authorization endpoint URL the user signs in at.
+	StateToken string // Login state token to present with the authorization code.
+}
+
+// Handler handles the start of single sign-ons.
+type Handler struct {
+	provider ioidc.Provider   // Identity provider the users sign in at.
+	states   ijwt.LoginStates // Issuer of login state tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	Provider ioidc.Provider   // Identity provider the users sign in at.
+	States   ijwt.LoginStates // Issuer of login state tokens.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		provider: cfg.Provider,
+		states:   cfg.States,
+	}
+}
+
+// Handle generates the state, nonce and code verifier of a new sign in and returns the
+// authorization URL together with the login state token carrying them.
+func (h *Handler) Handle(cmd *Command) (*Result, error) {
+	var state ijwt.LoginState
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		secret, err := authtoken.NewSecret()
+		if err != nil {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate single sign-on state, %v", err))
+		}
+		*value = secret
+	}
+
+	url, err := h.provider.AuthCodeURL(state.State, state.Nonce, codeChallenge(state.CodeVerifier))
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to reach the identity provider, %v", err))
+	}
+
+	stateToken, err := h.states.GenerateLoginState(state)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate login state token, %v", err))
+	}
+
+	return &Result{URL: url, StateToken: stateToken}, nil
+}
+
+// codeChallenge returns the S256 PKCE code challenge of a code verifier (RFC 7636).
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,75 @@
+package oidcstartcmd_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	ioidc_mock "github.com/beka-birhanu/task_manager_final/app/common/i_oidc/mocks"
+	oidcstartcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/start"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// StartHandlerTestSuite defines the test suite for the single sign-on start handler.
+type StartHandlerTestSuite struct {
+	suite.Suite
+	mockProvider *ioidc_mock.Provider
+	mockStates   *ijwt_mock.MockLoginStates
+	handler      *oidcstartcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *StartHandlerTestSuite) SetupTest() {
+	suite.mockProvider = new(ioidc_mock.Provider)
+	suite.mockStates = new(ijwt_mock.MockLoginStates)
+
+	suite.handler = oidcstartcmd.NewHandler(oidcstartcmd.Config{
+		Provider: suite.mockProvider,
+		States:   suite.mockStates,
+	})
+}
+
+// TestHandle_Success tests that the provider gets the challenge of the verifier kept in the state token.
+func (suite *StartHandlerTestSuite) TestHandle_Success() {
+	var state ijwt.LoginState
+	suite.mockStates.On("GenerateLoginState", mock.AnythingOfType("ijwt.LoginState")).
+		Run(func(args mock.Arguments) { state = args.Get(0).(ijwt.LoginState) }).
+		Return("state_token", nil)
+	suite.mockProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://sso.example.com/authorize?client_id=task-manager", nil)
+
+	result, err := suite.handler.Handle(oidcstartcmd.NewCommand())
+
+	suite.NoError(err)
+	suite.Equal("https://sso.example.com/authorize?client_id=task-manager", result.URL)
+	suite.Equal("state_token", result.StateToken)
+
+	suite.NotEmpty(state.State)
+	suite.NotEmpty(state.Nonce)
+	suite.GreaterOrEqual(len(state.CodeVerifier), 43) // RFC 7636 minimum.
+	suite.NotEqual(state.State, state.Nonce)
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+	suite.mockProvider.AssertCalled(suite.T(), "AuthCodeURL", state.State, state.Nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+}
+
+// TestHandle_ProviderUnreachable tests that a provider that cannot be reached fails the start.
+func (suite *StartHandlerTestSuite) TestHandle_ProviderUnreachable() {
+	suite.mockProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("", errors.New("connection refused"))
+
+	result, err := suite.handler.Handle(oidcstartcmd.NewCommand())
+
+	suite.Nil(result)
+	suite.Equal(errdmn.Unexpected, err.(*errdmn.Error).Type())
+	suite.mockStates.AssertNotCalled(suite.T(), "GenerateLoginState", mock.Anything)
+}
+
+func TestStartHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(StartHandlerTestSuite))
+}
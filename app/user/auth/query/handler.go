@@ -1,5 +1,13 @@
 // Package loginqry handles the login query, including user authentication
-// and JWT generation.
+// and token issuance.
+//
+// Users with two-factor authentication enabled get an MFA challenge token instead of
+// their tokens, to be exchanged together with their code at the MFA sign in step.
+//
+// Failed attempts are counted per username and per client address, and locked ones are
+// refused. A wrong username and a wrong password fail alike, with
+// errdmn.InvalidCredentials, so the response does not tell whether a user exists.
+// Deactivated users are only refused once their password is checked, for the same reason.
 package loginqry
 
 import (
@@ -9,15 +17,21 @@ import (
 	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	"github.com/google/uuid"
 )
 
 // Handler processes login queries.
 type Handler struct {
-	userRepo irepo.User    // Repository for user data operations.
-	jwtSvc   ijwt.Service  // Service for JWT operations.
-	hashSvc  ihash.Service // Service for password hashing.
+	userRepo             irepo.User        // Repository for user data operations.
+	tokens               authtoken.IIssuer // Issuer of access and refresh tokens.
+	hashSvc              ihash.Service     // Service for password hashing.
+	challenges           ijwt.Challenges   // Issuer of MFA challenge tokens.
+	guard                lockout.IGuard    // Tracks failed attempts and refuses locked ones.
+	requireVerifiedEmail bool              // Refuse users whose email is not verified.
 }
 
 // Ensure Handler implements iquery.Handler
@@ -25,26 +39,47 @@ var _ iquery.IHandler[*Query, *authresult.Result] = &Handler{}
 
 // Config holds the dependencies for creating a new Handler.
 type Config struct {
-	UserRepo irepo.User    // Repository for user data operations.
-	JwtSvc   ijwt.Service  // Service for JWT operations.
-	HashSvc  ihash.Service // Service for password hashing.
+	UserRepo             irepo.User        // Repository for user data operations.
+	Tokens               authtoken.IIssuer // Issuer of access and refresh tokens.
+	HashSvc              ihash.Service     // Service for password hashing.
+	Challenges           ijwt.Challenges   // Issuer of MFA challenge tokens.
+	Guard                lockout.IGuard    // Tracks failed attempts and refuses locked ones.
+	RequireVerifiedEmail bool              // Refuse users whose email is not verified.
 }
 
 // NewHandler creates a new Handler with the given configuration.
 func NewHandler(cfg Config) *Handler {
 	return &Handler{
-		userRepo: cfg.UserRepo,
-		jwtSvc:   cfg.JwtSvc,
-		hashSvc:  cfg.HashSvc,
+		userRepo:             cfg.UserRepo,
+		tokens:               cfg.Tokens,
+		hashSvc:              cfg.HashSvc,
+		challenges:           cfg.Challenges,
+		guard:                cfg.Guard,
+		requireVerifiedEmail: cfg.RequireVerifiedEmail,
 	}
 }
 
-// Handle processes a login query, authenticates the user, and generates a JWT.
+// Handle processes a login query, authenticates the user, and issues its tokens, or
+// an MFA challenge token if the user has two-factor authentication enabled.
 func (s *Handler) Handle(qry *Query) (*authresult.Result, error) {
+	if err := s.guard.Check(qry.Username, qry.IP); err != nil {
+		return nil, err
+	}
+
 	// Retrieve user by username.
 	user, err := s.userRepo.ByUsername(qry.Username)
-	if err != nil {
-		return nil, errdmn.NewUnauthorized(err.Error())
+	if err == errdmn.UserNotFound {
+		// Hash the password anyway so the response time does not tell the user does not exist.
+		_, _ = s.hashSvc.Hash(qry.Password)
+		return nil, s.fail(qry)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Users signing in with single sign-on have no password, so any password is wrong.
+	if !user.HasPassword() {
+		_, _ = s.hashSvc.Hash(qry.Password)
+		return nil, s.fail(qry)
 	}
 
 	// Validate the provided password.
@@ -55,15 +90,44 @@ func (s *Handler) Handle(qry *Query) (*authresult.Result, error) {
 	}
 
 	if !isPasswordCorrect {
-		return nil, errdmn.NewUnauthorized("incorrect password")
+		return nil, s.fail(qry)
+	}
+
+	if user.IsDeactivated() {
+		return nil, errdmn.UserDeactivated
+	}
+
+	// Accounts created before email addresses were collected have none to verify.
+	if s.requireVerifiedEmail && user.Email() != "" && !user.IsEmailVerified() {
+		return nil, errdmn.EmailNotVerified
 	}
 
-	// Generate JWT for the authenticated user.
-	token, err := s.jwtSvc.Generate(user)
+	// The failures are only forgotten once the second factor is proven as well.
+	if user.IsMFAEnabled() {
+		challenge, err := s.challenges.GenerateChallenge(user)
+		if err != nil {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate mfa challenge, %v", err))
+		}
+		return authresult.WithChallenge(user, challenge), nil
+	}
+
+	if err := s.guard.Succeed(user.Username()); err != nil {
+		return nil, err
+	}
+
+	// Issue tokens for the authenticated user, starting a new refresh token family.
+	tokens, err := s.tokens.Issue(user, uuid.Nil)
 	if err != nil {
-		errMessage := fmt.Sprintf("failed to generate JWT for user, %v", err)
-		return nil, errdmn.NewUnexpected(errMessage)
+		return nil, err
 	}
 
-	return authresult.New(user, token), nil
+	return authresult.New(user, tokens), nil
+}
+
+// fail records the failed attempt of the query and returns the error reported for it.
+func (s *Handler) fail(qry *Query) error {
+	if err := s.guard.Fail(qry.Username, qry.IP); err != nil {
+		return err
+	}
+	return errdmn.InvalidCredentials
 }
@@ -7,9 +7,14 @@ import (
 
 	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
 	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	lockout_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/lockout/mocks"
 	loginqry "github.com/beka-birhanu/task_manager_final/app/user/auth/query"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -18,8 +23,10 @@ import (
 type LoginQueryHandlerTestSuite struct {
 	suite.Suite
 	mockUserRepo *irepo_mock.User
-	mockJwtSvc   *ijwt_mock.MockService
+	mockTokens   *authtoken_mock.IIssuer
 	mockHashSvc  *ihash_mocks.Service
+	mockMFA      *ijwt_mock.MockChallenges
+	mockGuard    *lockout_mock.IGuard
 	handler      *loginqry.Handler
 	existingUser *usermodel.User
 	query        *loginqry.Query
@@ -28,13 +35,17 @@ type LoginQueryHandlerTestSuite struct {
 // SetupTest sets up the test environment.
 func (suite *LoginQueryHandlerTestSuite) SetupTest() {
 	suite.mockUserRepo = new(irepo_mock.User)
-	suite.mockJwtSvc = new(ijwt_mock.MockService)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
 	suite.mockHashSvc = new(ihash_mocks.Service)
+	suite.mockMFA = new(ijwt_mock.MockChallenges)
+	suite.mockGuard = new(lockout_mock.IGuard)
 
 	suite.handler = loginqry.NewHandler(loginqry.Config{
-		UserRepo: suite.mockUserRepo,
-		JwtSvc:   suite.mockJwtSvc,
-		HashSvc:  suite.mockHashSvc,
+		UserRepo:   suite.mockUserRepo,
+		Tokens:     suite.mockTokens,
+		HashSvc:    suite.mockHashSvc,
+		Challenges: suite.mockMFA,
+		Guard:      suite.mockGuard,
 	})
 
 	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_password", nil)
@@ -43,7 +54,6 @@ func (suite *LoginQueryHandlerTestSuite) SetupTest() {
 	suite.existingUser, err = usermodel.New(usermodel.Config{
 		Username:       "existinguser",
 		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
-		IsAdmin:        false,
 		PasswordHasher: suite.mockHashSvc,
 	})
 	suite.Require().NoError(err)
@@ -51,15 +61,18 @@ func (suite *LoginQueryHandlerTestSuite) SetupTest() {
 	suite.query = &loginqry.Query{
 		Username: "existinguser",
 		Password: "&&^_str0ngp@ssw0rd!@d$",
+		IP:       "10.0.0.1",
 	}
+	suite.mockGuard.On("Check", suite.query.Username, suite.query.IP).Return(nil)
 }
 
-// TestHandle_Success tests the successful login and JWT generation.
+// TestHandle_Success tests the successful login and token issuance.
 func (suite *LoginQueryHandlerTestSuite) TestHandle_Success() {
 	// Mock expected behavior
 	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(suite.existingUser, nil)
 	suite.mockHashSvc.On("Match", suite.existingUser.PasswordHash(), suite.query.Password).Return(true, nil)
-	suite.mockJwtSvc.On("Generate", suite.existingUser).Return("jwt_token", nil)
+	suite.mockGuard.On("Succeed", suite.query.Username).Return(nil)
+	suite.mockTokens.On("Issue", suite.existingUser, uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
 
 	// Execute the Handle method with the query.
 	result, err := suite.handler.Handle(suite.query)
@@ -67,12 +80,59 @@ func (suite *LoginQueryHandlerTestSuite) TestHandle_Success() {
 	// Verify the results.
 	suite.Assert().NotNil(result)
 	suite.Assert().Equal("jwt_token", result.Token)
+	suite.Assert().Equal("refresh_token", result.RefreshToken)
 	suite.Assert().NoError(err)
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
+	suite.mockGuard.AssertExpectations(suite.T())
+}
+
+// TestHandle_Locked tests that locked attempts are refused before the password is checked.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_Locked() {
+	suite.mockGuard = new(lockout_mock.IGuard)
+	suite.mockGuard.On("Check", suite.query.Username, suite.query.IP).Return(errdmn.LoginLocked)
+	handler := loginqry.NewHandler(loginqry.Config{
+		UserRepo: suite.mockUserRepo,
+		Tokens:   suite.mockTokens,
+		HashSvc:  suite.mockHashSvc,
+		Guard:    suite.mockGuard,
+	})
+
+	result, err := handler.Handle(suite.query)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.LoginLocked, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByUsername", mock.Anything)
+	suite.mockHashSvc.AssertNotCalled(suite.T(), "Match", mock.Anything, mock.Anything)
+}
+
+// TestHandle_Deactivated tests that deactivated users are refused once their password is checked.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_Deactivated() {
+	suite.existingUser.Deactivate()
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(suite.existingUser, nil)
+	suite.mockHashSvc.On("Match", suite.existingUser.PasswordHash(), suite.query.Password).Return(true, nil)
+
+	result, err := suite.handler.Handle(suite.query)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.UserDeactivated, err)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_UserNotFound tests that an unknown username fails like a wrong password and is counted.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(nil, errdmn.UserNotFound)
+	suite.mockGuard.On("Fail", suite.query.Username, suite.query.IP).Return(nil)
+
+	result, err := suite.handler.Handle(suite.query)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.InvalidCredentials, err)
+	suite.mockHashSvc.AssertCalled(suite.T(), "Hash", suite.query.Password)
+	suite.mockGuard.AssertExpectations(suite.T())
 }
 
 // TestHandle_UserRepoError tests the scenario where retrieving the user from the repository fails.
@@ -84,11 +144,11 @@ func (suite *LoginQueryHandlerTestSuite) TestHandle_UserRepoError() {
 
 	// Verify the results.
 	suite.Assert().Nil(result)
-	suite.Assert().EqualError(err, "Unauthorized: repo error")
+	suite.Assert().EqualError(err, "repo error")
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
@@ -97,18 +157,34 @@ func (suite *LoginQueryHandlerTestSuite) TestHandle_InvalidPassword() {
 	log.Println(suite.existingUser.PasswordHash(), suite.query.Password, "test")
 	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(suite.existingUser, nil)
 	suite.mockHashSvc.On("Match", suite.existingUser.PasswordHash(), suite.query.Password).Return(false, nil)
+	suite.mockGuard.On("Fail", suite.query.Username, suite.query.IP).Return(nil)
 
 	// Execute the Handle method with the query.
 	result, err := suite.handler.Handle(suite.query)
 
 	// Verify the results.
 	suite.Assert().Nil(result)
-	suite.Assert().EqualError(err, "Unauthorized: incorrect password")
+	suite.Assert().Equal(errdmn.InvalidCredentials, err)
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
+	suite.mockGuard.AssertExpectations(suite.T())
+}
+
+// TestHandle_NoPassword tests that users signing in with single sign-on cannot sign in with a password.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_NoPassword() {
+	ssoUser := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: suite.query.Username})
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(ssoUser, nil)
+	suite.mockGuard.On("Fail", suite.query.Username, suite.query.IP).Return(nil)
+
+	result, err := suite.handler.Handle(suite.query)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.InvalidCredentials, err)
+	suite.mockHashSvc.AssertNotCalled(suite.T(), "Match", mock.Anything, mock.Anything)
+	suite.mockGuard.AssertExpectations(suite.T())
 }
 
 // TestHandle_PasswordHashError tests the scenario where password hash comparison fails.
@@ -125,29 +201,99 @@ func (suite *LoginQueryHandlerTestSuite) TestHandle_PasswordHashError() {
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
-// TestHandle_JwtGenerationError tests the scenario where JWT generation fails.
-func (suite *LoginQueryHandlerTestSuite) TestHandle_JwtGenerationError() {
+// TestHandle_TokenIssueError tests the scenario where issuing the tokens fails.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_TokenIssueError() {
 	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(suite.existingUser, nil)
 	suite.mockHashSvc.On("Match", suite.existingUser.PasswordHash(), suite.query.Password).Return(true, nil)
-	suite.mockJwtSvc.On("Generate", suite.existingUser).Return("", errors.New("jwt error"))
+	suite.mockGuard.On("Succeed", suite.query.Username).Return(nil)
+	suite.mockTokens.On("Issue", suite.existingUser, uuid.Nil).Return(nil, errors.New("jwt error"))
 
 	// Execute the Handle method with the query.
 	result, err := suite.handler.Handle(suite.query)
 
 	// Verify the results.
 	suite.Assert().Nil(result)
-	suite.Assert().EqualError(err, "ServerError: failed to generate JWT for user, jwt error")
+	suite.Assert().EqualError(err, "jwt error")
 
 	// Verify that the mocks were called as expected.
 	suite.mockUserRepo.AssertExpectations(suite.T())
-	suite.mockJwtSvc.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
 	suite.mockHashSvc.AssertExpectations(suite.T())
 }
 
+// TestHandle_EmailNotVerified tests that users with an unverified email are refused when verified emails are required.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_EmailNotVerified() {
+	handler := loginqry.NewHandler(loginqry.Config{
+		UserRepo:             suite.mockUserRepo,
+		Tokens:               suite.mockTokens,
+		HashSvc:              suite.mockHashSvc,
+		Guard:                suite.mockGuard,
+		RequireVerifiedEmail: true,
+	})
+	user, err := usermodel.New(usermodel.Config{
+		Username:       "existinguser",
+		Email:          "existinguser@example.com",
+		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
+		PasswordHasher: suite.mockHashSvc,
+	})
+	suite.Require().NoError(err)
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(user, nil)
+	suite.mockHashSvc.On("Match", user.PasswordHash(), suite.query.Password).Return(true, nil)
+
+	result, err := handler.Handle(suite.query)
+
+	suite.Assert().Nil(result)
+	suite.Assert().Equal(errdmn.EmailNotVerified, err)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_RequireVerifiedEmail_NoEmail tests that users without an email can still sign in when verified emails are required.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_RequireVerifiedEmail_NoEmail() {
+	handler := loginqry.NewHandler(loginqry.Config{
+		UserRepo:             suite.mockUserRepo,
+		Tokens:               suite.mockTokens,
+		HashSvc:              suite.mockHashSvc,
+		Guard:                suite.mockGuard,
+		RequireVerifiedEmail: true,
+	})
+	suite.mockGuard.On("Succeed", suite.query.Username).Return(nil)
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(suite.existingUser, nil)
+	suite.mockHashSvc.On("Match", suite.existingUser.PasswordHash(), suite.query.Password).Return(true, nil)
+	suite.mockTokens.On("Issue", suite.existingUser, uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
+
+	result, err := handler.Handle(suite.query)
+
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("jwt_token", result.Token)
+}
+
+// TestHandle_MFAChallenge tests that users with two-factor authentication get a challenge instead of their tokens.
+func (suite *LoginQueryHandlerTestSuite) TestHandle_MFAChallenge() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:           uuid.New(),
+		Username:     "existinguser",
+		PasswordHash: "hashed_password",
+		TOTPSecret:   "SECRET",
+	})
+	suite.mockUserRepo.On("ByUsername", suite.query.Username).Return(user, nil)
+	suite.mockHashSvc.On("Match", "hashed_password", suite.query.Password).Return(true, nil)
+	suite.mockMFA.On("GenerateChallenge", user).Return("challenge_token", nil)
+
+	result, err := suite.handler.Handle(suite.query)
+
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("challenge_token", result.MFAChallenge)
+	suite.Assert().Empty(result.Token)
+	suite.Assert().Empty(result.RefreshToken)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+	suite.mockGuard.AssertNotCalled(suite.T(), "Succeed", mock.Anything)
+	suite.mockMFA.AssertExpectations(suite.T())
+}
+
 // Run the test suite
 func TestLoginQueryHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(LoginQueryHandlerTestSuite))
@@ -4,12 +4,14 @@ package loginqry
 type Query struct {
 	Username string // Username of the user trying to log in.
 	Password string // Password of the user trying to log in.
+	IP       string // Address of the client trying to log in.
 }
 
-// NewQuery creates a new Query instance with the provided username and password.
-func NewQuery(username, password string) *Query {
+// NewQuery creates a new Query instance with the provided username, password, and client address.
+func NewQuery(username, password, ip string) *Query {
 	return &Query{
 		Username: username,
 		Password: password,
+		IP:       ip,
 	}
 }
@@ -0,0 +1,13 @@
+package refreshcmd
+
+// Command represents the data required to exchange a refresh token.
+type Command struct {
+	Token string // Opaque refresh token previously issued to the user.
+}
+
+// NewCommand creates a new Command instance for the given refresh token.
+func NewCommand(token string) *Command {
+	return &Command{
+		Token: token,
+	}
+}
@@ -0,0 +1,135 @@
+// Package refreshcmd provides the command and handler for exchanging a refresh token
+// for a new access token.
+//
+// Refresh tokens are rotated: each one can be used once and is replaced by a new token of
+// the same family. Presenting a token that was already used means it was copied, so the
+// whole family is revoked along with the access tokens issued from it, and the user has to
+// sign in again.
+package refreshcmd
+
+import (
+	"fmt"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+)
+
+// Handler handles the refresh token exchange.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	tokens      authtoken.IIssuer  // Issuer of access and refresh tokens.
+	hashSvc     ihash.Service      // Service for matching refresh token secrets.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *authresult.Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Tokens      authtoken.IIssuer  // Issuer of access and refresh tokens.
+	HashSvc     ihash.Service      // Service for matching refresh token secrets.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		refreshRepo: cfg.RefreshRepo,
+		tokens:      cfg.Tokens,
+		hashSvc:     cfg.HashSvc,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle validates the refresh token, rotates it, and issues a new token pair.
+func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
+	token, err := h.verify(cmd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.IsUsed() {
+		return nil, h.revoke(token)
+	}
+
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, errdmn.RefreshTokenExpired
+	}
+
+	// Another request may have used the token since it was read.
+	if err := h.refreshRepo.MarkUsed(token.ID()); err != nil {
+		if err == errdmn.RefreshTokenReused {
+			return nil, h.revoke(token)
+		}
+		return nil, err
+	}
+
+	user, err := h.userRepo.ById(token.UserID())
+	if err != nil {
+		if err == errdmn.UserNotFound {
+			return nil, errdmn.InvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// Deactivating a user ends their sessions; one started since is ended as well.
+	if user.IsDeactivated() {
+		if err := authtoken.RevokeSession(h.refreshRepo, h.revocations, token.FamilyID(), h.accessTTL); err != nil {
+			return nil, err
+		}
+		return nil, errdmn.UserDeactivated
+	}
+
+	tokens, err := h.tokens.Issue(user, token.FamilyID())
+	if err != nil {
+		return nil, err
+	}
+
+	return authresult.New(user, tokens), nil
+}
+
+// verify loads the refresh token and checks its secret.
+func (h *Handler) verify(rawToken string) (*refreshtokenmodel.RefreshToken, error) {
+	id, secret, err := authtoken.ParseRefreshToken(rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.refreshRepo.ByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := token.MatchesSecret(secret, h.hashSvc)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to validate refresh token, %v", err))
+	}
+	if !matches {
+		return nil, errdmn.InvalidRefreshToken
+	}
+	return token, nil
+}
+
+// revoke handles the reuse of a refresh token by ending the session it belongs to,
+// which also rejects the access tokens already issued within it.
+func (h *Handler) revoke(token *refreshtokenmodel.RefreshToken) error {
+	if err := authtoken.RevokeSession(h.refreshRepo, h.revocations, token.FamilyID(), h.accessTTL); err != nil {
+		return err
+	}
+	return errdmn.RefreshTokenReused
+}
@@ -0,0 +1,218 @@
+package refreshcmd_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	refreshcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/refresh"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// RefreshCommandHandlerTestSuite defines the test suite for the refresh command handler.
+type RefreshCommandHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockTokens      *authtoken_mock.IIssuer
+	mockHashSvc     *ihash_mocks.Service
+	mockRevocations *irevocation_mock.Store
+	handler         *refreshcmd.Handler
+	user            *usermodel.User
+	token           *refreshtokenmodel.RefreshToken
+	cmd             *refreshcmd.Command
+}
+
+// SetupTest sets up the test environment.
+func (suite *RefreshCommandHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+	suite.mockRevocations = new(irevocation_mock.Store)
+
+	suite.handler = refreshcmd.NewHandler(refreshcmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Tokens:      suite.mockTokens,
+		HashSvc:     suite.mockHashSvc,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   15 * time.Minute,
+	})
+
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+
+	var err error
+	suite.user, err = usermodel.New(usermodel.Config{
+		Username:       "existinguser",
+		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
+		PasswordHasher: suite.mockHashSvc,
+	})
+	suite.Require().NoError(err)
+
+	suite.token = suite.newToken(time.Hour, time.Time{})
+	suite.cmd = refreshcmd.NewCommand(authtoken.FormatRefreshToken(suite.token.ID(), "secret"))
+}
+
+// newToken returns a stored refresh token of the user with the given remaining lifetime and use time.
+func (suite *RefreshCommandHandlerTestSuite) newToken(ttl time.Duration, usedAt time.Time) *refreshtokenmodel.RefreshToken {
+	return refreshtokenmodel.FromBSON(&refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   uuid.New(),
+		UserID:     suite.user.ID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(ttl),
+		UsedAt:     usedAt,
+	})
+}
+
+// TestHandle_Success tests that a valid token is rotated into a new pair of the same family.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_Success() {
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("MarkUsed", suite.token.ID()).Return(nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockTokens.On("Issue", suite.user, suite.token.FamilyID()).
+		Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "next_refresh_token"}, nil)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.NoError(err)
+	suite.Equal(suite.user.ID(), result.ID)
+	suite.Equal("jwt_token", result.Token)
+	suite.Equal("next_refresh_token", result.RefreshToken)
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "RevokeFamily", mock.Anything)
+}
+
+// TestHandle_Malformed tests that tokens which cannot be parsed are rejected.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_Malformed() {
+	for _, token := range []string{"", "no-separator", "not-a-uuid.secret", uuid.NewString() + "."} {
+		result, err := suite.handler.Handle(refreshcmd.NewCommand(token))
+		suite.Nil(result)
+		suite.Equal(errdmn.InvalidRefreshToken, err, token)
+	}
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "ByID", mock.Anything)
+}
+
+// TestHandle_SecretMismatch tests that a token with a wrong secret is rejected without being used.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_SecretMismatch() {
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(false, nil)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidRefreshToken, err)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "RevokeFamily", mock.Anything)
+}
+
+// TestHandle_Expired tests that an expired token is rejected.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_Expired() {
+	token := suite.newToken(-time.Minute, time.Time{})
+	suite.mockRefreshRepo.On("ByID", token.ID()).Return(token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+
+	result, err := suite.handler.Handle(refreshcmd.NewCommand(authtoken.FormatRefreshToken(token.ID(), "secret")))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.RefreshTokenExpired, err)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything)
+}
+
+// TestHandle_Reused tests that replaying a used token revokes its whole family.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_Reused() {
+	token := suite.newToken(time.Hour, time.Now().UTC().Add(-time.Minute))
+	suite.mockRefreshRepo.On("ByID", token.ID()).Return(token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", token.FamilyID()).Return(nil)
+	suite.mockRevocations.On("Revoke", token.FamilyID().String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.handler.Handle(refreshcmd.NewCommand(authtoken.FormatRefreshToken(token.ID(), "secret")))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.RefreshTokenReused, err)
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything)
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_ConcurrentUse tests that losing the race to use a token is treated as reuse.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_ConcurrentUse() {
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("MarkUsed", suite.token.ID()).Return(errdmn.RefreshTokenReused)
+	suite.mockRefreshRepo.On("RevokeFamily", suite.token.FamilyID()).Return(nil)
+	suite.mockRevocations.On("Revoke", suite.token.FamilyID().String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.RefreshTokenReused, err)
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_UserDeleted tests that tokens of a user who no longer exists are rejected.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_UserDeleted() {
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("MarkUsed", suite.token.ID()).Return(nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidRefreshToken, err)
+}
+
+// TestHandle_UserDeactivated tests that the session of a deactivated user is ended.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_UserDeactivated() {
+	suite.user.Deactivate()
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("MarkUsed", suite.token.ID()).Return(nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", suite.token.FamilyID()).Return(nil)
+	suite.mockRevocations.On("Revoke", suite.token.FamilyID().String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserDeactivated, err)
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// TestHandle_IssueError tests the scenario where issuing the new tokens fails.
+func (suite *RefreshCommandHandlerTestSuite) TestHandle_IssueError() {
+	suite.mockRefreshRepo.On("ByID", suite.token.ID()).Return(suite.token, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockRefreshRepo.On("MarkUsed", suite.token.ID()).Return(nil)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockTokens.On("Issue", suite.user, suite.token.FamilyID()).Return(nil, errors.New("jwt error"))
+
+	result, err := suite.handler.Handle(suite.cmd)
+
+	suite.Nil(result)
+	suite.EqualError(err, "jwt error")
+}
+
+// Run the test suite
+func TestRefreshCommandHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshCommandHandlerTestSuite))
+}
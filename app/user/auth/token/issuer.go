@@ -0,0 +1,165 @@
+// Package authtoken issues the tokens handed to a user once they are authenticated:
+// a short-lived JWT access token and a long-lived opaque refresh token.
+//
+// The tokens issued from one login form a session, identified by the refresh token family
+// and carried in the sid claim of every access token of that session.
+//
+// A refresh token has the form "<token id>.<secret>". Only a hash of the secret is stored,
+// so a leaked token store cannot be used to mint sessions.
+package authtoken
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// secretSize is the number of random bytes in a refresh token secret.
+const secretSize = 32
+
+// Pair holds the tokens issued to an authenticated user.
+type Pair struct {
+	AccessToken      string    // Signed JWT.
+	AccessExpiresAt  time.Time // Time after which the access token is no longer accepted.
+	RefreshToken     string    // Opaque token exchanged for a new pair once the access token expires.
+	RefreshExpiresAt time.Time // Time after which the refresh token can no longer be used.
+}
+
+// IIssuer issues token pairs.
+type IIssuer interface {
+	// Issue creates a new token pair for the user. The refresh token joins the given
+	// family, or starts a new one when familyID is uuid.Nil.
+	Issue(user *usermodel.User, familyID uuid.UUID) (*Pair, error)
+}
+
+// Issuer issues token pairs, storing the refresh tokens in a repository.
+type Issuer struct {
+	jwtSvc      ijwt.Service       // Service for JWT operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	hashSvc     ihash.Service      // Service for hashing refresh token secrets.
+	accessTTL   time.Duration      // Lifetime of access tokens, as signed by the JWT service.
+	refreshTTL  time.Duration      // Lifetime of refresh tokens.
+}
+
+// Ensure Issuer implements IIssuer.
+var _ IIssuer = &Issuer{}
+
+// Config holds the dependencies for creating a new Issuer.
+type Config struct {
+	JwtSvc      ijwt.Service       // Service for JWT operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	HashSvc     ihash.Service      // Service for hashing refresh token secrets.
+	AccessTTL   time.Duration      // Lifetime of access tokens, as signed by the JWT service.
+	RefreshTTL  time.Duration      // Lifetime of refresh tokens.
+}
+
+// NewIssuer creates a new Issuer with the given configuration.
+func NewIssuer(cfg Config) *Issuer {
+	return &Issuer{
+		jwtSvc:      cfg.JwtSvc,
+		refreshRepo: cfg.RefreshRepo,
+		hashSvc:     cfg.HashSvc,
+		accessTTL:   cfg.AccessTTL,
+		refreshTTL:  cfg.RefreshTTL,
+	}
+}
+
+// Issue creates a new token pair for the user and stores the refresh token.
+func (i *Issuer) Issue(user *usermodel.User, familyID uuid.UUID) (*Pair, error) {
+	secret, err := NewSecret()
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate refresh token, %v", err))
+	}
+
+	refreshToken, err := refreshtokenmodel.New(refreshtokenmodel.Config{
+		UserID:       user.ID(),
+		FamilyID:     familyID,
+		Secret:       secret,
+		TTL:          i.refreshTTL,
+		SecretHasher: i.hashSvc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The refresh token family identifies the session the access token belongs to.
+	accessExpiresAt := time.Now().Add(i.accessTTL)
+	accessToken, err := i.jwtSvc.Generate(user, refreshToken.FamilyID())
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate JWT for user, %v", err))
+	}
+
+	if err := i.refreshRepo.Save(refreshToken); err != nil {
+		return nil, err
+	}
+
+	return &Pair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     FormatRefreshToken(refreshToken.ID(), secret),
+		RefreshExpiresAt: refreshToken.ExpiresAt(),
+	}, nil
+}
+
+// RevokeSession ends a session: its refresh tokens are removed and its access tokens
+// are rejected for as long as any of them can still be valid.
+func RevokeSession(refreshRepo irepo.RefreshToken, revocations irevocation.Store, sessionID uuid.UUID, accessTTL time.Duration) error {
+	if err := refreshRepo.RevokeFamily(sessionID); err != nil {
+		return err
+	}
+	return revocations.Revoke(sessionID.String(), time.Now().UTC().Add(accessTTL))
+}
+
+// RevokeUserSessions ends every session of the user that still holds refresh tokens.
+func RevokeUserSessions(refreshRepo irepo.RefreshToken, revocations irevocation.Store, userID uuid.UUID, accessTTL time.Duration) error {
+	sessions, err := refreshRepo.FamiliesByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessions {
+		if err := RevokeSession(refreshRepo, revocations, sessionID, accessTTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatRefreshToken builds the opaque refresh token handed to clients.
+func FormatRefreshToken(id uuid.UUID, secret string) string {
+	return id.String() + "." + secret
+}
+
+// ParseRefreshToken splits an opaque refresh token into its ID and secret.
+func ParseRefreshToken(token string) (uuid.UUID, string, error) {
+	rawID, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", errdmn.InvalidRefreshToken
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", errdmn.InvalidRefreshToken
+	}
+	return id, secret, nil
+}
+
+// NewSecret returns a random URL-safe secret, as used in refresh and other opaque tokens.
+func NewSecret() (string, error) {
+	raw := make([]byte, secretSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
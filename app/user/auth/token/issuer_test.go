@@ -0,0 +1,125 @@
+package authtoken_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	ijwt_mock "github.com/beka-birhanu/task_manager_final/app/common/i_jwt/mock"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// IssuerTestSuite defines the test suite for the token Issuer.
+type IssuerTestSuite struct {
+	suite.Suite
+	mockJwtSvc      *ijwt_mock.MockService
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockHashSvc     *ihash_mocks.Service
+	issuer          *authtoken.Issuer
+	user            *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *IssuerTestSuite) SetupTest() {
+	suite.mockJwtSvc = new(ijwt_mock.MockService)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.issuer = authtoken.NewIssuer(authtoken.Config{
+		JwtSvc:      suite.mockJwtSvc,
+		RefreshRepo: suite.mockRefreshRepo,
+		HashSvc:     suite.mockHashSvc,
+		AccessTTL:   15 * time.Minute,
+		RefreshTTL:  time.Hour,
+	})
+
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed", nil)
+
+	var err error
+	suite.user, err = usermodel.New(usermodel.Config{
+		Username:       "existinguser",
+		PlainPassword:  "&&^_str0ngp@ssw0rd!@d$",
+		PasswordHasher: suite.mockHashSvc,
+	})
+	suite.Require().NoError(err)
+}
+
+// TestIssue_NewFamily tests that a pair is issued and its refresh token stored hashed.
+func (suite *IssuerTestSuite) TestIssue_NewFamily() {
+	var saved *refreshtokenmodel.RefreshToken
+	suite.mockJwtSvc.On("Generate", suite.user, mock.AnythingOfType("uuid.UUID")).Return("jwt_token", nil)
+	suite.mockRefreshRepo.On("Save", mock.AnythingOfType("*refreshtokenmodel.RefreshToken")).
+		Run(func(args mock.Arguments) { saved = args.Get(0).(*refreshtokenmodel.RefreshToken) }).
+		Return(nil)
+
+	pair, err := suite.issuer.Issue(suite.user, uuid.Nil)
+
+	suite.Require().NoError(err)
+	suite.Equal("jwt_token", pair.AccessToken)
+	suite.WithinDuration(time.Now().Add(15*time.Minute), pair.AccessExpiresAt, time.Minute)
+	suite.Equal(saved.ExpiresAt(), pair.RefreshExpiresAt)
+	suite.WithinDuration(time.Now().Add(time.Hour), pair.RefreshExpiresAt, time.Minute)
+
+	id, secret, err := authtoken.ParseRefreshToken(pair.RefreshToken)
+	suite.Require().NoError(err)
+	suite.Equal(saved.ID(), id)
+	suite.Equal(suite.user.ID(), saved.UserID())
+	suite.NotEqual(uuid.Nil, saved.FamilyID())
+	suite.Equal("hashed", saved.SecretHash())
+	suite.NotContains(pair.RefreshToken, saved.SecretHash())
+	suite.mockHashSvc.AssertCalled(suite.T(), "Hash", secret)
+	suite.mockJwtSvc.AssertCalled(suite.T(), "Generate", suite.user, saved.FamilyID())
+}
+
+// TestIssue_ExistingFamily tests that a rotated token stays in its family and gets a fresh secret.
+func (suite *IssuerTestSuite) TestIssue_ExistingFamily() {
+	familyID := uuid.New()
+	suite.mockJwtSvc.On("Generate", suite.user, familyID).Return("jwt_token", nil)
+	suite.mockRefreshRepo.On("Save", mock.MatchedBy(func(token *refreshtokenmodel.RefreshToken) bool {
+		return token.FamilyID() == familyID
+	})).Return(nil)
+
+	first, err := suite.issuer.Issue(suite.user, familyID)
+	suite.Require().NoError(err)
+	second, err := suite.issuer.Issue(suite.user, familyID)
+	suite.Require().NoError(err)
+
+	suite.NotEqual(first.RefreshToken, second.RefreshToken)
+	suite.False(strings.HasSuffix(first.RefreshToken, "."))
+	suite.mockRefreshRepo.AssertNumberOfCalls(suite.T(), "Save", 2)
+}
+
+// TestIssue_JwtError tests that nothing is stored when the access token cannot be generated.
+func (suite *IssuerTestSuite) TestIssue_JwtError() {
+	suite.mockJwtSvc.On("Generate", suite.user, mock.AnythingOfType("uuid.UUID")).Return("", errors.New("jwt error"))
+
+	pair, err := suite.issuer.Issue(suite.user, uuid.Nil)
+
+	suite.Nil(pair)
+	suite.EqualError(err, "ServerError: failed to generate JWT for user, jwt error")
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestIssue_SaveError tests the scenario where storing the refresh token fails.
+func (suite *IssuerTestSuite) TestIssue_SaveError() {
+	suite.mockJwtSvc.On("Generate", suite.user, mock.AnythingOfType("uuid.UUID")).Return("jwt_token", nil)
+	suite.mockRefreshRepo.On("Save", mock.Anything).Return(errors.New("repo error"))
+
+	pair, err := suite.issuer.Issue(suite.user, uuid.Nil)
+
+	suite.Nil(pair)
+	suite.EqualError(err, "repo error")
+}
+
+// Run the test suite
+func TestIssuerTestSuite(t *testing.T) {
+	suite.Run(t, new(IssuerTestSuite))
+}
@@ -0,0 +1,22 @@
+package authtoken_mock
+
+import (
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// IIssuer is a mock implementation of the IIssuer interface using testify.
+type IIssuer struct {
+	mock.Mock
+}
+
+// Issue mocks the Issue method of the IIssuer interface.
+func (m *IIssuer) Issue(user *usermodel.User, familyID uuid.UUID) (*authtoken.Pair, error) {
+	args := m.Called(user, familyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authtoken.Pair), args.Error(1)
+}
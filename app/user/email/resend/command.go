@@ -0,0 +1,13 @@
+package resendemailcmd
+
+// Command represents the data required to send a new email verification token.
+type Command struct {
+	Username string // User whose email is to be verified.
+}
+
+// NewCommand creates a new Command instance for the given username.
+func NewCommand(username string) *Command {
+	return &Command{
+		Username: username,
+	}
+}
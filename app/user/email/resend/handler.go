@@ -0,0 +1,56 @@
+// Package resendemailcmd provides the command and handler for sending a new email
+// verification token, replacing the one sent before.
+package resendemailcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+)
+
+// Handler handles requests for a new email verification token.
+type Handler struct {
+	userRepo     irepo.User                // Repository for user data operations.
+	verification *emailverification.Sender // Starts the verification and sends its token.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo     irepo.User                // Repository for user data operations.
+	Verification *emailverification.Sender // Starts the verification and sends its token.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:     cfg.UserRepo,
+		verification: cfg.Verification,
+	}
+}
+
+// Handle starts a new verification of the user's email and sends its token.
+// It fails with errdmn.UserNotFound if the user does not exist, errdmn.EmailRequired
+// if they have no email, and errdmn.EmailAlreadyVerified if it is verified.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	msg, err := h.verification.Start(user)
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return false, err
+	}
+
+	if err := h.verification.Send(msg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
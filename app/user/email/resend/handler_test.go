@@ -0,0 +1,119 @@
+package resendemailcmd_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	inotifier_mock "github.com/beka-birhanu/task_manager_final/app/common/i_notifier/mocks"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	resendemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/resend"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ResendEmailHandlerTestSuite defines the test suite for the resend verification handler.
+type ResendEmailHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	mockHashSvc  *ihash_mocks.Service
+	mockNotifier *inotifier_mock.Notifier
+	handler      *resendemailcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ResendEmailHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+	suite.mockNotifier = new(inotifier_mock.Notifier)
+
+	suite.handler = resendemailcmd.NewHandler(resendemailcmd.Config{
+		UserRepo: suite.mockUserRepo,
+		Verification: emailverification.NewSender(emailverification.Config{
+			HashSvc:   suite.mockHashSvc,
+			Notifier:  suite.mockNotifier,
+			TTL:       time.Hour,
+			VerifyURL: "https://example.com/verify",
+		}),
+	})
+
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+}
+
+// storedUser returns a user as loaded from the repository.
+func (suite *ResendEmailHandlerTestSuite) storedUser(email string, verified bool) *usermodel.User {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:            uuid.New(),
+		Username:      "normaluser",
+		Email:         email,
+		EmailVerified: verified,
+	})
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(user, nil)
+	return user
+}
+
+// TestHandle_Success tests that a new token is saved and sent to the user's email.
+func (suite *ResendEmailHandlerTestSuite) TestHandle_Success() {
+	user := suite.storedUser("normaluser@example.com", false)
+	suite.mockUserRepo.On("Save", user).Return(nil)
+	suite.mockNotifier.On("Notify", mock.MatchedBy(func(msg *inotifier.Message) bool {
+		return msg.Email == "normaluser@example.com"
+	})).Return(nil)
+
+	ok, err := suite.handler.Handle(resendemailcmd.NewCommand("normaluser"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal("hashed_secret", user.EmailVerificationHash())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockNotifier.AssertExpectations(suite.T())
+}
+
+// TestHandle_Refused tests that no token is sent to missing users, users without an email, and verified emails.
+func (suite *ResendEmailHandlerTestSuite) TestHandle_Refused() {
+	suite.Run("unknown user", func() {
+		suite.SetupTest()
+		suite.mockUserRepo.On("ByUsername", "normaluser").Return(nil, errdmn.UserNotFound)
+		_, err := suite.handler.Handle(resendemailcmd.NewCommand("normaluser"))
+		suite.Equal(errdmn.UserNotFound, err)
+	})
+
+	suite.Run("no email", func() {
+		suite.SetupTest()
+		suite.storedUser("", false)
+		_, err := suite.handler.Handle(resendemailcmd.NewCommand("normaluser"))
+		suite.Equal(errdmn.EmailRequired, err)
+	})
+
+	suite.Run("already verified", func() {
+		suite.SetupTest()
+		suite.storedUser("normaluser@example.com", true)
+		_, err := suite.handler.Handle(resendemailcmd.NewCommand("normaluser"))
+		suite.Equal(errdmn.EmailAlreadyVerified, err)
+		suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+		suite.mockNotifier.AssertNotCalled(suite.T(), "Notify", mock.Anything)
+	})
+}
+
+// TestHandle_NotifyError tests that a failure to send the token is reported.
+func (suite *ResendEmailHandlerTestSuite) TestHandle_NotifyError() {
+	user := suite.storedUser("normaluser@example.com", false)
+	suite.mockUserRepo.On("Save", user).Return(nil)
+	suite.mockNotifier.On("Notify", mock.Anything).Return(errors.New("smtp error"))
+
+	ok, err := suite.handler.Handle(resendemailcmd.NewCommand("normaluser"))
+
+	suite.False(ok)
+	suite.EqualError(err, "ServerError: failed to send email verification token, smtp error")
+}
+
+// Run the test suite
+func TestResendEmailHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ResendEmailHandlerTestSuite))
+}
@@ -0,0 +1,104 @@
+// Package emailverification starts the verification of email addresses and sends the
+// verification tokens to the users.
+//
+// A verification token has the form "<user id>.<secret>". Only a hash of the secret is
+// stored, on the user, and starting a new verification replaces the pending one.
+package emailverification
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+)
+
+// Sender starts email verifications and sends their tokens.
+type Sender struct {
+	hashSvc   ihash.Service      // Service for hashing verification secrets.
+	notifier  inotifier.Notifier // Delivers the verification tokens to the users.
+	ttl       time.Duration      // Lifetime of verification tokens.
+	verifyURL string             // Page the user verifies their email on, if any.
+}
+
+// Config holds the dependencies for creating a new Sender.
+type Config struct {
+	HashSvc   ihash.Service      // Service for hashing verification secrets.
+	Notifier  inotifier.Notifier // Delivers the verification tokens to the users.
+	TTL       time.Duration      // Lifetime of verification tokens.
+	VerifyURL string             // Page the user verifies their email on; the token is added as the token query parameter.
+}
+
+// NewSender creates a new Sender with the given configuration.
+func NewSender(cfg Config) *Sender {
+	return &Sender{
+		hashSvc:   cfg.HashSvc,
+		notifier:  cfg.Notifier,
+		ttl:       cfg.TTL,
+		verifyURL: cfg.VerifyURL,
+	}
+}
+
+// Start starts the verification of the user's email and returns the message carrying
+// its token. The user has to be saved before the message is sent.
+func (s *Sender) Start(user *usermodel.User) (*inotifier.Message, error) {
+	secret, err := authtoken.NewSecret()
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate email verification token, %v", err))
+	}
+
+	if err := user.StartEmailVerification(secret, s.ttl, s.hashSvc); err != nil {
+		if _, ok := err.(*errdmn.Error); !ok {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to hash email verification token, %v", err))
+		}
+		return nil, err
+	}
+
+	token := FormatToken(user.ID(), secret)
+	instructions := "To verify it, use this verification token:\n" + token
+	if s.verifyURL != "" {
+		instructions = "To verify it, visit:\n" + s.verifyURL + "?token=" + url.QueryEscape(token)
+	}
+
+	return &inotifier.Message{
+		Username: user.Username(),
+		Email:    user.Email(),
+		Subject:  "Verify your email address",
+		Body: fmt.Sprintf("The email address %s was given for the account %s.\n\n%s\n\n"+
+			"It can be used until %s. If you did not create this account, you can ignore this message.",
+			user.Email(), user.Username(), instructions, user.EmailVerificationExpiresAt().Format(time.RFC1123)),
+	}, nil
+}
+
+// Send delivers a message returned by Start.
+func (s *Sender) Send(msg *inotifier.Message) error {
+	if err := s.notifier.Notify(msg); err != nil {
+		return errdmn.NewUnexpected(fmt.Sprintf("failed to send email verification token, %v", err))
+	}
+	return nil
+}
+
+// FormatToken builds the opaque verification token sent to the user.
+func FormatToken(userID uuid.UUID, secret string) string {
+	return userID.String() + "." + secret
+}
+
+// ParseToken splits an opaque verification token into the user ID and the secret.
+func ParseToken(token string) (uuid.UUID, string, error) {
+	rawID, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", errdmn.InvalidVerificationToken
+	}
+
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", errdmn.InvalidVerificationToken
+	}
+	return userID, secret, nil
+}
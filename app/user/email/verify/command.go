@@ -0,0 +1,13 @@
+package verifyemailcmd
+
+// Command represents the data required to verify an email address.
+type Command struct {
+	Token string // Opaque verification token sent to the user.
+}
+
+// NewCommand creates a new Command instance for the given verification token.
+func NewCommand(token string) *Command {
+	return &Command{
+		Token: token,
+	}
+}
@@ -0,0 +1,65 @@
+// Package verifyemailcmd provides the command and handler for verifying the email
+// address of a user with the token sent to it.
+package verifyemailcmd
+
+import (
+	"fmt"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+)
+
+// Handler handles email verifications.
+type Handler struct {
+	userRepo irepo.User    // Repository for user data operations.
+	hashSvc  ihash.Service // Service for matching verification secrets.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo irepo.User    // Repository for user data operations.
+	HashSvc  ihash.Service // Service for matching verification secrets.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo: cfg.UserRepo,
+		hashSvc:  cfg.HashSvc,
+	}
+}
+
+// Handle checks the verification token and marks the email of its user as verified.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	userID, secret, err := emailverification.ParseToken(cmd.Token)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := h.userRepo.ById(userID)
+	if err != nil {
+		if err == errdmn.UserNotFound {
+			return false, errdmn.InvalidVerificationToken
+		}
+		return false, err
+	}
+
+	if err := user.VerifyEmail(secret, time.Now().UTC(), h.hashSvc); err != nil {
+		if _, ok := err.(*errdmn.Error); !ok {
+			return false, errdmn.NewUnexpected(fmt.Sprintf("failed to validate email verification token, %v", err))
+		}
+		return false, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package verifyemailcmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	verifyemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/verify"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// VerifyEmailHandlerTestSuite defines the test suite for the verify email handler.
+type VerifyEmailHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	mockHashSvc  *ihash_mocks.Service
+	handler      *verifyemailcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *VerifyEmailHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = verifyemailcmd.NewHandler(verifyemailcmd.Config{
+		UserRepo: suite.mockUserRepo,
+		HashSvc:  suite.mockHashSvc,
+	})
+
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "guess").Return(false, nil)
+}
+
+// storedUser returns a user with a pending verification, as loaded from the repository.
+func (suite *VerifyEmailHandlerTestSuite) storedUser(expiresAt time.Time) *usermodel.User {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                         uuid.New(),
+		Username:                   "normaluser",
+		Email:                      "normaluser@example.com",
+		EmailVerificationHash:      "hashed_secret",
+		EmailVerificationExpiresAt: expiresAt,
+	})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+	return user
+}
+
+// TestHandle_Success tests that the email is marked as verified.
+func (suite *VerifyEmailHandlerTestSuite) TestHandle_Success() {
+	user := suite.storedUser(time.Now().Add(time.Hour))
+	suite.mockUserRepo.On("Save", user).Return(nil)
+
+	ok, err := suite.handler.Handle(&verifyemailcmd.Command{Token: emailverification.FormatToken(user.ID(), "secret")})
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.True(user.IsEmailVerified())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_InvalidToken tests that malformed, unknown, and wrong tokens are rejected.
+func (suite *VerifyEmailHandlerTestSuite) TestHandle_InvalidToken() {
+	user := suite.storedUser(time.Now().Add(time.Hour))
+	unknownID := uuid.New()
+	suite.mockUserRepo.On("ById", unknownID).Return(nil, errdmn.UserNotFound)
+
+	for _, token := range []string{
+		"malformed",
+		emailverification.FormatToken(unknownID, "secret"),
+		emailverification.FormatToken(user.ID(), "guess"),
+	} {
+		ok, err := suite.handler.Handle(&verifyemailcmd.Command{Token: token})
+		suite.False(ok)
+		suite.Equal(errdmn.InvalidVerificationToken, err)
+	}
+	suite.False(user.IsEmailVerified())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Expired tests that an expired token is rejected.
+func (suite *VerifyEmailHandlerTestSuite) TestHandle_Expired() {
+	user := suite.storedUser(time.Now().Add(-time.Minute))
+
+	ok, err := suite.handler.Handle(&verifyemailcmd.Command{Token: emailverification.FormatToken(user.ID(), "secret")})
+
+	suite.False(ok)
+	suite.Equal(errdmn.VerificationTokenExpired, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestVerifyEmailHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifyEmailHandlerTestSuite))
+}
@@ -0,0 +1,67 @@
+// Package lockstatusqry provides the query and handler letting admins see the failed
+// sign in attempts of a user and whether signing in as them is locked.
+package lockstatusqry
+
+import (
+	"time"
+
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// Result holds the sign in lock status of a user.
+type Result struct {
+	Username    string    // Username of the user.
+	Failures    int       // Failed sign in attempts still remembered.
+	Locked      bool      // Whether signing in as the user is refused.
+	LockedUntil time.Time // End of the lock; the zero time when not locked.
+}
+
+// Handler handles lock status queries.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	attemptRepo irepo.LoginAttempt // Repository for failed login attempts.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, *Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	AttemptRepo irepo.LoginAttempt // Repository for failed login attempts.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		attemptRepo: cfg.AttemptRepo,
+	}
+}
+
+// Handle returns the lock status of the user. Users without failed attempts are not locked.
+func (h *Handler) Handle(qry *Query) (*Result, error) {
+	user, err := h.userRepo.ByUsername(qry.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Username: user.Username()}
+	attempt, err := h.attemptRepo.ByKey(lockout.UserKey(user.Username()))
+	if err == errdmn.LoginAttemptNotFound {
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result.Failures = attempt.Failures(now)
+	if attempt.IsLocked(now) {
+		result.Locked = true
+		result.LockedUntil = attempt.LockedUntil()
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package lockstatusqry_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	lockstatusqry "github.com/beka-birhanu/task_manager_final/app/user/lock/status"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// LockStatusHandlerTestSuite defines the test suite for the lock status handler.
+type LockStatusHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockAttemptRepo *irepo_mock.LoginAttempt
+	handler         *lockstatusqry.Handler
+	user            *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *LockStatusHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockAttemptRepo = new(irepo_mock.LoginAttempt)
+	suite.handler = lockstatusqry.New(lockstatusqry.Config{
+		UserRepo:    suite.mockUserRepo,
+		AttemptRepo: suite.mockAttemptRepo,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "normaluser"})
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(suite.user, nil)
+}
+
+// TestHandle_Locked tests the status of a locked user.
+func (suite *LockStatusHandlerTestSuite) TestHandle_Locked() {
+	lockedUntil := time.Now().Add(time.Minute)
+	suite.mockAttemptRepo.On("ByKey", "user:normaluser").Return(loginattemptmodel.FromBSON(&loginattemptmodel.LoginAttemptBSON{
+		Key:         "user:normaluser",
+		Failures:    5,
+		LockedUntil: lockedUntil,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}), nil)
+
+	result, err := suite.handler.Handle(lockstatusqry.NewQuery("normaluser"))
+
+	suite.NoError(err)
+	suite.Equal(5, result.Failures)
+	suite.True(result.Locked)
+	suite.Equal(lockedUntil, result.LockedUntil)
+}
+
+// TestHandle_NoFailures tests the status of a user without failed attempts.
+func (suite *LockStatusHandlerTestSuite) TestHandle_NoFailures() {
+	suite.mockAttemptRepo.On("ByKey", "user:normaluser").Return(nil, errdmn.LoginAttemptNotFound)
+
+	result, err := suite.handler.Handle(lockstatusqry.NewQuery("normaluser"))
+
+	suite.NoError(err)
+	suite.Equal("normaluser", result.Username)
+	suite.Zero(result.Failures)
+	suite.False(result.Locked)
+}
+
+// TestHandle_UserNotFound tests that unknown users are reported to the admin.
+func (suite *LockStatusHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(lockstatusqry.NewQuery("ghost"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserNotFound, err)
+	suite.mockAttemptRepo.AssertNotCalled(suite.T(), "ByKey", mock.Anything)
+}
+
+// Run the test suite
+func TestLockStatusHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(LockStatusHandlerTestSuite))
+}
@@ -0,0 +1,11 @@
+package unlockcmd
+
+// Command represents the data required to lift the sign in lock of a user.
+type Command struct {
+	Username string // Username of the user to unlock.
+}
+
+// NewCommand creates a new Command instance with the given username.
+func NewCommand(username string) *Command {
+	return &Command{Username: username}
+}
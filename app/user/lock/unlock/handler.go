@@ -0,0 +1,49 @@
+// Package unlockcmd provides the command and handler letting admins lift the sign in
+// lock of a user and forget their failed attempts.
+package unlockcmd
+
+import (
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+)
+
+// Handler handles unlock commands.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	attemptRepo irepo.LoginAttempt // Repository for failed login attempts.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	AttemptRepo irepo.LoginAttempt // Repository for failed login attempts.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		attemptRepo: cfg.AttemptRepo,
+	}
+}
+
+// Handle removes the failed attempts recorded for the user. Locks of client IP
+// addresses are left in place.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.attemptRepo.Delete(lockout.UserKey(user.Username())); err != nil {
+		return false, err
+	}
+
+	applog.Audit("Sign in lock of user %v lifted", user.Username())
+	return true, nil
+}
@@ -0,0 +1,60 @@
+package unlockcmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	unlockcmd "github.com/beka-birhanu/task_manager_final/app/user/lock/unlock"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// UnlockHandlerTestSuite defines the test suite for the unlock handler.
+type UnlockHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockAttemptRepo *irepo_mock.LoginAttempt
+	handler         *unlockcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *UnlockHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockAttemptRepo = new(irepo_mock.LoginAttempt)
+	suite.handler = unlockcmd.New(unlockcmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		AttemptRepo: suite.mockAttemptRepo,
+	})
+}
+
+// TestHandle_Success tests that the failed attempts of the user are removed.
+func (suite *UnlockHandlerTestSuite) TestHandle_Success() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "normaluser"})
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(user, nil)
+	suite.mockAttemptRepo.On("Delete", "user:normaluser").Return(nil)
+
+	ok, err := suite.handler.Handle(unlockcmd.NewCommand("normaluser"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockAttemptRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_UserNotFound tests that unknown users are reported to the admin.
+func (suite *UnlockHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	ok, err := suite.handler.Handle(unlockcmd.NewCommand("ghost"))
+
+	suite.False(ok)
+	suite.Equal(errdmn.UserNotFound, err)
+	suite.mockAttemptRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// Run the test suite
+func TestUnlockHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(UnlockHandlerTestSuite))
+}
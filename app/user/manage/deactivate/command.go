@@ -0,0 +1,17 @@
+package deactivatecmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to deactivate a user.
+type Command struct {
+	Username string    // Username of the user to deactivate.
+	AdminID  uuid.UUID // ID of the admin performing the deactivation.
+}
+
+// NewCommand creates a new Command instance with the given username and admin ID.
+func NewCommand(username string, adminID uuid.UUID) *Command {
+	return &Command{
+		Username: username,
+		AdminID:  adminID,
+	}
+}
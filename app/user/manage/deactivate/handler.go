@@ -0,0 +1,96 @@
+// Package deactivatecmd provides the command and handler letting admins deactivate a user.
+//
+// A deactivated user can no longer sign in, and their sessions are ended so the tokens
+// they hold are rejected. Admins cannot deactivate themselves, and the last remaining
+// active admin cannot be deactivated.
+package deactivatecmd
+
+import (
+	"time"
+
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	"github.com/beka-birhanu/task_manager_final/app/user/admin_status/lastadmin"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// Handler handles deactivate commands.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle deactivates the user and ends their sessions.
+// Deactivating a user who already is changes nothing.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	admin, err := h.userRepo.ById(cmd.AdminID)
+	if err != nil {
+		return false, err
+	}
+
+	if user.ID() == admin.ID() {
+		return false, errdmn.SelfDeactivation
+	}
+	if user.IsDeactivated() {
+		return true, nil
+	}
+
+	last, err := lastadmin.IsLast(h.userRepo, user)
+	if err != nil {
+		return false, err
+	}
+	if last {
+		return false, errdmn.DeactivateLastAdmin
+	}
+
+	// An active admin is checked again along with the save, as another admin may
+	// have been deactivated since the check.
+	wasAdmin := user.IsAdmin()
+	user.Deactivate()
+	if wasAdmin {
+		err = lastadmin.Save(h.userRepo.SaveDeactivationKeepingAdmin, user, errdmn.DeactivateLastAdmin)
+	} else {
+		err = h.userRepo.SaveDeactivation(user)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return false, err
+	}
+
+	applog.Audit("Admin %v deactivated user %v", admin.Username(), user.Username())
+	return true, nil
+}
@@ -0,0 +1,131 @@
+package deactivatecmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	deactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/deactivate"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DeactivateHandlerTestSuite defines the test suite for the deactivate handler.
+type DeactivateHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	handler         *deactivatecmd.Handler
+	admin           *usermodel.User
+	member          *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *DeactivateHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.handler = deactivatecmd.New(deactivatecmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   time.Minute,
+	})
+
+	suite.admin = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin1", Role: rolemodel.Admin})
+	suite.member = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
+}
+
+// TestHandle_Success tests that the user is deactivated and their sessions ended.
+func (suite *DeactivateHandlerTestSuite) TestHandle_Success() {
+	sessionID := uuid.New()
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.member, nil)
+	suite.mockUserRepo.On("SaveDeactivation", suite.member).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.member.ID()).Return([]uuid.UUID{sessionID}, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+	suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("user1", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.True(suite.member.IsDeactivated())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+}
+
+// TestHandle_AlreadyDeactivated tests that deactivating a deactivated user changes nothing.
+func (suite *DeactivateHandlerTestSuite) TestHandle_AlreadyDeactivated() {
+	suite.member.Deactivate()
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.member, nil)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("user1", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveDeactivation", mock.Anything)
+}
+
+// TestHandle_Self tests that admins cannot deactivate themselves.
+func (suite *DeactivateHandlerTestSuite) TestHandle_Self() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("admin1", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.SelfDeactivation, err)
+	suite.False(suite.admin.IsDeactivated())
+}
+
+// TestHandle_LastAdmin tests that the last active admin cannot be deactivated.
+func (suite *DeactivateHandlerTestSuite) TestHandle_LastAdmin() {
+	other := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(other, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(1), nil)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.DeactivateLastAdmin, err)
+	suite.False(other.IsDeactivated())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveDeactivation", mock.Anything)
+}
+
+// TestHandle_ConcurrentDeactivation tests that an admin is not deactivated when the other
+// admin was deactivated between the check and the save.
+func (suite *DeactivateHandlerTestSuite) TestHandle_ConcurrentDeactivation() {
+	other := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(other, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveDeactivationKeepingAdmin", other).Return(errdmn.LastAdmin)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("admin2", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.DeactivateLastAdmin, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveDeactivation", mock.Anything)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_UserNotFound tests that unknown users are reported to the admin.
+func (suite *DeactivateHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	ok, err := suite.handler.Handle(deactivatecmd.NewCommand("ghost", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// Run the test suite
+func TestDeactivateHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DeactivateHandlerTestSuite))
+}
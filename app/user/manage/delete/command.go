@@ -0,0 +1,27 @@
+package deleteusercmd
+
+import "github.com/google/uuid"
+
+// What may be done with the tasks of a deleted user.
+const (
+	TasksReassign = "reassign" // Give the tasks to another user.
+	TasksCascade  = "cascade"  // Delete the tasks along with the user.
+)
+
+// Command represents the data required to delete a user.
+type Command struct {
+	Username   string    // Username of the user to delete.
+	Tasks      string    // TasksReassign or TasksCascade.
+	ReassignTo string    // Username of the user given the tasks when reassigning.
+	AdminID    uuid.UUID // ID of the admin performing the deletion.
+}
+
+// NewCommand creates a new Command instance with the given values.
+func NewCommand(username, tasks, reassignTo string, adminID uuid.UUID) *Command {
+	return &Command{
+		Username:   username,
+		Tasks:      tasks,
+		ReassignTo: reassignTo,
+		AdminID:    adminID,
+	}
+}
@@ -0,0 +1,164 @@
+// Package deleteusercmd provides the command and handler letting admins delete a user
+// for good.
+//
+// The tasks of the user are either reassigned to another active user or deleted with
+// them, as the admin chooses, and the user is removed from the assignees of every task.
+// The sessions and API keys of the user are ended. Admins cannot delete themselves,
+// and the last remaining active admin cannot be deleted.
+package deleteusercmd
+
+import (
+	"time"
+
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	"github.com/beka-birhanu/task_manager_final/app/user/admin_status/lastadmin"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+// Handler handles delete user commands.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	taskRepo    irepo.Task         // Repository for the tasks of the user.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	apiKeyRepo  irepo.APIKey       // Repository for the API keys of the user.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	TaskRepo    irepo.Task         // Repository for the tasks of the user.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	APIKeyRepo  irepo.APIKey       // Repository for the API keys of the user.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// New creates a new Handler with the given configuration.
+func New(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		taskRepo:    cfg.TaskRepo,
+		refreshRepo: cfg.RefreshRepo,
+		apiKeyRepo:  cfg.APIKeyRepo,
+		revocations: cfg.Revocations,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle disposes of the tasks of the user as requested, unassigns them from every task,
+// ends their sessions, deletes their API keys, and deletes them.
+//
+// The writes span several collections and are not run in one transaction. Everything
+// is validated before the first of them, which deactivates the user, so a deletion
+// failing halfway leaves a deactivated user who cannot sign in. Every following write
+// can be repeated, and the user is only deleted last, so sending the command again
+// finishes the deletion.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	if cmd.Tasks != TasksReassign && cmd.Tasks != TasksCascade {
+		return false, errdmn.InvalidTaskDisposition
+	}
+
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	admin, err := h.userRepo.ById(cmd.AdminID)
+	if err != nil {
+		return false, err
+	}
+
+	if user.ID() == admin.ID() {
+		return false, errdmn.SelfDeletion
+	}
+
+	heir, err := h.heir(cmd, user)
+	if err != nil {
+		return false, err
+	}
+
+	last, err := lastadmin.IsLast(h.userRepo, user)
+	if err != nil {
+		return false, err
+	}
+	if last {
+		return false, errdmn.DeleteLastAdmin
+	}
+
+	if err := h.deactivate(user); err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return false, err
+	}
+
+	if err := h.apiKeyRepo.DeleteByUser(user.ID()); err != nil {
+		return false, err
+	}
+
+	if heir != nil {
+		err = h.taskRepo.ReassignOwner(user.ID(), heir.ID())
+	} else {
+		err = h.taskRepo.DeleteByOwner(user.ID())
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.taskRepo.UnassignUser(user.ID()); err != nil {
+		return false, err
+	}
+
+	if err := h.userRepo.Delete(user.ID()); err != nil {
+		return false, err
+	}
+
+	applog.Audit("Admin %v deleted user %v, tasks: %v", admin.Username(), user.Username(), cmd.Tasks)
+	return true, nil
+}
+
+// heir returns the user the tasks are reassigned to, or nil when they are deleted.
+func (h *Handler) heir(cmd *Command, user *usermodel.User) (*usermodel.User, error) {
+	if cmd.Tasks == TasksCascade {
+		return nil, nil
+	}
+
+	if cmd.ReassignTo == "" {
+		return nil, errdmn.InvalidReassignTarget
+	}
+	target, err := h.userRepo.ByUsername(cmd.ReassignTo)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID() == user.ID() || target.IsDeactivated() {
+		return nil, errdmn.InvalidReassignTarget
+	}
+	return target, nil
+}
+
+// deactivate deactivates the user ahead of the deletion. An active admin is checked again
+// along with the save, as another admin may have lost the role since the check. A user
+// left deactivated by an earlier attempt is kept as is.
+func (h *Handler) deactivate(user *usermodel.User) error {
+	if user.IsDeactivated() {
+		return nil
+	}
+
+	wasAdmin := user.IsAdmin()
+	user.Deactivate()
+	if wasAdmin {
+		return lastadmin.Save(h.userRepo.SaveDeactivationKeepingAdmin, user, errdmn.DeleteLastAdmin)
+	}
+	return h.userRepo.SaveDeactivation(user)
+}
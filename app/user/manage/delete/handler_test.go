@@ -0,0 +1,192 @@
+package deleteusercmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	deleteusercmd "github.com/beka-birhanu/task_manager_final/app/user/manage/delete"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DeleteUserHandlerTestSuite defines the test suite for the delete user handler.
+type DeleteUserHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockTaskRepo    *irepo_mock.Task
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockAPIKeyRepo  *irepo_mock.APIKey
+	mockRevocations *irevocation_mock.Store
+	handler         *deleteusercmd.Handler
+	admin           *usermodel.User
+	member          *usermodel.User
+	heir            *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *DeleteUserHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockTaskRepo = new(irepo_mock.Task)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockAPIKeyRepo = new(irepo_mock.APIKey)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.handler = deleteusercmd.New(deleteusercmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		TaskRepo:    suite.mockTaskRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		APIKeyRepo:  suite.mockAPIKeyRepo,
+		Revocations: suite.mockRevocations,
+		AccessTTL:   time.Minute,
+	})
+
+	suite.admin = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin1", Role: rolemodel.Admin})
+	suite.member = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Role: rolemodel.Member})
+	suite.heir = usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user2", Role: rolemodel.Member})
+	suite.mockUserRepo.On("ById", suite.admin.ID()).Return(suite.admin, nil)
+	suite.mockUserRepo.On("ByUsername", "user1").Return(suite.member, nil)
+	suite.mockUserRepo.On("ByUsername", "user2").Return(suite.heir, nil)
+}
+
+// expectDeletion sets up the expectations for deactivating the member, unassigning them,
+// ending their sessions, deleting their API keys, and deleting them.
+func (suite *DeleteUserHandlerTestSuite) expectDeletion() {
+	suite.mockUserRepo.On("SaveDeactivation", suite.member).Return(nil)
+	suite.mockTaskRepo.On("UnassignUser", suite.member.ID()).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.member.ID()).Return([]uuid.UUID{}, nil)
+	suite.mockAPIKeyRepo.On("DeleteByUser", suite.member.ID()).Return(nil)
+	suite.mockUserRepo.On("Delete", suite.member.ID()).Return(nil)
+}
+
+// TestHandle_Reassign tests that the tasks of the user are given to another user.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_Reassign() {
+	suite.mockTaskRepo.On("ReassignOwner", suite.member.ID(), suite.heir.ID()).Return(nil)
+	suite.expectDeletion()
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("user1", deleteusercmd.TasksReassign, "user2", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockAPIKeyRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Cascade tests that the tasks of the user are deleted with them.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_Cascade() {
+	suite.mockTaskRepo.On("DeleteByOwner", suite.member.ID()).Return(nil)
+	suite.expectDeletion()
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("user1", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockTaskRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertCalled(suite.T(), "Delete", suite.member.ID())
+}
+
+// TestHandle_InvalidDisposition tests that the tasks have to be either reassigned or deleted.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_InvalidDisposition() {
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("user1", "", "", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.InvalidTaskDisposition, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ByUsername", mock.Anything)
+}
+
+// TestHandle_InvalidReassignTarget tests that tasks are only reassigned to another active user.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_InvalidReassignTarget() {
+	suite.heir.Deactivate()
+
+	for _, target := range []string{"", "user1", "user2"} {
+		ok, err := suite.handler.Handle(deleteusercmd.NewCommand("user1", deleteusercmd.TasksReassign, target, suite.admin.ID()))
+
+		suite.False(ok)
+		suite.Equal(errdmn.InvalidReassignTarget, err, target)
+	}
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "ReassignOwner", mock.Anything, mock.Anything)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveDeactivation", mock.Anything)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_Retry tests that a deletion failing halfway leaves the user deactivated, and
+// that sending it again finishes it.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_Retry() {
+	suite.mockUserRepo.On("SaveDeactivation", suite.member).Return(nil).Once()
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.member.ID()).Return([]uuid.UUID{}, nil)
+	suite.mockAPIKeyRepo.On("DeleteByUser", suite.member.ID()).Return(nil)
+	suite.mockTaskRepo.On("DeleteByOwner", suite.member.ID()).Return(errdmn.NewUnexpected("connection lost")).Once()
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("user1", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Error(err)
+	suite.True(suite.member.IsDeactivated())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+
+	// The user is already deactivated, so the retry goes straight to the remaining writes.
+	suite.mockTaskRepo.On("DeleteByOwner", suite.member.ID()).Return(nil).Once()
+	suite.mockTaskRepo.On("UnassignUser", suite.member.ID()).Return(nil)
+	suite.mockUserRepo.On("Delete", suite.member.ID()).Return(nil)
+
+	ok, err = suite.handler.Handle(deleteusercmd.NewCommand("user1", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "SaveDeactivation", 1)
+	suite.mockUserRepo.AssertCalled(suite.T(), "Delete", suite.member.ID())
+}
+
+// TestHandle_Self tests that admins cannot delete themselves.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_Self() {
+	suite.mockUserRepo.On("ByUsername", "admin1").Return(suite.admin, nil)
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("admin1", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.SelfDeletion, err)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "DeleteByOwner", mock.Anything)
+}
+
+// TestHandle_LastAdmin tests that the last active admin cannot be deleted.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_LastAdmin() {
+	other := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(other, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(1), nil)
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("admin2", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.DeleteLastAdmin, err)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "DeleteByOwner", mock.Anything)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// TestHandle_ConcurrentDeletion tests that nothing of an admin is deleted when the other
+// admin lost the role between the check and the deactivation.
+func (suite *DeleteUserHandlerTestSuite) TestHandle_ConcurrentDeletion() {
+	other := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "admin2", Role: rolemodel.Admin})
+	suite.mockUserRepo.On("ByUsername", "admin2").Return(other, nil)
+	suite.mockUserRepo.On("CountAdmins").Return(int64(2), nil)
+	suite.mockUserRepo.On("SaveDeactivationKeepingAdmin", other).Return(errdmn.LastAdmin)
+
+	ok, err := suite.handler.Handle(deleteusercmd.NewCommand("admin2", deleteusercmd.TasksCascade, "", suite.admin.ID()))
+
+	suite.False(ok)
+	suite.Equal(errdmn.DeleteLastAdmin, err)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+	suite.mockTaskRepo.AssertNotCalled(suite.T(), "DeleteByOwner", mock.Anything)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// Run the test suite
+func TestDeleteUserHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteUserHandlerTestSuite))
+}
@@ -0,0 +1,26 @@
+// Package getuserqry provides the query and handler letting admins inspect a single user.
+package getuserqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+// Handler handles user lookup queries.
+type Handler struct {
+	userRepo irepo.User // Repository for user data operations.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, *usermodel.User] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle returns the user with the username of the query.
+func (h *Handler) Handle(qry *Query) (*usermodel.User, error) {
+	return h.userRepo.ByUsername(qry.Username)
+}
@@ -0,0 +1,51 @@
+package getuserqry_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	getuserqry "github.com/beka-birhanu/task_manager_final/app/user/manage/get"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// GetUserHandlerTestSuite defines the test suite for the get user handler.
+type GetUserHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *getuserqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *GetUserHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = getuserqry.New(suite.mockUserRepo)
+}
+
+// TestHandle_Success tests that the user with the username is returned.
+func (suite *GetUserHandlerTestSuite) TestHandle_Success() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1"})
+	suite.mockUserRepo.On("ByUsername", "user1").Return(user, nil)
+
+	result, err := suite.handler.Handle(getuserqry.NewQuery("user1"))
+
+	suite.NoError(err)
+	suite.Equal(user, result)
+}
+
+// TestHandle_UserNotFound tests that unknown users are reported to the admin.
+func (suite *GetUserHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(getuserqry.NewQuery("ghost"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// Run the test suite
+func TestGetUserHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(GetUserHandlerTestSuite))
+}
@@ -0,0 +1,11 @@
+package getuserqry
+
+// Query represents the data required to look up a user.
+type Query struct {
+	Username string // Username of the user to look up.
+}
+
+// NewQuery creates a new Query instance with the given username.
+func NewQuery(username string) *Query {
+	return &Query{Username: username}
+}
@@ -0,0 +1,63 @@
+// Package listusersqry provides the query and handler letting admins list and search
+// the users, one page at a time.
+package listusersqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+)
+
+const (
+	// DefaultLimit is the page size used when the query does not specify one.
+	DefaultLimit = 20
+	// MaxLimit is the largest page size a query may request.
+	MaxLimit = 100
+)
+
+// Handler handles user listing queries.
+type Handler struct {
+	userRepo irepo.User // Repository for user data operations.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, *irepo.UserPage] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle returns a page of the users matching the query.
+func (h *Handler) Handle(qry *Query) (*irepo.UserPage, error) {
+	listing, err := toListUsers(qry)
+	if err != nil {
+		return nil, err
+	}
+	return h.userRepo.List(listing)
+}
+
+// toListUsers validates the query and applies defaults.
+func toListUsers(qry *Query) (irepo.ListUsers, error) {
+	listing := irepo.ListUsers{
+		Text:   qry.Text,
+		Limit:  qry.Limit,
+		Cursor: qry.Cursor,
+	}
+
+	if qry.Role != "" {
+		role, err := rolemodel.Parse(qry.Role)
+		if err != nil {
+			return irepo.ListUsers{}, err
+		}
+		listing.Role = role
+	}
+
+	if listing.Limit == 0 {
+		listing.Limit = DefaultLimit
+	} else if listing.Limit < 0 || listing.Limit > MaxLimit {
+		return irepo.ListUsers{}, errdmn.InvalidPageLimit
+	}
+	return listing, nil
+}
@@ -0,0 +1,69 @@
+package listusersqry_test
+
+import (
+	"testing"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	listusersqry "github.com/beka-birhanu/task_manager_final/app/user/manage/list"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ListUsersHandlerTestSuite defines the test suite for the list users handler.
+type ListUsersHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *listusersqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ListUsersHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = listusersqry.New(suite.mockUserRepo)
+}
+
+// TestHandle_Defaults tests that a query without parameters lists a page of the default size.
+func (suite *ListUsersHandlerTestSuite) TestHandle_Defaults() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1"})
+	expected := irepo.ListUsers{Limit: listusersqry.DefaultLimit}
+	suite.mockUserRepo.On("List", expected).Return(&irepo.UserPage{Users: []*usermodel.User{user}, NextCursor: "next"}, nil)
+
+	page, err := suite.handler.Handle(&listusersqry.Query{})
+
+	suite.NoError(err)
+	suite.Len(page.Users, 1)
+	suite.Equal("next", page.NextCursor)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Filters tests that the filters of the query are passed on.
+func (suite *ListUsersHandlerTestSuite) TestHandle_Filters() {
+	expected := irepo.ListUsers{Text: "ali", Role: rolemodel.Manager, Limit: 5, Cursor: "cursor"}
+	suite.mockUserRepo.On("List", expected).Return(&irepo.UserPage{}, nil)
+
+	_, err := suite.handler.Handle(&listusersqry.Query{Text: "ali", Role: "manager", Limit: 5, Cursor: "cursor"})
+
+	suite.NoError(err)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_InvalidQuery tests that invalid roles and page sizes are refused.
+func (suite *ListUsersHandlerTestSuite) TestHandle_InvalidQuery() {
+	_, err := suite.handler.Handle(&listusersqry.Query{Role: "root"})
+	suite.Equal(errdmn.InvalidRole, err)
+
+	_, err = suite.handler.Handle(&listusersqry.Query{Limit: listusersqry.MaxLimit + 1})
+	suite.Equal(errdmn.InvalidPageLimit, err)
+
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "List", mock.Anything)
+}
+
+// Run the test suite
+func TestListUsersHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ListUsersHandlerTestSuite))
+}
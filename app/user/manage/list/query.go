@@ -0,0 +1,10 @@
+package listusersqry
+
+// Query represents a filtered and paginated listing of users, sorted by username.
+// Zero values leave the corresponding filter unset or fall back to a default.
+type Query struct {
+	Text   string // Case-insensitive match against the username or email.
+	Role   string // Only users with this role.
+	Limit  int    // Page size; defaults to DefaultLimit.
+	Cursor string // Token from a previous page's NextCursor.
+}
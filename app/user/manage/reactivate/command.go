@@ -0,0 +1,11 @@
+package reactivatecmd
+
+// Command represents the data required to reactivate a user.
+type Command struct {
+	Username string // Username of the user to reactivate.
+}
+
+// NewCommand creates a new Command instance with the given username.
+func NewCommand(username string) *Command {
+	return &Command{Username: username}
+}
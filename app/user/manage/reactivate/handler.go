@@ -0,0 +1,42 @@
+// Package reactivatecmd provides the command and handler letting admins reactivate a
+// deactivated user, who may then sign in again.
+package reactivatecmd
+
+import (
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+)
+
+// Handler handles reactivate commands.
+type Handler struct {
+	userRepo irepo.User // Repository for user data operations.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle reactivates the user. Reactivating a user who is active changes nothing.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	if !user.IsDeactivated() {
+		return true, nil
+	}
+
+	user.Reactivate()
+	if err := h.userRepo.SaveDeactivation(user); err != nil {
+		return false, err
+	}
+
+	applog.Audit("User %v reactivated", user.Username())
+	return true, nil
+}
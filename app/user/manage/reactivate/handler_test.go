@@ -0,0 +1,67 @@
+package reactivatecmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	reactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/reactivate"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ReactivateHandlerTestSuite defines the test suite for the reactivate handler.
+type ReactivateHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *reactivatecmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ReactivateHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = reactivatecmd.New(suite.mockUserRepo)
+}
+
+// TestHandle_Success tests that a deactivated user is reactivated.
+func (suite *ReactivateHandlerTestSuite) TestHandle_Success() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", Deactivated: true})
+	suite.mockUserRepo.On("ByUsername", "user1").Return(user, nil)
+	suite.mockUserRepo.On("SaveDeactivation", user).Return(nil)
+
+	ok, err := suite.handler.Handle(reactivatecmd.NewCommand("user1"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.False(user.IsDeactivated())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Active tests that reactivating an active user changes nothing.
+func (suite *ReactivateHandlerTestSuite) TestHandle_Active() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1"})
+	suite.mockUserRepo.On("ByUsername", "user1").Return(user, nil)
+
+	ok, err := suite.handler.Handle(reactivatecmd.NewCommand("user1"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SaveDeactivation", mock.Anything)
+}
+
+// TestHandle_UserNotFound tests that unknown users are reported to the admin.
+func (suite *ReactivateHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	ok, err := suite.handler.Handle(reactivatecmd.NewCommand("ghost"))
+
+	suite.False(ok)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// Run the test suite
+func TestReactivateHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReactivateHandlerTestSuite))
+}
@@ -0,0 +1,73 @@
+// Package mfacode checks the second factor codes of users and generates their
+// recovery codes.
+//
+// Users prove their second factor with the code shown by their authenticator app or,
+// when they lost it, with one of the recovery codes given at enrollment. Each code is
+// accepted once.
+package mfacode
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10 // Characters, 50 random bits.
+)
+
+// recoveryEncoding spells recovery codes in lowercase base32, which has no look-alike characters.
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// NewRecoveryCodes returns a new set of recovery codes, formatted as "xxxxx-xxxxx".
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate recovery codes, %v", err))
+		}
+		code := recoveryEncoding.EncodeToString(random)[:recoveryCodeLength]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode removes the formatting of a recovery code, so codes are hashed
+// and matched the same however the user typed them.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// Check accepts a TOTP code or an unused recovery code of the user, and records its use
+// so it cannot be accepted again. The user has to be saved afterwards.
+func Check(user *usermodel.User, code string, totp itotp.Service, hashSvc ihash.Service) error {
+	if !user.IsMFAEnabled() {
+		return errdmn.MFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Validate(user.TOTPSecret(), code); ok {
+		return user.UseTOTPStep(step)
+	}
+
+	code = NormalizeRecoveryCode(code)
+	if len(code) != recoveryCodeLength {
+		return errdmn.InvalidMFACode
+	}
+	if err := user.UseRecoveryCode(code, hashSvc); err != nil {
+		if _, ok := err.(*errdmn.Error); !ok {
+			return errdmn.NewUnexpected(fmt.Sprintf("failed to validate recovery code, %v", err))
+		}
+		return err
+	}
+	return nil
+}
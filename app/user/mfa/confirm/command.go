@@ -0,0 +1,17 @@
+package confirmmfacmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to confirm a TOTP enrollment.
+type Command struct {
+	UserID uuid.UUID // User confirming their enrollment.
+	Code   string    // Code shown by the authenticator app.
+}
+
+// NewCommand creates a new Command instance with the given user ID and code.
+func NewCommand(userID uuid.UUID, code string) *Command {
+	return &Command{
+		UserID: userID,
+		Code:   code,
+	}
+}
@@ -0,0 +1,85 @@
+// Package confirmmfacmd provides the command and handler for confirming the enrollment
+// of an authenticator app, which enables two-factor authentication.
+//
+// The recovery codes are returned once, on confirmation; only their hashes are stored.
+package confirmmfacmd
+
+import (
+	"fmt"
+	"strings"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	mfacode "github.com/beka-birhanu/task_manager_final/app/user/mfa/code"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+)
+
+// Handler handles TOTP enrollment confirmations.
+type Handler struct {
+	userRepo irepo.User    // Repository for user data operations.
+	totp     itotp.Service // Checks the TOTP code.
+	hashSvc  ihash.Service // Service for hashing recovery codes.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, []string] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo irepo.User    // Repository for user data operations.
+	TOTP     itotp.Service // Checks the TOTP code.
+	HashSvc  ihash.Service // Service for hashing recovery codes.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo: cfg.UserRepo,
+		totp:     cfg.TOTP,
+		hashSvc:  cfg.HashSvc,
+	}
+}
+
+// Handle checks the code against the pending TOTP secret, enables two-factor
+// authentication, and returns the new recovery codes.
+func (h *Handler) Handle(cmd *Command) ([]string, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, errdmn.MFAAlreadyEnabled
+	}
+	if user.PendingTOTPSecret() == "" {
+		return nil, errdmn.MFAEnrollmentNotStarted
+	}
+
+	step, ok := h.totp.Validate(user.PendingTOTPSecret(), strings.TrimSpace(cmd.Code))
+	if !ok {
+		return nil, errdmn.InvalidMFACode
+	}
+
+	recoveryCodes, err := mfacode.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		normalized[i] = mfacode.NormalizeRecoveryCode(code)
+	}
+	if err := user.ConfirmTOTPEnrollment(step, normalized, h.hashSvc); err != nil {
+		if _, ok := err.(*errdmn.Error); !ok {
+			return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to hash recovery codes, %v", err))
+		}
+		return nil, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
@@ -0,0 +1,93 @@
+package confirmmfacmd_test
+
+import (
+	"regexp"
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	itotp_mock "github.com/beka-birhanu/task_manager_final/app/common/i_totp/mocks"
+	confirmmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/confirm"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ConfirmMFAHandlerTestSuite defines the test suite for the TOTP enrollment confirmation handler.
+type ConfirmMFAHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	mockTOTP     *itotp_mock.Service
+	mockHashSvc  *ihash_mocks.Service
+	handler      *confirmmfacmd.Handler
+	user         *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *ConfirmMFAHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockTOTP = new(itotp_mock.Service)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = confirmmfacmd.NewHandler(confirmmfacmd.Config{
+		UserRepo: suite.mockUserRepo,
+		TOTP:     suite.mockTOTP,
+		HashSvc:  suite.mockHashSvc,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                uuid.New(),
+		Username:          "normaluser",
+		PendingTOTPSecret: "SECRET",
+	})
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+}
+
+// TestHandle_Success tests that two-factor authentication is enabled and recovery codes are returned.
+func (suite *ConfirmMFAHandlerTestSuite) TestHandle_Success() {
+	suite.mockTOTP.On("Validate", "SECRET", "123456").Return(int64(100), true)
+	suite.mockHashSvc.On("Hash", mock.MatchedBy(regexp.MustCompile(`^[a-z2-7]{10}$`).MatchString)).Return("hashed_code", nil)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+
+	codes, err := suite.handler.Handle(confirmmfacmd.NewCommand(suite.user.ID(), " 123456 "))
+
+	suite.NoError(err)
+	suite.Len(codes, 10)
+	for _, code := range codes {
+		suite.Regexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+	}
+	suite.True(suite.user.IsMFAEnabled())
+	suite.Equal(int64(100), suite.user.TOTPLastStep())
+	suite.Len(suite.user.RecoveryCodeHashes(), 10)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_InvalidCode tests that a wrong code leaves two-factor authentication off.
+func (suite *ConfirmMFAHandlerTestSuite) TestHandle_InvalidCode() {
+	suite.mockTOTP.On("Validate", "SECRET", "654321").Return(int64(0), false)
+
+	codes, err := suite.handler.Handle(confirmmfacmd.NewCommand(suite.user.ID(), "654321"))
+
+	suite.Nil(codes)
+	suite.Equal(errdmn.InvalidMFACode, err)
+	suite.False(suite.user.IsMFAEnabled())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_NotStarted tests that there must be an enrollment to confirm.
+func (suite *ConfirmMFAHandlerTestSuite) TestHandle_NotStarted() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "otheruser"})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+
+	codes, err := suite.handler.Handle(confirmmfacmd.NewCommand(user.ID(), "123456"))
+
+	suite.Nil(codes)
+	suite.Equal(errdmn.MFAEnrollmentNotStarted, err)
+}
+
+// Run the test suite
+func TestConfirmMFAHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfirmMFAHandlerTestSuite))
+}
@@ -0,0 +1,19 @@
+package disablemfacmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to disable two-factor authentication.
+type Command struct {
+	UserID   uuid.UUID // User disabling two-factor authentication.
+	Password string    // Current password of the user.
+	Code     string    // TOTP code or recovery code of the user.
+}
+
+// NewCommand creates a new Command instance with the given user ID, password and code.
+func NewCommand(userID uuid.UUID, password, code string) *Command {
+	return &Command{
+		UserID:   userID,
+		Password: password,
+		Code:     code,
+	}
+}
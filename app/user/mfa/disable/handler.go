@@ -0,0 +1,76 @@
+// Package disablemfacmd provides the command and handler for disabling two-factor
+// authentication.
+//
+// Both factors are required, so a stolen session alone cannot weaken the account.
+package disablemfacmd
+
+import (
+	"fmt"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	mfacode "github.com/beka-birhanu/task_manager_final/app/user/mfa/code"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+)
+
+// Handler handles requests to disable two-factor authentication.
+type Handler struct {
+	userRepo irepo.User    // Repository for user data operations.
+	totp     itotp.Service // Checks TOTP codes.
+	hashSvc  ihash.Service // Service for matching passwords and recovery codes.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo irepo.User    // Repository for user data operations.
+	TOTP     itotp.Service // Checks TOTP codes.
+	HashSvc  ihash.Service // Service for matching passwords and recovery codes.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo: cfg.UserRepo,
+		totp:     cfg.TOTP,
+		hashSvc:  cfg.HashSvc,
+	}
+}
+
+// Handle verifies the password, if the user has one, and the second factor code, then
+// disables two-factor authentication.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	// Users signing in with single sign-on have no password; the code alone proves them.
+	if user.HasPassword() {
+		isPasswordCorrect, err := h.hashSvc.Match(user.PasswordHash(), cmd.Password)
+		if err != nil {
+			errMessage := fmt.Sprintf("failed to validate user password, %v", err)
+			return false, errdmn.NewUnexpected(errMessage)
+		}
+		if !isPasswordCorrect {
+			return false, errdmn.IncorrectCurrentPassword
+		}
+	}
+
+	if err := mfacode.Check(user, cmd.Code, h.totp, h.hashSvc); err != nil {
+		return false, err
+	}
+
+	if err := user.DisableMFA(); err != nil {
+		return false, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
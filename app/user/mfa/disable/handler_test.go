@@ -0,0 +1,109 @@
+package disablemfacmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	itotp_mock "github.com/beka-birhanu/task_manager_final/app/common/i_totp/mocks"
+	disablemfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/disable"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DisableMFAHandlerTestSuite defines the test suite for the disable two-factor authentication handler.
+type DisableMFAHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	mockTOTP     *itotp_mock.Service
+	mockHashSvc  *ihash_mocks.Service
+	handler      *disablemfacmd.Handler
+	user         *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *DisableMFAHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockTOTP = new(itotp_mock.Service)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = disablemfacmd.NewHandler(disablemfacmd.Config{
+		UserRepo: suite.mockUserRepo,
+		TOTP:     suite.mockTOTP,
+		HashSvc:  suite.mockHashSvc,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:           uuid.New(),
+		Username:     "normaluser",
+		PasswordHash: "hashed_password",
+		TOTPSecret:   "SECRET",
+		TOTPLastStep: 100,
+	})
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockHashSvc.On("Match", "hashed_password", "password").Return(true, nil)
+	suite.mockHashSvc.On("Match", "hashed_password", "guess").Return(false, nil)
+}
+
+// TestHandle_Success tests that both factors disable two-factor authentication.
+func (suite *DisableMFAHandlerTestSuite) TestHandle_Success() {
+	suite.mockTOTP.On("Validate", "SECRET", "123456").Return(int64(101), true)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+
+	ok, err := suite.handler.Handle(disablemfacmd.NewCommand(suite.user.ID(), "password", "123456"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.False(suite.user.IsMFAEnabled())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_IncorrectPassword tests that the password is required.
+func (suite *DisableMFAHandlerTestSuite) TestHandle_IncorrectPassword() {
+	ok, err := suite.handler.Handle(disablemfacmd.NewCommand(suite.user.ID(), "guess", "123456"))
+
+	suite.False(ok)
+	suite.Equal(errdmn.IncorrectCurrentPassword, err)
+	suite.True(suite.user.IsMFAEnabled())
+	suite.mockTOTP.AssertNotCalled(suite.T(), "Validate", mock.Anything, mock.Anything)
+}
+
+// TestHandle_NoPassword tests that users without a password disable it with the code alone.
+func (suite *DisableMFAHandlerTestSuite) TestHandle_NoPassword() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:           uuid.New(),
+		Username:     "sso_user",
+		TOTPSecret:   "SECRET",
+		TOTPLastStep: 100,
+	})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+	suite.mockTOTP.On("Validate", "SECRET", "123456").Return(int64(101), true)
+	suite.mockUserRepo.On("Save", user).Return(nil)
+
+	ok, err := suite.handler.Handle(disablemfacmd.NewCommand(user.ID(), "", "123456"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.False(user.IsMFAEnabled())
+	suite.mockHashSvc.AssertNotCalled(suite.T(), "Match", mock.Anything, mock.Anything)
+}
+
+// TestHandle_InvalidCode tests that the second factor is required.
+func (suite *DisableMFAHandlerTestSuite) TestHandle_InvalidCode() {
+	suite.mockTOTP.On("Validate", "SECRET", "654321").Return(int64(0), false)
+
+	ok, err := suite.handler.Handle(disablemfacmd.NewCommand(suite.user.ID(), "password", "654321"))
+
+	suite.False(ok)
+	suite.Equal(errdmn.InvalidMFACode, err)
+	suite.True(suite.user.IsMFAEnabled())
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestDisableMFAHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DisableMFAHandlerTestSuite))
+}
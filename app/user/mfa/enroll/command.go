@@ -0,0 +1,15 @@
+package enrollmfacmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to start a TOTP enrollment.
+type Command struct {
+	UserID uuid.UUID // User enrolling an authenticator app.
+}
+
+// NewCommand creates a new Command instance for the given user.
+func NewCommand(userID uuid.UUID) *Command {
+	return &Command{
+		UserID: userID,
+	}
+}
@@ -0,0 +1,72 @@
+// Package enrollmfacmd provides the command and handler for starting the enrollment of
+// an authenticator app, the first step of enabling two-factor authentication.
+//
+// The new TOTP secret is only pending: two-factor authentication is enabled once a code
+// generated from it is confirmed, so a user cannot lock themselves out with a secret
+// their app did not store.
+package enrollmfacmd
+
+import (
+	"fmt"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// Result holds the secret to enter in the authenticator app.
+type Result struct {
+	Secret string // Base32 encoded TOTP secret, for apps the URI cannot be scanned into.
+	URI    string // otpauth:// URI, usually shown as a QR code.
+}
+
+// Handler handles TOTP enrollments.
+type Handler struct {
+	userRepo irepo.User    // Repository for user data operations.
+	totp     itotp.Service // Generates TOTP secrets.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo irepo.User    // Repository for user data operations.
+	TOTP     itotp.Service // Generates TOTP secrets.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo: cfg.UserRepo,
+		totp:     cfg.TOTP,
+	}
+}
+
+// Handle generates a TOTP secret and stores it as the user's pending enrollment,
+// replacing any previous one.
+func (h *Handler) Handle(cmd *Command) (*Result, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := h.totp.NewSecret()
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to generate totp secret, %v", err))
+	}
+
+	if err := user.StartTOTPEnrollment(secret); err != nil {
+		return nil, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Secret: secret,
+		URI:    h.totp.URI(user.Username(), secret),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package enrollmfacmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	itotp_mock "github.com/beka-birhanu/task_manager_final/app/common/i_totp/mocks"
+	enrollmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/enroll"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// EnrollMFAHandlerTestSuite defines the test suite for the TOTP enrollment handler.
+type EnrollMFAHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	mockTOTP     *itotp_mock.Service
+	handler      *enrollmfacmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *EnrollMFAHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockTOTP = new(itotp_mock.Service)
+
+	suite.handler = enrollmfacmd.NewHandler(enrollmfacmd.Config{
+		UserRepo: suite.mockUserRepo,
+		TOTP:     suite.mockTOTP,
+	})
+
+	suite.mockTOTP.On("NewSecret").Return("SECRET", nil)
+	suite.mockTOTP.On("URI", "normaluser", "SECRET").Return("otpauth://totp/Task%20Manager:normaluser?secret=SECRET")
+}
+
+// TestHandle_Success tests that the secret is stored as pending and returned.
+func (suite *EnrollMFAHandlerTestSuite) TestHandle_Success() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "normaluser"})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+	suite.mockUserRepo.On("Save", user).Return(nil)
+
+	result, err := suite.handler.Handle(enrollmfacmd.NewCommand(user.ID()))
+
+	suite.NoError(err)
+	suite.Equal("SECRET", result.Secret)
+	suite.Equal("otpauth://totp/Task%20Manager:normaluser?secret=SECRET", result.URI)
+	suite.Equal("SECRET", user.PendingTOTPSecret())
+	suite.False(user.IsMFAEnabled())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_AlreadyEnabled tests that a user with two-factor authentication cannot enroll again.
+func (suite *EnrollMFAHandlerTestSuite) TestHandle_AlreadyEnabled() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "normaluser", TOTPSecret: "OLD"})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+
+	result, err := suite.handler.Handle(enrollmfacmd.NewCommand(user.ID()))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.MFAAlreadyEnabled, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestEnrollMFAHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnrollMFAHandlerTestSuite))
+}
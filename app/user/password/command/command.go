@@ -0,0 +1,19 @@
+package passwordcmd
+
+import "github.com/google/uuid"
+
+// Command represents the data required to change the password of a user.
+type Command struct {
+	UserID          uuid.UUID // User changing their password.
+	CurrentPassword string    // Current password, proving the user knows it.
+	NewPassword     string    // Password replacing the current one.
+}
+
+// NewCommand creates a new Command instance with the given user ID and passwords.
+func NewCommand(userID uuid.UUID, currentPassword, newPassword string) *Command {
+	return &Command{
+		UserID:          userID,
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	}
+}
@@ -0,0 +1,100 @@
+// Package passwordcmd provides the command and handler for changing the password
+// of an authenticated user.
+//
+// Changing the password ends every existing session of the user, so a stolen session
+// does not survive it, and starts a new one for the caller.
+package passwordcmd
+
+import (
+	"fmt"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	authresult "github.com/beka-birhanu/task_manager_final/app/user/auth/common"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	"github.com/google/uuid"
+)
+
+// Handler handles password changes.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	tokens      authtoken.IIssuer  // Issuer of access and refresh tokens.
+	hashSvc     ihash.Service      // Service for password hashing.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *authresult.Result] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	Tokens      authtoken.IIssuer  // Issuer of access and refresh tokens.
+	HashSvc     ihash.Service      // Service for password hashing.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		tokens:      cfg.Tokens,
+		hashSvc:     cfg.HashSvc,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle verifies the current password, stores the new one, ends the existing
+// sessions of the user and issues the tokens of a new session.
+func (h *Handler) Handle(cmd *Command) (*authresult.Result, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users signing in with single sign-on have no password to prove.
+	if !user.HasPassword() {
+		return nil, errdmn.PasswordNotSet
+	}
+
+	isPasswordCorrect, err := h.hashSvc.Match(user.PasswordHash(), cmd.CurrentPassword)
+	if err != nil {
+		errMessage := fmt.Sprintf("failed to validate user password, %v", err)
+		return nil, errdmn.NewUnexpected(errMessage)
+	}
+	if !isPasswordCorrect {
+		return nil, errdmn.IncorrectCurrentPassword
+	}
+	if cmd.NewPassword == cmd.CurrentPassword {
+		return nil, errdmn.PasswordUnchanged
+	}
+
+	// Enforces the password strength policy.
+	if err := user.UpdatePassword(cmd.NewPassword, h.hashSvc); err != nil {
+		return nil, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.tokens.Issue(user, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	return authresult.New(user, tokens), nil
+}
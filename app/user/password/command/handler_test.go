@@ -0,0 +1,155 @@
+package passwordcmd_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	authtoken_mock "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+	passwordcmd "github.com/beka-birhanu/task_manager_final/app/user/password/command"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	currentPassword = "&&^_str0ngp@ssw0rd!@d$"
+	newPassword     = "c0rrect-h0rse-b@ttery-st@ple"
+)
+
+// ChangePasswordHandlerTestSuite defines the test suite for the change password handler.
+type ChangePasswordHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	mockTokens      *authtoken_mock.IIssuer
+	mockHashSvc     *ihash_mocks.Service
+	handler         *passwordcmd.Handler
+	user            *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *ChangePasswordHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.mockTokens = new(authtoken_mock.IIssuer)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = passwordcmd.NewHandler(passwordcmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		Tokens:      suite.mockTokens,
+		HashSvc:     suite.mockHashSvc,
+		AccessTTL:   15 * time.Minute,
+	})
+
+	suite.mockHashSvc.On("Hash", currentPassword).Return("current_hash", nil).Once()
+	var err error
+	suite.user, err = usermodel.New(usermodel.Config{
+		Username:       "normaluser",
+		PlainPassword:  currentPassword,
+		PasswordHasher: suite.mockHashSvc,
+	})
+	suite.Require().NoError(err)
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+}
+
+// TestHandle_Success tests that the password is replaced and every session is ended.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_Success() {
+	sessions := []uuid.UUID{uuid.New(), uuid.New()}
+	suite.mockHashSvc.On("Match", "current_hash", currentPassword).Return(true, nil)
+	suite.mockHashSvc.On("Hash", newPassword).Return("new_hash", nil)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.user.ID()).Return(sessions, nil)
+	for _, sessionID := range sessions {
+		suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+		suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+	}
+	suite.mockTokens.On("Issue", suite.user, uuid.Nil).Return(&authtoken.Pair{AccessToken: "jwt_token", RefreshToken: "refresh_token"}, nil)
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(suite.user.ID(), currentPassword, newPassword))
+
+	suite.NoError(err)
+	suite.Equal("jwt_token", result.Token)
+	suite.Equal("refresh_token", result.RefreshToken)
+	suite.Equal("new_hash", suite.user.PasswordHash())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+	suite.mockTokens.AssertExpectations(suite.T())
+}
+
+// TestHandle_IncorrectCurrentPassword tests that a wrong current password changes nothing.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_IncorrectCurrentPassword() {
+	suite.mockHashSvc.On("Match", "current_hash", "wrong").Return(false, nil)
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(suite.user.ID(), "wrong", newPassword))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.IncorrectCurrentPassword, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+	suite.mockRefreshRepo.AssertNotCalled(suite.T(), "FamiliesByUser", mock.Anything)
+}
+
+// TestHandle_PasswordNotSet tests that users signing in with single sign-on have no password to change.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_PasswordNotSet() {
+	ssoUser := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "sso_user"})
+	suite.mockUserRepo.On("ById", ssoUser.ID()).Return(ssoUser, nil)
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(ssoUser.ID(), "", newPassword))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.PasswordNotSet, err)
+	suite.mockHashSvc.AssertNotCalled(suite.T(), "Match", mock.Anything, mock.Anything)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_WeakPassword tests that the password strength policy is enforced.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_WeakPassword() {
+	suite.mockHashSvc.On("Match", "current_hash", currentPassword).Return(true, nil)
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(suite.user.ID(), currentPassword, "password"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.WeakPassword, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_PasswordUnchanged tests that the new password must differ from the current one.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_PasswordUnchanged() {
+	suite.mockHashSvc.On("Match", "current_hash", currentPassword).Return(true, nil)
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(suite.user.ID(), currentPassword, currentPassword))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.PasswordUnchanged, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_RevokeError tests that a failure to end the sessions is returned.
+func (suite *ChangePasswordHandlerTestSuite) TestHandle_RevokeError() {
+	suite.mockHashSvc.On("Match", "current_hash", currentPassword).Return(true, nil)
+	suite.mockHashSvc.On("Hash", newPassword).Return("new_hash", nil)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.user.ID()).Return(nil, errors.New("repo error"))
+
+	result, err := suite.handler.Handle(passwordcmd.NewCommand(suite.user.ID(), currentPassword, newPassword))
+
+	suite.Nil(result)
+	suite.EqualError(err, "repo error")
+	suite.mockTokens.AssertNotCalled(suite.T(), "Issue", mock.Anything, mock.Anything)
+}
+
+// Run the test suite
+func TestChangePasswordHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangePasswordHandlerTestSuite))
+}
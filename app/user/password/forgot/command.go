@@ -0,0 +1,13 @@
+package forgotcmd
+
+// Command represents the data required to send a password reset token to a user.
+type Command struct {
+	Username string // User who forgot their password.
+}
+
+// NewCommand creates a new Command instance for the given username.
+func NewCommand(username string) *Command {
+	return &Command{
+		Username: username,
+	}
+}
@@ -0,0 +1,114 @@
+// Package forgotcmd provides the command and handler for starting a password reset.
+//
+// A single-use reset token is issued to the user and delivered through the notifier.
+// Issuing a token discards the tokens issued to the user before, so only the latest
+// message can be used. A token that cannot be delivered is logged as a warning rather
+// than failing the request, so that callers answering anonymous requests cannot tell
+// existing users apart by it.
+package forgotcmd
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	applog "github.com/beka-birhanu/task_manager_final/app/common/app_log"
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	resettoken "github.com/beka-birhanu/task_manager_final/app/user/password/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+// Handler handles password reset requests.
+type Handler struct {
+	userRepo  irepo.User         // Repository for user data operations.
+	resetRepo irepo.ResetToken   // Repository for password reset tokens.
+	notifier  inotifier.Notifier // Delivers the reset tokens to the users.
+	hashSvc   ihash.Service      // Service for hashing reset token secrets.
+	ttl       time.Duration      // Lifetime of reset tokens.
+	resetURL  string             // Page the user resets their password on, if any.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo  irepo.User         // Repository for user data operations.
+	ResetRepo irepo.ResetToken   // Repository for password reset tokens.
+	Notifier  inotifier.Notifier // Delivers the reset tokens to the users.
+	HashSvc   ihash.Service      // Service for hashing reset token secrets.
+	TTL       time.Duration      // Lifetime of reset tokens.
+	ResetURL  string             // Page the user resets their password on; the token is added as the token query parameter.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:  cfg.UserRepo,
+		resetRepo: cfg.ResetRepo,
+		notifier:  cfg.Notifier,
+		hashSvc:   cfg.HashSvc,
+		ttl:       cfg.TTL,
+		resetURL:  cfg.ResetURL,
+	}
+}
+
+// Handle issues a reset token to the user and sends it to them, reporting whether it
+// was delivered. It fails with errdmn.UserNotFound if the user does not exist.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	user, err := h.userRepo.ByUsername(cmd.Username)
+	if err != nil {
+		return false, err
+	}
+
+	secret, err := authtoken.NewSecret()
+	if err != nil {
+		return false, errdmn.NewUnexpected(fmt.Sprintf("failed to generate password reset token, %v", err))
+	}
+
+	token, err := resettokenmodel.New(resettokenmodel.Config{
+		UserID:       user.ID(),
+		Secret:       secret,
+		TTL:          h.ttl,
+		SecretHasher: h.hashSvc,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.resetRepo.DeleteByUser(user.ID()); err != nil {
+		return false, err
+	}
+	if err := h.resetRepo.Save(token); err != nil {
+		return false, err
+	}
+
+	if err := h.notifier.Notify(h.message(user, token, resettoken.Format(token.ID(), secret))); err != nil {
+		applog.Warn("password reset token of user %v not sent: %v", user.Username(), err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// message builds the message carrying the reset token.
+func (h *Handler) message(user *usermodel.User, token *resettokenmodel.ResetToken, rawToken string) *inotifier.Message {
+	instructions := "To choose a new password, use this reset token:\n" + rawToken
+	if h.resetURL != "" {
+		instructions = "To choose a new password, visit:\n" + h.resetURL + "?token=" + url.QueryEscape(rawToken)
+	}
+
+	return &inotifier.Message{
+		Username: user.Username(),
+		Email:    user.Email(),
+		Subject:  "Reset your password",
+		Body: fmt.Sprintf("A password reset was requested for your account %s.\n\n%s\n\n"+
+			"It can be used once, until %s. If you did not ask for it, you can ignore this message.",
+			user.Username(), instructions, token.ExpiresAt().Format(time.RFC1123)),
+	}
+}
@@ -0,0 +1,137 @@
+package forgotcmd_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	inotifier_mock "github.com/beka-birhanu/task_manager_final/app/common/i_notifier/mocks"
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	resettoken "github.com/beka-birhanu/task_manager_final/app/user/password/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ForgotPasswordHandlerTestSuite defines the test suite for the forgot password handler.
+type ForgotPasswordHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo  *irepo_mock.User
+	mockResetRepo *irepo_mock.ResetToken
+	mockNotifier  *inotifier_mock.Notifier
+	mockHashSvc   *ihash_mocks.Service
+	handler       *forgotcmd.Handler
+	user          *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *ForgotPasswordHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockResetRepo = new(irepo_mock.ResetToken)
+	suite.mockNotifier = new(inotifier_mock.Notifier)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = forgotcmd.NewHandler(forgotcmd.Config{
+		UserRepo:  suite.mockUserRepo,
+		ResetRepo: suite.mockResetRepo,
+		Notifier:  suite.mockNotifier,
+		HashSvc:   suite.mockHashSvc,
+		TTL:       time.Hour,
+		ResetURL:  "https://tasks.example.com/reset-password",
+	})
+
+	suite.mockHashSvc.On("Hash", "&&^_Str0ngP@ssw0rd!@D$").Return("password_hash", nil).Once()
+	var err error
+	suite.user, err = usermodel.New(usermodel.Config{
+		Username:       "normaluser",
+		PlainPassword:  "&&^_Str0ngP@ssw0rd!@D$",
+		PasswordHasher: suite.mockHashSvc,
+	})
+	suite.Require().NoError(err)
+}
+
+// TestHandle_Success tests that a token is stored hashed and its plain form is sent to the user.
+func (suite *ForgotPasswordHandlerTestSuite) TestHandle_Success() {
+	var saved *resettokenmodel.ResetToken
+	var sent *inotifier.Message
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(suite.user, nil)
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+	suite.mockResetRepo.On("DeleteByUser", suite.user.ID()).Return(nil)
+	suite.mockResetRepo.On("Save", mock.AnythingOfType("*resettokenmodel.ResetToken")).
+		Run(func(args mock.Arguments) { saved = args.Get(0).(*resettokenmodel.ResetToken) }).Return(nil)
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(0).(*inotifier.Message) }).Return(nil)
+
+	ok, err := suite.handler.Handle(forgotcmd.NewCommand("normaluser"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal(suite.user.ID(), saved.UserID())
+	suite.Equal("hashed_secret", saved.SecretHash())
+	suite.WithinDuration(time.Now().Add(time.Hour), saved.ExpiresAt(), time.Minute)
+
+	suite.Equal("normaluser", sent.Username)
+	_, link, found := strings.Cut(sent.Body, "https://tasks.example.com/reset-password?token=")
+	suite.Require().True(found)
+	rawToken, _, _ := strings.Cut(link, "\n")
+	id, secret, err := resettoken.Parse(rawToken)
+	suite.NoError(err)
+	suite.Equal(saved.ID(), id)
+	suite.NotEqual("hashed_secret", secret)
+	suite.mockResetRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_UserNotFound tests that no token is issued for an unknown user.
+func (suite *ForgotPasswordHandlerTestSuite) TestHandle_UserNotFound() {
+	suite.mockUserRepo.On("ByUsername", "ghost").Return(nil, errdmn.UserNotFound)
+
+	ok, err := suite.handler.Handle(forgotcmd.NewCommand("ghost"))
+
+	suite.Equal(errdmn.UserNotFound, err)
+	suite.False(ok)
+	suite.mockResetRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+	suite.mockNotifier.AssertNotCalled(suite.T(), "Notify", mock.Anything)
+}
+
+// TestHandle_NotifyError tests that a delivery failure is reported as an undelivered
+// token rather than an error.
+func (suite *ForgotPasswordHandlerTestSuite) TestHandle_NotifyError() {
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(suite.user, nil)
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+	suite.mockResetRepo.On("DeleteByUser", suite.user.ID()).Return(nil)
+	suite.mockResetRepo.On("Save", mock.AnythingOfType("*resettokenmodel.ResetToken")).Return(nil)
+	suite.mockNotifier.On("Notify", mock.AnythingOfType("*inotifier.Message")).Return(errors.New("smtp down"))
+
+	ok, err := suite.handler.Handle(forgotcmd.NewCommand("normaluser"))
+
+	suite.NoError(err)
+	suite.False(ok)
+}
+
+// TestHandle_NoEmail tests that a user without an email address, whom the notifier
+// cannot address, is answered like any other.
+func (suite *ForgotPasswordHandlerTestSuite) TestHandle_NoEmail() {
+	suite.Require().Empty(suite.user.Email())
+	suite.mockUserRepo.On("ByUsername", "normaluser").Return(suite.user, nil)
+	suite.mockHashSvc.On("Hash", mock.AnythingOfType("string")).Return("hashed_secret", nil)
+	suite.mockResetRepo.On("DeleteByUser", suite.user.ID()).Return(nil)
+	suite.mockResetRepo.On("Save", mock.AnythingOfType("*resettokenmodel.ResetToken")).Return(nil)
+	suite.mockNotifier.On("Notify", mock.MatchedBy(func(msg *inotifier.Message) bool { return msg.Email == "" })).
+		Return(errors.New("smtp notifier: no recipient domain configured"))
+
+	ok, err := suite.handler.Handle(forgotcmd.NewCommand("normaluser"))
+
+	suite.NoError(err)
+	suite.False(ok)
+	suite.mockNotifier.AssertExpectations(suite.T())
+}
+
+func TestForgotPasswordHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ForgotPasswordHandlerTestSuite))
+}
@@ -0,0 +1,15 @@
+package resetcmd
+
+// Command represents the data required to reset a password with a reset token.
+type Command struct {
+	Token       string // Opaque reset token sent to the user.
+	NewPassword string // Password replacing the forgotten one.
+}
+
+// NewCommand creates a new Command instance with the given reset token and new password.
+func NewCommand(token, newPassword string) *Command {
+	return &Command{
+		Token:       token,
+		NewPassword: newPassword,
+	}
+}
@@ -0,0 +1,125 @@
+// Package resetcmd provides the command and handler for finishing a password reset.
+//
+// The reset token is checked and used up, the new password is stored, and every existing
+// session of the user is ended, so whoever may have had access to the account loses it.
+package resetcmd
+
+import (
+	"fmt"
+	"time"
+
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	resettoken "github.com/beka-birhanu/task_manager_final/app/user/password/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+)
+
+// Handler handles password resets.
+type Handler struct {
+	userRepo    irepo.User         // Repository for user data operations.
+	resetRepo   irepo.ResetToken   // Repository for password reset tokens.
+	refreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	hashSvc     ihash.Service      // Service for password and reset token secret hashing.
+	accessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// Config holds the dependencies for creating a new Handler.
+type Config struct {
+	UserRepo    irepo.User         // Repository for user data operations.
+	ResetRepo   irepo.ResetToken   // Repository for password reset tokens.
+	RefreshRepo irepo.RefreshToken // Repository for refresh tokens.
+	Revocations irevocation.Store  // Store of revoked access tokens and sessions.
+	HashSvc     ihash.Service      // Service for password and reset token secret hashing.
+	AccessTTL   time.Duration      // Lifetime of access tokens.
+}
+
+// NewHandler creates a new Handler with the given configuration.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{
+		userRepo:    cfg.UserRepo,
+		resetRepo:   cfg.ResetRepo,
+		refreshRepo: cfg.RefreshRepo,
+		revocations: cfg.Revocations,
+		hashSvc:     cfg.HashSvc,
+		accessTTL:   cfg.AccessTTL,
+	}
+}
+
+// Handle validates the reset token, stores the new password and ends the existing
+// sessions of the user.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	token, err := h.verify(cmd.Token)
+	if err != nil {
+		return false, err
+	}
+
+	if token.IsUsed() {
+		return false, errdmn.ResetTokenUsed
+	}
+
+	if token.IsExpired(time.Now().UTC()) {
+		return false, errdmn.ResetTokenExpired
+	}
+
+	user, err := h.userRepo.ById(token.UserID())
+	if err != nil {
+		if err == errdmn.UserNotFound {
+			return false, errdmn.InvalidResetToken
+		}
+		return false, err
+	}
+
+	// Enforces the password strength policy before the token is used up, so a rejected
+	// password can be corrected with the same token.
+	if err := user.UpdatePassword(cmd.NewPassword, h.hashSvc); err != nil {
+		return false, err
+	}
+
+	// Another request may have used the token since it was read.
+	if err := h.resetRepo.MarkUsed(token.ID()); err != nil {
+		return false, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return false, err
+	}
+
+	if err := h.resetRepo.DeleteByUser(user.ID()); err != nil {
+		return false, err
+	}
+
+	if err := authtoken.RevokeUserSessions(h.refreshRepo, h.revocations, user.ID(), h.accessTTL); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// verify loads the reset token and checks its secret.
+func (h *Handler) verify(rawToken string) (*resettokenmodel.ResetToken, error) {
+	id, secret, err := resettoken.Parse(rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.resetRepo.ByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := token.MatchesSecret(secret, h.hashSvc)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(fmt.Sprintf("failed to validate password reset token, %v", err))
+	}
+	if !matches {
+		return nil, errdmn.InvalidResetToken
+	}
+	return token, nil
+}
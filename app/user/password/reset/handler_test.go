@@ -0,0 +1,151 @@
+package resetcmd_test
+
+import (
+	"testing"
+	"time"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	irevocation_mock "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+	resetcmd "github.com/beka-birhanu/task_manager_final/app/user/password/reset"
+	resettoken "github.com/beka-birhanu/task_manager_final/app/user/password/token"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const newPassword = "c0rrect-h0rse-b@ttery-st@ple"
+
+// ResetPasswordHandlerTestSuite defines the test suite for the reset password handler.
+type ResetPasswordHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo    *irepo_mock.User
+	mockResetRepo   *irepo_mock.ResetToken
+	mockRefreshRepo *irepo_mock.RefreshToken
+	mockRevocations *irevocation_mock.Store
+	mockHashSvc     *ihash_mocks.Service
+	handler         *resetcmd.Handler
+	user            *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *ResetPasswordHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.mockResetRepo = new(irepo_mock.ResetToken)
+	suite.mockRefreshRepo = new(irepo_mock.RefreshToken)
+	suite.mockRevocations = new(irevocation_mock.Store)
+	suite.mockHashSvc = new(ihash_mocks.Service)
+
+	suite.handler = resetcmd.NewHandler(resetcmd.Config{
+		UserRepo:    suite.mockUserRepo,
+		ResetRepo:   suite.mockResetRepo,
+		RefreshRepo: suite.mockRefreshRepo,
+		Revocations: suite.mockRevocations,
+		HashSvc:     suite.mockHashSvc,
+		AccessTTL:   15 * time.Minute,
+	})
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:           uuid.New(),
+		Username:     "normaluser",
+		PasswordHash: "old_hash",
+	})
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.mockHashSvc.On("Match", "hashed_secret", "guess").Return(false, nil)
+}
+
+// storedToken returns a token of the user as loaded from the repository.
+func (suite *ResetPasswordHandlerTestSuite) storedToken(expiresAt, usedAt time.Time) *resettokenmodel.ResetToken {
+	token := resettokenmodel.FromBSON(&resettokenmodel.ResetTokenBSON{
+		ID:         uuid.New(),
+		UserID:     suite.user.ID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  expiresAt,
+		UsedAt:     usedAt,
+	})
+	suite.mockResetRepo.On("ByID", token.ID()).Return(token, nil)
+	return token
+}
+
+// TestHandle_Success tests that the password is replaced and every session is ended.
+func (suite *ResetPasswordHandlerTestSuite) TestHandle_Success() {
+	token := suite.storedToken(time.Now().Add(time.Hour), time.Time{})
+	sessionID := uuid.New()
+	suite.mockHashSvc.On("Hash", newPassword).Return("new_hash", nil)
+	suite.mockResetRepo.On("MarkUsed", token.ID()).Return(nil)
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+	suite.mockResetRepo.On("DeleteByUser", suite.user.ID()).Return(nil)
+	suite.mockRefreshRepo.On("FamiliesByUser", suite.user.ID()).Return([]uuid.UUID{sessionID}, nil)
+	suite.mockRefreshRepo.On("RevokeFamily", sessionID).Return(nil)
+	suite.mockRevocations.On("Revoke", sessionID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	ok, err := suite.handler.Handle(resetcmd.NewCommand(resettoken.Format(token.ID(), "secret"), newPassword))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.Equal("new_hash", suite.user.PasswordHash())
+	suite.mockResetRepo.AssertExpectations(suite.T())
+	suite.mockRefreshRepo.AssertExpectations(suite.T())
+	suite.mockRevocations.AssertExpectations(suite.T())
+}
+
+// TestHandle_InvalidToken tests that malformed, unknown and mismatching tokens are rejected.
+func (suite *ResetPasswordHandlerTestSuite) TestHandle_InvalidToken() {
+	token := suite.storedToken(time.Now().Add(time.Hour), time.Time{})
+	unknownID := uuid.New()
+	suite.mockResetRepo.On("ByID", unknownID).Return(nil, errdmn.InvalidResetToken)
+
+	for _, rawToken := range []string{"malformed", resettoken.Format(unknownID, "secret"), resettoken.Format(token.ID(), "guess")} {
+		ok, err := suite.handler.Handle(resetcmd.NewCommand(rawToken, newPassword))
+		suite.Equal(errdmn.InvalidResetToken, err)
+		suite.False(ok)
+	}
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Expired tests that an expired token is rejected.
+func (suite *ResetPasswordHandlerTestSuite) TestHandle_Expired() {
+	token := suite.storedToken(time.Now().Add(-time.Minute), time.Time{})
+
+	ok, err := suite.handler.Handle(resetcmd.NewCommand(resettoken.Format(token.ID(), "secret"), newPassword))
+
+	suite.Equal(errdmn.ResetTokenExpired, err)
+	suite.False(ok)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_Used tests that a token cannot be used twice, even by concurrent requests.
+func (suite *ResetPasswordHandlerTestSuite) TestHandle_Used() {
+	used := suite.storedToken(time.Now().Add(time.Hour), time.Now())
+	ok, err := suite.handler.Handle(resetcmd.NewCommand(resettoken.Format(used.ID(), "secret"), newPassword))
+	suite.Equal(errdmn.ResetTokenUsed, err)
+	suite.False(ok)
+
+	raced := suite.storedToken(time.Now().Add(time.Hour), time.Time{})
+	suite.mockHashSvc.On("Hash", newPassword).Return("new_hash", nil)
+	suite.mockResetRepo.On("MarkUsed", raced.ID()).Return(errdmn.ResetTokenUsed)
+	ok, err = suite.handler.Handle(resetcmd.NewCommand(resettoken.Format(raced.ID(), "secret"), newPassword))
+	suite.Equal(errdmn.ResetTokenUsed, err)
+	suite.False(ok)
+
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// TestHandle_WeakPassword tests that a weak password is rejected without using up the token.
+func (suite *ResetPasswordHandlerTestSuite) TestHandle_WeakPassword() {
+	token := suite.storedToken(time.Now().Add(time.Hour), time.Time{})
+
+	ok, err := suite.handler.Handle(resetcmd.NewCommand(resettoken.Format(token.ID(), "secret"), "1234"))
+
+	suite.Equal(errdmn.WeakPassword, err)
+	suite.False(ok)
+	suite.mockResetRepo.AssertNotCalled(suite.T(), "MarkUsed", mock.Anything)
+}
+
+func TestResetPasswordHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ResetPasswordHandlerTestSuite))
+}
@@ -0,0 +1,31 @@
+// Package resettoken defines the format of the password reset tokens sent to users.
+//
+// A reset token has the form "<token id>.<secret>", like refresh tokens. Only a hash of
+// the secret is stored, so a leaked token store cannot be used to take over accounts.
+package resettoken
+
+import (
+	"strings"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/google/uuid"
+)
+
+// Format builds the opaque reset token sent to the user.
+func Format(id uuid.UUID, secret string) string {
+	return id.String() + "." + secret
+}
+
+// Parse splits an opaque reset token into its ID and secret.
+func Parse(token string) (uuid.UUID, string, error) {
+	rawID, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", errdmn.InvalidResetToken
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, "", errdmn.InvalidResetToken
+	}
+	return id, secret, nil
+}
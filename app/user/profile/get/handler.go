@@ -0,0 +1,27 @@
+// Package profileqry provides the query and handler returning the profile of the
+// signed in user, so clients need not decode their token to know who they are.
+package profileqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+// Handler handles profile queries.
+type Handler struct {
+	userRepo irepo.User // Repository for user data operations.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, *usermodel.User] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle returns the signed in user.
+func (h *Handler) Handle(qry *Query) (*usermodel.User, error) {
+	return h.userRepo.ById(qry.UserID)
+}
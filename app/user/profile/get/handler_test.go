@@ -0,0 +1,52 @@
+package profileqry_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	profileqry "github.com/beka-birhanu/task_manager_final/app/user/profile/get"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// ProfileQueryHandlerTestSuite defines the test suite for the profile query handler.
+type ProfileQueryHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *profileqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ProfileQueryHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = profileqry.New(suite.mockUserRepo)
+}
+
+// TestHandle_Success tests that the signed in user is returned.
+func (suite *ProfileQueryHandlerTestSuite) TestHandle_Success() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), Username: "user1", DisplayName: "User One"})
+	suite.mockUserRepo.On("ById", user.ID()).Return(user, nil)
+
+	result, err := suite.handler.Handle(profileqry.NewQuery(user.ID()))
+
+	suite.NoError(err)
+	suite.Equal(user, result)
+}
+
+// TestHandle_UserNotFound tests the scenario where the user no longer exists.
+func (suite *ProfileQueryHandlerTestSuite) TestHandle_UserNotFound() {
+	userID := uuid.New()
+	suite.mockUserRepo.On("ById", userID).Return(nil, errdmn.UserNotFound)
+
+	result, err := suite.handler.Handle(profileqry.NewQuery(userID))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UserNotFound, err)
+}
+
+// Run the test suite
+func TestProfileQueryHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileQueryHandlerTestSuite))
+}
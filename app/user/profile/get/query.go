@@ -0,0 +1,13 @@
+package profileqry
+
+import "github.com/google/uuid"
+
+// Query represents the data required to look up the profile of the signed in user.
+type Query struct {
+	UserID uuid.UUID // ID of the signed in user.
+}
+
+// NewQuery creates a new Query instance with the given user ID.
+func NewQuery(userID uuid.UUID) *Query {
+	return &Query{UserID: userID}
+}
@@ -0,0 +1,14 @@
+package profilecmd
+
+import "github.com/google/uuid"
+
+// Command represents a partial update of the profile of the signed in user.
+// Nil fields are left unchanged; empty strings remove the optional details.
+type Command struct {
+	UserID      uuid.UUID // ID of the signed in user.
+	Username    *string   // New username; cannot be removed.
+	DisplayName *string   // Name shown instead of the username.
+	TimeZone    *string   // IANA time zone name.
+	Locale      *string   // BCP 47 language tag.
+	AvatarURL   *string   // Absolute http(s) URL of the avatar image.
+}
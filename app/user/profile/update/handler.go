@@ -0,0 +1,73 @@
+// Package profilecmd provides the command and handler letting the signed in user
+// update their own profile: username, display name, time zone, locale, and avatar.
+//
+// A username already taken by another user is refused with errdmn.UsernameConflict,
+// as detected by the repository when the user is saved.
+package profilecmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+)
+
+// Handler handles profile update commands.
+type Handler struct {
+	userRepo irepo.User // Repository for user data operations.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *usermodel.User] = &Handler{}
+
+// New creates a new Handler with the given user repository.
+func New(userRepo irepo.User) *Handler {
+	return &Handler{userRepo: userRepo}
+}
+
+// Handle applies the given changes to the profile of the user and returns the
+// updated user. Nothing is saved if any change is invalid.
+func (h *Handler) Handle(cmd *Command) (*usermodel.User, error) {
+	user, err := h.userRepo.ById(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(user, cmd); err != nil {
+		return nil, err
+	}
+
+	if err := h.userRepo.Save(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// apply updates the fields of the user given in the command.
+func apply(user *usermodel.User, cmd *Command) error {
+	if cmd.Username != nil && *cmd.Username != user.Username() {
+		if err := user.UpdateUsername(*cmd.Username); err != nil {
+			return err
+		}
+	}
+	if cmd.DisplayName != nil {
+		if err := user.UpdateDisplayName(*cmd.DisplayName); err != nil {
+			return err
+		}
+	}
+	if cmd.TimeZone != nil {
+		if err := user.UpdateTimeZone(*cmd.TimeZone); err != nil {
+			return err
+		}
+	}
+	if cmd.Locale != nil {
+		if err := user.UpdateLocale(*cmd.Locale); err != nil {
+			return err
+		}
+	}
+	if cmd.AvatarURL != nil {
+		if err := user.UpdateAvatarURL(*cmd.AvatarURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package profilecmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	profilecmd "github.com/beka-birhanu/task_manager_final/app/user/profile/update"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// ProfileCommandHandlerTestSuite defines the test suite for the profile update handler.
+type ProfileCommandHandlerTestSuite struct {
+	suite.Suite
+	mockUserRepo *irepo_mock.User
+	handler      *profilecmd.Handler
+	user         *usermodel.User
+}
+
+// SetupTest sets up the test environment.
+func (suite *ProfileCommandHandlerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(irepo_mock.User)
+	suite.handler = profilecmd.New(suite.mockUserRepo)
+
+	suite.user = usermodel.FromBSON(&usermodel.UserBSON{
+		ID:          uuid.New(),
+		Username:    "user1",
+		DisplayName: "User One",
+		TimeZone:    "UTC",
+	})
+	suite.mockUserRepo.On("ById", suite.user.ID()).Return(suite.user, nil)
+}
+
+// text returns a pointer to the given string.
+func text(s string) *string {
+	return &s
+}
+
+// TestHandle_Success tests that only the given fields are changed and the user saved.
+func (suite *ProfileCommandHandlerTestSuite) TestHandle_Success() {
+	suite.mockUserRepo.On("Save", suite.user).Return(nil)
+
+	result, err := suite.handler.Handle(&profilecmd.Command{
+		UserID:   suite.user.ID(),
+		Username: text("user_one"),
+		Locale:   text("am-ET"),
+		TimeZone: text(""),
+	})
+
+	suite.NoError(err)
+	suite.Equal("user_one", result.Username())
+	suite.Equal("am-ET", result.Locale())
+	suite.Empty(result.TimeZone())
+	suite.Equal("User One", result.DisplayName())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_UsernameConflict tests that a username taken by another user is refused.
+func (suite *ProfileCommandHandlerTestSuite) TestHandle_UsernameConflict() {
+	suite.mockUserRepo.On("Save", suite.user).Return(errdmn.UsernameConflict)
+
+	result, err := suite.handler.Handle(&profilecmd.Command{UserID: suite.user.ID(), Username: text("taken")})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.UsernameConflict, err)
+}
+
+// TestHandle_Invalid tests that nothing is saved when a change is invalid.
+func (suite *ProfileCommandHandlerTestSuite) TestHandle_Invalid() {
+	result, err := suite.handler.Handle(&profilecmd.Command{
+		UserID:    suite.user.ID(),
+		Locale:    text("en-US"),
+		AvatarURL: text("ftp://example.com/avatar.png"),
+	})
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidAvatarURL, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestProfileCommandHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileCommandHandlerTestSuite))
+}
@@ -0,0 +1,12 @@
+package defineworkflowcmd
+
+import workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+
+// Command represents the data required to define the workflow of a project.
+type Command struct {
+	Project     string                     // Project the workflow is for.
+	States      []string                   // States the tasks of the project can have.
+	Initial     string                     // State new tasks start in; the first state when empty.
+	Final       []string                   // States tasks are finished in; done, if the workflow has it, when empty.
+	Transitions []workflowmodel.Transition // Moves allowed between the states.
+}
@@ -0,0 +1,44 @@
+// Package defineworkflowcmd provides the command and handler letting admins define
+// the workflow of a project, replacing the one it had.
+//
+// Tasks of the project left in a state the new workflow does not have keep it until
+// they are moved to the initial state.
+package defineworkflowcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+)
+
+// Handler handles workflow definition commands.
+type Handler struct {
+	repo irepo.Workflow // Repository for the workflows of projects.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, *workflowmodel.Workflow] = &Handler{}
+
+// New creates a new Handler with the given workflow repository.
+func New(repo irepo.Workflow) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle validates and saves the workflow of the command.
+func (h *Handler) Handle(cmd *Command) (*workflowmodel.Workflow, error) {
+	workflow, err := workflowmodel.New(workflowmodel.Config{
+		Project:     cmd.Project,
+		States:      cmd.States,
+		Initial:     cmd.Initial,
+		Final:       cmd.Final,
+		Transitions: cmd.Transitions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(workflow); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
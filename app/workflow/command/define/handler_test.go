@@ -0,0 +1,59 @@
+package defineworkflowcmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	defineworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/define"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// DefineWorkflowHandlerTestSuite defines the test suite for the define workflow handler.
+type DefineWorkflowHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Workflow
+	handler  *defineworkflowcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *DefineWorkflowHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Workflow)
+	suite.handler = defineworkflowcmd.New(suite.mockRepo)
+}
+
+// TestHandle_Success tests that a valid workflow is saved.
+func (suite *DefineWorkflowHandlerTestSuite) TestHandle_Success() {
+	suite.mockRepo.On("Save", mock.AnythingOfType("*workflowmodel.Workflow")).Return(nil)
+
+	workflow, err := suite.handler.Handle(&defineworkflowcmd.Command{
+		Project:     "website",
+		States:      []string{"todo", "shipped"},
+		Transitions: []workflowmodel.Transition{{From: "todo", To: "shipped"}},
+	})
+
+	suite.NoError(err)
+	suite.Equal("website", workflow.Project())
+	suite.Equal("todo", workflow.Initial())
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_Invalid tests that invalid workflows are not saved.
+func (suite *DefineWorkflowHandlerTestSuite) TestHandle_Invalid() {
+	workflow, err := suite.handler.Handle(&defineworkflowcmd.Command{
+		Project:     "website",
+		States:      []string{"todo", "shipped"},
+		Transitions: []workflowmodel.Transition{{From: "todo", To: "archived"}},
+	})
+
+	suite.Nil(workflow)
+	suite.Equal(errdmn.InvalidWorkflowTransition, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Run the test suite
+func TestDefineWorkflowHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DefineWorkflowHandlerTestSuite))
+}
@@ -0,0 +1,11 @@
+package deleteworkflowcmd
+
+// Command represents the data required to delete the workflow of a project.
+type Command struct {
+	Project string // Project whose workflow is deleted.
+}
+
+// NewCommand creates a new Command instance with the given project.
+func NewCommand(project string) *Command {
+	return &Command{Project: project}
+}
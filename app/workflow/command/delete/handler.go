@@ -0,0 +1,29 @@
+// Package deleteworkflowcmd provides the command and handler letting admins delete
+// the workflow of a project, whose tasks then follow the default workflow.
+package deleteworkflowcmd
+
+import (
+	icmd "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+)
+
+// Handler handles workflow deletion commands.
+type Handler struct {
+	repo irepo.Workflow // Repository for the workflows of projects.
+}
+
+// Ensure Handler implements icmd.IHandler
+var _ icmd.IHandler[*Command, bool] = &Handler{}
+
+// New creates a new Handler with the given workflow repository.
+func New(repo irepo.Workflow) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle deletes the workflow of the project of the command.
+func (h *Handler) Handle(cmd *Command) (bool, error) {
+	if err := h.repo.Delete(cmd.Project); err != nil {
+		return false, err
+	}
+	return true, nil
+}
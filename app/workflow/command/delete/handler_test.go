@@ -0,0 +1,49 @@
+package deleteworkflowcmd_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	deleteworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/delete"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+// DeleteWorkflowHandlerTestSuite defines the test suite for the delete workflow handler.
+type DeleteWorkflowHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Workflow
+	handler  *deleteworkflowcmd.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *DeleteWorkflowHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Workflow)
+	suite.handler = deleteworkflowcmd.New(suite.mockRepo)
+}
+
+// TestHandle_Success tests that the workflow of the project is deleted.
+func (suite *DeleteWorkflowHandlerTestSuite) TestHandle_Success() {
+	suite.mockRepo.On("Delete", "website").Return(nil)
+
+	ok, err := suite.handler.Handle(deleteworkflowcmd.NewCommand("website"))
+
+	suite.NoError(err)
+	suite.True(ok)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// TestHandle_NotFound tests that projects without a workflow are reported to the admin.
+func (suite *DeleteWorkflowHandlerTestSuite) TestHandle_NotFound() {
+	suite.mockRepo.On("Delete", "mobile").Return(errdmn.WorkflowNotFound)
+
+	ok, err := suite.handler.Handle(deleteworkflowcmd.NewCommand("mobile"))
+
+	suite.False(ok)
+	suite.Equal(errdmn.WorkflowNotFound, err)
+}
+
+// Run the test suite
+func TestDeleteWorkflowHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteWorkflowHandlerTestSuite))
+}
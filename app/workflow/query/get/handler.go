@@ -0,0 +1,30 @@
+// Package getworkflowqry provides the query and handler returning the workflow the
+// tasks of a project follow: its own, or the default workflow when it has none.
+package getworkflowqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+)
+
+// Handler handles workflow lookup queries.
+type Handler struct {
+	repo irepo.Workflow // Repository for the workflows of projects.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, *workflowmodel.Workflow] = &Handler{}
+
+// New creates a new Handler with the given workflow repository.
+func New(repo irepo.Workflow) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle returns the workflow of the project of the query.
+func (h *Handler) Handle(qry *Query) (*workflowmodel.Workflow, error) {
+	if err := workflowmodel.ValidateProject(qry.Project); err != nil {
+		return nil, err
+	}
+	return h.repo.ForProject(qry.Project)
+}
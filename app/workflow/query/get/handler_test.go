@@ -0,0 +1,50 @@
+package getworkflowqry_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	getworkflowqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/get"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// GetWorkflowHandlerTestSuite defines the test suite for the get workflow handler.
+type GetWorkflowHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Workflow
+	handler  *getworkflowqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *GetWorkflowHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Workflow)
+	suite.handler = getworkflowqry.New(suite.mockRepo)
+}
+
+// TestHandle_Success tests that the workflow of the project is returned.
+func (suite *GetWorkflowHandlerTestSuite) TestHandle_Success() {
+	workflow := workflowmodel.Default()
+	suite.mockRepo.On("ForProject", "website").Return(workflow, nil)
+
+	result, err := suite.handler.Handle(getworkflowqry.NewQuery("website"))
+
+	suite.NoError(err)
+	suite.Equal(workflow, result)
+}
+
+// TestHandle_InvalidProject tests that invalid project names are refused.
+func (suite *GetWorkflowHandlerTestSuite) TestHandle_InvalidProject() {
+	result, err := suite.handler.Handle(getworkflowqry.NewQuery("Web Site"))
+
+	suite.Nil(result)
+	suite.Equal(errdmn.InvalidProject, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "ForProject", mock.Anything)
+}
+
+// Run the test suite
+func TestGetWorkflowHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(GetWorkflowHandlerTestSuite))
+}
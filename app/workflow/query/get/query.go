@@ -0,0 +1,11 @@
+package getworkflowqry
+
+// Query represents the data required to look up the workflow of a project.
+type Query struct {
+	Project string // Project whose workflow is looked up; none when empty.
+}
+
+// NewQuery creates a new Query instance with the given project.
+func NewQuery(project string) *Query {
+	return &Query{Project: project}
+}
@@ -0,0 +1,27 @@
+// Package listworkflowsqry provides the query and handler listing the workflows
+// defined for projects.
+package listworkflowsqry
+
+import (
+	iquery "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+)
+
+// Handler handles workflow listing queries.
+type Handler struct {
+	repo irepo.Workflow // Repository for the workflows of projects.
+}
+
+// Ensure Handler implements iquery.IHandler
+var _ iquery.IHandler[*Query, []*workflowmodel.Workflow] = &Handler{}
+
+// New creates a new Handler with the given workflow repository.
+func New(repo irepo.Workflow) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Handle returns the workflows defined for projects, sorted by project.
+func (h *Handler) Handle(qry *Query) ([]*workflowmodel.Workflow, error) {
+	return h.repo.List()
+}
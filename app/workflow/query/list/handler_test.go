@@ -0,0 +1,40 @@
+package listworkflowsqry_test
+
+import (
+	"testing"
+
+	irepo_mock "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+	listworkflowsqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/list"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/stretchr/testify/suite"
+)
+
+// ListWorkflowsHandlerTestSuite defines the test suite for the list workflows handler.
+type ListWorkflowsHandlerTestSuite struct {
+	suite.Suite
+	mockRepo *irepo_mock.Workflow
+	handler  *listworkflowsqry.Handler
+}
+
+// SetupTest sets up the test environment.
+func (suite *ListWorkflowsHandlerTestSuite) SetupTest() {
+	suite.mockRepo = new(irepo_mock.Workflow)
+	suite.handler = listworkflowsqry.New(suite.mockRepo)
+}
+
+// TestHandle tests that the workflows defined for projects are returned.
+func (suite *ListWorkflowsHandlerTestSuite) TestHandle() {
+	workflow, err := workflowmodel.New(workflowmodel.Config{Project: "website", States: []string{"todo"}})
+	suite.Require().NoError(err)
+	suite.mockRepo.On("List").Return([]*workflowmodel.Workflow{workflow}, nil)
+
+	result, err := suite.handler.Handle(&listworkflowsqry.Query{})
+
+	suite.NoError(err)
+	suite.Equal([]*workflowmodel.Workflow{workflow}, result)
+}
+
+// Run the test suite
+func TestListWorkflowsHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ListWorkflowsHandlerTestSuite))
+}
@@ -0,0 +1,4 @@
+package listworkflowsqry
+
+// Query represents the request to list the workflows defined for projects.
+type Query struct{}
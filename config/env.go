@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,12 +14,44 @@ import (
 
 // Config holds the application configuration values.
 type Config struct {
-	ServerHost             string        // Hostname or IP for the server.
-	ServerPort             string        // Port number for the server.
-	DBName                 string        // Name of the database.
-	DBConnectionString     string        // Connection string for the database.
-	JWTSecret              string        // Secret key for JWT signing.
-	JWTExpirationInSeconds time.Duration // JWT expiration time.
+	ServerHost                           string        // Hostname or IP for the server.
+	ServerPort                           string        // Port number for the server.
+	DBName                               string        // Name of the database.
+	DBConnectionString                   string        // Connection string for the database.
+	JWTSecret                            string        // Secret key for JWT signing, used when no key manifest is set.
+	JWTKeysFile                          string        // Manifest of the asymmetric JWT signing keys.
+	JWTAudience                          string        // Audience of the JWTs; tokens for other audiences are rejected.
+	JWTKeyGracePeriodInSeconds           time.Duration // How long a replaced signing key keeps verifying tokens.
+	JWTExpirationInSeconds               time.Duration // JWT expiration time.
+	RefreshTokenExpirationInSeconds      time.Duration // Refresh token expiration time.
+	AuthCookieEnabled                    bool          // Accept access tokens from the accessToken cookie.
+	PasswordResetExpirationInSeconds     time.Duration // Password reset token expiration time.
+	PasswordResetURL                     string        // Page users reset their password on; the token is sent as is when empty.
+	Notifier                             string        // How messages reach users: "smtp", or "log" to write them out.
+	NotifierLogFile                      string        // File the log notifier appends to; the standard output when empty.
+	SMTPHost                             string        // Host of the SMTP server.
+	SMTPPort                             string        // Port of the SMTP server.
+	SMTPUsername                         string        // User to authenticate to the SMTP server as.
+	SMTPPassword                         string        // Password of the SMTP user.
+	SMTPFrom                             string        // Sender address of the emails.
+	SMTPRecipientDomain                  string        // Mail domain of users without an email address, addressed as <username>@<domain>.
+	EmailVerificationExpirationInSeconds time.Duration // Email verification token expiration time.
+	EmailVerificationURL                 string        // Page users verify their email on; the token is sent as is when empty.
+	RequireVerifiedEmail                 bool          // Refuse to sign in users whose email is not verified.
+	MFAIssuer                            string        // Name authenticator apps show the accounts under.
+	MFAChallengeExpirationInSeconds      time.Duration // How long a sign in waits for the second factor.
+	LoginUserMaxFailures                 int           // Failed sign ins allowed per username before it is locked; 0 never locks.
+	LoginIPMaxFailures                   int           // Failed sign ins allowed per client address before it is locked; 0 never locks.
+	LoginFailureWindowInSeconds          time.Duration // How long failed sign ins are remembered after the last one.
+	LoginLockoutInSeconds                time.Duration // First lockout, doubled by each further failure.
+	LoginMaxLockoutInSeconds             time.Duration // Longest lockout.
+	TrustedProxies                       []string      // Reverse proxies whose X-Forwarded-For header gives the client address.
+	OIDCIssuer                           string        // Issuer URL of the OpenID Connect provider; single sign-on is off when empty.
+	OIDCClientID                         string        // ID of the client registered at the provider.
+	OIDCClientSecret                     string        // Secret of the client; empty for public clients.
+	OIDCRedirectURL                      string        // Callback URL registered at the provider, ending in /api/auth/oidc/callback.
+	OIDCScopes                           []string      // Scopes requested from the provider besides openid.
+	OIDCLoginExpirationInSeconds         time.Duration // How long a single sign-on may take at the provider.
 }
 
 // Envs holds the loaded configuration values.
@@ -31,13 +64,50 @@ func initConfig() Config {
 		log.Panicln("Error loading .env file:", err)
 	}
 
+	oidcScopes := getListEnv("OIDC_SCOPES")
+	if oidcScopes == nil {
+		oidcScopes = []string{"email", "profile"}
+	}
+
 	return Config{
-		ServerHost:             getEnv("PUBLIC_HOST", "http://localhost"),
-		ServerPort:             getEnv("PORT", "8080"),
-		DBConnectionString:     getEnv("DB_CONNECTION_STRING", ""),
-		DBName:                 getEnv("DB_NAME", "taskdb"),
-		JWTSecret:              getEnv("JWT_SECRET", "not-so-secret-now-is-it?"),
-		JWTExpirationInSeconds: time.Duration(getTimeEnv("JWT_EXPIRATION_IN_SECONDS", 60*24)) * time.Second,
+		ServerHost:                           getEnv("PUBLIC_HOST", "http://localhost"),
+		ServerPort:                           getEnv("PORT", "8080"),
+		DBConnectionString:                   getEnv("DB_CONNECTION_STRING", ""),
+		DBName:                               getEnv("DB_NAME", "taskdb"),
+		JWTSecret:                            getEnv("JWT_SECRET", "not-so-secret-now-is-it?"),
+		JWTKeysFile:                          getEnv("JWT_KEYS_FILE", ""),
+		JWTAudience:                          getEnv("JWT_AUDIENCE", "task_manager"),
+		JWTKeyGracePeriodInSeconds:           time.Duration(getTimeEnv("JWT_KEY_GRACE_PERIOD_IN_SECONDS", 60*60)) * time.Second,
+		JWTExpirationInSeconds:               time.Duration(getTimeEnv("JWT_EXPIRATION_IN_SECONDS", 60*15)) * time.Second,
+		RefreshTokenExpirationInSeconds:      time.Duration(getTimeEnv("REFRESH_TOKEN_EXPIRATION_IN_SECONDS", 60*60*24*30)) * time.Second,
+		AuthCookieEnabled:                    getBoolEnv("AUTH_COOKIE_ENABLED", true),
+		PasswordResetExpirationInSeconds:     time.Duration(getTimeEnv("PASSWORD_RESET_EXPIRATION_IN_SECONDS", 60*60)) * time.Second,
+		PasswordResetURL:                     getEnv("PASSWORD_RESET_URL", ""),
+		Notifier:                             getEnv("NOTIFIER", ""),
+		NotifierLogFile:                      getEnv("NOTIFIER_LOG_FILE", ""),
+		SMTPHost:                             getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                             getEnv("SMTP_PORT", "587"),
+		SMTPUsername:                         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                         getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                             getEnv("SMTP_FROM", ""),
+		SMTPRecipientDomain:                  getEnv("SMTP_RECIPIENT_DOMAIN", ""),
+		EmailVerificationExpirationInSeconds: time.Duration(getTimeEnv("EMAIL_VERIFICATION_EXPIRATION_IN_SECONDS", 60*60*24)) * time.Second,
+		EmailVerificationURL:                 getEnv("EMAIL_VERIFICATION_URL", ""),
+		RequireVerifiedEmail:                 getBoolEnv("REQUIRE_VERIFIED_EMAIL", false),
+		MFAIssuer:                            getEnv("MFA_ISSUER", "Task Manager"),
+		MFAChallengeExpirationInSeconds:      time.Duration(getTimeEnv("MFA_CHALLENGE_EXPIRATION_IN_SECONDS", 60*5)) * time.Second,
+		LoginUserMaxFailures:                 getIntEnv("LOGIN_USER_MAX_FAILURES", 5),
+		LoginIPMaxFailures:                   getIntEnv("LOGIN_IP_MAX_FAILURES", 20),
+		LoginFailureWindowInSeconds:          time.Duration(getTimeEnv("LOGIN_FAILURE_WINDOW_IN_SECONDS", 60*15)) * time.Second,
+		LoginLockoutInSeconds:                time.Duration(getTimeEnv("LOGIN_LOCKOUT_IN_SECONDS", 60)) * time.Second,
+		LoginMaxLockoutInSeconds:             time.Duration(getTimeEnv("LOGIN_MAX_LOCKOUT_IN_SECONDS", 60*60)) * time.Second,
+		TrustedProxies:                       getListEnv("TRUSTED_PROXIES"),
+		OIDCIssuer:                           getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:                         getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:                     getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:                      getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:                           oidcScopes,
+		OIDCLoginExpirationInSeconds:         time.Duration(getTimeEnv("OIDC_LOGIN_EXPIRATION_IN_SECONDS", 60*10)) * time.Second,
 	}
 }
 
@@ -63,3 +133,43 @@ func getTimeEnv(key string, fallback int64) int64 {
 	}
 	return fallback
 }
+
+// getIntEnv retrieves the value of an environment variable as an integer.
+// It falls back to a default value if the variable is not set or if there's
+// an error in parsing.
+func getIntEnv(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fallback
+		}
+		return i
+	}
+	return fallback
+}
+
+// getListEnv retrieves the comma-separated values of an environment variable,
+// or nil if the variable is not set or empty.
+func getListEnv(key string) []string {
+	var list []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// getBoolEnv retrieves the value of an environment variable as a boolean.
+// It falls back to a default value if the variable is not set or if there's
+// an error in parsing.
+func getBoolEnv(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fallback
+		}
+		return b
+	}
+	return fallback
+}
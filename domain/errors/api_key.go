@@ -0,0 +1,43 @@
+package errdmn
+
+// Validation errors
+var (
+	// API key name is empty.
+	APIKeyNameEmpty = NewValidation("api key name cannot be empty.")
+
+	// API key name is longer than allowed.
+	APIKeyNameTooLong = NewValidation("api key name is too long.")
+
+	// API key was given no scope.
+	APIKeyScopesEmpty = NewValidation("api key must have at least one scope.")
+
+	// API key expiry is not in the future.
+	APIKeyExpiryInPast = NewValidation("api key expiry must be in the future.")
+
+	// API key must be issued to a user.
+	APIKeyUserEmpty = NewValidation("api key user cannot be empty.")
+
+	// API key must have a secret.
+	APIKeySecretEmpty = NewValidation("api key secret cannot be empty.")
+)
+
+// Unauthorized errors
+var (
+	// API key is malformed, unknown, or does not match.
+	InvalidAPIKey = NewUnauthorized("invalid api key.")
+
+	// API key expired and can no longer be used.
+	APIKeyExpired = NewUnauthorized("api key expired.")
+)
+
+// Forbidden errors
+var (
+	// API key scope is not granted by the role of its owner.
+	APIKeyScopeNotGranted = NewForbidden("api key scope is not granted by your role.")
+)
+
+// NotFound errors
+var (
+	// API key does not exist or belongs to someone else.
+	APIKeyNotFound = NewNotFound("api key not found.")
+)
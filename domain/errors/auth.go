@@ -0,0 +1,25 @@
+package errdmn
+
+// Validation errors
+var (
+	// RefreshTokenUserEmpty indicates that a refresh token must be issued to a user.
+	RefreshTokenUserEmpty = NewValidation("refresh token user cannot be empty")
+
+	// RefreshTokenSecretEmpty indicates that a refresh token must have a secret.
+	RefreshTokenSecretEmpty = NewValidation("refresh token secret cannot be empty")
+)
+
+// Unauthorized errors
+var (
+	// InvalidCredentials indicates that the username or password is wrong, without telling which.
+	InvalidCredentials = NewUnauthorized("invalid credentials")
+
+	// InvalidRefreshToken indicates that the refresh token is malformed, unknown, or does not match.
+	InvalidRefreshToken = NewUnauthorized("invalid refresh token")
+
+	// RefreshTokenExpired indicates that the refresh token can no longer be used.
+	RefreshTokenExpired = NewUnauthorized("refresh token expired")
+
+	// RefreshTokenReused indicates that an already exchanged refresh token was presented again.
+	RefreshTokenReused = NewUnauthorized("refresh token reuse detected")
+)
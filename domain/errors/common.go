@@ -1,6 +1,6 @@
 // Package errdmn provides a mechanism for creating and handling custom domain errors.
 // It defines a set of predefined error types such as Validation, Conflict, Unexpected,
-// NotFound, and Unauthorized, allowing for consistent error categorization and handling across the application.
+// NotFound, Unauthorized, Forbidden, and TooManyRequests, allowing for consistent error categorization and handling across the application.
 //
 // Each error type is represented by a string constant, and the package includes functions to create
 // errors of these types with specific messages. The custom `Error` type implements the `IErr` interface,
@@ -31,6 +31,12 @@ const (
 
 	// Unauthorized represents an error for unauthorized access.
 	Unauthorized = "Unauthorized"
+
+	// Forbidden represents an error for an authenticated but disallowed action.
+	Forbidden = "Forbidden"
+
+	// TooManyRequests represents an error for an action refused until the caller slows down.
+	TooManyRequests = "TooManyRequests"
 )
 
 // Error represents a custom domain error with a type and message.
@@ -82,3 +88,13 @@ func NewNotFound(message string) *Error {
 func NewUnauthorized(message string) *Error {
 	return new(Unauthorized, message)
 }
+
+// NewForbidden creates a new forbidden error with the given message.
+func NewForbidden(message string) *Error {
+	return new(Forbidden, message)
+}
+
+// NewTooManyRequests creates a new too many requests error with the given message.
+func NewTooManyRequests(message string) *Error {
+	return new(TooManyRequests, message)
+}
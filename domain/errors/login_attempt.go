@@ -0,0 +1,13 @@
+package errdmn
+
+// NotFound errors
+var (
+	// LoginAttemptNotFound indicates that no failed login attempt is recorded under the key.
+	LoginAttemptNotFound = NewNotFound("login attempt not found")
+)
+
+// TooManyRequests errors
+var (
+	// LoginLocked indicates that signing in is refused for a while after too many failed attempts.
+	LoginLocked = NewTooManyRequests("too many failed sign in attempts, try again later.")
+)
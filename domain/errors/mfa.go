@@ -0,0 +1,25 @@
+package errdmn
+
+// Validation errors
+var (
+	// TOTPSecretEmpty indicates that a TOTP enrollment must have a secret.
+	TOTPSecretEmpty = NewValidation("totp secret cannot be empty")
+
+	// MFAAlreadyEnabled indicates that the user already has two-factor authentication enabled.
+	MFAAlreadyEnabled = NewValidation("two-factor authentication is already enabled")
+
+	// MFANotEnabled indicates that the user does not have two-factor authentication enabled.
+	MFANotEnabled = NewValidation("two-factor authentication is not enabled")
+
+	// MFAEnrollmentNotStarted indicates that no TOTP enrollment is waiting for confirmation.
+	MFAEnrollmentNotStarted = NewValidation("no two-factor enrollment to confirm")
+
+	// InvalidMFACode indicates that the one-time or recovery code is wrong, expired, or was already used.
+	InvalidMFACode = NewValidation("invalid verification code")
+)
+
+// Unauthorized errors
+var (
+	// InvalidMFAChallenge indicates that the MFA challenge token is malformed, expired, or does not match.
+	InvalidMFAChallenge = NewUnauthorized("invalid mfa challenge")
+)
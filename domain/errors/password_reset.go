@@ -0,0 +1,19 @@
+package errdmn
+
+// Validation errors
+var (
+	// ResetTokenUserEmpty indicates that a password reset token must be issued to a user.
+	ResetTokenUserEmpty = NewValidation("password reset token user cannot be empty")
+
+	// ResetTokenSecretEmpty indicates that a password reset token must have a secret.
+	ResetTokenSecretEmpty = NewValidation("password reset token secret cannot be empty")
+
+	// InvalidResetToken indicates that the password reset token is malformed, unknown, or does not match.
+	InvalidResetToken = NewValidation("invalid password reset token")
+
+	// ResetTokenExpired indicates that the password reset token can no longer be used.
+	ResetTokenExpired = NewValidation("password reset token expired")
+
+	// ResetTokenUsed indicates that the password reset token was already used.
+	ResetTokenUsed = NewValidation("password reset token already used")
+)
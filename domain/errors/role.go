@@ -0,0 +1,16 @@
+package errdmn
+
+// Validation errors
+var (
+	// Role is not one of the defined roles.
+	InvalidRole = NewValidation("invalid role.")
+
+	// Permission is not one of the defined permissions.
+	InvalidPermission = NewValidation("invalid permission.")
+)
+
+// Forbidden errors
+var (
+	// User's role does not grant the permission needed for the action.
+	PermissionDenied = NewForbidden("permission denied.")
+)
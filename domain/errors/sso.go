@@ -0,0 +1,22 @@
+package errdmn
+
+// Validation errors
+var (
+	// External identity is missing its issuer or subject.
+	ExternalIdentityEmpty = NewValidation("external identity issuer and subject cannot be empty.")
+)
+
+// Conflict errors
+var (
+	// External identity is already linked to another user.
+	ExternalIdentityConflict = NewConflict("external identity is already linked to another user.")
+)
+
+// Unauthorized errors
+var (
+	// Single sign-on state is missing, expired, or does not match the one the sign in started with.
+	InvalidSSOState = NewUnauthorized("invalid single sign-on state.")
+
+	// Identity provider refused the sign in, or its ID token did not check out.
+	SSOLoginFailed = NewUnauthorized("single sign-on failed.")
+)
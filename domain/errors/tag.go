@@ -0,0 +1,28 @@
+package errdmn
+
+// Validation errors
+var (
+	// Tag is empty, too long, or uses characters other than letters, digits, dots, dashes and underscores.
+	InvalidTag = NewValidation("tags must be 1-30 letters, digits, dots, dashes or underscores.")
+
+	// Task has more tags than allowed.
+	TooManyTags = NewValidation("task has too many tags.")
+
+	// Tag filter is neither matching any nor all of the tags.
+	InvalidTagMatch = NewValidation("tag match must be any or all.")
+
+	// Tag is renamed or merged into itself.
+	TagUnchanged = NewValidation("tag cannot be renamed or merged into itself.")
+)
+
+// NotFound errors
+var (
+	// No task has the tag.
+	TagNotFound = NewNotFound("tag not found.")
+)
+
+// Conflict errors
+var (
+	// Tasks already have the tag a tag is renamed to.
+	TagInUse = NewConflict("tag is already in use; merge into it instead.")
+)
@@ -11,10 +11,63 @@ var (
 	// DueDateZero indicates that the due date cannot be zero.
 	DueDateZero = NewValidation("due date cannot be zero")
 
+	// OwnerEmpty indicates that a task must belong to a user.
+	OwnerEmpty = NewValidation("owner cannot be empty")
+
 	// InvalidStatus indicates that the status is invalid.
 	InvalidStatus = NewValidation("invalid status")
 
+	// InvalidPriority indicates that the priority is not one of urgent, high, medium, or low.
+	InvalidPriority = NewValidation("invalid priority")
+
+	// InvalidSortField indicates that tasks cannot be sorted by the requested field.
+	InvalidSortField = NewValidation("invalid sort field")
+
+	// InvalidSortOrder indicates that the sort order is neither ascending nor descending.
+	InvalidSortOrder = NewValidation("invalid sort order")
+
+	// InvalidPageLimit indicates that the requested page size is out of range.
+	InvalidPageLimit = NewValidation("invalid page limit")
+
+	// InvalidDueDateRange indicates that the due date range ends before it starts.
+	InvalidDueDateRange = NewValidation("invalid due date range")
+
+	// SearchQueryEmpty indicates that a search was requested without any terms.
+	SearchQueryEmpty = NewValidation("search query cannot be empty")
+
+	// InvalidCursor indicates that the pagination cursor is malformed.
+	InvalidCursor = NewValidation("invalid cursor")
+
+	// InvalidAssignee indicates that a task can only be assigned to an existing, active user.
+	InvalidAssignee = NewValidation("tasks can only be assigned to active users")
+
+	// TooManyAssignees indicates that a task is assigned to as many users as allowed.
+	TooManyAssignees = NewValidation("task has too many assignees")
+
+	// InvalidParent indicates that a task cannot be its own parent.
+	InvalidParent = NewValidation("a task cannot be its own parent")
+
+	// ParentNotFound indicates that the parent of a task does not exist or cannot be seen.
+	ParentNotFound = NewValidation("parent task not found")
+
+	// TooManySubtasks indicates that a task has as many subtasks as allowed.
+	TooManySubtasks = NewValidation("parent task has too many subtasks")
+
 	// TaskNotFound indicates that a task was not found.
 	TaskNotFound = NewValidation("task not found")
 )
 
+// Conflict errors
+var (
+	// TransitionNotAllowed indicates that the workflow of a task does not allow moving it from its status to the requested one.
+	TransitionNotAllowed = NewConflict("task cannot move from its current status to the requested one")
+
+	// ParentCycle indicates that a task cannot be moved under itself or one of its subtasks.
+	ParentCycle = NewConflict("a task cannot be moved under one of its subtasks")
+
+	// SubtasksOpen indicates that a task cannot be done while some of its subtasks are not.
+	SubtasksOpen = NewConflict("task cannot be done while it has open subtasks")
+
+	// ParentDone indicates that an open task cannot be placed under a task that is done.
+	ParentDone = NewConflict("an open task cannot be placed under a task that is done")
+)
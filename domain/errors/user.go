@@ -13,12 +13,84 @@ var (
 
 	// Username is not UUID.
 	UsernameInvalidFormat = NewValidation("username has an invalid format.")
+
+	// Current password given to change the password is wrong.
+	IncorrectCurrentPassword = NewValidation("current password is incorrect.")
+
+	// New password is the same as the current one.
+	PasswordUnchanged = NewValidation("new password must differ from the current password.")
+
+	// Email address is missing.
+	EmailRequired = NewValidation("email is required.")
+
+	// Email address is malformed.
+	EmailInvalidFormat = NewValidation("email has an invalid format.")
+
+	// Email address was already verified.
+	EmailAlreadyVerified = NewValidation("email is already verified.")
+
+	// Email verification token is malformed, unknown, or does not match.
+	InvalidVerificationToken = NewValidation("invalid email verification token.")
+
+	// Email verification token can no longer be used.
+	VerificationTokenExpired = NewValidation("email verification token expired.")
+
+	// Display name is longer than allowed.
+	DisplayNameTooLong = NewValidation("display name is too long.")
+
+	// Time zone is not a known IANA time zone name.
+	InvalidTimeZone = NewValidation("time zone is invalid.")
+
+	// Locale is not a well-formed BCP 47 language tag.
+	InvalidLocale = NewValidation("locale is invalid.")
+
+	// Avatar URL is not an absolute http or https URL.
+	InvalidAvatarURL = NewValidation("avatar url is invalid.")
+
+	// What to do with the tasks of a deleted user is not one of the known options.
+	InvalidTaskDisposition = NewValidation("tasks must be either reassigned or deleted.")
+
+	// Tasks of a deleted user can only be reassigned to another active user.
+	InvalidReassignTarget = NewValidation("tasks can only be reassigned to another active user.")
+
+	// User signs in with single sign-on and has no password to check.
+	PasswordNotSet = NewValidation("user has no password; sign in with single sign-on.")
 )
 
 // Conflict errors
 var (
 	// User with a similar username exists.
 	UsernameConflict = NewConflict("username already taken.")
+
+	// User with the same email exists.
+	EmailConflict = NewConflict("email already taken.")
+
+	// Demoting the user would leave no admin.
+	LastAdmin = NewConflict("cannot demote the last admin.")
+
+	// Admin tried to demote themselves while no other admin exists.
+	SelfDemotionLastAdmin = NewConflict("cannot demote yourself while no other admin exists.")
+
+	// Deactivating the user would leave no active admin.
+	DeactivateLastAdmin = NewConflict("cannot deactivate the last admin.")
+
+	// Deleting the user would leave no active admin.
+	DeleteLastAdmin = NewConflict("cannot delete the last admin.")
+
+	// Admin tried to deactivate their own account.
+	SelfDeactivation = NewConflict("cannot deactivate yourself.")
+
+	// Admin tried to delete their own account.
+	SelfDeletion = NewConflict("cannot delete yourself.")
+)
+
+// Forbidden errors
+var (
+	// User has to verify their email before signing in.
+	EmailNotVerified = NewForbidden("email is not verified.")
+
+	// User was deactivated by an admin and can neither sign in nor use their tokens.
+	UserDeactivated = NewForbidden("user is deactivated.")
 )
 
 // NotFound errors
@@ -0,0 +1,34 @@
+package errdmn
+
+// Validation errors
+var (
+	// Project name is empty, too long, or uses characters other than lowercase letters, digits, dashes and underscores.
+	InvalidProject = NewValidation("project must be 1-50 lowercase letters, digits, dashes or underscores.")
+
+	// Workflow was given no state.
+	WorkflowStatesEmpty = NewValidation("workflow must have at least one state.")
+
+	// Workflow has more states than allowed.
+	TooManyWorkflowStates = NewValidation("workflow has too many states.")
+
+	// Workflow state is empty, too long, or uses characters other than lowercase letters, digits, dashes and underscores.
+	InvalidWorkflowState = NewValidation("workflow states must be 1-30 lowercase letters, digits, dashes or underscores.")
+
+	// Workflow state is listed more than once.
+	DuplicateWorkflowState = NewValidation("workflow states must be unique.")
+
+	// Workflow starts in a state it does not have.
+	InvalidWorkflowInitial = NewValidation("workflow initial state must be one of its states.")
+
+	// Workflow finishes tasks in a state it does not have.
+	InvalidWorkflowFinal = NewValidation("workflow final states must be among its states.")
+
+	// Workflow transition does not lead from one of its states to another.
+	InvalidWorkflowTransition = NewValidation("workflow transitions must lead from one of its states to another.")
+)
+
+// NotFound errors
+var (
+	// Project has no workflow of its own.
+	WorkflowNotFound = NewNotFound("workflow not found.")
+)
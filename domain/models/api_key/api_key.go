@@ -0,0 +1,222 @@
+/*
+Package apikeymodel defines the `APIKey` aggregate, a long-lived credential a user
+creates for scripts and CI so they do not have to sign in.
+
+A key is limited to its scopes, the permissions it was created with, and may expire.
+Only a hash of the key secret is kept; the secret itself is shown once, when the key
+is created.
+
+Key Components:
+  - APIKey: Represents an API key with its owner, scopes, and expiry.
+  - Config: Holds parameters required to create a new APIKey.
+  - New: Creates a new APIKey, hashing its secret.
+  - APIKeyBSON: Represents the BSON format of an APIKey for MongoDB operations.
+  - FromBSON: Converts a BSON representation back to an APIKey.
+
+Dependencies:
+- github.com/google/uuid: For generating unique IDs.
+*/
+package apikeymodel
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/google/uuid"
+)
+
+// maxNameLength is the maximum number of characters in the name of a key.
+const maxNameLength = 50
+
+// APIKey represents the aggregate API key with private fields.
+type APIKey struct {
+	id         uuid.UUID
+	userID     uuid.UUID
+	name       string
+	scopes     []rolemodel.Permission
+	secretHash string
+	createdAt  time.Time
+	expiresAt  time.Time
+	lastUsedAt time.Time
+}
+
+// APIKeyBSON represents the BSON version of the APIKey for database storage.
+type APIKeyBSON struct {
+	ID         uuid.UUID              `bson:"_id"`
+	UserID     uuid.UUID              `bson:"userId"`
+	Name       string                 `bson:"name"`
+	Scopes     []rolemodel.Permission `bson:"scopes"`
+	SecretHash string                 `bson:"secretHash"`
+	CreatedAt  time.Time              `bson:"createdAt"`
+	ExpiresAt  time.Time              `bson:"expiresAt,omitempty"`
+	LastUsedAt time.Time              `bson:"lastUsedAt,omitempty"`
+}
+
+// Config holds parameters for creating a new APIKey.
+type Config struct {
+	UserID       uuid.UUID
+	Name         string
+	Scopes       []rolemodel.Permission
+	ExpiresAt    time.Time // Time after which the key can no longer be used; the zero time never expires.
+	Secret       string
+	SecretHasher ihash.Service
+}
+
+// New creates a new APIKey with the provided configuration.
+// Repeated scopes are kept once.
+func New(config Config) (*APIKey, error) {
+	if config.UserID == uuid.Nil {
+		return nil, errdmn.APIKeyUserEmpty
+	}
+
+	name := strings.TrimSpace(config.Name)
+	if name == "" {
+		return nil, errdmn.APIKeyNameEmpty
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return nil, errdmn.APIKeyNameTooLong
+	}
+
+	scopes, err := uniqueScopes(config.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if !config.ExpiresAt.IsZero() && !config.ExpiresAt.After(now) {
+		return nil, errdmn.APIKeyExpiryInPast
+	}
+
+	if config.Secret == "" {
+		return nil, errdmn.APIKeySecretEmpty
+	}
+
+	secretHash, err := config.SecretHasher.Hash(config.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKey{
+		id:         uuid.New(),
+		userID:     config.UserID,
+		name:       name,
+		scopes:     scopes,
+		secretHash: secretHash,
+		createdAt:  now,
+		expiresAt:  config.ExpiresAt.UTC(),
+	}, nil
+}
+
+// uniqueScopes validates the scopes of a key and removes the repeated ones.
+func uniqueScopes(scopes []rolemodel.Permission) ([]rolemodel.Permission, error) {
+	if len(scopes) == 0 {
+		return nil, errdmn.APIKeyScopesEmpty
+	}
+
+	seen := make(map[rolemodel.Permission]bool, len(scopes))
+	unique := make([]rolemodel.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, errdmn.InvalidPermission
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique, nil
+}
+
+// FromBSON creates an APIKey from a BSON representation.
+func FromBSON(bsonKey *APIKeyBSON) *APIKey {
+	return &APIKey{
+		id:         bsonKey.ID,
+		userID:     bsonKey.UserID,
+		name:       bsonKey.Name,
+		scopes:     bsonKey.Scopes,
+		secretHash: bsonKey.SecretHash,
+		createdAt:  bsonKey.CreatedAt,
+		expiresAt:  bsonKey.ExpiresAt,
+		lastUsedAt: bsonKey.LastUsedAt,
+	}
+}
+
+// ToBSON converts an APIKey to an APIKeyBSON.
+func (k *APIKey) ToBSON() *APIKeyBSON {
+	return &APIKeyBSON{
+		ID:         k.id,
+		UserID:     k.userID,
+		Name:       k.name,
+		Scopes:     k.scopes,
+		SecretHash: k.secretHash,
+		CreatedAt:  k.createdAt,
+		ExpiresAt:  k.expiresAt,
+		LastUsedAt: k.lastUsedAt,
+	}
+}
+
+// ID returns the key's ID.
+func (k *APIKey) ID() uuid.UUID {
+	return k.id
+}
+
+// UserID returns the ID of the user who owns the key.
+func (k *APIKey) UserID() uuid.UUID {
+	return k.userID
+}
+
+// Name returns the name the user gave the key.
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+// Scopes returns the permissions the key is limited to.
+func (k *APIKey) Scopes() []rolemodel.Permission {
+	return append([]rolemodel.Permission(nil), k.scopes...)
+}
+
+// SecretHash returns the hash of the key secret.
+func (k *APIKey) SecretHash() string {
+	return k.secretHash
+}
+
+// CreatedAt returns the time the key was created.
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// ExpiresAt returns the time after which the key can no longer be used, or the zero
+// time if it never expires.
+func (k *APIKey) ExpiresAt() time.Time {
+	return k.expiresAt
+}
+
+// LastUsedAt returns the time the key was last used, or the zero time if it never was.
+func (k *APIKey) LastUsedAt() time.Time {
+	return k.lastUsedAt
+}
+
+// IsExpired returns whether the key is expired at the given time.
+// Keys without an expiry never expire.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return !k.expiresAt.IsZero() && !now.Before(k.expiresAt)
+}
+
+// Can reports whether the permission is one of the scopes of the key.
+func (k *APIKey) Can(permission rolemodel.Permission) bool {
+	for _, scope := range k.scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesSecret checks the given plain secret against the stored hash.
+func (k *APIKey) MatchesSecret(secret string, secretHasher ihash.Service) (bool, error) {
+	return secretHasher.Match(k.secretHash, secret)
+}
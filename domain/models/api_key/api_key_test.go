@@ -0,0 +1,163 @@
+package apikeymodel_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyModelSuite struct {
+	suite.Suite
+	hasher *ihash_mocks.Service
+	config apikeymodel.Config
+}
+
+func (suite *APIKeyModelSuite) SetupTest() {
+	suite.hasher = new(ihash_mocks.Service)
+	suite.config = apikeymodel.Config{
+		UserID:       uuid.New(),
+		Name:         " ci ",
+		Scopes:       []rolemodel.Permission{rolemodel.TaskRead, rolemodel.TaskCreate, rolemodel.TaskRead},
+		Secret:       "secret",
+		SecretHasher: suite.hasher,
+	}
+}
+
+func (suite *APIKeyModelSuite) TestNew() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+
+	suite.Run("should create a key without expiry", func() {
+		key, err := apikeymodel.New(suite.config)
+		suite.NoError(err)
+		suite.NotEqual(uuid.Nil, key.ID())
+		suite.Equal(suite.config.UserID, key.UserID())
+		suite.Equal("ci", key.Name())
+		suite.Equal([]rolemodel.Permission{rolemodel.TaskRead, rolemodel.TaskCreate}, key.Scopes())
+		suite.Equal("hashed_secret", key.SecretHash())
+		suite.False(key.CreatedAt().IsZero())
+		suite.True(key.ExpiresAt().IsZero())
+		suite.True(key.LastUsedAt().IsZero())
+		suite.False(key.IsExpired(time.Now().AddDate(10, 0, 0)))
+	})
+
+	suite.Run("should expire at the given time", func() {
+		config := suite.config
+		config.ExpiresAt = time.Now().Add(time.Hour)
+		key, err := apikeymodel.New(config)
+		suite.NoError(err)
+		suite.False(key.IsExpired(time.Now()))
+		suite.True(key.IsExpired(time.Now().Add(2 * time.Hour)))
+	})
+
+	suite.Run("should refuse an expiry in the past", func() {
+		config := suite.config
+		config.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeyExpiryInPast, err)
+	})
+
+	suite.Run("should require a user", func() {
+		config := suite.config
+		config.UserID = uuid.Nil
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeyUserEmpty, err)
+	})
+
+	suite.Run("should require a name", func() {
+		config := suite.config
+		config.Name = "   "
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeyNameEmpty, err)
+	})
+
+	suite.Run("should refuse a long name", func() {
+		config := suite.config
+		config.Name = strings.Repeat("k", 51)
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeyNameTooLong, err)
+	})
+
+	suite.Run("should require a scope", func() {
+		config := suite.config
+		config.Scopes = nil
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeyScopesEmpty, err)
+	})
+
+	suite.Run("should refuse unknown scopes", func() {
+		config := suite.config
+		config.Scopes = []rolemodel.Permission{"task:*"}
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.InvalidPermission, err)
+	})
+
+	suite.Run("should require a secret", func() {
+		config := suite.config
+		config.Secret = ""
+		_, err := apikeymodel.New(config)
+		suite.Equal(errdmn.APIKeySecretEmpty, err)
+	})
+}
+
+func (suite *APIKeyModelSuite) TestNew_HashError() {
+	suite.hasher.On("Hash", "secret").Return("", errors.New("hash error"))
+
+	key, err := apikeymodel.New(suite.config)
+	suite.Nil(key)
+	suite.EqualError(err, "hash error")
+}
+
+func (suite *APIKeyModelSuite) TestCan() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+
+	key, err := apikeymodel.New(suite.config)
+	suite.Require().NoError(err)
+
+	suite.True(key.Can(rolemodel.TaskCreate))
+	suite.False(key.Can(rolemodel.TaskDelete))
+}
+
+func (suite *APIKeyModelSuite) TestBSON() {
+	keyBSON := &apikeymodel.APIKeyBSON{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Name:       "ci",
+		Scopes:     []rolemodel.Permission{rolemodel.TaskRead},
+		SecretHash: "hashed_secret",
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastUsedAt: time.Now(),
+	}
+
+	key := apikeymodel.FromBSON(keyBSON)
+	suite.Equal(keyBSON, key.ToBSON())
+}
+
+func (suite *APIKeyModelSuite) TestMatchesSecret() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+	suite.hasher.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.hasher.On("Match", "hashed_secret", "guess").Return(false, nil)
+
+	key, err := apikeymodel.New(suite.config)
+	suite.Require().NoError(err)
+
+	matches, err := key.MatchesSecret("secret", suite.hasher)
+	suite.NoError(err)
+	suite.True(matches)
+
+	matches, err = key.MatchesSecret("guess", suite.hasher)
+	suite.NoError(err)
+	suite.False(matches)
+}
+
+func TestAPIKeyModelSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyModelSuite))
+}
@@ -0,0 +1,117 @@
+/*
+Package loginattemptmodel defines the `LoginAttempt` aggregate, the failed sign in
+attempts recorded under a key such as a username or a client IP address.
+
+Once a key reaches the failures allowed by its Policy it is locked. Each further
+failure doubles the lockout, up to a maximum. Failures are forgotten once the record
+expires, a while after the last failure and never before the lock ends. Failures are
+counted by the repository, so concurrent attempts cannot overwrite each other.
+
+Key Components:
+  - LoginAttempt: Represents the failures and lock of a key.
+  - Policy: Holds the thresholds deciding when and for how long a key is locked.
+  - LoginAttemptBSON: Represents the BSON format of a LoginAttempt for MongoDB operations.
+  - FromBSON: Converts a BSON representation back to a LoginAttempt.
+*/
+package loginattemptmodel
+
+import "time"
+
+// maxDoublings bounds the shift applied to the base lockout so it cannot overflow.
+const maxDoublings = 30
+
+// Policy holds the thresholds applied to the failed attempts of a key.
+type Policy struct {
+	MaxFailures int           // Failures allowed before the key is locked; 0 never locks it.
+	Window      time.Duration // How long failures are remembered after the last one.
+	BaseLockout time.Duration // Lockout once MaxFailures is reached; doubled by each further failure.
+	MaxLockout  time.Duration // Upper bound of the lockout.
+}
+
+// Lockout returns how long a key with the given number of failures is locked,
+// or 0 if it is not.
+func (p Policy) Lockout(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+
+	doublings := failures - p.MaxFailures
+	if doublings > maxDoublings {
+		doublings = maxDoublings
+	}
+
+	lockout := p.BaseLockout << doublings
+	if lockout > p.MaxLockout || lockout <= 0 {
+		return p.MaxLockout
+	}
+	return lockout
+}
+
+// LoginAttempt represents the aggregate failed login attempts with private fields.
+type LoginAttempt struct {
+	key         string
+	failures    int
+	lockedUntil time.Time
+	expiresAt   time.Time
+}
+
+// LoginAttemptBSON represents the BSON version of the LoginAttempt for database storage.
+type LoginAttemptBSON struct {
+	Key         string    `bson:"_id"`
+	Failures    int       `bson:"failures"`
+	LockedUntil time.Time `bson:"lockedUntil,omitempty"`
+	ExpiresAt   time.Time `bson:"expiresAt"`
+}
+
+// FromBSON creates a LoginAttempt from a BSON representation.
+func FromBSON(bsonAttempt *LoginAttemptBSON) *LoginAttempt {
+	return &LoginAttempt{
+		key:         bsonAttempt.Key,
+		failures:    bsonAttempt.Failures,
+		lockedUntil: bsonAttempt.LockedUntil,
+		expiresAt:   bsonAttempt.ExpiresAt,
+	}
+}
+
+// ToBSON converts a LoginAttempt to a LoginAttemptBSON.
+func (a *LoginAttempt) ToBSON() *LoginAttemptBSON {
+	return &LoginAttemptBSON{
+		Key:         a.key,
+		Failures:    a.failures,
+		LockedUntil: a.lockedUntil,
+		ExpiresAt:   a.expiresAt,
+	}
+}
+
+// Key returns the key the failures are recorded under.
+func (a *LoginAttempt) Key() string {
+	return a.key
+}
+
+// Failures returns the number of failed attempts remembered at the given time.
+func (a *LoginAttempt) Failures(now time.Time) int {
+	if a.IsExpired(now) {
+		return 0
+	}
+	return a.failures
+}
+
+// LockedUntil returns the time the lock ends, or the zero time if the key was never locked.
+func (a *LoginAttempt) LockedUntil() time.Time {
+	return a.lockedUntil
+}
+
+// ExpiresAt returns the time after which the failures are forgotten.
+func (a *LoginAttempt) ExpiresAt() time.Time {
+	return a.expiresAt
+}
+
+// IsExpired returns whether the failures are forgotten at the given time.
+func (a *LoginAttempt) IsExpired(now time.Time) bool {
+	return !now.Before(a.expiresAt)
+}
+
+// IsLocked returns whether signing in is refused for the key at the given time.
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return now.Before(a.lockedUntil)
+}
@@ -0,0 +1,82 @@
+package loginattemptmodel_test
+
+import (
+	"testing"
+	"time"
+
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginAttemptModelSuite struct {
+	suite.Suite
+	policy loginattemptmodel.Policy
+	now    time.Time
+}
+
+func (suite *LoginAttemptModelSuite) SetupTest() {
+	suite.policy = loginattemptmodel.Policy{
+		MaxFailures: 3,
+		Window:      15 * time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  10 * time.Minute,
+	}
+	suite.now = time.Now()
+}
+
+func (suite *LoginAttemptModelSuite) TestLockout() {
+	suite.Run("should not lock below the threshold", func() {
+		suite.Equal(time.Duration(0), suite.policy.Lockout(2))
+	})
+
+	suite.Run("should double with each failure", func() {
+		suite.Equal(time.Minute, suite.policy.Lockout(3))
+		suite.Equal(2*time.Minute, suite.policy.Lockout(4))
+		suite.Equal(8*time.Minute, suite.policy.Lockout(6))
+	})
+
+	suite.Run("should cap the lockout", func() {
+		suite.Equal(10*time.Minute, suite.policy.Lockout(7))
+		suite.Equal(10*time.Minute, suite.policy.Lockout(1000))
+	})
+
+	suite.Run("should never lock without a threshold", func() {
+		policy := suite.policy
+		policy.MaxFailures = 0
+		suite.Equal(time.Duration(0), policy.Lockout(1000))
+	})
+}
+
+func (suite *LoginAttemptModelSuite) TestBSON() {
+	attemptBSON := &loginattemptmodel.LoginAttemptBSON{
+		Key:         "user:testuser",
+		Failures:    4,
+		LockedUntil: suite.now.Add(time.Minute),
+		ExpiresAt:   suite.now.Add(time.Hour),
+	}
+
+	attempt := loginattemptmodel.FromBSON(attemptBSON)
+	suite.Equal("user:testuser", attempt.Key())
+	suite.Equal(attemptBSON, attempt.ToBSON())
+}
+
+func (suite *LoginAttemptModelSuite) TestIsLocked() {
+	attempt := loginattemptmodel.FromBSON(&loginattemptmodel.LoginAttemptBSON{
+		Key:         "user:testuser",
+		Failures:    4,
+		LockedUntil: suite.now.Add(time.Minute),
+		ExpiresAt:   suite.now.Add(time.Hour),
+	})
+
+	suite.True(attempt.IsLocked(suite.now))
+	suite.False(attempt.IsLocked(suite.now.Add(time.Minute)))
+	suite.Equal(4, attempt.Failures(suite.now.Add(time.Minute)))
+
+	// Failures are forgotten once the record expires.
+	suite.True(attempt.IsExpired(suite.now.Add(time.Hour)))
+	suite.Equal(0, attempt.Failures(suite.now.Add(time.Hour)))
+}
+
+func TestLoginAttemptModelSuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptModelSuite))
+}
@@ -0,0 +1,153 @@
+/*
+Package refreshtokenmodel defines the `RefreshToken` aggregate, a long-lived credential
+used to obtain new access tokens without logging in again.
+
+Only a hash of the token secret is kept. Tokens issued from the same login share a family;
+each token may be used once, after which it is replaced by a new token of the same family.
+
+Key Components:
+  - RefreshToken: Represents a refresh token with its owner, family, and expiry.
+  - Config: Holds parameters required to create a new RefreshToken.
+  - New: Creates a new RefreshToken, hashing its secret.
+  - RefreshTokenBSON: Represents the BSON format of a RefreshToken for MongoDB operations.
+  - FromBSON: Converts a BSON representation back to a RefreshToken.
+
+Dependencies:
+- github.com/google/uuid: For generating unique IDs.
+*/
+package refreshtokenmodel
+
+import (
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents the aggregate refresh token with private fields.
+type RefreshToken struct {
+	id         uuid.UUID
+	familyID   uuid.UUID
+	userID     uuid.UUID
+	secretHash string
+	expiresAt  time.Time
+	usedAt     time.Time
+}
+
+// RefreshTokenBSON represents the BSON version of the RefreshToken for database storage.
+type RefreshTokenBSON struct {
+	ID         uuid.UUID `bson:"_id"`
+	FamilyID   uuid.UUID `bson:"familyId"`
+	UserID     uuid.UUID `bson:"userId"`
+	SecretHash string    `bson:"secretHash"`
+	ExpiresAt  time.Time `bson:"expiresAt"`
+	UsedAt     time.Time `bson:"usedAt,omitempty"`
+}
+
+// Config holds parameters for creating a new RefreshToken.
+type Config struct {
+	UserID       uuid.UUID
+	FamilyID     uuid.UUID // Family of the token being replaced; uuid.Nil starts a new family.
+	Secret       string
+	TTL          time.Duration
+	SecretHasher ihash.Service
+}
+
+// New creates a new RefreshToken with the provided configuration.
+func New(config Config) (*RefreshToken, error) {
+	if config.UserID == uuid.Nil {
+		return nil, errdmn.RefreshTokenUserEmpty
+	}
+
+	if config.Secret == "" {
+		return nil, errdmn.RefreshTokenSecretEmpty
+	}
+
+	secretHash, err := config.SecretHasher.Hash(config.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID := config.FamilyID
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+
+	return &RefreshToken{
+		id:         uuid.New(),
+		familyID:   familyID,
+		userID:     config.UserID,
+		secretHash: secretHash,
+		expiresAt:  time.Now().UTC().Add(config.TTL),
+	}, nil
+}
+
+// FromBSON creates a RefreshToken from a BSON representation.
+func FromBSON(bsonToken *RefreshTokenBSON) *RefreshToken {
+	return &RefreshToken{
+		id:         bsonToken.ID,
+		familyID:   bsonToken.FamilyID,
+		userID:     bsonToken.UserID,
+		secretHash: bsonToken.SecretHash,
+		expiresAt:  bsonToken.ExpiresAt,
+		usedAt:     bsonToken.UsedAt,
+	}
+}
+
+// ToBSON converts a RefreshToken to a RefreshTokenBSON.
+func (t *RefreshToken) ToBSON() *RefreshTokenBSON {
+	return &RefreshTokenBSON{
+		ID:         t.id,
+		FamilyID:   t.familyID,
+		UserID:     t.userID,
+		SecretHash: t.secretHash,
+		ExpiresAt:  t.expiresAt,
+		UsedAt:     t.usedAt,
+	}
+}
+
+// ID returns the token's ID.
+func (t *RefreshToken) ID() uuid.UUID {
+	return t.id
+}
+
+// FamilyID returns the ID shared by every token issued from the same login.
+func (t *RefreshToken) FamilyID() uuid.UUID {
+	return t.familyID
+}
+
+// UserID returns the ID of the user the token was issued to.
+func (t *RefreshToken) UserID() uuid.UUID {
+	return t.userID
+}
+
+// SecretHash returns the hash of the token secret.
+func (t *RefreshToken) SecretHash() string {
+	return t.secretHash
+}
+
+// ExpiresAt returns the time after which the token can no longer be used.
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns the time the token was exchanged, or the zero time if it was not.
+func (t *RefreshToken) UsedAt() time.Time {
+	return t.usedAt
+}
+
+// IsUsed returns whether the token was already exchanged for a new one.
+func (t *RefreshToken) IsUsed() bool {
+	return !t.usedAt.IsZero()
+}
+
+// IsExpired returns whether the token is expired at the given time.
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// MatchesSecret checks the given plain secret against the stored hash.
+func (t *RefreshToken) MatchesSecret(secret string, secretHasher ihash.Service) (bool, error) {
+	return secretHasher.Match(t.secretHash, secret)
+}
@@ -0,0 +1,111 @@
+package refreshtokenmodel_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenModelSuite struct {
+	suite.Suite
+	hasher *ihash_mocks.Service
+	config refreshtokenmodel.Config
+}
+
+func (suite *RefreshTokenModelSuite) SetupTest() {
+	suite.hasher = new(ihash_mocks.Service)
+	suite.config = refreshtokenmodel.Config{
+		UserID:       uuid.New(),
+		Secret:       "secret",
+		TTL:          time.Hour,
+		SecretHasher: suite.hasher,
+	}
+}
+
+func (suite *RefreshTokenModelSuite) TestNew() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+
+	suite.Run("should start a new family", func() {
+		token, err := refreshtokenmodel.New(suite.config)
+		suite.NoError(err)
+		suite.NotEqual(uuid.Nil, token.ID())
+		suite.NotEqual(uuid.Nil, token.FamilyID())
+		suite.Equal(suite.config.UserID, token.UserID())
+		suite.Equal("hashed_secret", token.SecretHash())
+		suite.False(token.IsUsed())
+		suite.False(token.IsExpired(time.Now()))
+		suite.True(token.IsExpired(time.Now().Add(2 * time.Hour)))
+	})
+
+	suite.Run("should join the given family", func() {
+		config := suite.config
+		config.FamilyID = uuid.New()
+		token, err := refreshtokenmodel.New(config)
+		suite.NoError(err)
+		suite.Equal(config.FamilyID, token.FamilyID())
+	})
+
+	suite.Run("should require a user", func() {
+		config := suite.config
+		config.UserID = uuid.Nil
+		_, err := refreshtokenmodel.New(config)
+		suite.Equal(errdmn.RefreshTokenUserEmpty, err)
+	})
+
+	suite.Run("should require a secret", func() {
+		config := suite.config
+		config.Secret = ""
+		_, err := refreshtokenmodel.New(config)
+		suite.Equal(errdmn.RefreshTokenSecretEmpty, err)
+	})
+}
+
+func (suite *RefreshTokenModelSuite) TestNew_HashError() {
+	suite.hasher.On("Hash", "secret").Return("", errors.New("hash error"))
+
+	token, err := refreshtokenmodel.New(suite.config)
+	suite.Nil(token)
+	suite.EqualError(err, "hash error")
+}
+
+func (suite *RefreshTokenModelSuite) TestBSON() {
+	tokenBSON := &refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   uuid.New(),
+		UserID:     uuid.New(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().Add(time.Hour),
+		UsedAt:     time.Now(),
+	}
+
+	token := refreshtokenmodel.FromBSON(tokenBSON)
+	suite.True(token.IsUsed())
+	suite.Equal(tokenBSON, token.ToBSON())
+}
+
+func (suite *RefreshTokenModelSuite) TestMatchesSecret() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+	suite.hasher.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.hasher.On("Match", "hashed_secret", "guess").Return(false, nil)
+
+	token, err := refreshtokenmodel.New(suite.config)
+	suite.Require().NoError(err)
+
+	matches, err := token.MatchesSecret("secret", suite.hasher)
+	suite.NoError(err)
+	suite.True(matches)
+
+	matches, err = token.MatchesSecret("guess", suite.hasher)
+	suite.NoError(err)
+	suite.False(matches)
+}
+
+func TestRefreshTokenModelSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenModelSuite))
+}
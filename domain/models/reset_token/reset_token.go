@@ -0,0 +1,137 @@
+/*
+Package resettokenmodel defines the `ResetToken` aggregate, a single-use credential
+sent to a user who forgot their password, allowing them to choose a new one.
+
+Only a hash of the token secret is kept. A token expires after a short time and
+can be used once.
+
+Key Components:
+  - ResetToken: Represents a password reset token with its owner and expiry.
+  - Config: Holds parameters required to create a new ResetToken.
+  - New: Creates a new ResetToken, hashing its secret.
+  - ResetTokenBSON: Represents the BSON format of a ResetToken for MongoDB operations.
+  - FromBSON: Converts a BSON representation back to a ResetToken.
+
+Dependencies:
+- github.com/google/uuid: For generating unique IDs.
+*/
+package resettokenmodel
+
+import (
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	"github.com/google/uuid"
+)
+
+// ResetToken represents the aggregate password reset token with private fields.
+type ResetToken struct {
+	id         uuid.UUID
+	userID     uuid.UUID
+	secretHash string
+	expiresAt  time.Time
+	usedAt     time.Time
+}
+
+// ResetTokenBSON represents the BSON version of the ResetToken for database storage.
+type ResetTokenBSON struct {
+	ID         uuid.UUID `bson:"_id"`
+	UserID     uuid.UUID `bson:"userId"`
+	SecretHash string    `bson:"secretHash"`
+	ExpiresAt  time.Time `bson:"expiresAt"`
+	UsedAt     time.Time `bson:"usedAt,omitempty"`
+}
+
+// Config holds parameters for creating a new ResetToken.
+type Config struct {
+	UserID       uuid.UUID
+	Secret       string
+	TTL          time.Duration
+	SecretHasher ihash.Service
+}
+
+// New creates a new ResetToken with the provided configuration.
+func New(config Config) (*ResetToken, error) {
+	if config.UserID == uuid.Nil {
+		return nil, errdmn.ResetTokenUserEmpty
+	}
+
+	if config.Secret == "" {
+		return nil, errdmn.ResetTokenSecretEmpty
+	}
+
+	secretHash, err := config.SecretHasher.Hash(config.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResetToken{
+		id:         uuid.New(),
+		userID:     config.UserID,
+		secretHash: secretHash,
+		expiresAt:  time.Now().UTC().Add(config.TTL),
+	}, nil
+}
+
+// FromBSON creates a ResetToken from a BSON representation.
+func FromBSON(bsonToken *ResetTokenBSON) *ResetToken {
+	return &ResetToken{
+		id:         bsonToken.ID,
+		userID:     bsonToken.UserID,
+		secretHash: bsonToken.SecretHash,
+		expiresAt:  bsonToken.ExpiresAt,
+		usedAt:     bsonToken.UsedAt,
+	}
+}
+
+// ToBSON converts a ResetToken to a ResetTokenBSON.
+func (t *ResetToken) ToBSON() *ResetTokenBSON {
+	return &ResetTokenBSON{
+		ID:         t.id,
+		UserID:     t.userID,
+		SecretHash: t.secretHash,
+		ExpiresAt:  t.expiresAt,
+		UsedAt:     t.usedAt,
+	}
+}
+
+// ID returns the token's ID.
+func (t *ResetToken) ID() uuid.UUID {
+	return t.id
+}
+
+// UserID returns the ID of the user whose password the token resets.
+func (t *ResetToken) UserID() uuid.UUID {
+	return t.userID
+}
+
+// SecretHash returns the hash of the token secret.
+func (t *ResetToken) SecretHash() string {
+	return t.secretHash
+}
+
+// ExpiresAt returns the time after which the token can no longer be used.
+func (t *ResetToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns the time the token was used, or the zero time if it was not.
+func (t *ResetToken) UsedAt() time.Time {
+	return t.usedAt
+}
+
+// IsUsed returns whether the token was already used to reset the password.
+func (t *ResetToken) IsUsed() bool {
+	return !t.usedAt.IsZero()
+}
+
+// IsExpired returns whether the token is expired at the given time.
+func (t *ResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// MatchesSecret checks the given plain secret against the stored hash.
+func (t *ResetToken) MatchesSecret(secret string, secretHasher ihash.Service) (bool, error) {
+	return secretHasher.Match(t.secretHash, secret)
+}
@@ -0,0 +1,101 @@
+package resettokenmodel_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResetTokenModelSuite struct {
+	suite.Suite
+	hasher *ihash_mocks.Service
+	config resettokenmodel.Config
+}
+
+func (suite *ResetTokenModelSuite) SetupTest() {
+	suite.hasher = new(ihash_mocks.Service)
+	suite.config = resettokenmodel.Config{
+		UserID:       uuid.New(),
+		Secret:       "secret",
+		TTL:          time.Hour,
+		SecretHasher: suite.hasher,
+	}
+}
+
+func (suite *ResetTokenModelSuite) TestNew() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+
+	suite.Run("should create an unused token", func() {
+		token, err := resettokenmodel.New(suite.config)
+		suite.NoError(err)
+		suite.NotEqual(uuid.Nil, token.ID())
+		suite.Equal(suite.config.UserID, token.UserID())
+		suite.Equal("hashed_secret", token.SecretHash())
+		suite.False(token.IsUsed())
+		suite.False(token.IsExpired(time.Now()))
+		suite.True(token.IsExpired(time.Now().Add(2 * time.Hour)))
+	})
+
+	suite.Run("should require a user", func() {
+		config := suite.config
+		config.UserID = uuid.Nil
+		_, err := resettokenmodel.New(config)
+		suite.Equal(errdmn.ResetTokenUserEmpty, err)
+	})
+
+	suite.Run("should require a secret", func() {
+		config := suite.config
+		config.Secret = ""
+		_, err := resettokenmodel.New(config)
+		suite.Equal(errdmn.ResetTokenSecretEmpty, err)
+	})
+}
+
+func (suite *ResetTokenModelSuite) TestNew_HashError() {
+	suite.hasher.On("Hash", "secret").Return("", errors.New("hash error"))
+
+	token, err := resettokenmodel.New(suite.config)
+	suite.Nil(token)
+	suite.EqualError(err, "hash error")
+}
+
+func (suite *ResetTokenModelSuite) TestBSON() {
+	tokenBSON := &resettokenmodel.ResetTokenBSON{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().Add(time.Hour),
+		UsedAt:     time.Now(),
+	}
+
+	token := resettokenmodel.FromBSON(tokenBSON)
+	suite.True(token.IsUsed())
+	suite.Equal(tokenBSON, token.ToBSON())
+}
+
+func (suite *ResetTokenModelSuite) TestMatchesSecret() {
+	suite.hasher.On("Hash", "secret").Return("hashed_secret", nil)
+	suite.hasher.On("Match", "hashed_secret", "secret").Return(true, nil)
+	suite.hasher.On("Match", "hashed_secret", "guess").Return(false, nil)
+
+	token, err := resettokenmodel.New(suite.config)
+	suite.Require().NoError(err)
+
+	matches, err := token.MatchesSecret("secret", suite.hasher)
+	suite.NoError(err)
+	suite.True(matches)
+
+	matches, err = token.MatchesSecret("guess", suite.hasher)
+	suite.NoError(err)
+	suite.False(matches)
+}
+
+func TestResetTokenModelSuite(t *testing.T) {
+	suite.Run(t, new(ResetTokenModelSuite))
+}
@@ -0,0 +1,136 @@
+/*
+Package rolemodel defines the named roles users are given and the fine-grained
+permissions each role grants.
+
+Permissions are named resource:action, with a trailing :any when the action may be
+performed on resources owned by someone else, e.g. task:delete only allows deleting
+one's own tasks while task:delete:any allows deleting every task.
+
+Key Components:
+  - Role: A named set of permissions: viewer, member, manager, or admin.
+  - Permission: A single action a role may be granted.
+  - Parse: Validates the name of a role.
+  - ParsePermission: Validates the name of a permission.
+  - Can: Reports whether a role grants a permission.
+*/
+package rolemodel
+
+import (
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// Role is a named set of permissions given to a user.
+type Role string
+
+const (
+	Viewer  Role = "viewer"  // Reads every task without changing anything.
+	Member  Role = "member"  // Manages their own tasks.
+	Manager Role = "manager" // Manages every task and the tags of tasks.
+	Admin   Role = "admin"   // Manages every task, the tags of tasks, every user, and the workflows of projects.
+)
+
+// Default is the role of users who were not given one.
+const Default = Member
+
+// Permission is an action a role may be granted.
+type Permission string
+
+// Task permissions
+const (
+	TaskRead      Permission = "task:read"
+	TaskReadAny   Permission = "task:read:any"
+	TaskCreate    Permission = "task:create"
+	TaskUpdate    Permission = "task:update"
+	TaskUpdateAny Permission = "task:update:any"
+	TaskDelete    Permission = "task:delete"
+	TaskDeleteAny Permission = "task:delete:any"
+)
+
+// User permissions
+const (
+	UserPromote       Permission = "user:promote"
+	UserDemote        Permission = "user:demote"
+	UserAssignRole    Permission = "user:role:assign"
+	UserResetPassword Permission = "user:password:reset"
+	UserReadLock      Permission = "user:lock:read"
+	UserUnlock        Permission = "user:unlock"
+	UserRead          Permission = "user:read"
+	UserDeactivate    Permission = "user:deactivate"
+	UserDelete        Permission = "user:delete"
+)
+
+// Tag permissions
+const (
+	TagManage Permission = "tag:manage"
+)
+
+// Workflow permissions
+const (
+	WorkflowManage Permission = "workflow:manage"
+)
+
+// permissions maps each role to the permissions it grants.
+var permissions = map[Role][]Permission{
+	Viewer: {
+		TaskRead, TaskReadAny,
+	},
+	Member: {
+		TaskRead, TaskCreate, TaskUpdate, TaskDelete,
+	},
+	Manager: {
+		TaskRead, TaskReadAny, TaskCreate, TaskUpdate, TaskUpdateAny, TaskDelete, TaskDeleteAny,
+		TagManage,
+	},
+	Admin: {
+		TaskRead, TaskReadAny, TaskCreate, TaskUpdate, TaskUpdateAny, TaskDelete, TaskDeleteAny,
+		TagManage,
+		UserPromote, UserDemote, UserAssignRole, UserResetPassword, UserReadLock, UserUnlock,
+		UserRead, UserDeactivate, UserDelete,
+		WorkflowManage,
+	},
+}
+
+// Parse returns the role with the given name, or an error if there is none.
+func Parse(name string) (Role, error) {
+	role := Role(name)
+	if !role.IsValid() {
+		return "", errdmn.InvalidRole
+	}
+	return role, nil
+}
+
+// IsValid reports whether the role is one of the defined roles.
+func (r Role) IsValid() bool {
+	_, ok := permissions[r]
+	return ok
+}
+
+// Permissions returns the permissions granted by the role. Unknown roles grant none.
+func (r Role) Permissions() []Permission {
+	return append([]Permission(nil), permissions[r]...)
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(permission Permission) bool {
+	for _, p := range permissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePermission returns the permission with the given name, or an error if there is none.
+func ParsePermission(name string) (Permission, error) {
+	permission := Permission(name)
+	if !permission.IsValid() {
+		return "", errdmn.InvalidPermission
+	}
+	return permission, nil
+}
+
+// IsValid reports whether the permission is one of the defined permissions.
+// Admins are granted every permission, so these are the permissions of the admin role.
+func (p Permission) IsValid() bool {
+	return Admin.Can(p)
+}
@@ -0,0 +1,83 @@
+package rolemodel_test
+
+import (
+	"testing"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleModelSuite struct {
+	suite.Suite
+}
+
+func (suite *RoleModelSuite) TestParse() {
+	suite.Run("should parse defined roles", func() {
+		for _, name := range []string{"viewer", "member", "manager", "admin"} {
+			role, err := rolemodel.Parse(name)
+			suite.NoError(err)
+			suite.Equal(rolemodel.Role(name), role)
+		}
+	})
+
+	suite.Run("should refuse unknown roles", func() {
+		for _, name := range []string{"", "root", "Admin"} {
+			_, err := rolemodel.Parse(name)
+			suite.Equal(errdmn.InvalidRole, err)
+		}
+	})
+}
+
+func (suite *RoleModelSuite) TestCan() {
+	suite.Run("viewer should only read", func() {
+		suite.True(rolemodel.Viewer.Can(rolemodel.TaskReadAny))
+		suite.False(rolemodel.Viewer.Can(rolemodel.TaskCreate))
+	})
+
+	suite.Run("member should only manage their own tasks", func() {
+		suite.True(rolemodel.Member.Can(rolemodel.TaskDelete))
+		suite.False(rolemodel.Member.Can(rolemodel.TaskDeleteAny))
+		suite.False(rolemodel.Member.Can(rolemodel.TaskReadAny))
+	})
+
+	suite.Run("manager should manage every task but no user", func() {
+		suite.True(rolemodel.Manager.Can(rolemodel.TaskUpdateAny))
+		suite.False(rolemodel.Manager.Can(rolemodel.UserPromote))
+	})
+
+	suite.Run("admin should be granted every permission", func() {
+		for _, role := range []rolemodel.Role{rolemodel.Viewer, rolemodel.Member, rolemodel.Manager} {
+			for _, permission := range role.Permissions() {
+				suite.True(rolemodel.Admin.Can(permission))
+			}
+		}
+		suite.True(rolemodel.Admin.Can(rolemodel.UserAssignRole))
+	})
+
+	suite.Run("unknown roles should grant nothing", func() {
+		suite.Empty(rolemodel.Role("root").Permissions())
+		suite.False(rolemodel.Role("root").Can(rolemodel.TaskRead))
+	})
+}
+
+func (suite *RoleModelSuite) TestParsePermission() {
+	suite.Run("should parse defined permissions", func() {
+		for _, name := range []string{"task:read", "task:delete:any", "user:role:assign"} {
+			permission, err := rolemodel.ParsePermission(name)
+			suite.NoError(err)
+			suite.Equal(rolemodel.Permission(name), permission)
+		}
+	})
+
+	suite.Run("should refuse unknown permissions", func() {
+		for _, name := range []string{"", "task:*", "Task:read"} {
+			_, err := rolemodel.ParsePermission(name)
+			suite.Equal(errdmn.InvalidPermission, err)
+		}
+	})
+}
+
+func TestRoleModelSuite(t *testing.T) {
+	suite.Run(t, new(RoleModelSuite))
+}
@@ -0,0 +1,46 @@
+package taskmodel
+
+import errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+
+// Priority ranks how much a task matters. Lower values come first, so tasks sorted
+// in ascending order of priority start with the most urgent.
+type Priority int
+
+const (
+	PriorityUrgent Priority = iota + 1
+	PriorityHigh
+	PriorityMedium
+	PriorityLow
+)
+
+// DefaultPriority is the priority of tasks created without one.
+const DefaultPriority = PriorityMedium
+
+// priorityNames maps each priority to its name.
+var priorityNames = map[Priority]string{
+	PriorityUrgent: "urgent",
+	PriorityHigh:   "high",
+	PriorityMedium: "medium",
+	PriorityLow:    "low",
+}
+
+// ParsePriority returns the priority with the given name, or an error if there is none.
+func ParsePriority(name string) (Priority, error) {
+	for priority, n := range priorityNames {
+		if n == name {
+			return priority, nil
+		}
+	}
+	return 0, errdmn.InvalidPriority
+}
+
+// IsValid reports whether the priority is one of the defined priorities.
+func (p Priority) IsValid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	return priorityNames[p]
+}
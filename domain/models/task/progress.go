@@ -0,0 +1,17 @@
+package taskmodel
+
+// MaxSubtasks is the largest number of subtasks a task can have.
+const MaxSubtasks = 50
+
+// Progress rolls up the subtasks of a task: how many there are and how many are done,
+// that is in a final state of the workflow they follow.
+// The zero value stands for a task without subtasks.
+type Progress struct {
+	Done  int
+	Total int
+}
+
+// Open returns the number of subtasks that are not done.
+func (p Progress) Open() int {
+	return p.Total - p.Done
+}
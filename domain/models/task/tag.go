@@ -0,0 +1,51 @@
+package taskmodel
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// MaxTags is the largest number of tags a task can have.
+const MaxTags = 10
+
+// maxTagLength is the largest number of characters in a tag.
+const maxTagLength = 30
+
+// tagPattern matches normalized tags.
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// NormalizeTag returns the tag in its normalized form: trimmed, lowercased, and with
+// runs of whitespace replaced by a single dash, so that "Back End" and "back-end" are
+// the same tag. It returns an error if the normalized tag is still not valid.
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+	if len(normalized) > maxTagLength || !tagPattern.MatchString(normalized) {
+		return "", errdmn.InvalidTag
+	}
+	return normalized, nil
+}
+
+// NormalizeTags normalizes each of the tags and returns them sorted and without duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		n, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	if len(normalized) > MaxTags {
+		return nil, errdmn.TooManyTags
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -1,10 +1,16 @@
 /*
 Package taskmodel provides the `Task` aggregate, which represents a task with
-a title, description, due date, and status. The package includes functionality
-for creating, updating, and converting tasks to and from BSON format for MongoDB operations.
+a title, description, due date, status, priority, tags, owner, creator, and the
+users assigned to do it. A task may be a subtask of another one, which can only reach
+a final state of its workflow once all of its subtasks are in one of theirs. A task may belong to a project, whose workflow decides the statuses the task
+can have and how it moves between them. The package includes functionality for
+creating, updating, and converting tasks to and from BSON format for MongoDB operations.
 
 Key Components:
-  - Task: Represents a task with an ID, title, description, due date, and status.
+  - Task: Represents a task with an ID, title, description, due date, status, priority, tags, project, parent, owner, creator, and assignees.
+  - Progress: Rolls up how many of the subtasks of a task are finished.
+  - Priority: Ranks how much a task matters, from urgent to low.
+  - NormalizeTags: Brings tags to the form they are stored and compared in.
   - TaskConfig: Holds parameters for creating or updating a Task.
   - New: Creates a new Task with validation and generates a unique ID.
   - TaskBSON: Represents the BSON format of a Task for MongoDB operations.
@@ -18,35 +24,58 @@ Dependencies:
 package taskmodel
 
 import (
+	"sort"
 	"time"
 
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
 	"github.com/google/uuid"
 )
 
+// Statuses of the default workflow.
 const (
-	StatusDone       = "done"
-	StatusInProgress = "inprogress"
-	StatusPending    = "pending"
+	StatusDone       = workflowmodel.StateDone
+	StatusInProgress = workflowmodel.StateInProgress
+	StatusPending    = workflowmodel.StatePending
+	StatusReview     = workflowmodel.StateReview
 )
 
-// Task represents a task with an ID, title, description, due date, and status.
+// MaxAssignees is the largest number of users a task can be assigned to.
+const MaxAssignees = 20
+
+// Task represents a task with an ID, title, description, due date, status, and owner.
+// The creator is the user who created the task, and stays so when the task is handed
+// to another owner; the assignees are the users expected to do the work.
 type Task struct {
 	id          uuid.UUID
 	title       string
 	description string
 	dueDate     time.Time
 	status      string
+	priority    Priority
+	tags        []string
+	project     string
+	parentID    uuid.UUID
+	ownerID     uuid.UUID
+	creatorID   uuid.UUID
+	assigneeIDs []uuid.UUID
 }
 
 // TaskBSON represents the BSON format of a Task for MongoDB operations.
 type TaskBSON struct {
-	ID          uuid.UUID `bson:"_id"`
-	Title       string    `bson:"title"`
-	Description string    `bson:"description"`
-	DueDate     time.Time `bson:"dueDate"`
-	Status      string    `bson:"status"`
-	UpdatedAt   time.Time `bson:"updatedAt"`
+	ID          uuid.UUID   `bson:"_id"`
+	Title       string      `bson:"title"`
+	Description string      `bson:"description"`
+	DueDate     time.Time   `bson:"dueDate"`
+	Status      string      `bson:"status"`
+	Priority    Priority    `bson:"priority"` // Stored as its rank so tasks sort by it.
+	Tags        []string    `bson:"tags"`
+	Project     string      `bson:"project,omitempty"`
+	ParentID    uuid.UUID   `bson:"parentId"`
+	OwnerID     uuid.UUID   `bson:"ownerId"`
+	CreatorID   uuid.UUID   `bson:"creatorId"`
+	AssigneeIDs []uuid.UUID `bson:"assigneeIds"`
+	UpdatedAt   time.Time   `bson:"updatedAt"`
 }
 
 // ToBSON converts a Task to a TaskBSON.
@@ -57,27 +86,63 @@ func (t *Task) ToBSON() *TaskBSON {
 		Description: t.Description(),
 		DueDate:     t.DueDate(),
 		Status:      t.Status(),
+		Priority:    t.Priority(),
+		Tags:        t.Tags(),
+		Project:     t.Project(),
+		ParentID:    t.ParentID(),
+		OwnerID:     t.OwnerID(),
+		CreatorID:   t.CreatorID(),
+		AssigneeIDs: t.AssigneeIDs(),
 		UpdatedAt:   time.Now(),
 	}
 }
 
 // FromBSON converts a TaskBSON to a Task.
+// Tasks stored before creators were recorded were created by their owner, and
+// tasks stored before priorities were introduced have the default priority.
+// Tags are kept sorted.
 func FromBSON(bson *TaskBSON) *Task {
+	creatorID := bson.CreatorID
+	if creatorID == uuid.Nil {
+		creatorID = bson.OwnerID
+	}
+	priority := bson.Priority
+	if !priority.IsValid() {
+		priority = DefaultPriority
+	}
+	tags := append([]string{}, bson.Tags...)
+	sort.Strings(tags)
 	return &Task{
 		id:          bson.ID,
 		title:       bson.Title,
 		description: bson.Description,
 		dueDate:     bson.DueDate,
 		status:      bson.Status,
+		priority:    priority,
+		tags:        tags,
+		project:     bson.Project,
+		parentID:    bson.ParentID,
+		ownerID:     bson.OwnerID,
+		creatorID:   creatorID,
+		assigneeIDs: append([]uuid.UUID{}, bson.AssigneeIDs...),
 	}
 }
 
 // Config represents the configuration for creating or updating a Task.
+// Project, ParentID and OwnerID are only used on creation, where the owner is also
+// recorded as the creator; none of them changes on update.
 type Config struct {
 	Title       string
 	Description string
 	DueDate     time.Time
-	Status      string
+	Status      string   // The initial state of the workflow when empty on creation; unchanged when empty on update.
+	Priority    string   // Name of the priority; DefaultPriority when empty on creation, unchanged when empty on update.
+	Tags        []string // Tags of the task, normalized before they are kept; unchanged when nil on update.
+	Project     string
+	ParentID    uuid.UUID // Task the new task is a subtask of; none when uuid.Nil.
+	OwnerID     uuid.UUID
+	Workflow    *workflowmodel.Workflow // Workflow of the project; the default workflow when nil.
+	Subtasks    Progress                // Progress of the subtasks, which must all be finished before the task is.
 }
 
 // New creates a new Task with the given configuration, validates its properties, and generates an ID.
@@ -85,13 +150,40 @@ func New(config Config) (*Task, error) {
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
+	if config.OwnerID == uuid.Nil {
+		return nil, errdmn.OwnerEmpty
+	}
+	if err := workflowmodel.ValidateProject(config.Project); err != nil {
+		return nil, err
+	}
+
+	workflow := workflowOf(config)
+	status := config.Status
+	if status == "" {
+		status = workflow.Initial()
+	}
+	if !workflow.HasState(status) {
+		return nil, errdmn.InvalidStatus
+	}
+	priority := DefaultPriority
+	if config.Priority != "" {
+		priority, _ = ParsePriority(config.Priority)
+	}
+	tags, _ := NormalizeTags(config.Tags)
 
 	return &Task{
 		id:          uuid.New(),
 		title:       config.Title,
 		description: config.Description,
 		dueDate:     config.DueDate,
-		status:      config.Status,
+		status:      status,
+		priority:    priority,
+		tags:        tags,
+		project:     config.Project,
+		parentID:    config.ParentID,
+		ownerID:     config.OwnerID,
+		creatorID:   config.OwnerID,
+		assigneeIDs: []uuid.UUID{},
 	}, nil
 }
 
@@ -106,20 +198,23 @@ func validateConfig(config Config) error {
 	if config.DueDate.IsZero() {
 		return errdmn.DueDateZero
 	}
-	if !isValidStatus(config.Status) {
-		return errdmn.InvalidStatus
+	if config.Priority != "" {
+		if _, err := ParsePriority(config.Priority); err != nil {
+			return err
+		}
+	}
+	if _, err := NormalizeTags(config.Tags); err != nil {
+		return err
 	}
 	return nil
 }
 
-// isValidStatus checks if the given status is one of the allowed statuses.
-func isValidStatus(status string) bool {
-	switch status {
-	case StatusDone, StatusInProgress, StatusPending:
-		return true
-	default:
-		return false
+// workflowOf returns the workflow of the configuration, or the default workflow when it has none.
+func workflowOf(config Config) *workflowmodel.Workflow {
+	if config.Workflow == nil {
+		return workflowmodel.Default()
 	}
+	return config.Workflow
 }
 
 // ID returns the task's ID.
@@ -147,15 +242,147 @@ func (t *Task) Status() string {
 	return t.status
 }
 
+// Priority returns the task's priority.
+func (t *Task) Priority() Priority {
+	return t.priority
+}
+
+// Tags returns the tags of the task, sorted.
+func (t *Task) Tags() []string {
+	return append([]string{}, t.tags...)
+}
+
+// HasTag reports whether the task has the tag, given in its normalized form.
+func (t *Task) HasTag(tag string) bool {
+	for _, tg := range t.tags {
+		if tg == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Project returns the project the task belongs to, or an empty string when it belongs to none.
+func (t *Task) Project() string {
+	return t.project
+}
+
+// ParentID returns the ID of the task this one is a subtask of, or uuid.Nil when it has no parent.
+func (t *Task) ParentID() uuid.UUID {
+	return t.parentID
+}
+
+// SetParent makes the task a subtask of the task with the given ID, or detaches it
+// from its parent for uuid.Nil. Whether the parent exists and is not a subtask of
+// this task is left to the caller, who can look the tasks up.
+func (t *Task) SetParent(parentID uuid.UUID) error {
+	if parentID == t.id {
+		return errdmn.InvalidParent
+	}
+
+	t.parentID = parentID
+	return nil
+}
+
+// OwnerID returns the ID of the user who owns the task.
+func (t *Task) OwnerID() uuid.UUID {
+	return t.ownerID
+}
+
+// IsOwnedBy reports whether the task belongs to the user with the given ID.
+func (t *Task) IsOwnedBy(userID uuid.UUID) bool {
+	return t.ownerID == userID
+}
+
+// CreatorID returns the ID of the user who created the task.
+func (t *Task) CreatorID() uuid.UUID {
+	return t.creatorID
+}
+
+// AssigneeIDs returns the IDs of the users the task is assigned to, in the order they were assigned.
+func (t *Task) AssigneeIDs() []uuid.UUID {
+	return append([]uuid.UUID{}, t.assigneeIDs...)
+}
+
+// IsAssignedTo reports whether the task is assigned to the user with the given ID.
+func (t *Task) IsAssignedTo(userID uuid.UUID) bool {
+	for _, id := range t.assigneeIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Assign assigns the task to the user with the given ID.
+// Assigning a task to a user it is already assigned to changes nothing.
+func (t *Task) Assign(userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return errdmn.InvalidAssignee
+	}
+	if t.IsAssignedTo(userID) {
+		return nil
+	}
+	if len(t.assigneeIDs) >= MaxAssignees {
+		return errdmn.TooManyAssignees
+	}
+
+	t.assigneeIDs = append(t.assigneeIDs, userID)
+	return nil
+}
+
+// Unassign removes the user with the given ID from the assignees of the task.
+// Unassigning a user the task is not assigned to changes nothing.
+func (t *Task) Unassign(userID uuid.UUID) {
+	for i, id := range t.assigneeIDs {
+		if id == userID {
+			t.assigneeIDs = append(t.assigneeIDs[:i:i], t.assigneeIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Transition moves the task to the given status, which the workflow must allow
+// from its current one. A nil workflow stands for the default workflow. The task
+// cannot reach a final state of the workflow while any of its subtasks is open.
+func (t *Task) Transition(status string, workflow *workflowmodel.Workflow, subtasks Progress) error {
+	if workflow == nil {
+		workflow = workflowmodel.Default()
+	}
+	if !workflow.HasState(status) {
+		return errdmn.InvalidStatus
+	}
+	if !workflow.CanTransition(t.status, status) {
+		return errdmn.TransitionNotAllowed
+	}
+	if workflow.IsFinal(status) && subtasks.Open() > 0 {
+		return errdmn.SubtasksOpen
+	}
+
+	t.status = status
+	return nil
+}
+
 // Update updates the task's fields with the provided configuration after validating the data.
+// A status other than the current one is a transition the workflow must allow.
 func (t *Task) Update(config Config) error {
 	if err := validateConfig(config); err != nil {
 		return err
 	}
+	if config.Status != "" && config.Status != t.status {
+		if err := t.Transition(config.Status, config.Workflow, config.Subtasks); err != nil {
+			return err
+		}
+	}
 
 	t.title = config.Title
 	t.description = config.Description
 	t.dueDate = config.DueDate
-	t.status = config.Status
+	if config.Priority != "" {
+		t.priority, _ = ParsePriority(config.Priority)
+	}
+	if config.Tags != nil {
+		t.tags, _ = NormalizeTags(config.Tags)
+	}
 	return nil
 }
@@ -1,11 +1,14 @@
 package taskmodel_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
@@ -22,6 +25,8 @@ func (suite *TaskModelSuite) SetupTest() {
 		Description: "This is a test task.",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusPending,
+		Priority:    "high",
+		OwnerID:     uuid.New(),
 	}
 	var err error
 	suite.task, err = taskmodel.New(suite.validConfig)
@@ -37,9 +42,37 @@ func (suite *TaskModelSuite) TestNewTask() {
 		suite.Equal(suite.validConfig.Description, task.Description())
 		suite.Equal(suite.validConfig.DueDate, task.DueDate())
 		suite.Equal(suite.validConfig.Status, task.Status())
+		suite.Equal(taskmodel.PriorityHigh, task.Priority())
+		suite.Equal(suite.validConfig.OwnerID, task.OwnerID())
+		suite.True(task.IsOwnedBy(suite.validConfig.OwnerID))
+		suite.Equal(suite.validConfig.OwnerID, task.CreatorID())
+		suite.Empty(task.AssigneeIDs())
 		suite.NotEqual(uuid.Nil, task.ID())
 	})
 
+	suite.Run("should keep the tags normalized", func() {
+		config := suite.validConfig
+		config.Tags = []string{"Backend", " bug ", "back end", "bug"}
+		task, err := taskmodel.New(config)
+		suite.NoError(err)
+		suite.Equal([]string{"back-end", "backend", "bug"}, task.Tags())
+		suite.True(task.HasTag("bug"))
+		suite.False(task.HasTag("Bug"))
+
+		config.Tags = []string{"needs review!"}
+		task, err = taskmodel.New(config)
+		suite.Nil(task)
+		suite.Equal(errdmn.InvalidTag, err)
+	})
+
+	suite.Run("should return error if owner is empty", func() {
+		invalidConfig := suite.validConfig
+		invalidConfig.OwnerID = uuid.Nil
+		task, err := taskmodel.New(invalidConfig)
+		suite.Nil(task)
+		suite.Equal(errdmn.OwnerEmpty, err)
+	})
+
 	suite.Run("should return error if title is empty", func() {
 		invalidConfig := suite.validConfig
 		invalidConfig.Title = ""
@@ -71,6 +104,108 @@ func (suite *TaskModelSuite) TestNewTask() {
 		suite.Nil(task)
 		suite.Equal(errdmn.InvalidStatus, err)
 	})
+
+	suite.Run("should return error if priority is invalid", func() {
+		invalidConfig := suite.validConfig
+		invalidConfig.Priority = "critical"
+		task, err := taskmodel.New(invalidConfig)
+		suite.Nil(task)
+		suite.Equal(errdmn.InvalidPriority, err)
+	})
+
+	suite.Run("should take the default priority without one", func() {
+		config := suite.validConfig
+		config.Priority = ""
+		task, err := taskmodel.New(config)
+		suite.NoError(err)
+		suite.Equal(taskmodel.DefaultPriority, task.Priority())
+	})
+
+	suite.Run("should start in the initial state of the workflow without a status", func() {
+		config := suite.validConfig
+		config.Status = ""
+		task, err := taskmodel.New(config)
+		suite.NoError(err)
+		suite.Equal(taskmodel.StatusPending, task.Status())
+		suite.Empty(task.Project())
+	})
+
+	suite.Run("should take the statuses of the workflow of its project", func() {
+		workflow, err := workflowmodel.New(workflowmodel.Config{Project: "website", States: []string{"todo", "shipped"}})
+		suite.Require().NoError(err)
+
+		config := suite.validConfig
+		config.Project = "website"
+		config.Workflow = workflow
+		_, err = taskmodel.New(config)
+		suite.Equal(errdmn.InvalidStatus, err)
+
+		config.Status = ""
+		task, err := taskmodel.New(config)
+		suite.NoError(err)
+		suite.Equal("todo", task.Status())
+		suite.Equal("website", task.Project())
+	})
+
+	suite.Run("should return error if project is invalid", func() {
+		invalidConfig := suite.validConfig
+		invalidConfig.Project = "Web Site"
+		task, err := taskmodel.New(invalidConfig)
+		suite.Nil(task)
+		suite.Equal(errdmn.InvalidProject, err)
+	})
+}
+
+func (suite *TaskModelSuite) TestTask_Transition() {
+	suite.Run("should follow the default workflow", func() {
+		suite.NoError(suite.task.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+		suite.NoError(suite.task.Transition(taskmodel.StatusReview, nil, taskmodel.Progress{}))
+		suite.NoError(suite.task.Transition(taskmodel.StatusDone, nil, taskmodel.Progress{}))
+		suite.Equal(taskmodel.StatusDone, suite.task.Status())
+	})
+
+	suite.Run("should reopen a done task", func() {
+		suite.NoError(suite.task.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+		suite.Equal(taskmodel.StatusInProgress, suite.task.Status())
+	})
+
+	suite.Run("should refuse transitions the workflow does not allow", func() {
+		suite.Equal(errdmn.TransitionNotAllowed, suite.task.Transition(taskmodel.StatusDone, nil, taskmodel.Progress{}))
+		suite.Equal(errdmn.TransitionNotAllowed, suite.task.Transition(taskmodel.StatusInProgress, nil, taskmodel.Progress{}))
+		suite.Equal(taskmodel.StatusInProgress, suite.task.Status())
+	})
+
+	suite.Run("should refuse statuses the workflow does not have", func() {
+		suite.Equal(errdmn.InvalidStatus, suite.task.Transition("archived", nil, taskmodel.Progress{}))
+	})
+
+	suite.Run("should only be done once every subtask is", func() {
+		suite.NoError(suite.task.Transition(taskmodel.StatusReview, nil, taskmodel.Progress{Done: 1, Total: 2}))
+		suite.Equal(errdmn.SubtasksOpen, suite.task.Transition(taskmodel.StatusDone, nil, taskmodel.Progress{Done: 1, Total: 2}))
+		suite.Equal(taskmodel.StatusReview, suite.task.Status())
+
+		suite.NoError(suite.task.Transition(taskmodel.StatusDone, nil, taskmodel.Progress{Done: 2, Total: 2}))
+		suite.Equal(taskmodel.StatusDone, suite.task.Status())
+	})
+
+	suite.Run("should only reach a final state of its workflow once every subtask is finished", func() {
+		workflow, err := workflowmodel.New(workflowmodel.Config{
+			Project:     "website",
+			States:      []string{"todo", "shipped"},
+			Final:       []string{"shipped"},
+			Transitions: []workflowmodel.Transition{{From: "todo", To: "shipped"}},
+		})
+		suite.Require().NoError(err)
+		config := suite.validConfig
+		config.Project = "website"
+		config.Status = ""
+		config.Workflow = workflow
+		task, err := taskmodel.New(config)
+		suite.Require().NoError(err)
+
+		suite.Equal(errdmn.SubtasksOpen, task.Transition("shipped", workflow, taskmodel.Progress{Done: 1, Total: 2}))
+		suite.NoError(task.Transition("shipped", workflow, taskmodel.Progress{Done: 2, Total: 2}))
+	})
 }
 
 func (suite *TaskModelSuite) TestTask_Update() {
@@ -87,6 +222,23 @@ func (suite *TaskModelSuite) TestTask_Update() {
 		suite.Equal(updateConfig.Description, suite.task.Description())
 		suite.Equal(updateConfig.DueDate, suite.task.DueDate())
 		suite.Equal(updateConfig.Status, suite.task.Status())
+		suite.Equal(taskmodel.PriorityHigh, suite.task.Priority())
+		suite.Equal(suite.validConfig.OwnerID, suite.task.OwnerID())
+	})
+
+	suite.Run("should change the priority when one is given", func() {
+		updateConfig := taskmodel.Config{
+			Title:       "Updated Task",
+			Description: "This is an updated test task.",
+			DueDate:     time.Now().Add(48 * time.Hour),
+			Priority:    "urgent",
+		}
+		suite.NoError(suite.task.Update(updateConfig))
+		suite.Equal(taskmodel.PriorityUrgent, suite.task.Priority())
+
+		updateConfig.Priority = "someday"
+		suite.Equal(errdmn.InvalidPriority, suite.task.Update(updateConfig))
+		suite.Equal(taskmodel.PriorityUrgent, suite.task.Priority())
 	})
 
 	suite.Run("should return error when updating with invalid config", func() {
@@ -99,6 +251,108 @@ func (suite *TaskModelSuite) TestTask_Update() {
 		err := suite.task.Update(invalidConfig)
 		suite.Equal(errdmn.TitleEmpty, err)
 	})
+
+	suite.Run("should refuse a status the workflow does not allow", func() {
+		updateConfig := taskmodel.Config{
+			Title:       "Skipped Task",
+			Description: "This task skips review.",
+			DueDate:     time.Now().Add(48 * time.Hour),
+			Status:      taskmodel.StatusDone,
+		}
+		err := suite.task.Update(updateConfig)
+		suite.Equal(errdmn.TransitionNotAllowed, err)
+		suite.Equal(taskmodel.StatusInProgress, suite.task.Status())
+		suite.Equal("Updated Task", suite.task.Title())
+	})
+
+	suite.Run("should replace the tags only when they are given", func() {
+		updateConfig := taskmodel.Config{
+			Title:       "Updated Task",
+			Description: "This is an updated test task.",
+			DueDate:     time.Now().Add(48 * time.Hour),
+			Tags:        []string{"infra", "Backend"},
+		}
+		suite.NoError(suite.task.Update(updateConfig))
+		suite.Equal([]string{"backend", "infra"}, suite.task.Tags())
+
+		updateConfig.Tags = nil
+		suite.NoError(suite.task.Update(updateConfig))
+		suite.Equal([]string{"backend", "infra"}, suite.task.Tags())
+
+		updateConfig.Tags = []string{}
+		suite.NoError(suite.task.Update(updateConfig))
+		suite.Empty(suite.task.Tags())
+	})
+
+	suite.Run("should keep the status when none is given", func() {
+		updateConfig := taskmodel.Config{
+			Title:       "Renamed Task",
+			Description: "This is a renamed test task.",
+			DueDate:     time.Now().Add(48 * time.Hour),
+		}
+		suite.NoError(suite.task.Update(updateConfig))
+		suite.Equal(taskmodel.StatusInProgress, suite.task.Status())
+	})
+}
+
+func (suite *TaskModelSuite) TestTask_SetParent() {
+	suite.Run("should start without a parent unless given one", func() {
+		suite.Equal(uuid.Nil, suite.task.ParentID())
+
+		config := suite.validConfig
+		config.ParentID = suite.task.ID()
+		subtask, err := taskmodel.New(config)
+		suite.NoError(err)
+		suite.Equal(suite.task.ID(), subtask.ParentID())
+	})
+
+	suite.Run("should move the task under another one and detach it", func() {
+		parentID := uuid.New()
+		suite.NoError(suite.task.SetParent(parentID))
+		suite.Equal(parentID, suite.task.ParentID())
+
+		suite.NoError(suite.task.SetParent(uuid.Nil))
+		suite.Equal(uuid.Nil, suite.task.ParentID())
+	})
+
+	suite.Run("should refuse to make the task its own parent", func() {
+		suite.Equal(errdmn.InvalidParent, suite.task.SetParent(suite.task.ID()))
+		suite.Equal(uuid.Nil, suite.task.ParentID())
+	})
+}
+
+func (suite *TaskModelSuite) TestTask_Assign() {
+	first, second := uuid.New(), uuid.New()
+
+	suite.Run("should assign users in order", func() {
+		suite.NoError(suite.task.Assign(first))
+		suite.NoError(suite.task.Assign(second))
+		suite.Equal([]uuid.UUID{first, second}, suite.task.AssigneeIDs())
+		suite.True(suite.task.IsAssignedTo(first))
+	})
+
+	suite.Run("should ignore a user already assigned", func() {
+		suite.NoError(suite.task.Assign(first))
+		suite.Len(suite.task.AssigneeIDs(), 2)
+	})
+
+	suite.Run("should return error for an empty user", func() {
+		suite.Equal(errdmn.InvalidAssignee, suite.task.Assign(uuid.Nil))
+	})
+
+	suite.Run("should unassign users", func() {
+		suite.task.Unassign(first)
+		suite.task.Unassign(uuid.New())
+		suite.Equal([]uuid.UUID{second}, suite.task.AssigneeIDs())
+		suite.False(suite.task.IsAssignedTo(first))
+	})
+
+	suite.Run("should return error past the limit", func() {
+		for len(suite.task.AssigneeIDs()) < taskmodel.MaxAssignees {
+			suite.Require().NoError(suite.task.Assign(uuid.New()))
+		}
+		suite.Equal(errdmn.TooManyAssignees, suite.task.Assign(uuid.New()))
+	})
 }
 
 func (suite *TaskModelSuite) TestTask_ToBSON() {
@@ -109,6 +363,13 @@ func (suite *TaskModelSuite) TestTask_ToBSON() {
 		suite.Equal(suite.task.Description(), bson.Description)
 		suite.Equal(suite.task.DueDate(), bson.DueDate)
 		suite.Equal(suite.task.Status(), bson.Status)
+		suite.Equal(suite.task.Priority(), bson.Priority)
+		suite.Equal(suite.task.Tags(), bson.Tags)
+		suite.Equal(suite.task.Project(), bson.Project)
+		suite.Equal(suite.task.ParentID(), bson.ParentID)
+		suite.Equal(suite.task.OwnerID(), bson.OwnerID)
+		suite.Equal(suite.task.CreatorID(), bson.CreatorID)
+		suite.Equal(suite.task.AssigneeIDs(), bson.AssigneeIDs)
 		suite.NotZero(bson.UpdatedAt)
 	})
 }
@@ -120,6 +381,13 @@ func (suite *TaskModelSuite) TestFromBSON() {
 		Description: "This is a BSON task.",
 		DueDate:     time.Now().Add(24 * time.Hour),
 		Status:      taskmodel.StatusDone,
+		Priority:    taskmodel.PriorityLow,
+		Tags:        []string{"infra", "bug"},
+		Project:     "website",
+		ParentID:    uuid.New(),
+		OwnerID:     uuid.New(),
+		CreatorID:   uuid.New(),
+		AssigneeIDs: []uuid.UUID{uuid.New()},
 		UpdatedAt:   time.Now(),
 	}
 
@@ -130,6 +398,76 @@ func (suite *TaskModelSuite) TestFromBSON() {
 		suite.Equal(taskBSON.Description, task.Description())
 		suite.Equal(taskBSON.DueDate, task.DueDate())
 		suite.Equal(taskBSON.Status, task.Status())
+		suite.Equal(taskBSON.Priority, task.Priority())
+		suite.Equal([]string{"bug", "infra"}, task.Tags())
+		suite.Equal(taskBSON.Project, task.Project())
+		suite.Equal(taskBSON.ParentID, task.ParentID())
+		suite.Equal(taskBSON.OwnerID, task.OwnerID())
+		suite.Equal(taskBSON.CreatorID, task.CreatorID())
+		suite.Equal(taskBSON.AssigneeIDs, task.AssigneeIDs())
+	})
+
+	suite.Run("should take the owner for the creator of older tasks", func() {
+		legacy := *taskBSON
+		legacy.CreatorID = uuid.Nil
+		legacy.AssigneeIDs = nil
+		legacy.Priority = 0
+		legacy.Tags = nil
+		task := taskmodel.FromBSON(&legacy)
+		suite.Equal(legacy.OwnerID, task.CreatorID())
+		suite.Empty(task.AssigneeIDs())
+		suite.Empty(task.Tags())
+		suite.Equal(taskmodel.DefaultPriority, task.Priority())
+	})
+}
+
+func (suite *TaskModelSuite) TestParsePriority() {
+	suite.Run("should parse priorities from most to least urgent", func() {
+		var previous taskmodel.Priority
+		for _, name := range []string{"urgent", "high", "medium", "low"} {
+			priority, err := taskmodel.ParsePriority(name)
+			suite.NoError(err)
+			suite.Equal(name, priority.String())
+			suite.Greater(priority, previous)
+			previous = priority
+		}
+	})
+
+	suite.Run("should refuse unknown priorities", func() {
+		for _, name := range []string{"", "critical", "High"} {
+			_, err := taskmodel.ParsePriority(name)
+			suite.Equal(errdmn.InvalidPriority, err)
+		}
+	})
+}
+
+func (suite *TaskModelSuite) TestNormalizeTags() {
+	suite.Run("should lowercase tags and join their words with dashes", func() {
+		tag, err := taskmodel.NormalizeTag("  Front   End ")
+		suite.NoError(err)
+		suite.Equal("front-end", tag)
+	})
+
+	suite.Run("should refuse invalid tags", func() {
+		for _, tag := range []string{"", "   ", "-bug", "c++", strings.Repeat("a", 31)} {
+			_, err := taskmodel.NormalizeTag(tag)
+			suite.Equal(errdmn.InvalidTag, err, tag)
+		}
+	})
+
+	suite.Run("should drop duplicates and sort the tags", func() {
+		tags, err := taskmodel.NormalizeTags([]string{"v1.2", "Bug", "bug", "api_v2"})
+		suite.NoError(err)
+		suite.Equal([]string{"api_v2", "bug", "v1.2"}, tags)
+	})
+
+	suite.Run("should refuse more tags than allowed", func() {
+		tags := make([]string, taskmodel.MaxTags+1)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag%d", i)
+		}
+		_, err := taskmodel.NormalizeTags(tags)
+		suite.Equal(errdmn.TooManyTags, err)
 	})
 }
 
@@ -1,12 +1,16 @@
 /*
 Package usermodel defines the `User` aggregate, representing an individual user with methods
-for creation and management. It handles user creation, username and password validation,
-and user-expense associations.
+for creation and management. It handles user creation, username, email and password validation,
+profile details, email verification, two-factor authentication, deactivation, links to identities at
+external identity providers, and user-expense associations.
 
 Key Components:
-  - User: Represents a user with details like username, password hash, and role.
+  - User: Represents a user with details like username, email, password hash, and role.
+    The role decides what the user is allowed to do; see package rolemodel.
   - Config: Holds parameters required to create a new User.
   - New: Creates a new User instance using the provided configuration.
+  - ExternalIdentity: The account of a user at an identity provider they sign in with.
+  - NewExternal: Creates a User signing in through an identity provider, without a password.
   - ConfigBSON: Holds parameters for creating a User with an existing password hash.
   - ToBSON: Creates a User instance with a pre-hashed password.
 
@@ -17,12 +21,19 @@ Dependencies:
 package usermodel
 
 import (
+	"net/mail"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash "github.com/beka-birhanu/task_manager_final/domain/i_hash"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	"github.com/google/uuid"
 	"github.com/nbutton23/zxcvbn-go"
+	"golang.org/x/text/language"
 )
 
 const (
@@ -31,6 +42,11 @@ const (
 	usernamePattern   = `^[a-zA-Z0-9_]+$` // Alphanumeric with underscores
 	minUsernameLength = 3
 	maxUsernameLength = 20
+
+	maxEmailLength = 254
+
+	maxDisplayNameLength = 50
+	maxAvatarURLLength   = 2048
 )
 
 var (
@@ -39,34 +55,80 @@ var (
 
 // User represents the aggregate user with private fields.
 type User struct {
-	id           uuid.UUID
-	username     string
-	passwordHash string
-	isAdmin      bool
+	id                         uuid.UUID
+	username                   string
+	email                      string
+	emailVerified              bool
+	emailVerificationHash      string
+	emailVerificationExpiresAt time.Time
+	passwordHash               string
+	role                       rolemodel.Role
+	displayName                string             // Name shown instead of the username; optional.
+	timeZone                   string             // IANA time zone name, e.g. Europe/Berlin; optional.
+	locale                     string             // BCP 47 language tag, e.g. en-US; optional.
+	avatarURL                  string             // Absolute http(s) URL of the avatar image; optional.
+	deactivated                bool               // Deactivated users can neither sign in nor use their tokens.
+	totpSecret                 string             // Secret of the confirmed TOTP enrollment; two-factor authentication is on when set.
+	pendingTOTPSecret          string             // Secret of a TOTP enrollment waiting for its first code.
+	totpLastStep               int64              // Time step of the last accepted TOTP code, so a code is accepted once.
+	recoveryCodeHashes         []string           // Hashes of the unused recovery codes.
+	externalIdentities         []ExternalIdentity // Accounts at identity providers the user signs in with.
 }
 
 // UserBSON represents the BSON version of the User for database storage.
 type UserBSON struct {
-	ID           uuid.UUID `bson:"_id"`
-	Username     string    `bson:"username"`
-	PasswordHash string    `bson:"passwordHash"`
-	IsAdmin      bool      `bson:"isAdmin"`
+	ID                         uuid.UUID          `bson:"_id"`
+	Username                   string             `bson:"username"`
+	Email                      string             `bson:"email,omitempty"`
+	EmailVerified              bool               `bson:"emailVerified"`
+	EmailVerificationHash      string             `bson:"emailVerificationHash,omitempty"`
+	EmailVerificationExpiresAt time.Time          `bson:"emailVerificationExpiresAt,omitempty"`
+	PasswordHash               string             `bson:"passwordHash"`
+	Role                       rolemodel.Role     `bson:"role"`
+	DisplayName                string             `bson:"displayName,omitempty"`
+	TimeZone                   string             `bson:"timeZone,omitempty"`
+	Locale                     string             `bson:"locale,omitempty"`
+	AvatarURL                  string             `bson:"avatarUrl,omitempty"`
+	Deactivated                bool               `bson:"deactivated,omitempty"`
+	TOTPSecret                 string             `bson:"totpSecret,omitempty"`
+	PendingTOTPSecret          string             `bson:"pendingTotpSecret,omitempty"`
+	TOTPLastStep               int64              `bson:"totpLastStep,omitempty"`
+	RecoveryCodeHashes         []string           `bson:"recoveryCodeHashes,omitempty"`
+	ExternalIdentities         []ExternalIdentity `bson:"externalIdentities,omitempty"`
+}
+
+// ExternalIdentity identifies the account of a user at an OpenID Connect identity
+// provider: the subject is unique within the issuer only.
+type ExternalIdentity struct {
+	Issuer  string `bson:"issuer"`  // Issuer identifier URL of the provider.
+	Subject string `bson:"subject"` // ID of the account at the provider.
 }
 
 // Config holds parameters for creating a new User.
 type Config struct {
 	Username       string
+	Email          string // Optional; validated when given.
 	PlainPassword  string
-	IsAdmin        bool
+	Role           rolemodel.Role // Optional; rolemodel.Default when empty.
 	PasswordHasher ihash.Service
 }
 
+// ExternalConfig holds parameters for creating a User who signs in through an
+// identity provider.
+type ExternalConfig struct {
+	Username      string
+	Email         string // Optional; validated when given.
+	EmailVerified bool   // Whether the provider vouches for the email address.
+	Identity      ExternalIdentity
+	Role          rolemodel.Role // Optional; rolemodel.Default when empty.
+}
+
 // ConfigBSON holds parameters for creating a User with an existing password hash.
 type ConfigBSON struct {
 	ID           uuid.UUID
 	Username     string
 	PasswordHash string
-	IsAdmin      bool
+	Role         rolemodel.Role
 }
 
 // New creates a new User with the provided configuration.
@@ -75,6 +137,21 @@ func New(config Config) (*User, error) {
 		return nil, err
 	}
 
+	email := normalizeEmail(config.Email)
+	if email != "" {
+		if err := validateEmail(email); err != nil {
+			return nil, err
+		}
+	}
+
+	role := config.Role
+	if role == "" {
+		role = rolemodel.Default
+	}
+	if !role.IsValid() {
+		return nil, errdmn.InvalidRole
+	}
+
 	if err := validatePassword(config.PlainPassword); err != nil {
 		return nil, err
 	}
@@ -87,18 +164,69 @@ func New(config Config) (*User, error) {
 	return &User{
 		id:           uuid.New(), // New ID for the user
 		username:     config.Username,
+		email:        email,
 		passwordHash: passwordHash,
-		isAdmin:      config.IsAdmin,
+		role:         role,
+	}, nil
+}
+
+// NewExternal creates a new User linked to an account at an identity provider. The user
+// has no password, so they can only sign in through the provider.
+func NewExternal(config ExternalConfig) (*User, error) {
+	if err := validateUsername(config.Username); err != nil {
+		return nil, err
+	}
+
+	email := normalizeEmail(config.Email)
+	if email != "" {
+		if err := validateEmail(email); err != nil {
+			return nil, err
+		}
+	}
+
+	role := config.Role
+	if role == "" {
+		role = rolemodel.Default
+	}
+	if !role.IsValid() {
+		return nil, errdmn.InvalidRole
+	}
+
+	if config.Identity.Issuer == "" || config.Identity.Subject == "" {
+		return nil, errdmn.ExternalIdentityEmpty
+	}
+
+	return &User{
+		id:                 uuid.New(),
+		username:           config.Username,
+		email:              email,
+		emailVerified:      email != "" && config.EmailVerified,
+		role:               role,
+		externalIdentities: []ExternalIdentity{config.Identity},
 	}, nil
 }
 
 // FromBSON creates a User from a BSON representation.
 func FromBSON(bsonUser *UserBSON) *User {
 	return &User{
-		id:           bsonUser.ID,
-		username:     bsonUser.Username,
-		passwordHash: bsonUser.PasswordHash,
-		isAdmin:      bsonUser.IsAdmin,
+		id:                         bsonUser.ID,
+		username:                   bsonUser.Username,
+		email:                      bsonUser.Email,
+		emailVerified:              bsonUser.EmailVerified,
+		emailVerificationHash:      bsonUser.EmailVerificationHash,
+		emailVerificationExpiresAt: bsonUser.EmailVerificationExpiresAt,
+		passwordHash:               bsonUser.PasswordHash,
+		role:                       bsonUser.Role,
+		displayName:                bsonUser.DisplayName,
+		timeZone:                   bsonUser.TimeZone,
+		locale:                     bsonUser.Locale,
+		avatarURL:                  bsonUser.AvatarURL,
+		deactivated:                bsonUser.Deactivated,
+		totpSecret:                 bsonUser.TOTPSecret,
+		pendingTOTPSecret:          bsonUser.PendingTOTPSecret,
+		totpLastStep:               bsonUser.TOTPLastStep,
+		recoveryCodeHashes:         bsonUser.RecoveryCodeHashes,
+		externalIdentities:         bsonUser.ExternalIdentities,
 	}
 }
 
@@ -116,6 +244,47 @@ func validateUsername(username string) error {
 	return nil
 }
 
+// normalizeEmail trims the email address and lowercases it, so the same address
+// cannot be registered twice with different cases.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateEmail validates a normalized email address. Only bare addresses are
+// accepted, without a display name.
+func validateEmail(email string) error {
+	if len(email) > maxEmailLength {
+		return errdmn.EmailInvalidFormat
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return errdmn.EmailInvalidFormat
+	}
+	return nil
+}
+
+// normalizeLocale returns the canonical form of a BCP 47 language tag.
+func normalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", errdmn.InvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// validateAvatarURL accepts absolute http and https URLs only, so no other scheme
+// ends up in an image source.
+func validateAvatarURL(avatarURL string) error {
+	if len(avatarURL) > maxAvatarURLLength {
+		return errdmn.InvalidAvatarURL
+	}
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errdmn.InvalidAvatarURL
+	}
+	return nil
+}
+
 // validatePassword checks the strength of the password.
 func validatePassword(password string) error {
 	result := zxcvbn.PasswordStrength(password, nil)
@@ -135,14 +304,116 @@ func (u *User) Username() string {
 	return u.username
 }
 
+// Email returns the user's email address, or an empty string if they have none.
+func (u *User) Email() string {
+	return u.email
+}
+
+// IsEmailVerified returns whether the user proved they own their email address.
+func (u *User) IsEmailVerified() bool {
+	return u.emailVerified
+}
+
+// EmailVerificationHash returns the hash of the pending email verification secret, if any.
+func (u *User) EmailVerificationHash() string {
+	return u.emailVerificationHash
+}
+
+// EmailVerificationExpiresAt returns the time after which the pending email verification
+// secret can no longer be used.
+func (u *User) EmailVerificationExpiresAt() time.Time {
+	return u.emailVerificationExpiresAt
+}
+
 // PasswordHash returns the user's password hash.
 func (u *User) PasswordHash() string {
 	return u.passwordHash
 }
 
-// IsAdmin returns whether the user is an admin.
+// HasPassword returns whether the user can sign in with a password. Users created
+// through an identity provider have none until they reset it.
+func (u *User) HasPassword() bool {
+	return u.passwordHash != ""
+}
+
+// Role returns the user's role.
+func (u *User) Role() rolemodel.Role {
+	return u.role
+}
+
+// IsAdmin returns whether the user has the admin role.
 func (u *User) IsAdmin() bool {
-	return u.isAdmin
+	return u.role == rolemodel.Admin
+}
+
+// DisplayName returns the name the user is shown by, or an empty string if they chose none.
+func (u *User) DisplayName() string {
+	return u.displayName
+}
+
+// TimeZone returns the IANA name of the user's time zone, or an empty string if unset.
+func (u *User) TimeZone() string {
+	return u.timeZone
+}
+
+// Locale returns the user's BCP 47 language tag, or an empty string if unset.
+func (u *User) Locale() string {
+	return u.locale
+}
+
+// AvatarURL returns the URL of the user's avatar image, or an empty string if unset.
+func (u *User) AvatarURL() string {
+	return u.avatarURL
+}
+
+// IsDeactivated returns whether the user was deactivated and may not sign in.
+func (u *User) IsDeactivated() bool {
+	return u.deactivated
+}
+
+// IsMFAEnabled returns whether the user signs in with a TOTP code besides their password.
+func (u *User) IsMFAEnabled() bool {
+	return u.totpSecret != ""
+}
+
+// TOTPSecret returns the secret of the user's confirmed TOTP enrollment, if any.
+func (u *User) TOTPSecret() string {
+	return u.totpSecret
+}
+
+// PendingTOTPSecret returns the secret of the TOTP enrollment waiting for confirmation, if any.
+func (u *User) PendingTOTPSecret() string {
+	return u.pendingTOTPSecret
+}
+
+// TOTPLastStep returns the time step of the last TOTP code accepted from the user.
+func (u *User) TOTPLastStep() int64 {
+	return u.totpLastStep
+}
+
+// RecoveryCodeHashes returns the hashes of the user's unused recovery codes.
+func (u *User) RecoveryCodeHashes() []string {
+	return append([]string(nil), u.recoveryCodeHashes...)
+}
+
+// ExternalIdentities returns the accounts at identity providers the user signs in with.
+func (u *User) ExternalIdentities() []ExternalIdentity {
+	return append([]ExternalIdentity(nil), u.externalIdentities...)
+}
+
+// LinkExternalIdentity lets the user sign in with an account at an identity provider.
+// Linking an identity twice has no effect.
+func (u *User) LinkExternalIdentity(identity ExternalIdentity) error {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return errdmn.ExternalIdentityEmpty
+	}
+	for _, linked := range u.externalIdentities {
+		if linked == identity {
+			return nil
+		}
+	}
+	u.externalIdentities = append(u.externalIdentities, identity)
+	return nil
 }
 
 // UpdateUsername updates the user's username after validation.
@@ -154,6 +425,122 @@ func (u *User) UpdateUsername(newUsername string) error {
 	return nil
 }
 
+// UpdateDisplayName updates the name the user is shown by after validation.
+// An empty name removes it.
+func (u *User) UpdateDisplayName(displayName string) error {
+	displayName = strings.TrimSpace(displayName)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+		return errdmn.DisplayNameTooLong
+	}
+	u.displayName = displayName
+	return nil
+}
+
+// UpdateTimeZone updates the user's time zone after checking it is a known IANA name.
+// An empty name removes it.
+func (u *User) UpdateTimeZone(timeZone string) error {
+	if timeZone != "" {
+		// LoadLocation also accepts "Local", which means nothing to other machines.
+		if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+			return errdmn.InvalidTimeZone
+		}
+	}
+	u.timeZone = timeZone
+	return nil
+}
+
+// UpdateLocale updates the user's locale after validation, storing the canonical form
+// of the language tag. An empty tag removes it.
+func (u *User) UpdateLocale(locale string) error {
+	if locale == "" {
+		u.locale = ""
+		return nil
+	}
+	canonical, err := normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	u.locale = canonical
+	return nil
+}
+
+// UpdateAvatarURL updates the URL of the user's avatar image after validation.
+// An empty URL removes it.
+func (u *User) UpdateAvatarURL(avatarURL string) error {
+	if avatarURL != "" {
+		if err := validateAvatarURL(avatarURL); err != nil {
+			return err
+		}
+	}
+	u.avatarURL = avatarURL
+	return nil
+}
+
+// UpdateEmail updates the user's email address after validation. A changed address
+// has to be verified again.
+func (u *User) UpdateEmail(newEmail string) error {
+	email := normalizeEmail(newEmail)
+	if err := validateEmail(email); err != nil {
+		return err
+	}
+	if email == u.email {
+		return nil
+	}
+
+	u.email = email
+	u.emailVerified = false
+	u.emailVerificationHash = ""
+	u.emailVerificationExpiresAt = time.Time{}
+	return nil
+}
+
+// StartEmailVerification stores the hash of a secret the user proves they own their email
+// address with, replacing any pending one. The secret can be used until the TTL elapses.
+func (u *User) StartEmailVerification(secret string, ttl time.Duration, secretHasher ihash.Service) error {
+	if u.email == "" {
+		return errdmn.EmailRequired
+	}
+	if u.emailVerified {
+		return errdmn.EmailAlreadyVerified
+	}
+
+	secretHash, err := secretHasher.Hash(secret)
+	if err != nil {
+		return err
+	}
+
+	u.emailVerificationHash = secretHash
+	u.emailVerificationExpiresAt = time.Now().UTC().Add(ttl)
+	return nil
+}
+
+// VerifyEmail marks the email address as verified if the secret matches the pending one
+// and has not expired at the given time. The secret cannot be used again.
+func (u *User) VerifyEmail(secret string, now time.Time, secretHasher ihash.Service) error {
+	if u.emailVerified {
+		return errdmn.EmailAlreadyVerified
+	}
+	if u.emailVerificationHash == "" {
+		return errdmn.InvalidVerificationToken
+	}
+
+	matches, err := secretHasher.Match(u.emailVerificationHash, secret)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return errdmn.InvalidVerificationToken
+	}
+	if !now.Before(u.emailVerificationExpiresAt) {
+		return errdmn.VerificationTokenExpired
+	}
+
+	u.emailVerified = true
+	u.emailVerificationHash = ""
+	u.emailVerificationExpiresAt = time.Time{}
+	return nil
+}
+
 // UpdatePassword updates the user's password after validation.
 func (u *User) UpdatePassword(newPassword string, passwordHasher ihash.Service) error {
 	if err := validatePassword(newPassword); err != nil {
@@ -169,7 +556,110 @@ func (u *User) UpdatePassword(newPassword string, passwordHasher ihash.Service)
 	return nil
 }
 
-// UpdateAdminStatus updates the user's admin status.
-func (u *User) UpdateAdminStatus(isAdmin bool) {
-	u.isAdmin = isAdmin
+// UpdateRole gives the user another role after validation.
+func (u *User) UpdateRole(role rolemodel.Role) error {
+	if !role.IsValid() {
+		return errdmn.InvalidRole
+	}
+	u.role = role
+	return nil
+}
+
+// Deactivate keeps the user from signing in until they are reactivated.
+func (u *User) Deactivate() {
+	u.deactivated = true
+}
+
+// Reactivate lets a deactivated user sign in again.
+func (u *User) Reactivate() {
+	u.deactivated = false
+}
+
+// StartTOTPEnrollment stores the secret of a new TOTP enrollment, replacing any pending
+// one. Two-factor authentication is enabled once the enrollment is confirmed.
+func (u *User) StartTOTPEnrollment(secret string) error {
+	if u.IsMFAEnabled() {
+		return errdmn.MFAAlreadyEnabled
+	}
+	if secret == "" {
+		return errdmn.TOTPSecretEmpty
+	}
+
+	u.pendingTOTPSecret = secret
+	return nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication with the pending TOTP secret,
+// once a code generated from it at the given time step was checked. The recovery codes
+// replace any previous ones; only their hashes are kept.
+func (u *User) ConfirmTOTPEnrollment(step int64, recoveryCodes []string, codeHasher ihash.Service) error {
+	if u.IsMFAEnabled() {
+		return errdmn.MFAAlreadyEnabled
+	}
+	if u.pendingTOTPSecret == "" {
+		return errdmn.MFAEnrollmentNotStarted
+	}
+
+	codeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		codeHash, err := codeHasher.Hash(code)
+		if err != nil {
+			return err
+		}
+		codeHashes = append(codeHashes, codeHash)
+	}
+
+	u.totpSecret = u.pendingTOTPSecret
+	u.pendingTOTPSecret = ""
+	u.totpLastStep = step
+	u.recoveryCodeHashes = codeHashes
+	return nil
+}
+
+// UseTOTPStep records that a valid TOTP code of the given time step was used. A code
+// is accepted once: codes of the same or an earlier step are refused.
+func (u *User) UseTOTPStep(step int64) error {
+	if !u.IsMFAEnabled() {
+		return errdmn.MFANotEnabled
+	}
+	if step <= u.totpLastStep {
+		return errdmn.InvalidMFACode
+	}
+
+	u.totpLastStep = step
+	return nil
+}
+
+// UseRecoveryCode checks the code against the unused recovery codes. A matching code
+// is removed so it cannot be used again.
+func (u *User) UseRecoveryCode(code string, codeHasher ihash.Service) error {
+	if !u.IsMFAEnabled() {
+		return errdmn.MFANotEnabled
+	}
+
+	for i, codeHash := range u.recoveryCodeHashes {
+		matches, err := codeHasher.Match(codeHash, code)
+		if err != nil {
+			return err
+		}
+		if matches {
+			u.recoveryCodeHashes = append(u.recoveryCodeHashes[:i:i], u.recoveryCodeHashes[i+1:]...)
+			return nil
+		}
+	}
+	return errdmn.InvalidMFACode
+}
+
+// DisableMFA turns two-factor authentication off, discarding the TOTP secret and the
+// recovery codes.
+func (u *User) DisableMFA() error {
+	if !u.IsMFAEnabled() {
+		return errdmn.MFANotEnabled
+	}
+
+	u.totpSecret = ""
+	u.pendingTOTPSecret = ""
+	u.totpLastStep = 0
+	u.recoveryCodeHashes = nil
+	return nil
 }
@@ -1,10 +1,13 @@
 package usermodel_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	ihash_mock "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -33,7 +36,6 @@ func (suite *UserModelSuite) SetupTest() {
 	suite.validConfig = usermodel.Config{
 		Username:       suite.validUsername,
 		PlainPassword:  suite.strongPassword,
-		IsAdmin:        false,
 		PasswordHasher: suite.mockHasher,
 	}
 
@@ -50,10 +52,27 @@ func (suite *UserModelSuite) TestNewUser() {
 		suite.NotNil(user)
 		suite.Equal(suite.validConfig.Username, user.Username())
 		suite.Equal("hashedStrongPassword", user.PasswordHash())
+		suite.Equal(rolemodel.Default, user.Role())
 		suite.False(user.IsAdmin())
 		suite.NotEqual(uuid.Nil, user.ID())
 	})
 
+	suite.Run("should create a user with the given role", func() {
+		config := suite.validConfig
+		config.Role = rolemodel.Admin
+		user, err := usermodel.New(config)
+		suite.NoError(err)
+		suite.True(user.IsAdmin())
+	})
+
+	suite.Run("should return error if role is invalid", func() {
+		config := suite.validConfig
+		config.Role = "root"
+		user, err := usermodel.New(config)
+		suite.Nil(user)
+		suite.Equal(errdmn.InvalidRole, err)
+	})
+
 	suite.Run("should return error if username is invalid", func() {
 		invalidConfig := suite.validConfig
 		invalidConfig.Username = suite.invalidUsername
@@ -71,6 +90,64 @@ func (suite *UserModelSuite) TestNewUser() {
 	})
 }
 
+func (suite *UserModelSuite) TestNewExternalUser() {
+	identity := usermodel.ExternalIdentity{Issuer: "https://sso.example.com", Subject: "248289761001"}
+	config := usermodel.ExternalConfig{
+		Username:      "jane_doe",
+		Email:         " Jane@Example.com ",
+		EmailVerified: true,
+		Identity:      identity,
+	}
+
+	suite.Run("should create a user without a password", func() {
+		user, err := usermodel.NewExternal(config)
+		suite.NoError(err)
+		suite.Equal("jane_doe", user.Username())
+		suite.Equal("jane@example.com", user.Email())
+		suite.True(user.IsEmailVerified())
+		suite.False(user.HasPassword())
+		suite.Equal(rolemodel.Default, user.Role())
+		suite.Equal([]usermodel.ExternalIdentity{identity}, user.ExternalIdentities())
+	})
+
+	suite.Run("should not mark a missing email as verified", func() {
+		noEmail := config
+		noEmail.Email = ""
+		user, err := usermodel.NewExternal(noEmail)
+		suite.NoError(err)
+		suite.False(user.IsEmailVerified())
+	})
+
+	suite.Run("should return error if identity is incomplete", func() {
+		invalid := config
+		invalid.Identity.Subject = ""
+		user, err := usermodel.NewExternal(invalid)
+		suite.Nil(user)
+		suite.Equal(errdmn.ExternalIdentityEmpty, err)
+	})
+
+	suite.Run("should return error if username is invalid", func() {
+		invalid := config
+		invalid.Username = suite.invalidUsername
+		user, err := usermodel.NewExternal(invalid)
+		suite.Nil(user)
+		suite.Equal(errdmn.UsernameInvalidFormat, err)
+	})
+}
+
+func (suite *UserModelSuite) TestUser_LinkExternalIdentity() {
+	identity := usermodel.ExternalIdentity{Issuer: "https://sso.example.com", Subject: "248289761001"}
+
+	suite.True(suite.user.HasPassword())
+	suite.Empty(suite.user.ExternalIdentities())
+
+	suite.NoError(suite.user.LinkExternalIdentity(identity))
+	suite.NoError(suite.user.LinkExternalIdentity(identity))
+	suite.Equal([]usermodel.ExternalIdentity{identity}, suite.user.ExternalIdentities())
+
+	suite.Equal(errdmn.ExternalIdentityEmpty, suite.user.LinkExternalIdentity(usermodel.ExternalIdentity{Issuer: identity.Issuer}))
+}
+
 func (suite *UserModelSuite) TestUser_UpdateUsername() {
 	suite.Run("should update the username if valid", func() {
 		newUsername := "new_valid_user"
@@ -100,30 +177,232 @@ func (suite *UserModelSuite) TestUser_UpdatePassword() {
 	})
 }
 
-func (suite *UserModelSuite) TestUser_UpdateAdminStatus() {
-	suite.Run("should update the admin status", func() {
-		suite.user.UpdateAdminStatus(true)
+func (suite *UserModelSuite) TestUser_UpdateRole() {
+	suite.Run("should update the role", func() {
+		suite.NoError(suite.user.UpdateRole(rolemodel.Admin))
 		suite.True(suite.user.IsAdmin())
 
-		suite.user.UpdateAdminStatus(false)
+		suite.NoError(suite.user.UpdateRole(rolemodel.Viewer))
+		suite.Equal(rolemodel.Viewer, suite.user.Role())
 		suite.False(suite.user.IsAdmin())
 	})
+
+	suite.Run("should refuse an invalid role", func() {
+		suite.Equal(errdmn.InvalidRole, suite.user.UpdateRole("root"))
+		suite.Equal(rolemodel.Viewer, suite.user.Role())
+	})
+}
+
+func (suite *UserModelSuite) TestNewUser_Email() {
+	suite.Run("should normalize the email", func() {
+		config := suite.validConfig
+		config.Email = "  Valid.User@Example.com "
+		user, err := usermodel.New(config)
+		suite.NoError(err)
+		suite.Equal("valid.user@example.com", user.Email())
+		suite.False(user.IsEmailVerified())
+	})
+
+	suite.Run("should return error if email is invalid", func() {
+		for _, email := range []string{"not-an-email", "User <user@example.com>", "user@example.com, other@example.com"} {
+			config := suite.validConfig
+			config.Email = email
+			user, err := usermodel.New(config)
+			suite.Nil(user)
+			suite.Equal(errdmn.EmailInvalidFormat, err, email)
+		}
+	})
+}
+
+func (suite *UserModelSuite) TestUser_VerifyEmail() {
+	suite.Require().NoError(suite.user.UpdateEmail("valid.user@example.com"))
+	suite.mockHasher.On("Hash", "secret").Return("hashedSecret", nil)
+	suite.mockHasher.On("Match", "hashedSecret", "secret").Return(true, nil)
+	suite.mockHasher.On("Match", "hashedSecret", "guess").Return(false, nil)
+
+	suite.Run("should reject a verification that was not started", func() {
+		err := suite.user.VerifyEmail("secret", time.Now(), suite.mockHasher)
+		suite.Equal(errdmn.InvalidVerificationToken, err)
+	})
+
+	suite.NoError(suite.user.StartEmailVerification("secret", time.Hour, suite.mockHasher))
+	suite.Equal("hashedSecret", suite.user.EmailVerificationHash())
+
+	suite.Run("should reject a wrong secret", func() {
+		err := suite.user.VerifyEmail("guess", time.Now(), suite.mockHasher)
+		suite.Equal(errdmn.InvalidVerificationToken, err)
+	})
+
+	suite.Run("should reject an expired secret", func() {
+		err := suite.user.VerifyEmail("secret", time.Now().Add(2*time.Hour), suite.mockHasher)
+		suite.Equal(errdmn.VerificationTokenExpired, err)
+	})
+
+	suite.Run("should verify the email once", func() {
+		err := suite.user.VerifyEmail("secret", time.Now(), suite.mockHasher)
+		suite.NoError(err)
+		suite.True(suite.user.IsEmailVerified())
+		suite.Empty(suite.user.EmailVerificationHash())
+
+		err = suite.user.VerifyEmail("secret", time.Now(), suite.mockHasher)
+		suite.Equal(errdmn.EmailAlreadyVerified, err)
+		err = suite.user.StartEmailVerification("secret", time.Hour, suite.mockHasher)
+		suite.Equal(errdmn.EmailAlreadyVerified, err)
+	})
+
+	suite.Run("should require verifying a changed email again", func() {
+		suite.NoError(suite.user.UpdateEmail("VALID.USER@example.com"))
+		suite.True(suite.user.IsEmailVerified())
+
+		suite.NoError(suite.user.UpdateEmail("other@example.com"))
+		suite.Equal("other@example.com", suite.user.Email())
+		suite.False(suite.user.IsEmailVerified())
+	})
+}
+
+func (suite *UserModelSuite) TestUser_StartEmailVerification_NoEmail() {
+	err := suite.user.StartEmailVerification("secret", time.Hour, suite.mockHasher)
+	suite.Equal(errdmn.EmailRequired, err)
+}
+
+func (suite *UserModelSuite) TestUser_TOTPEnrollment() {
+	suite.mockHasher.On("Hash", "code-1").Return("hashedCode1", nil)
+	suite.mockHasher.On("Hash", "code-2").Return("hashedCode2", nil)
+
+	suite.Run("should not confirm an enrollment that was not started", func() {
+		err := suite.user.ConfirmTOTPEnrollment(100, []string{"code-1"}, suite.mockHasher)
+		suite.Equal(errdmn.MFAEnrollmentNotStarted, err)
+	})
+
+	suite.Run("should require a secret", func() {
+		suite.Equal(errdmn.TOTPSecretEmpty, suite.user.StartTOTPEnrollment(""))
+	})
+
+	suite.NoError(suite.user.StartTOTPEnrollment("SECRET"))
+	suite.False(suite.user.IsMFAEnabled())
+	suite.Equal("SECRET", suite.user.PendingTOTPSecret())
+
+	suite.Run("should enable two-factor authentication on confirmation", func() {
+		err := suite.user.ConfirmTOTPEnrollment(100, []string{"code-1", "code-2"}, suite.mockHasher)
+		suite.NoError(err)
+		suite.True(suite.user.IsMFAEnabled())
+		suite.Equal("SECRET", suite.user.TOTPSecret())
+		suite.Empty(suite.user.PendingTOTPSecret())
+		suite.Equal(int64(100), suite.user.TOTPLastStep())
+		suite.Equal([]string{"hashedCode1", "hashedCode2"}, suite.user.RecoveryCodeHashes())
+	})
+
+	suite.Run("should not enroll twice", func() {
+		suite.Equal(errdmn.MFAAlreadyEnabled, suite.user.StartTOTPEnrollment("OTHER"))
+	})
+}
+
+func (suite *UserModelSuite) TestUser_UseTOTPStep() {
+	suite.Equal(errdmn.MFANotEnabled, suite.user.UseTOTPStep(100))
+
+	user := usermodel.FromBSON(&usermodel.UserBSON{ID: uuid.New(), TOTPSecret: "SECRET", TOTPLastStep: 100})
+	suite.Equal(errdmn.InvalidMFACode, user.UseTOTPStep(99))
+	suite.Equal(errdmn.InvalidMFACode, user.UseTOTPStep(100))
+	suite.NoError(user.UseTOTPStep(101))
+	suite.Equal(int64(101), user.TOTPLastStep())
+	suite.Equal(errdmn.InvalidMFACode, user.UseTOTPStep(101))
+}
+
+func (suite *UserModelSuite) TestUser_UseRecoveryCode() {
+	suite.mockHasher.On("Match", "hashedCode1", "code-2").Return(false, nil)
+	suite.mockHasher.On("Match", "hashedCode2", "code-2").Return(true, nil)
+	suite.mockHasher.On("Match", "hashedCode1", "guess").Return(false, nil)
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                 uuid.New(),
+		TOTPSecret:         "SECRET",
+		RecoveryCodeHashes: []string{"hashedCode1", "hashedCode2"},
+	})
+
+	suite.NoError(user.UseRecoveryCode("code-2", suite.mockHasher))
+	suite.Equal([]string{"hashedCode1"}, user.RecoveryCodeHashes())
+	suite.Equal(errdmn.InvalidMFACode, user.UseRecoveryCode("code-2", suite.mockHasher))
+	suite.Equal(errdmn.InvalidMFACode, user.UseRecoveryCode("guess", suite.mockHasher))
+}
+
+func (suite *UserModelSuite) TestUser_DisableMFA() {
+	suite.Equal(errdmn.MFANotEnabled, suite.user.DisableMFA())
+
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                 uuid.New(),
+		TOTPSecret:         "SECRET",
+		TOTPLastStep:       100,
+		RecoveryCodeHashes: []string{"hashedCode1"},
+	})
+	suite.NoError(user.DisableMFA())
+	suite.False(user.IsMFAEnabled())
+	suite.Zero(user.TOTPLastStep())
+	suite.Empty(user.RecoveryCodeHashes())
+}
+
+func (suite *UserModelSuite) TestUser_UpdateProfile() {
+	suite.Run("should update valid profile details", func() {
+		suite.NoError(suite.user.UpdateDisplayName("  Beka Birhanu "))
+		suite.NoError(suite.user.UpdateTimeZone("Africa/Addis_Ababa"))
+		suite.NoError(suite.user.UpdateLocale("en_us"))
+		suite.NoError(suite.user.UpdateAvatarURL("https://example.com/avatar.png"))
+
+		suite.Equal("Beka Birhanu", suite.user.DisplayName())
+		suite.Equal("Africa/Addis_Ababa", suite.user.TimeZone())
+		suite.Equal("en-US", suite.user.Locale())
+		suite.Equal("https://example.com/avatar.png", suite.user.AvatarURL())
+	})
+
+	suite.Run("should refuse invalid profile details", func() {
+		suite.Equal(errdmn.DisplayNameTooLong, suite.user.UpdateDisplayName(strings.Repeat("a", 51)))
+		suite.Equal(errdmn.InvalidTimeZone, suite.user.UpdateTimeZone("Mars/Olympus_Mons"))
+		suite.Equal(errdmn.InvalidTimeZone, suite.user.UpdateTimeZone("Local"))
+		suite.Equal(errdmn.InvalidLocale, suite.user.UpdateLocale("not a locale"))
+		suite.Equal(errdmn.InvalidAvatarURL, suite.user.UpdateAvatarURL("javascript:alert(1)"))
+		suite.Equal(errdmn.InvalidAvatarURL, suite.user.UpdateAvatarURL("/avatar.png"))
+		suite.Equal("Beka Birhanu", suite.user.DisplayName())
+	})
+
+	suite.Run("should clear profile details", func() {
+		suite.NoError(suite.user.UpdateDisplayName(""))
+		suite.NoError(suite.user.UpdateTimeZone(""))
+		suite.NoError(suite.user.UpdateLocale(""))
+		suite.NoError(suite.user.UpdateAvatarURL(""))
+
+		suite.Empty(suite.user.DisplayName())
+		suite.Empty(suite.user.TimeZone())
+		suite.Empty(suite.user.Locale())
+		suite.Empty(suite.user.AvatarURL())
+	})
+}
+
+func (suite *UserModelSuite) TestUser_Deactivate() {
+	suite.False(suite.user.IsDeactivated())
+
+	suite.user.Deactivate()
+	suite.True(suite.user.IsDeactivated())
+
+	suite.user.Reactivate()
+	suite.False(suite.user.IsDeactivated())
 }
 
 func (suite *UserModelSuite) TestFromBSON() {
 	bsonUser := &usermodel.UserBSON{
 		ID:           uuid.New(),
 		Username:     "bson_user",
+		Email:        "bson_user@example.com",
 		PasswordHash: "hashedPassword",
-		IsAdmin:      false,
+		Role:         rolemodel.Manager,
+		Deactivated:  true,
 	}
 
 	suite.Run("should convert BSON to user", func() {
 		user := usermodel.FromBSON(bsonUser)
 		suite.Equal(bsonUser.ID, user.ID())
 		suite.Equal(bsonUser.Username, user.Username())
+		suite.Equal(bsonUser.Email, user.Email())
 		suite.Equal(bsonUser.PasswordHash, user.PasswordHash())
-		suite.Equal(bsonUser.IsAdmin, user.IsAdmin())
+		suite.Equal(bsonUser.Role, user.Role())
+		suite.True(user.IsDeactivated())
 	})
 }
 
@@ -0,0 +1,293 @@
+/*
+Package workflowmodel defines the `Workflow` aggregate, the states the tasks of a
+project go through and the transitions allowed between them.
+
+Tasks that belong to no project, or to a project without a workflow of its own,
+follow the default workflow: pending, inprogress, review and done, where a task
+in review goes back to inprogress when changes are needed and a done task is
+reopened by moving it back to inprogress.
+
+The final states of a workflow are those a task is finished in: a task only reaches
+one once all of its subtasks have. Unless given, the final state is done for
+workflows that have it, and workflows without it have none.
+
+Key Components:
+  - Workflow: Represents the states and allowed transitions of the tasks of a project.
+  - Transition: A move allowed from one state to another.
+  - Config: Holds parameters required to define a Workflow.
+  - New: Creates a new Workflow, validating its states and transitions.
+  - Default: Returns the workflow of tasks whose project has none.
+  - WorkflowBSON: Represents the BSON format of a Workflow for MongoDB operations.
+  - FromBSON: Converts a BSON representation back to a Workflow.
+*/
+package workflowmodel
+
+import (
+	"regexp"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// States of the default workflow.
+const (
+	StatePending    = "pending"
+	StateInProgress = "inprogress"
+	StateReview     = "review"
+	StateDone       = "done"
+)
+
+const (
+	// MaxStates is the largest number of states a workflow may have.
+	MaxStates = 20
+
+	// maxProjectLength is the maximum number of characters in the name of a project.
+	maxProjectLength = 50
+
+	// maxStateLength is the maximum number of characters in the name of a state.
+	maxStateLength = 30
+)
+
+// namePattern matches the names of projects and states.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Transition is a move allowed from one state to another.
+type Transition struct {
+	From string `bson:"from"`
+	To   string `bson:"to"`
+}
+
+// Workflow represents the aggregate workflow with private fields.
+type Workflow struct {
+	project     string
+	states      []string
+	initial     string
+	final       []string
+	transitions []Transition
+}
+
+// WorkflowBSON represents the BSON version of the Workflow for database storage.
+// A project has at most one workflow, so the project is the ID.
+type WorkflowBSON struct {
+	Project     string       `bson:"_id"`
+	States      []string     `bson:"states"`
+	Initial     string       `bson:"initial"`
+	Final       []string     `bson:"final"` // Missing for workflows stored before final states were recorded.
+	Transitions []Transition `bson:"transitions"`
+	UpdatedAt   time.Time    `bson:"updatedAt"`
+}
+
+// Config holds parameters for defining a new Workflow.
+type Config struct {
+	Project     string
+	States      []string
+	Initial     string   // State new tasks start in; the first state when empty.
+	Final       []string // States tasks are finished in; done, if the workflow has it, when empty.
+	Transitions []Transition
+}
+
+// New creates a new Workflow with the provided configuration.
+// Repeated transitions and final states are kept once.
+func New(config Config) (*Workflow, error) {
+	if config.Project == "" {
+		return nil, errdmn.InvalidProject
+	}
+	if err := ValidateProject(config.Project); err != nil {
+		return nil, err
+	}
+
+	states, err := validateStates(config.States)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &Workflow{project: config.Project, states: states, initial: config.Initial}
+	if workflow.initial == "" {
+		workflow.initial = states[0]
+	}
+	if !workflow.HasState(workflow.initial) {
+		return nil, errdmn.InvalidWorkflowInitial
+	}
+
+	workflow.final = defaultFinal(states)
+	if len(config.Final) > 0 {
+		workflow.final = []string{}
+		for _, state := range config.Final {
+			if !workflow.HasState(state) {
+				return nil, errdmn.InvalidWorkflowFinal
+			}
+			if !workflow.IsFinal(state) {
+				workflow.final = append(workflow.final, state)
+			}
+		}
+	}
+
+	workflow.transitions = []Transition{}
+	for _, transition := range config.Transitions {
+		if transition.From == transition.To || !workflow.HasState(transition.From) || !workflow.HasState(transition.To) {
+			return nil, errdmn.InvalidWorkflowTransition
+		}
+		if !workflow.allows(transition.From, transition.To) {
+			workflow.transitions = append(workflow.transitions, transition)
+		}
+	}
+
+	return workflow, nil
+}
+
+// Default returns the workflow followed by tasks whose project has no workflow of its own.
+func Default() *Workflow {
+	return &Workflow{
+		states:  []string{StatePending, StateInProgress, StateReview, StateDone},
+		initial: StatePending,
+		final:   []string{StateDone},
+		transitions: []Transition{
+			{From: StatePending, To: StateInProgress},
+			{From: StateInProgress, To: StatePending},
+			{From: StateInProgress, To: StateReview},
+			{From: StateReview, To: StateInProgress},
+			{From: StateReview, To: StateDone},
+			{From: StateDone, To: StateInProgress},
+		},
+	}
+}
+
+// defaultFinal returns the final states of a workflow with the given states that was
+// given none: done when it is one of them.
+func defaultFinal(states []string) []string {
+	for _, state := range states {
+		if state == StateDone {
+			return []string{StateDone}
+		}
+	}
+	return []string{}
+}
+
+// ValidateProject checks the name of a project. The empty name stands for no project.
+func ValidateProject(project string) error {
+	if project == "" {
+		return nil
+	}
+	if len(project) > maxProjectLength || !namePattern.MatchString(project) {
+		return errdmn.InvalidProject
+	}
+	return nil
+}
+
+// validateStates checks the names of the states of a workflow.
+func validateStates(states []string) ([]string, error) {
+	if len(states) == 0 {
+		return nil, errdmn.WorkflowStatesEmpty
+	}
+	if len(states) > MaxStates {
+		return nil, errdmn.TooManyWorkflowStates
+	}
+
+	seen := make(map[string]bool, len(states))
+	for _, state := range states {
+		if len(state) > maxStateLength || !namePattern.MatchString(state) {
+			return nil, errdmn.InvalidWorkflowState
+		}
+		if seen[state] {
+			return nil, errdmn.DuplicateWorkflowState
+		}
+		seen[state] = true
+	}
+	return append([]string{}, states...), nil
+}
+
+// ToBSON converts a Workflow to a WorkflowBSON.
+func (w *Workflow) ToBSON() *WorkflowBSON {
+	return &WorkflowBSON{
+		Project:     w.project,
+		States:      w.States(),
+		Initial:     w.initial,
+		Final:       w.Final(),
+		Transitions: w.Transitions(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// FromBSON converts a WorkflowBSON to a Workflow.
+// Workflows stored without final states have the default ones.
+func FromBSON(bson *WorkflowBSON) *Workflow {
+	final := append([]string{}, bson.Final...)
+	if bson.Final == nil {
+		final = defaultFinal(bson.States)
+	}
+	return &Workflow{
+		project:     bson.Project,
+		states:      append([]string{}, bson.States...),
+		initial:     bson.Initial,
+		final:       final,
+		transitions: append([]Transition{}, bson.Transitions...),
+	}
+}
+
+// Project returns the project the workflow belongs to, or an empty string for the default workflow.
+func (w *Workflow) Project() string {
+	return w.project
+}
+
+// States returns the states of the workflow, in the order they were defined.
+func (w *Workflow) States() []string {
+	return append([]string{}, w.states...)
+}
+
+// Initial returns the state new tasks start in.
+func (w *Workflow) Initial() string {
+	return w.initial
+}
+
+// Final returns the states tasks are finished in, in the order they were defined.
+func (w *Workflow) Final() []string {
+	return append([]string{}, w.final...)
+}
+
+// IsFinal reports whether tasks in the given state are finished.
+func (w *Workflow) IsFinal(state string) bool {
+	for _, s := range w.final {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Transitions returns the transitions allowed between the states of the workflow.
+func (w *Workflow) Transitions() []Transition {
+	return append([]Transition{}, w.transitions...)
+}
+
+// HasState reports whether the workflow has the given state.
+func (w *Workflow) HasState(state string) bool {
+	for _, s := range w.states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether a task may move from one state to another.
+// Tasks left in a state the workflow does not have, because their project changed
+// workflow, may only move to the initial state.
+func (w *Workflow) CanTransition(from, to string) bool {
+	if !w.HasState(to) {
+		return false
+	}
+	if !w.HasState(from) {
+		return to == w.initial
+	}
+	return w.allows(from, to)
+}
+
+// allows reports whether the transition from one state to another is defined.
+func (w *Workflow) allows(from, to string) bool {
+	for _, t := range w.transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
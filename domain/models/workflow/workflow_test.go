@@ -0,0 +1,154 @@
+package workflowmodel_test
+
+import (
+	"strings"
+	"testing"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkflowModelSuite struct {
+	suite.Suite
+	validConfig workflowmodel.Config
+}
+
+func (suite *WorkflowModelSuite) SetupTest() {
+	suite.validConfig = workflowmodel.Config{
+		Project: "website",
+		States:  []string{"todo", "doing", "qa", "shipped"},
+		Transitions: []workflowmodel.Transition{
+			{From: "todo", To: "doing"},
+			{From: "doing", To: "qa"},
+			{From: "qa", To: "doing"},
+			{From: "qa", To: "shipped"},
+		},
+	}
+}
+
+func (suite *WorkflowModelSuite) TestNew() {
+	suite.Run("should create a workflow starting in its first state", func() {
+		workflow, err := workflowmodel.New(suite.validConfig)
+		suite.NoError(err)
+		suite.Equal("website", workflow.Project())
+		suite.Equal(suite.validConfig.States, workflow.States())
+		suite.Equal("todo", workflow.Initial())
+		suite.Equal(suite.validConfig.Transitions, workflow.Transitions())
+	})
+
+	suite.Run("should keep repeated transitions once", func() {
+		config := suite.validConfig
+		config.Transitions = append(config.Transitions, workflowmodel.Transition{From: "todo", To: "doing"})
+		workflow, err := workflowmodel.New(config)
+		suite.NoError(err)
+		suite.Len(workflow.Transitions(), 4)
+	})
+
+	suite.Run("should finish tasks in the given states", func() {
+		config := suite.validConfig
+		config.Final = []string{"shipped", "shipped"}
+		workflow, err := workflowmodel.New(config)
+		suite.NoError(err)
+		suite.Equal([]string{"shipped"}, workflow.Final())
+		suite.True(workflow.IsFinal("shipped"))
+		suite.False(workflow.IsFinal("qa"))
+	})
+
+	suite.Run("should finish tasks in done unless told otherwise", func() {
+		workflow, err := workflowmodel.New(suite.validConfig)
+		suite.NoError(err)
+		suite.Empty(workflow.Final())
+
+		config := suite.validConfig
+		config.States = append([]string{}, config.States...)
+		config.States[3] = workflowmodel.StateDone
+		config.Transitions = nil
+		workflow, err = workflowmodel.New(config)
+		suite.NoError(err)
+		suite.Equal([]string{workflowmodel.StateDone}, workflow.Final())
+	})
+
+	suite.Run("should refuse invalid workflows", func() {
+		cases := map[string]struct {
+			edit func(*workflowmodel.Config)
+			err  error
+		}{
+			"no project":      {func(c *workflowmodel.Config) { c.Project = "" }, errdmn.InvalidProject},
+			"invalid project": {func(c *workflowmodel.Config) { c.Project = "Web Site" }, errdmn.InvalidProject},
+			"long project":    {func(c *workflowmodel.Config) { c.Project = strings.Repeat("a", 51) }, errdmn.InvalidProject},
+			"no states":       {func(c *workflowmodel.Config) { c.States = nil }, errdmn.WorkflowStatesEmpty},
+			"too many states": {func(c *workflowmodel.Config) { c.States = manyStates(21) }, errdmn.TooManyWorkflowStates},
+			"invalid state":   {func(c *workflowmodel.Config) { c.States = []string{"todo", "In Review"} }, errdmn.InvalidWorkflowState},
+			"repeated state":  {func(c *workflowmodel.Config) { c.States = []string{"todo", "todo"} }, errdmn.DuplicateWorkflowState},
+			"unknown initial": {func(c *workflowmodel.Config) { c.Initial = "backlog" }, errdmn.InvalidWorkflowInitial},
+			"unknown final":   {func(c *workflowmodel.Config) { c.Final = []string{"archived"} }, errdmn.InvalidWorkflowFinal},
+			"unknown target": {func(c *workflowmodel.Config) {
+				c.Transitions = []workflowmodel.Transition{{From: "todo", To: "backlog"}}
+			}, errdmn.InvalidWorkflowTransition},
+			"transition to itself": {func(c *workflowmodel.Config) { c.Transitions = []workflowmodel.Transition{{From: "qa", To: "qa"}} }, errdmn.InvalidWorkflowTransition},
+		}
+		for name, tc := range cases {
+			config := suite.validConfig
+			tc.edit(&config)
+			workflow, err := workflowmodel.New(config)
+			suite.Nil(workflow, name)
+			suite.Equal(tc.err, err, name)
+		}
+	})
+}
+
+func (suite *WorkflowModelSuite) TestCanTransition() {
+	workflow, err := workflowmodel.New(suite.validConfig)
+	suite.Require().NoError(err)
+
+	suite.True(workflow.CanTransition("todo", "doing"))
+	suite.False(workflow.CanTransition("doing", "todo"))
+	suite.False(workflow.CanTransition("todo", "todo"))
+	suite.False(workflow.CanTransition("shipped", "archived"))
+
+	suite.Run("should only move tasks in unknown states to the initial state", func() {
+		suite.True(workflow.CanTransition("inprogress", "todo"))
+		suite.False(workflow.CanTransition("inprogress", "doing"))
+	})
+}
+
+func (suite *WorkflowModelSuite) TestDefault() {
+	workflow := workflowmodel.Default()
+	suite.Empty(workflow.Project())
+	suite.Equal(workflowmodel.StatePending, workflow.Initial())
+
+	suite.True(workflow.CanTransition(workflowmodel.StatePending, workflowmodel.StateInProgress))
+	suite.True(workflow.CanTransition(workflowmodel.StateInProgress, workflowmodel.StateReview))
+	suite.True(workflow.CanTransition(workflowmodel.StateReview, workflowmodel.StateDone))
+	suite.True(workflow.CanTransition(workflowmodel.StateDone, workflowmodel.StateInProgress))
+	suite.False(workflow.CanTransition(workflowmodel.StatePending, workflowmodel.StateDone))
+	suite.Equal([]string{workflowmodel.StateDone}, workflow.Final())
+}
+
+func (suite *WorkflowModelSuite) TestBSON() {
+	workflow, err := workflowmodel.New(suite.validConfig)
+	suite.Require().NoError(err)
+
+	bson := workflow.ToBSON()
+	suite.Equal("website", bson.Project)
+	suite.Equal(workflow, workflowmodel.FromBSON(bson))
+
+	suite.Run("should finish tasks in done for workflows stored without final states", func() {
+		bson := workflowmodel.Default().ToBSON()
+		bson.Final = nil
+		suite.Equal([]string{workflowmodel.StateDone}, workflowmodel.FromBSON(bson).Final())
+	})
+}
+
+func manyStates(n int) []string {
+	states := make([]string, n)
+	for i := range states {
+		states[i] = "s" + strings.Repeat("x", i)
+	}
+	return states
+}
+
+func TestWorkflowModelSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowModelSuite))
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"sync"
 
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -44,18 +46,166 @@ func Connect(config Config) *mongo.Client {
 // Migrate performs database migrations such as creating indexes if they do not already exist.
 func Migrate(client *mongo.Client, dbName string) {
 	database := client.Database(dbName)
-	usersCollection := database.Collection("users")
 
-	// Define the index model
-	indexModel := mongo.IndexModel{
+	migrateAdminStatus(database.Collection("users"))
+	migrateTaskPriority(database.Collection("tasks"))
+
+	ensureIndex(database.Collection("users"), "username_1", mongo.IndexModel{
 		Keys: bson.M{
 			"username": 1, // 1 for ascending order
 		},
 		Options: options.Index().SetUnique(true),
+	})
+
+	// Only users with an email are indexed, so any number of users can have none.
+	ensureIndex(database.Collection("users"), "email_1", mongo.IndexModel{
+		Keys: bson.M{"email": 1},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+	})
+
+	// A user signs in with an account at an identity provider, which is linked to
+	// nobody else. Only users with linked accounts are indexed.
+	ensureIndex(database.Collection("users"), "externalIdentities_1", mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "externalIdentities.issuer", Value: 1},
+			{Key: "externalIdentities.subject", Value: 1},
+		},
+		Options: options.Index().
+			SetName("externalIdentities_1").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"externalIdentities": bson.M{"$exists": true}}),
+	})
+
+	// Admins are counted to make sure the last one is never demoted.
+	ensureIndex(database.Collection("users"), "role_1", mongo.IndexModel{
+		Keys: bson.M{"role": 1},
+	})
+
+	// Tasks are looked up by owner when the owner is deleted.
+	ensureIndex(database.Collection("tasks"), "ownerId_1", mongo.IndexModel{
+		Keys: bson.M{"ownerId": 1},
+	})
+
+	// Tasks are listed by assignee, and their assignees removed when the user is deleted.
+	ensureIndex(database.Collection("tasks"), "assigneeIds_1", mongo.IndexModel{
+		Keys: bson.M{"assigneeIds": 1},
+	})
+
+	// Tasks are listed by priority, then due date, unless another order is requested.
+	ensureIndex(database.Collection("tasks"), "priority_1_dueDate_1", mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "priority", Value: 1},
+			{Key: "dueDate", Value: 1},
+		},
+	})
+
+	// Subtasks are looked up and counted by parent.
+	ensureIndex(database.Collection("tasks"), "parentId_1", mongo.IndexModel{
+		Keys: bson.M{"parentId": 1},
+	})
+
+	// Tasks are filtered by tag, and tags counted and renamed across tasks.
+	ensureIndex(database.Collection("tasks"), "tags_1", mongo.IndexModel{
+		Keys: bson.M{"tags": 1},
+	})
+
+	// Text index backing task search; title matches weigh more than description matches.
+	ensureIndex(database.Collection("tasks"), "task_text", mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("task_text").
+			SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "description", Value: 1}}),
+	})
+
+	refreshTokensCollection := database.Collection("refreshTokens")
+	ensureIndex(refreshTokensCollection, "familyId_1", mongo.IndexModel{
+		Keys: bson.M{"familyId": 1},
+	})
+
+	// Expired refresh tokens are removed by MongoDB.
+	ensureIndex(refreshTokensCollection, "expiresAt_1", mongo.IndexModel{
+		Keys:    bson.M{"expiresAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	resetTokensCollection := database.Collection("resetTokens")
+	ensureIndex(resetTokensCollection, "userId_1", mongo.IndexModel{
+		Keys: bson.M{"userId": 1},
+	})
+
+	// Expired password reset tokens are removed by MongoDB.
+	ensureIndex(resetTokensCollection, "expiresAt_1", mongo.IndexModel{
+		Keys:    bson.M{"expiresAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	apiKeysCollection := database.Collection("apiKeys")
+	ensureIndex(apiKeysCollection, "userId_1", mongo.IndexModel{
+		Keys: bson.M{"userId": 1},
+	})
+
+	// Expired API keys are removed by MongoDB; keys without an expiry are kept.
+	ensureIndex(apiKeysCollection, "expiresAt_1", mongo.IndexModel{
+		Keys:    bson.M{"expiresAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	// Failed login attempts are removed by MongoDB once they are forgotten and no lock is left.
+	ensureIndex(database.Collection("loginAttempts"), "expiresAt_1", mongo.IndexModel{
+		Keys:    bson.M{"expiresAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	// Revocations are removed by MongoDB once the revoked tokens would have expired anyway.
+	ensureIndex(database.Collection("revokedTokens"), "expiresAt_1", mongo.IndexModel{
+		Keys:    bson.M{"expiresAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+}
+
+// migrateAdminStatus gives a role to the users stored before roles replaced the
+// isAdmin flag: admins keep the admin role and everyone else becomes a member.
+func migrateAdminStatus(collection *mongo.Collection) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"role": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$isAdmin", true}},
+			string(rolemodel.Admin),
+			string(rolemodel.Member),
+		}}}}},
+		{{Key: "$unset", Value: "isAdmin"}},
 	}
 
+	result, err := collection.UpdateMany(context.TODO(), bson.M{"role": bson.M{"$exists": false}}, update)
+	if err != nil {
+		log.Fatalf("Error migrating admin status to roles: %v", err)
+	}
+	if result.ModifiedCount > 0 {
+		log.Printf("Gave roles to %d users\n", result.ModifiedCount)
+	}
+}
+
+// migrateTaskPriority gives the default priority to the tasks stored before priorities
+// were introduced, so they sort among the others.
+func migrateTaskPriority(collection *mongo.Collection) {
+	update := bson.M{"$set": bson.M{"priority": taskmodel.DefaultPriority}}
+	result, err := collection.UpdateMany(context.TODO(), bson.M{"priority": bson.M{"$exists": false}}, update)
+	if err != nil {
+		log.Fatalf("Error migrating task priorities: %v", err)
+	}
+	if result.ModifiedCount > 0 {
+		log.Printf("Gave the default priority to %d tasks\n", result.ModifiedCount)
+	}
+}
+
+// ensureIndex creates the index on the collection unless an index with the given name already exists.
+func ensureIndex(collection *mongo.Collection, name string, indexModel mongo.IndexModel) {
 	// Check if the index already exists
-	indexNames, err := usersCollection.Indexes().List(context.TODO())
+	indexNames, err := collection.Indexes().List(context.TODO())
 	if err != nil {
 		log.Fatalf("Error listing indexes: %v", err)
 	}
@@ -67,7 +217,7 @@ func Migrate(client *mongo.Client, dbName string) {
 			log.Fatalf("Error decoding index: %v", err)
 		}
 
-		if name, ok := index["name"].(string); ok && name == "username_1" {
+		if indexName, ok := index["name"].(string); ok && indexName == name {
 			indexExists = true
 			break
 		}
@@ -75,14 +225,13 @@ func Migrate(client *mongo.Client, dbName string) {
 
 	if !indexExists {
 		// Create the index
-		indexName, err := usersCollection.Indexes().CreateOne(context.TODO(), indexModel)
+		indexName, err := collection.Indexes().CreateOne(context.TODO(), indexModel)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		log.Printf("Created index %s\n", indexName)
 	} else {
-		log.Println("Index already exists. No changes made.")
+		log.Printf("Index %s already exists. No changes made.\n", name)
 	}
 }
-
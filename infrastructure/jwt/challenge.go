@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"errors"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// challengeAudience is the audience of MFA challenge tokens. Access tokens never
+// carry it, and challenge tokens carry no session, so neither passes for the other.
+const challengeAudience = "mfa_challenge"
+
+// Ensure Service implements ijwt.Challenges.
+var _ ijwt.Challenges = &Service{}
+
+// GenerateChallenge creates an MFA challenge token for the given user.
+func (s *Service) GenerateChallenge(user *usermodel.User) (string, error) {
+	now := s.now().UTC()
+	claims := jwt.RegisteredClaims{
+		Subject:   user.ID().String(),
+		ID:        uuid.NewString(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.challengeExpTime)),
+		Issuer:    s.issuer,
+		Audience:  jwt.ClaimStrings{challengeAudience},
+	}
+	return s.sign(claims, now)
+}
+
+// DecodeChallenge validates an MFA challenge token and returns the ID of its user.
+func (s *Service) DecodeChallenge(tokenString string) (uuid.UUID, error) {
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, s.getSigningKey, s.parserOptions(challengeAudience)...)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !token.Valid {
+		return uuid.Nil, errors.New("invalid token")
+	}
+
+	subject, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid sub claim")
+	}
+	return subject, nil
+}
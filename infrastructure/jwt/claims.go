@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"errors"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// tokenClaims is the JSON form of the access token claims.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Roles     []string `json:"roles,omitempty"`
+	SessionID string   `json:"sid"`
+}
+
+// roles returns the roles granted to the user.
+func roles(user *usermodel.User) []string {
+	if user.Role() == "" {
+		return nil
+	}
+	return []string{string(user.Role())}
+}
+
+// toClaims converts the parsed claims to their typed form, rejecting tokens
+// without a valid subject, session or token ID.
+func (c *tokenClaims) toClaims() (*ijwt.Claims, error) {
+	subject, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, errors.New("invalid sub claim")
+	}
+
+	sessionID, err := uuid.Parse(c.SessionID)
+	if err != nil {
+		return nil, errors.New("invalid sid claim")
+	}
+
+	if c.ID == "" {
+		return nil, errors.New("missing jti claim")
+	}
+
+	claims := &ijwt.Claims{
+		Subject:   subject,
+		Roles:     c.Roles,
+		SessionID: sessionID,
+		TokenID:   c.ID,
+		Issuer:    c.Issuer,
+		Audience:  c.Audience,
+	}
+	if c.IssuedAt != nil {
+		claims.IssuedAt = c.IssuedAt.Time
+	}
+	if c.ExpiresAt != nil {
+		claims.ExpiresAt = c.ExpiresAt.Time
+	}
+	return claims, nil
+}
@@ -0,0 +1,43 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+)
+
+// Ensure Service implements ijwt.KeySet.
+var _ ijwt.KeySet = &Service{}
+
+// PublicKeys returns the asymmetric keys that verify tokens now or will once
+// they activate. Shared secrets are never included.
+func (s *Service) PublicKeys() []ijwt.JWK {
+	now := s.now()
+	jwks := []ijwt.JWK{}
+	for i, key := range s.keys {
+		if key.isSymmetric() || !s.verifies(i, now) {
+			continue
+		}
+		jwks = append(jwks, toJWK(key))
+	}
+	return jwks
+}
+
+// toJWK converts the public part of an asymmetric key to a JWK.
+func toJWK(key *Key) ijwt.JWK {
+	jwk := ijwt.JWK{Kid: key.id, Use: "sig", Alg: key.Algorithm()}
+	switch pk := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pk.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pk.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pk)
+	}
+	return jwk
+}
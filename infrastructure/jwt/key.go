@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a key tokens are signed and verified with, identified in the token by the kid header.
+// A key signs new tokens from its activation time until the next key activates.
+type Key struct {
+	id         string
+	method     jwt.SigningMethod
+	signKey    interface{}
+	verifyKey  interface{}
+	activeFrom time.Time
+}
+
+// NewHMACKey creates an HS256 key from a shared secret.
+// Symmetric keys are never published in the JWKS.
+func NewHMACKey(id string, secret []byte, activeFrom time.Time) *Key {
+	return &Key{
+		id:         id,
+		method:     jwt.SigningMethodHS256,
+		signKey:    secret,
+		verifyKey:  secret,
+		activeFrom: activeFrom,
+	}
+}
+
+// NewKey creates an asymmetric key from a private key.
+// RSA keys accept RS256, RS384, RS512, PS256, PS384 and PS512 and default to RS256;
+// Ed25519 keys accept EdDSA only, which is also their default.
+func NewKey(id string, alg string, privateKey crypto.PrivateKey, activeFrom time.Time) (*Key, error) {
+	if id == "" {
+		return nil, errors.New("key ID is required")
+	}
+
+	key := &Key{id: id, signKey: privateKey, activeFrom: activeFrom}
+	switch pk := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if alg == "" {
+			alg = jwt.SigningMethodRS256.Alg()
+		}
+		switch alg {
+		case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+			key.method = jwt.GetSigningMethod(alg)
+		default:
+			return nil, fmt.Errorf("key %s: algorithm %s does not use RSA keys", id, alg)
+		}
+		key.verifyKey = &pk.PublicKey
+	case ed25519.PrivateKey:
+		if alg != "" && alg != jwt.SigningMethodEdDSA.Alg() {
+			return nil, fmt.Errorf("key %s: algorithm %s does not use Ed25519 keys", id, alg)
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.verifyKey = pk.Public()
+	default:
+		return nil, fmt.Errorf("key %s: unsupported private key type %T", id, privateKey)
+	}
+	return key, nil
+}
+
+// ParsePrivateKeyPEM parses a PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519 (PKCS #8) private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// ID returns the key ID carried in the kid header of the tokens it signs.
+func (k *Key) ID() string { return k.id }
+
+// Algorithm returns the JWS algorithm of the key.
+func (k *Key) Algorithm() string { return k.method.Alg() }
+
+// ActiveFrom returns the time from which the key signs new tokens.
+func (k *Key) ActiveFrom() time.Time { return k.activeFrom }
+
+// isSymmetric reports whether the key is a shared secret that must not be published.
+func (k *Key) isSymmetric() bool {
+	_, ok := k.verifyKey.([]byte)
+	return ok
+}
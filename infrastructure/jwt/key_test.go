@@ -0,0 +1,210 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyRotationSuite struct {
+	suite.Suite
+	rsaKey     *rsa.PrivateKey
+	edKey      ed25519.PrivateKey
+	user       *usermodel.User
+	rotationAt time.Time
+	now        time.Time
+	service    *Service
+}
+
+func (suite *KeyRotationSuite) SetupSuite() {
+	var err error
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	_, suite.edKey, err = ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	hasher := new(ihash_mocks.Service)
+	hasher.On("Hash", mock.Anything).Return("hashed", nil)
+	suite.user, err = usermodel.New(usermodel.Config{
+		Username:       "valid_user",
+		PlainPassword:  "&&^_Str0ngP@ssw0rd!@D$",
+		PasswordHasher: hasher,
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *KeyRotationSuite) SetupTest() {
+	suite.rotationAt = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	oldKey, err := NewKey("old", "RS256", suite.rsaKey, suite.rotationAt.Add(-30*24*time.Hour))
+	suite.Require().NoError(err)
+	newKey, err := NewKey("new", "", suite.edKey, suite.rotationAt)
+	suite.Require().NoError(err)
+
+	suite.service = New(Config{
+		Keys:        []*Key{newKey, oldKey},
+		GracePeriod: time.Hour,
+		Issuer:      "testissuer",
+		ExpTime:     15 * time.Minute,
+	})
+	suite.service.now = func() time.Time { return suite.now }
+}
+
+// generate issues a token at the given time.
+func (suite *KeyRotationSuite) generate(at time.Time) string {
+	suite.now = at
+	token, err := suite.service.Generate(suite.user, uuid.New())
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *KeyRotationSuite) TestSigningKeyFollowsSchedule() {
+	before := suite.generate(suite.rotationAt.Add(-time.Minute))
+	after := suite.generate(suite.rotationAt)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(before, jwt.MapClaims{})
+	suite.NoError(err)
+	suite.Equal("old", parsed.Header["kid"])
+	suite.Equal("RS256", parsed.Header["alg"])
+
+	parsed, _, err = jwt.NewParser().ParseUnverified(after, jwt.MapClaims{})
+	suite.NoError(err)
+	suite.Equal("new", parsed.Header["kid"])
+	suite.Equal("EdDSA", parsed.Header["alg"])
+
+	claims, err := suite.service.Decode(after)
+	suite.NoError(err)
+	suite.Equal(suite.user.ID(), claims.Subject)
+}
+
+func (suite *KeyRotationSuite) TestRetiredKeyVerifiesDuringGracePeriod() {
+	// Token expiry is not under test: let the old token live past the grace period.
+	suite.service.expTime = 24 * time.Hour
+	token := suite.generate(suite.rotationAt.Add(-time.Minute))
+
+	suite.now = suite.rotationAt.Add(59 * time.Minute)
+	_, err := suite.service.Decode(token)
+	suite.NoError(err)
+
+	suite.now = suite.rotationAt.Add(time.Hour)
+	_, err = suite.service.Decode(token)
+	suite.Error(err)
+}
+
+func (suite *KeyRotationSuite) TestNoActiveKey() {
+	suite.now = suite.rotationAt.Add(-60 * 24 * time.Hour)
+	_, err := suite.service.Generate(suite.user, uuid.New())
+	suite.Error(err)
+}
+
+func (suite *KeyRotationSuite) TestUnknownKeyRejected() {
+	other, err := NewKey("other", "", ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), time.Time{})
+	suite.Require().NoError(err)
+	stranger := New(Config{Keys: []*Key{other}, Issuer: "testissuer", ExpTime: time.Hour})
+	stranger.now = func() time.Time { return suite.rotationAt }
+
+	token, err := stranger.Generate(suite.user, uuid.New())
+	suite.NoError(err)
+
+	suite.now = suite.rotationAt
+	_, err = suite.service.Decode(token)
+	suite.Error(err)
+}
+
+func (suite *KeyRotationSuite) TestAlgorithmConfusionRejected() {
+	// An HMAC token keyed with the published RSA key under its kid must not verify.
+	publicDER, err := x509.MarshalPKIXPublicKey(&suite.rsaKey.PublicKey)
+	suite.Require().NoError(err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": uuid.NewString(), "exp": suite.rotationAt.Add(time.Hour).Unix()})
+	forged.Header["kid"] = "old"
+	token, err := forged.SignedString(publicDER)
+	suite.Require().NoError(err)
+
+	suite.now = suite.rotationAt.Add(-time.Minute)
+	_, err = suite.service.Decode(token)
+	suite.Error(err)
+}
+
+func (suite *KeyRotationSuite) TestPublicKeys() {
+	suite.now = suite.rotationAt.Add(-time.Minute)
+	keys := suite.service.PublicKeys()
+	suite.Len(keys, 2) // The next key is published before it activates.
+	suite.Equal("RSA", keys[0].Kty)
+	suite.Equal("old", keys[0].Kid)
+	suite.Equal("AQAB", keys[0].E)
+	suite.NotEmpty(keys[0].N)
+	suite.Equal("OKP", keys[1].Kty)
+	suite.Equal("Ed25519", keys[1].Crv)
+	suite.Equal("new", keys[1].Kid)
+	suite.Equal("EdDSA", keys[1].Alg)
+
+	suite.now = suite.rotationAt.Add(2 * time.Hour)
+	keys = suite.service.PublicKeys()
+	suite.Len(keys, 1) // The old key is dropped after the grace period.
+	suite.Equal("new", keys[0].Kid)
+}
+
+func (suite *KeyRotationSuite) TestSharedSecretNotPublished() {
+	service := New(Config{SecretKey: "secret", ExpTime: time.Hour})
+	suite.Empty(service.PublicKeys())
+}
+
+func (suite *KeyRotationSuite) TestNewKey_AlgorithmMismatch() {
+	_, err := NewKey("rsa", "EdDSA", suite.rsaKey, time.Time{})
+	suite.Error(err)
+	_, err = NewKey("ed", "RS256", suite.edKey, time.Time{})
+	suite.Error(err)
+	_, err = NewKey("", "", suite.edKey, time.Time{})
+	suite.Error(err)
+}
+
+func (suite *KeyRotationSuite) TestLoadKeys() {
+	dir := suite.T().TempDir()
+
+	rsaDER := x509.MarshalPKCS1PrivateKey(suite.rsaKey)
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "old.pem"), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: rsaDER}), 0o600))
+	edDER, err := x509.MarshalPKCS8PrivateKey(suite.edKey)
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "new.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}), 0o600))
+
+	manifest := `{"keys": [
+		{"kid": "old", "alg": "PS256", "privateKeyFile": "old.pem", "activeFrom": "2024-08-01T00:00:00Z"},
+		{"kid": "new", "privateKeyFile": "new.pem", "activeFrom": "2024-09-01T00:00:00Z"}
+	]}`
+	manifestPath := filepath.Join(dir, "keys.json")
+	suite.Require().NoError(os.WriteFile(manifestPath, []byte(manifest), 0o600))
+
+	keys, err := LoadKeys(manifestPath)
+	suite.NoError(err)
+	suite.Len(keys, 2)
+	suite.Equal("old", keys[0].ID())
+	suite.Equal("PS256", keys[0].Algorithm())
+	suite.Equal("new", keys[1].ID())
+	suite.Equal("EdDSA", keys[1].Algorithm())
+	suite.Equal(suite.rotationAt, keys[1].ActiveFrom())
+
+	duplicate := `{"keys": [
+		{"kid": "old", "privateKeyFile": "old.pem"},
+		{"kid": "old", "privateKeyFile": "new.pem"}
+	]}`
+	suite.Require().NoError(os.WriteFile(manifestPath, []byte(duplicate), 0o600))
+	_, err = LoadKeys(manifestPath)
+	suite.Error(err)
+}
+
+func TestKeyRotationSuite(t *testing.T) {
+	suite.Run(t, new(KeyRotationSuite))
+}
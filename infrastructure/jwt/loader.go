@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// keyManifest is the JSON file listing the signing keys, for example:
+//
+//	{
+//	  "keys": [
+//	    {"kid": "2024-08", "alg": "RS256", "privateKeyFile": "2024-08.pem", "activeFrom": "2024-08-01T00:00:00Z"},
+//	    {"kid": "2024-09", "alg": "EdDSA", "privateKeyFile": "2024-09.pem", "activeFrom": "2024-09-01T00:00:00Z"}
+//	  ]
+//	}
+//
+// Rotation is scheduled by adding the next key with a future activeFrom: it is published
+// right away and starts signing tokens once that time is reached. A key can be removed
+// from the manifest once the grace period after its successor's activation has passed.
+type keyManifest struct {
+	Keys []struct {
+		Kid            string    `json:"kid"`
+		Alg            string    `json:"alg"`
+		PrivateKeyFile string    `json:"privateKeyFile"` // Relative to the manifest.
+		ActiveFrom     time.Time `json:"activeFrom"`
+	} `json:"keys"`
+}
+
+// LoadKeys reads the signing keys listed in the key manifest at the given path.
+func LoadKeys(path string) ([]*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("key manifest %s: %w", path, err)
+	}
+	if len(manifest.Keys) == 0 {
+		return nil, fmt.Errorf("key manifest %s: no keys", path)
+	}
+
+	seen := make(map[string]bool)
+	keys := make([]*Key, 0, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		if seen[entry.Kid] {
+			return nil, fmt.Errorf("key manifest %s: duplicate key ID %q", path, entry.Kid)
+		}
+		seen[entry.Kid] = true
+
+		keyPath := entry.PrivateKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(path), keyPath)
+		}
+		pemData, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		privateKey, err := ParsePrivateKeyPEM(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", entry.Kid, err)
+		}
+
+		key, err := NewKey(entry.Kid, entry.Alg, privateKey, entry.ActiveFrom)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
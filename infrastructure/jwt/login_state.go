@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"errors"
+
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// loginStateAudience is the audience of single sign-on login state tokens, so they
+// pass for neither access tokens nor MFA challenge tokens.
+const loginStateAudience = "oidc_login"
+
+// Ensure Service implements ijwt.LoginStates.
+var _ ijwt.LoginStates = &Service{}
+
+// loginStateClaims are the claims of a login state token.
+type loginStateClaims struct {
+	jwt.RegisteredClaims
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// GenerateLoginState creates a token carrying the state of a single sign-on.
+func (s *Service) GenerateLoginState(state ijwt.LoginState) (string, error) {
+	now := s.now().UTC()
+	claims := loginStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.loginStateExpTime)),
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings{loginStateAudience},
+		},
+		State:        state.State,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+	}
+	return s.sign(claims, now)
+}
+
+// DecodeLoginState validates a login state token and returns the state it carries.
+func (s *Service) DecodeLoginState(tokenString string) (*ijwt.LoginState, error) {
+	var claims loginStateClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, s.getSigningKey, s.parserOptions(loginStateAudience)...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.State == "" || claims.Nonce == "" || claims.CodeVerifier == "" {
+		return nil, errors.New("incomplete login state")
+	}
+
+	return &ijwt.LoginState{
+		State:        claims.State,
+		Nonce:        claims.Nonce,
+		CodeVerifier: claims.CodeVerifier,
+	}, nil
+}
@@ -3,34 +3,54 @@ Package jwt provides functionality for creating and validating JSON Web Tokens (
 It includes methods for generating tokens with user claims and decoding tokens to
 extract claims.
 
+Tokens are signed with one of several keys, each identified by the kid header. A key
+signs new tokens from its activation time until the next key activates, and keeps
+verifying tokens for a grace period after that. Asymmetric keys (RSA or Ed25519) are
+published as a JWKS so other services can verify tokens on their own.
+
 Key Components:
   - Service: Implements JWT operations including token generation and validation.
   - Config: Holds the configuration for creating a new JWT Service.
+  - Key: A signing key and the algorithm it is used with.
   - New: Creates a new Service instance with the given configuration.
-  - Generate: Generates a JWT for a given user with specified claims.
+  - Generate: Generates a JWT for a given user and session with a unique token ID.
   - Decode: Decodes and validates a JWT, returning the claims if valid.
+  - GenerateChallenge, DecodeChallenge: Issue and validate MFA challenge tokens.
+  - GenerateLoginState, DecodeLoginState: Issue and validate single sign-on login state tokens.
+  - PublicKeys: Returns the public keys in JWK form.
 
 Dependencies:
-- github.com/dgrijalva/jwt-go: Library for working with JWTs.
+- github.com/golang-jwt/jwt/v5: Library for working with JWTs.
 - github.com/beka-birhanu/domain/models/user: User model for JWT claims.
 */
 package jwt
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// defaultKeyID identifies the HS256 key built from Config.SecretKey.
+const defaultKeyID = "default"
+
 // Service handles JWT operations.
 // Implements ijwt.Service.
 type Service struct {
-	secretKey string
-	issuer    string
-	expTime   time.Duration
+	keys              []*Key // Ordered by activation time.
+	gracePeriod       time.Duration
+	issuer            string
+	audience          string
+	expTime           time.Duration
+	challengeExpTime  time.Duration
+	loginStateExpTime time.Duration
+	now               func() time.Time
 }
 
 // Ensure Service implements ijwt.Service.
@@ -38,52 +58,139 @@ var _ ijwt.Service = &Service{}
 
 // Config holds JWT service configuration.
 type Config struct {
-	SecretKey string
-	Issuer    string
-	ExpTime   time.Duration
+	SecretKey         string        // Shared HS256 secret, used when no Keys are given.
+	Keys              []*Key        // Signing keys; the latest activated one signs new tokens.
+	GracePeriod       time.Duration // How long a replaced key keeps verifying tokens; never shorter than ExpTime.
+	Issuer            string        // Issuer of the tokens; decoded tokens must carry it.
+	Audience          string        // Audience of the tokens; decoded tokens must include it.
+	ExpTime           time.Duration
+	ChallengeExpTime  time.Duration // Lifetime of MFA challenge tokens.
+	LoginStateExpTime time.Duration // Lifetime of single sign-on login state tokens.
 }
 
 // New creates a new JWT Service with the provided configuration.
 func New(config Config) *Service {
+	keys := append([]*Key(nil), config.Keys...)
+	if len(keys) == 0 {
+		keys = append(keys, NewHMACKey(defaultKeyID, []byte(config.SecretKey), time.Time{}))
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].activeFrom.Before(keys[j].activeFrom)
+	})
+
+	gracePeriod := config.GracePeriod
+	if gracePeriod < config.ExpTime {
+		gracePeriod = config.ExpTime // Tokens signed just before a rotation must outlive it.
+	}
+
 	return &Service{
-		secretKey: config.SecretKey,
-		issuer:    config.Issuer,
-		expTime:   config.ExpTime,
+		keys:              keys,
+		gracePeriod:       gracePeriod,
+		issuer:            config.Issuer,
+		audience:          config.Audience,
+		expTime:           config.ExpTime,
+		challengeExpTime:  config.ChallengeExpTime,
+		loginStateExpTime: config.LoginStateExpTime,
+		now:               time.Now,
 	}
 }
 
-// Generate creates a JWT for the given user.
-func (s *Service) Generate(user *usermodel.User) (string, error) {
-	expirationTime := time.Now().UTC().Add(s.expTime).Unix()
-	claims := jwt.MapClaims{
-		"user_id":  user.ID().String(),
-		"is_admin": user.IsAdmin(),
-		"exp":      expirationTime,
-		"iss":      s.issuer,
+// Generate creates a JWT for the given user within the given session.
+func (s *Service) Generate(user *usermodel.User, sessionID uuid.UUID) (string, error) {
+	now := s.now().UTC()
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID().String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.expTime)),
+			Issuer:    s.issuer,
+		},
+		Roles:     roles(user),
+		SessionID: sessionID.String(),
+	}
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
+	return s.sign(claims, now)
 }
 
 // Decode parses and validates a JWT, returning the claims if valid.
-func (s *Service) Decode(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, s.getSigningKey)
+// Besides the signature and expiry, the issuer and audience must match the service's.
+func (s *Service) Decode(tokenString string) (*ijwt.Claims, error) {
+	var claims tokenClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, s.getSigningKey, s.parserOptions(s.audience)...)
 	if err != nil {
 		return nil, err
 	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+	return claims.toClaims()
+}
+
+// sign signs the claims with the key active at the given time.
+func (s *Service) sign(claims jwt.Claims, now time.Time) (string, error) {
+	key, err := s.signingKey(now)
+	if err != nil {
+		return "", err
 	}
 
-	return nil, errors.New("invalid token")
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.signKey)
 }
 
-// getSigningKey returns the signing key for token validation.
+// parserOptions returns the options validating a token's expiry and issuer, and its
+// audience when one is given.
+func (s *Service) parserOptions(audience string) []jwt.ParserOption {
+	options := []jwt.ParserOption{jwt.WithTimeFunc(s.now), jwt.WithExpirationRequired(), jwt.WithIssuedAt()}
+	if s.issuer != "" {
+		options = append(options, jwt.WithIssuer(s.issuer))
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	return options
+}
+
+// getSigningKey returns the key the token must have been signed with, looked up by its kid header.
+// The token's algorithm must be the one of the key, so a public key can never be used as an HMAC secret.
 func (s *Service) getSigningKey(token *jwt.Token) (interface{}, error) {
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, errors.New("unexpected signing method")
+	kid, _ := token.Header["kid"].(string)
+	now := s.now()
+	for i, key := range s.keys {
+		if key.id != kid {
+			continue
+		}
+		if !s.verifies(i, now) {
+			return nil, errors.New("signing key retired")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// signingKey returns the latest key activated at the given time.
+func (s *Service) signingKey(now time.Time) (*Key, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if !s.keys[i].activeFrom.After(now) {
+			return s.keys[i], nil
+		}
+	}
+	return nil, errors.New("no active signing key")
+}
+
+// verifies reports whether the i-th key still verifies tokens at the given time:
+// until the grace period has passed since the next key activated.
+func (s *Service) verifies(i int, now time.Time) bool {
+	if i == len(s.keys)-1 {
+		return true
 	}
-	return []byte(s.secretKey), nil
+	return now.Before(s.keys[i+1].activeFrom.Add(s.gracePeriod))
 }
@@ -4,11 +4,14 @@ import (
 	"testing"
 	"time"
 
+	ijwt "github.com/beka-birhanu/task_manager_final/app/common/i_jwt"
 	ihash_mocks "github.com/beka-birhanu/task_manager_final/domain/i_hash/mocks"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	"github.com/beka-birhanu/task_manager_final/infrastructure/jwt"
 
-	jwt_builtin "github.com/dgrijalva/jwt-go"
+	jwt_builtin "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -19,6 +22,7 @@ type JWTServiceSuite struct {
 	mockHasher     *ihash_mocks.Service
 	secretKey      string
 	issuer         string
+	audience       string
 	expirationTime time.Duration
 	username       string
 	password       string
@@ -29,6 +33,7 @@ func (suite *JWTServiceSuite) SetupTest() {
 	// Initialize property values
 	suite.secretKey = "testsecretkey"
 	suite.issuer = "testissuer"
+	suite.audience = "testaudience"
 	suite.expirationTime = time.Hour
 	suite.username = "valid_user"
 	suite.password = "&&^_Str0ngP@ssw0rd!@D$"
@@ -43,7 +48,6 @@ func (suite *JWTServiceSuite) SetupTest() {
 	suite.user, err = usermodel.New(usermodel.Config{
 		Username:       suite.username,
 		PlainPassword:  suite.password,
-		IsAdmin:        false,
 		PasswordHasher: suite.mockHasher,
 	})
 	suite.NoError(err)
@@ -51,15 +55,19 @@ func (suite *JWTServiceSuite) SetupTest() {
 
 	// Setting up the JWT service
 	suite.jwtService = jwt.New(jwt.Config{
-		SecretKey: suite.secretKey,
-		Issuer:    suite.issuer,
-		ExpTime:   suite.expirationTime,
+		SecretKey:         suite.secretKey,
+		Issuer:            suite.issuer,
+		Audience:          suite.audience,
+		ExpTime:           suite.expirationTime,
+		ChallengeExpTime:  5 * time.Minute,
+		LoginStateExpTime: 10 * time.Minute,
 	})
 }
 
 func (suite *JWTServiceSuite) TestGenerateToken() {
 	// Generate the token
-	token, err := suite.jwtService.Generate(suite.user)
+	sessionID := uuid.New()
+	token, err := suite.jwtService.Generate(suite.user, sessionID)
 	suite.NoError(err)
 	suite.NotEmpty(token)
 
@@ -69,22 +77,152 @@ func (suite *JWTServiceSuite) TestGenerateToken() {
 		return []byte(suite.secretKey), nil
 	})
 	suite.NoError(err)
-	suite.Equal(suite.user.ID().String(), claims["user_id"])
-	suite.Equal(false, claims["is_admin"])
+	suite.Equal(suite.user.ID().String(), claims["sub"])
+	suite.Equal([]interface{}{"member"}, claims["roles"])
 	suite.Equal(suite.issuer, claims["iss"])
+	suite.Equal([]interface{}{suite.audience}, claims["aud"])
+	suite.Equal(sessionID.String(), claims["sid"])
+	suite.NotEmpty(claims["jti"])
+	suite.NotEmpty(claims["iat"])
+
+	// Every token gets its own ID
+	other, err := suite.jwtService.Generate(suite.user, sessionID)
+	suite.NoError(err)
+	otherClaims, err := suite.jwtService.Decode(other)
+	suite.NoError(err)
+	suite.NotEqual(claims["jti"], otherClaims.TokenID)
 }
 
 func (suite *JWTServiceSuite) TestDecodeToken() {
 	// Create a valid token
-	token, err := suite.jwtService.Generate(suite.user)
+	token, err := suite.jwtService.Generate(suite.user, uuid.New())
 	suite.NoError(err)
 
 	// Decode the token
 	claims, err := suite.jwtService.Decode(token)
 	suite.NoError(err)
-	suite.Equal(suite.user.ID().String(), claims["user_id"])
-	suite.Equal(false, claims["is_admin"])
-	suite.Equal(suite.issuer, claims["iss"])
+	suite.Equal(suite.user.ID(), claims.Subject)
+	suite.Equal([]string{"member"}, claims.Roles)
+	suite.True(claims.Can(rolemodel.TaskCreate))
+	suite.False(claims.Can(rolemodel.UserPromote))
+	suite.Equal(suite.issuer, claims.Issuer)
+	suite.Equal([]string{suite.audience}, claims.Audience)
+	suite.WithinDuration(time.Now(), claims.IssuedAt, time.Minute)
+	suite.WithinDuration(time.Now().Add(suite.expirationTime), claims.ExpiresAt, time.Minute)
+}
+
+func (suite *JWTServiceSuite) TestDecodeToken_AdminRole() {
+	admin, err := usermodel.New(usermodel.Config{
+		Username:       "admin_user",
+		PlainPassword:  suite.password,
+		Role:           rolemodel.Admin,
+		PasswordHasher: suite.mockHasher,
+	})
+	suite.NoError(err)
+
+	token, err := suite.jwtService.Generate(admin, uuid.New())
+	suite.NoError(err)
+
+	claims, err := suite.jwtService.Decode(token)
+	suite.NoError(err)
+	suite.True(claims.Can(rolemodel.UserPromote))
+	suite.Equal([]string{"admin"}, claims.Roles)
+}
+
+func (suite *JWTServiceSuite) TestDecodeToken_WrongIssuerOrAudience() {
+	for _, config := range []jwt.Config{
+		{SecretKey: suite.secretKey, Issuer: "otherissuer", Audience: suite.audience, ExpTime: suite.expirationTime},
+		{SecretKey: suite.secretKey, Issuer: suite.issuer, Audience: "otheraudience", ExpTime: suite.expirationTime},
+	} {
+		token, err := jwt.New(config).Generate(suite.user, uuid.New())
+		suite.NoError(err)
+
+		_, err = suite.jwtService.Decode(token)
+		suite.Error(err)
+	}
+}
+
+func (suite *JWTServiceSuite) TestDecodeToken_Expired() {
+	expired := jwt.New(jwt.Config{
+		SecretKey: suite.secretKey,
+		Issuer:    suite.issuer,
+		Audience:  suite.audience,
+		ExpTime:   -time.Minute,
+	})
+	token, err := expired.Generate(suite.user, uuid.New())
+	suite.NoError(err)
+
+	_, err = suite.jwtService.Decode(token)
+	suite.ErrorIs(err, jwt_builtin.ErrTokenExpired)
+}
+
+func (suite *JWTServiceSuite) TestChallenge() {
+	challenge, err := suite.jwtService.GenerateChallenge(suite.user)
+	suite.NoError(err)
+
+	userID, err := suite.jwtService.DecodeChallenge(challenge)
+	suite.NoError(err)
+	suite.Equal(suite.user.ID(), userID)
+
+	// Challenges and access tokens are not interchangeable.
+	_, err = suite.jwtService.Decode(challenge)
+	suite.Error(err)
+
+	token, err := suite.jwtService.Generate(suite.user, uuid.New())
+	suite.NoError(err)
+	_, err = suite.jwtService.DecodeChallenge(token)
+	suite.Error(err)
+}
+
+func (suite *JWTServiceSuite) TestChallenge_Expired() {
+	expired := jwt.New(jwt.Config{
+		SecretKey:        suite.secretKey,
+		Issuer:           suite.issuer,
+		Audience:         suite.audience,
+		ExpTime:          suite.expirationTime,
+		ChallengeExpTime: -time.Minute,
+	})
+	challenge, err := expired.GenerateChallenge(suite.user)
+	suite.NoError(err)
+
+	_, err = suite.jwtService.DecodeChallenge(challenge)
+	suite.ErrorIs(err, jwt_builtin.ErrTokenExpired)
+}
+
+func (suite *JWTServiceSuite) TestLoginState() {
+	state := ijwt.LoginState{State: "state", Nonce: "nonce", CodeVerifier: "verifier"}
+	token, err := suite.jwtService.GenerateLoginState(state)
+	suite.NoError(err)
+
+	decoded, err := suite.jwtService.DecodeLoginState(token)
+	suite.NoError(err)
+	suite.Equal(state, *decoded)
+
+	// Login states pass for neither access tokens nor challenges.
+	_, err = suite.jwtService.Decode(token)
+	suite.Error(err)
+	_, err = suite.jwtService.DecodeChallenge(token)
+	suite.Error(err)
+
+	challenge, err := suite.jwtService.GenerateChallenge(suite.user)
+	suite.NoError(err)
+	_, err = suite.jwtService.DecodeLoginState(challenge)
+	suite.Error(err)
+}
+
+func (suite *JWTServiceSuite) TestLoginState_Expired() {
+	expired := jwt.New(jwt.Config{
+		SecretKey:         suite.secretKey,
+		Issuer:            suite.issuer,
+		Audience:          suite.audience,
+		ExpTime:           suite.expirationTime,
+		LoginStateExpTime: -time.Minute,
+	})
+	token, err := expired.GenerateLoginState(ijwt.LoginState{State: "state", Nonce: "nonce", CodeVerifier: "verifier"})
+	suite.NoError(err)
+
+	_, err = suite.jwtService.DecodeLoginState(token)
+	suite.ErrorIs(err, jwt_builtin.ErrTokenExpired)
 }
 
 func TestJWTServiceSuite(t *testing.T) {
@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"errors"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+)
+
+// ErrDisabled is returned by the disabled notifier for every message.
+var ErrDisabled = errors.New("notifier: no notifier configured")
+
+// DisabledNotifier delivers nothing, for deployments that did not choose a notifier.
+// Messages carry secrets such as reset tokens, so they are dropped rather than written
+// anywhere they were not meant to go.
+// Implements inotifier.Notifier.
+type DisabledNotifier struct{}
+
+// Ensure DisabledNotifier implements inotifier.Notifier.
+var _ inotifier.Notifier = DisabledNotifier{}
+
+// Notify drops the message and returns ErrDisabled.
+func (DisabledNotifier) Notify(msg *inotifier.Message) error {
+	return ErrDisabled
+}
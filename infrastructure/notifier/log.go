@@ -0,0 +1,58 @@
+// Package notifier provides implementations of inotifier.Notifier: an SMTP notifier
+// delivering messages by email, a log notifier writing them to a file or the standard
+// output for local development and tests, and a disabled notifier delivering nothing.
+package notifier
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+)
+
+// LogNotifier writes messages to a writer instead of delivering them.
+// Implements inotifier.Notifier.
+type LogNotifier struct {
+	mu  sync.Mutex
+	out io.Writer
+	now func() time.Time
+}
+
+// Ensure LogNotifier implements inotifier.Notifier.
+var _ inotifier.Notifier = &LogNotifier{}
+
+// NewLogNotifier creates a LogNotifier writing to the given writer.
+func NewLogNotifier(out io.Writer) *LogNotifier {
+	return &LogNotifier{
+		out: out,
+		now: time.Now,
+	}
+}
+
+// NewFileNotifier creates a LogNotifier appending to the file at the given path,
+// creating it if needed.
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogNotifier(file), nil
+}
+
+// Notify writes the message, preceded by its recipient and subject.
+func (n *LogNotifier) Notify(msg *inotifier.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	to := msg.Username
+	if msg.Email != "" {
+		to += " <" + msg.Email + ">"
+	}
+
+	_, err := fmt.Fprintf(n.out, "--- %s\nTo: %s\nSubject: %s\n\n%s\n",
+		n.now().UTC().Format(time.RFC3339), to, msg.Subject, msg.Body)
+	return err
+}
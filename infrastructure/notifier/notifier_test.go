@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"bytes"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotifierSuite struct {
+	suite.Suite
+	msg *inotifier.Message
+	now time.Time
+}
+
+func (suite *NotifierSuite) SetupTest() {
+	suite.now = time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	suite.msg = &inotifier.Message{
+		Username: "beka_birhanu",
+		Subject:  "Reset your password",
+		Body:     "Use this link:\nhttps://example.com/reset?token=abc",
+	}
+}
+
+func (suite *NotifierSuite) TestLogNotifier() {
+	var out bytes.Buffer
+	n := NewLogNotifier(&out)
+	n.now = func() time.Time { return suite.now }
+
+	suite.NoError(n.Notify(suite.msg))
+	suite.Equal("--- 2024-09-01T12:00:00Z\nTo: beka_birhanu\nSubject: Reset your password\n\nUse this link:\nhttps://example.com/reset?token=abc\n", out.String())
+}
+
+func (suite *NotifierSuite) TestLogNotifier_Email() {
+	var out bytes.Buffer
+	n := NewLogNotifier(&out)
+
+	suite.msg.Email = "beka@example.com"
+	suite.NoError(n.Notify(suite.msg))
+	suite.Contains(out.String(), "To: beka_birhanu <beka@example.com>\n")
+}
+
+func (suite *NotifierSuite) TestFileNotifier() {
+	path := filepath.Join(suite.T().TempDir(), "mail.log")
+	n, err := NewFileNotifier(path)
+	suite.Require().NoError(err)
+
+	suite.NoError(n.Notify(suite.msg))
+	suite.NoError(n.Notify(suite.msg))
+
+	data, err := os.ReadFile(path)
+	suite.NoError(err)
+	suite.Equal(2, bytes.Count(data, []byte("To: beka_birhanu")))
+}
+
+func (suite *NotifierSuite) TestSMTPNotifier() {
+	n := NewSMTPNotifier(SMTPConfig{
+		Host:            "smtp.example.com",
+		Port:            "587",
+		Username:        "mailer",
+		Password:        "secret",
+		From:            "noreply@example.com",
+		RecipientDomain: "example.com",
+	})
+	n.now = func() time.Time { return suite.now }
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	n.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+
+	suite.NoError(n.Notify(suite.msg))
+	suite.Equal("smtp.example.com:587", gotAddr)
+	suite.Equal("noreply@example.com", gotFrom)
+	suite.Equal([]string{"beka_birhanu@example.com"}, gotTo)
+	suite.Contains(string(gotMsg), "To: beka_birhanu@example.com\r\n")
+	suite.Contains(string(gotMsg), "Subject: Reset your password\r\n")
+	suite.Contains(string(gotMsg), "\r\n\r\nUse this link:\r\nhttps://example.com/reset?token=abc\r\n")
+}
+
+func (suite *NotifierSuite) TestSMTPNotifier_Email() {
+	n := NewSMTPNotifier(SMTPConfig{Host: "localhost", Port: "25", From: "noreply@example.com"})
+	var gotTo []string
+	n.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotTo = to
+		return nil
+	}
+
+	suite.msg.Email = "beka@mail.example.org"
+	suite.NoError(n.Notify(suite.msg))
+	suite.Equal([]string{"beka@mail.example.org"}, gotTo)
+}
+
+func (suite *NotifierSuite) TestSMTPNotifier_HeaderInjection() {
+	n := NewSMTPNotifier(SMTPConfig{Host: "localhost", Port: "25", From: "noreply@example.com", RecipientDomain: "example.com"})
+	n.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		suite.NotContains(string(msg), "\r\nBcc:")
+		return nil
+	}
+
+	suite.NoError(n.Notify(&inotifier.Message{Username: "user", Subject: "Hi\r\nBcc: victim@example.com"}))
+
+	err := n.Notify(&inotifier.Message{Username: "user\r\nBcc: victim@example.com"})
+	suite.Error(err)
+}
+
+func (suite *NotifierSuite) TestSMTPNotifier_NoRecipientDomain() {
+	n := NewSMTPNotifier(SMTPConfig{Host: "localhost", Port: "25"})
+	suite.Error(n.Notify(suite.msg))
+}
+
+func (suite *NotifierSuite) TestDisabledNotifier() {
+	suite.Equal(ErrDisabled, DisabledNotifier{}.Notify(suite.msg))
+}
+
+func TestNotifierSuite(t *testing.T) {
+	suite.Run(t, new(NotifierSuite))
+}
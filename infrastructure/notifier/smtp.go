@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+)
+
+// SMTPConfig holds the settings of the SMTP notifier.
+type SMTPConfig struct {
+	Host            string // Host of the SMTP server.
+	Port            string // Port of the SMTP server.
+	Username        string // User to authenticate as; no authentication when empty.
+	Password        string // Password of the SMTP user.
+	From            string // Sender address of the messages.
+	RecipientDomain string // Mail domain of users without an email address, addressed as <username>@<domain>.
+}
+
+// SMTPNotifier delivers messages by email through an SMTP server.
+// Implements inotifier.Notifier.
+type SMTPNotifier struct {
+	addr            string
+	auth            smtp.Auth
+	from            string
+	recipientDomain string
+	now             func() time.Time
+	sendMail        func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Ensure SMTPNotifier implements inotifier.Notifier.
+var _ inotifier.Notifier = &SMTPNotifier{}
+
+// NewSMTPNotifier creates an SMTPNotifier with the given configuration.
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPNotifier{
+		addr:            net.JoinHostPort(cfg.Host, cfg.Port),
+		auth:            auth,
+		from:            cfg.From,
+		recipientDomain: cfg.RecipientDomain,
+		now:             time.Now,
+		sendMail:        smtp.SendMail,
+	}
+}
+
+// Notify sends the message as a plain text email to the user.
+func (n *SMTPNotifier) Notify(msg *inotifier.Message) error {
+	to, err := n.recipient(msg)
+	if err != nil {
+		return err
+	}
+	return n.sendMail(n.addr, n.auth, n.from, []string{to}, n.compose(to, msg))
+}
+
+// recipient returns the email address of the user the message is for.
+func (n *SMTPNotifier) recipient(msg *inotifier.Message) (string, error) {
+	if msg.Email != "" {
+		if strings.ContainsAny(msg.Email, "\r\n") {
+			return "", fmt.Errorf("smtp notifier: invalid recipient %q", msg.Email)
+		}
+		return msg.Email, nil
+	}
+
+	if n.recipientDomain == "" {
+		return "", errors.New("smtp notifier: no recipient domain configured")
+	}
+	if msg.Username == "" || strings.ContainsAny(msg.Username, "@\r\n") {
+		return "", fmt.Errorf("smtp notifier: invalid recipient %q", msg.Username)
+	}
+	return msg.Username + "@" + n.recipientDomain, nil
+}
+
+// compose builds the email, encoding the subject so it cannot inject headers.
+func (n *SMTPNotifier) compose(to string, msg *inotifier.Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", n.now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,180 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyRefreshInterval is how long after the JWKS was fetched an unknown key ID is taken
+// for a forgery rather than a rotation, so tokens cannot make the provider be called
+// over and over.
+const keyRefreshInterval = time.Minute
+
+// publicKey is a signing key of the provider.
+type publicKey struct {
+	kid string
+	alg string // Algorithm the key is restricted to; any matching its type when empty.
+	key interface{}
+}
+
+// jsonWebKey is a key of the JWKS in JSON Web Key form (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey returns the key of the provider the token must have been signed with,
+// looked up by its kid header. The JWKS is fetched again when the key is not known yet.
+func (p *Provider) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	alg := token.Method.Alg()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := p.matchingKeys(kid, alg)
+	if len(keys.Keys) == 0 && p.now().Sub(p.keysFetchedAt) >= keyRefreshInterval {
+		if err := p.fetchKeys(); err != nil {
+			return nil, err
+		}
+		keys = p.matchingKeys(kid, alg)
+	}
+
+	switch len(keys.Keys) {
+	case 0:
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	case 1:
+		return keys.Keys[0], nil
+	default:
+		return keys, nil
+	}
+}
+
+// matchingKeys returns the known keys with the given key ID, or all of them for tokens
+// without one, that may be used with the algorithm.
+func (p *Provider) matchingKeys(kid, alg string) jwt.VerificationKeySet {
+	var set jwt.VerificationKeySet
+	for _, key := range p.keys {
+		if (kid != "" && key.kid != kid) || (key.alg != "" && key.alg != alg) {
+			continue
+		}
+		set.Keys = append(set.Keys, key.key)
+	}
+	return set
+}
+
+// fetchKeys replaces the known keys with the signing keys of the provider's JWKS.
+// Keys of unsupported types are skipped.
+func (p *Provider) fetchKeys() error {
+	req, err := http.NewRequest(http.MethodGet, p.metadata.JWKSURI, nil)
+	if err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := p.do(req, &jwks)
+	if err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("jwks: unexpected status %d", status)
+	}
+
+	keys := make([]publicKey, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, publicKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = p.now()
+	return nil
+}
+
+// publicKey converts the JWK to an RSA, ECDSA or Ed25519 public key.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeInt decodes a base64url encoded big-endian unsigned integer.
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,218 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockProvider is a local OpenID Connect identity provider, signing every user in as
+// the same account without asking.
+type mockProvider struct {
+	server       *httptest.Server
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	subject      string
+	email        string
+
+	mu         sync.Mutex
+	key        *rsa.PrivateKey
+	kid        string
+	grants     map[string]grant           // Authorization requests by the code issued for them.
+	tamper     func(claims jwt.MapClaims) // Changes the claims of the next ID tokens.
+	signSecret bool                       // Sign the next ID tokens with the client secret (HS256).
+	jwksHits   int                        // Number of times the JWKS was fetched.
+}
+
+// grant is an authorization request waiting for its code to be redeemed.
+type grant struct {
+	codeChallenge string
+	nonce         string
+}
+
+// newMockProvider starts a mock provider for the given client.
+func newMockProvider(clientID, clientSecret, redirectURL string) *mockProvider {
+	m := &mockProvider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		subject:      "248289761001",
+		email:        "jane@example.com",
+		grants:       make(map[string]grant),
+	}
+	m.rotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, m.discovery)
+	mux.HandleFunc("/authorize", m.authorize)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/jwks", m.jwks)
+	m.server = httptest.NewServer(mux)
+	return m
+}
+
+// issuer returns the issuer identifier URL of the provider.
+func (m *mockProvider) issuer() string {
+	return m.server.URL
+}
+
+// close shuts the provider down.
+func (m *mockProvider) close() {
+	m.server.Close()
+}
+
+// rotateKey replaces the signing key with a new one.
+func (m *mockProvider) rotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.key = key
+	m.kid = base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()[:8])
+}
+
+// signIn follows the authorization URL as a browser would and returns the code and
+// state the provider sends back to the redirect URL.
+func (m *mockProvider) signIn(authURL string) (code, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (m *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 m.issuer(),
+		"authorization_endpoint": m.issuer() + "/authorize",
+		"token_endpoint":         m.issuer() + "/token",
+		"jwks_uri":               m.issuer() + "/jwks",
+	})
+}
+
+func (m *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != m.clientID || query.Get("redirect_uri") != m.redirectURL ||
+		query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	m.mu.Lock()
+	m.grants[code] = grant{codeChallenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(m.redirectURL)
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != url.QueryEscape(m.clientID) || clientSecret != url.QueryEscape(m.clientSecret) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code := r.PostFormValue("code")
+	g, ok := m.grants[code]
+	delete(m.grants, code)
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != m.redirectURL ||
+		g.codeChallenge != s256(r.PostFormValue("code_verifier")) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code or verifier does not match"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.issuer(),
+		"sub":            m.subject,
+		"aud":            m.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          g.nonce,
+		"email":          m.email,
+		"email_verified": true,
+	}
+	if m.tamper != nil {
+		m.tamper(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	var signKey interface{} = m.key
+	if m.signSecret {
+		token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signKey = []byte(m.clientSecret)
+	}
+	idToken, err := token.SignedString(signKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": randomString(), "token_type": "Bearer", "id_token": idToken})
+}
+
+func (m *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jwksHits++
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": m.kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(m.key.PublicKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.PublicKey.E)).Bytes()),
+	}}})
+}
+
+// writeJSON writes v as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// randomString returns a random URL-safe string.
+func randomString() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// s256 returns the S256 code challenge of a PKCE code verifier.
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
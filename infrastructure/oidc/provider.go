@@ -0,0 +1,302 @@
+/*
+Package oidc signs users in at an OpenID Connect identity provider with the
+authorization code flow and PKCE.
+
+The endpoints of the provider are read from its discovery document on first use, and
+the keys its ID tokens are signed with from its JWKS, which is fetched again when a
+token is signed with a key not seen yet. ID tokens are only accepted when signed with
+an asymmetric key of the provider, issued by it to the configured client, unexpired,
+and carrying the nonce of the sign in.
+
+Key Components:
+  - Provider: Implements ioidc.Provider against a single identity provider.
+  - Config: Holds the issuer and client settings.
+  - New: Creates a Provider; nothing is fetched until it is used.
+
+Dependencies:
+- github.com/golang-jwt/jwt/v5: Library for verifying the ID tokens.
+*/
+package oidc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// discoveryPath is where the discovery document lives below the issuer URL.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// clockSkew is how far the clocks of the provider and the server may drift apart.
+	clockSkew = time.Minute
+
+	// maxResponseSize caps the responses read from the provider.
+	maxResponseSize = 1 << 20
+)
+
+// signingMethods are the algorithms ID tokens may be signed with. Tokens signed with
+// the client secret (HS256) are refused, so only the provider can sign them.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config holds the settings of the identity provider and of the client registered at it.
+type Config struct {
+	Issuer       string       // Issuer identifier URL of the provider; the discovery document is read below it.
+	ClientID     string       // ID of the client registered at the provider.
+	ClientSecret string       // Secret of the client; empty for public clients.
+	RedirectURL  string       // Callback URL the provider sends the user back to.
+	Scopes       []string     // Scopes requested besides openid.
+	HTTPClient   *http.Client // Client the provider is called with; one with a 10 second timeout when nil.
+}
+
+// Provider signs users in at an OpenID Connect identity provider.
+// Implements ioidc.Provider.
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu            sync.Mutex
+	metadata      *metadata   // Discovery document; nil until it was read.
+	keys          []publicKey // Signing keys of the JWKS.
+	keysFetchedAt time.Time
+}
+
+// Ensure Provider implements ioidc.Provider.
+var _ ioidc.Provider = &Provider{}
+
+// metadata is the part of the discovery document the sign in needs.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// idTokenClaims are the claims of an ID token the sign in uses.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string      `json:"nonce"`
+	AuthorizedParty   string      `json:"azp"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"` // Some providers send the string "true".
+	PreferredUsername string      `json:"preferred_username"`
+	Name              string      `json:"name"`
+}
+
+// New creates a Provider with the given configuration.
+func New(config Config) *Provider {
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// AuthCodeURL returns the URL of the authorization endpoint the user signs in at.
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	meta, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", p.scope())
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange redeems the authorization code at the token endpoint and returns the
+// identity held by the verified ID token.
+func (p *Provider) Exchange(code, codeVerifier, nonce string) (*ioidc.Identity, error) {
+	meta, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.redeem(meta, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	_, err = jwt.ParseWithClaims(rawIDToken, &claims, p.verificationKey,
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("id token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("id token: nonce does not match")
+	}
+	// A token issued to several clients must name this one as the party it was issued for.
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("id token: issued for another client")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token: sub claim is missing")
+	}
+
+	return &ioidc.Identity{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     isTrue(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
+
+// redeem exchanges the authorization code for the tokens of the user and returns the ID token.
+func (p *Provider) redeem(meta *metadata, code, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// Client credentials are form encoded before they go in the basic auth header.
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var response tokenResponse
+	status, err := p.do(req, &response)
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	if status != http.StatusOK {
+		if response.Error != "" {
+			return "", fmt.Errorf("token request: %s: %s", response.Error, response.ErrorDescription)
+		}
+		return "", fmt.Errorf("token request: unexpected status %d", status)
+	}
+	if response.IDToken == "" {
+		return "", errors.New("token request: no id token returned")
+	}
+	return response.IDToken, nil
+}
+
+// discover returns the discovery document of the provider, reading it on first use.
+// The issuer it names must be the configured one, so another provider cannot pose as it.
+func (p *Provider) discover() (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+
+	var meta metadata
+	status, err := p.do(req, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("discovery: unexpected status %d", status)
+	}
+	if meta.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("discovery: issuer %q does not match %q", meta.Issuer, p.config.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("discovery: authorization, token and jwks endpoints are required")
+	}
+
+	p.metadata = &meta
+	return p.metadata, nil
+}
+
+// do sends the request and decodes the JSON response into v, returning the status code.
+func (p *Provider) do(req *http.Request, v interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+// scope returns the scopes requested from the provider: openid and the configured ones.
+func (p *Provider) scope() string {
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// isTrue reports whether a boolean claim is true, whether it was sent as a boolean or a string.
+func isTrue(claim interface{}) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
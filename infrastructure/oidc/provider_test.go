@@ -0,0 +1,175 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	ioidc "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	clientID     = "task-manager"
+	clientSecret = "s3cret/with+symbols"
+	redirectURL  = "https://tasks.example.com/api/auth/oidc/callback"
+	verifier     = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	nonce        = "n-0S6_WzA2Mj"
+)
+
+type ProviderSuite struct {
+	suite.Suite
+	idp      *mockProvider
+	provider *Provider
+}
+
+func (suite *ProviderSuite) SetupTest() {
+	suite.idp = newMockProvider(clientID, clientSecret, redirectURL)
+	suite.provider = New(Config{
+		Issuer:       suite.idp.issuer(),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+}
+
+func (suite *ProviderSuite) TearDownTest() {
+	suite.idp.close()
+}
+
+// signIn sends the user to the provider and redeems the code it sends back.
+func (suite *ProviderSuite) signIn(codeVerifier, tokenNonce string) (*ioidc.Identity, error) {
+	authURL, err := suite.provider.AuthCodeURL("state-1", nonce, s256(verifier))
+	suite.Require().NoError(err)
+
+	code, state, err := suite.idp.signIn(authURL)
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(code)
+	suite.Equal("state-1", state)
+
+	return suite.provider.Exchange(code, codeVerifier, tokenNonce)
+}
+
+func (suite *ProviderSuite) TestAuthCodeURL() {
+	authURL, err := suite.provider.AuthCodeURL("state-1", nonce, s256(verifier))
+	suite.NoError(err)
+
+	parsed, err := url.Parse(authURL)
+	suite.NoError(err)
+	suite.Equal(suite.idp.issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	query := parsed.Query()
+	suite.Equal("code", query.Get("response_type"))
+	suite.Equal(clientID, query.Get("client_id"))
+	suite.Equal(redirectURL, query.Get("redirect_uri"))
+	suite.Equal("openid email profile", query.Get("scope"))
+	suite.Equal("state-1", query.Get("state"))
+	suite.Equal(nonce, query.Get("nonce"))
+	suite.Equal(s256(verifier), query.Get("code_challenge"))
+	suite.Equal("S256", query.Get("code_challenge_method"))
+}
+
+func (suite *ProviderSuite) TestExchange() {
+	identity, err := suite.signIn(verifier, nonce)
+	suite.NoError(err)
+	suite.Equal(&ioidc.Identity{
+		Issuer:        suite.idp.issuer(),
+		Subject:       "248289761001",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+	}, identity)
+}
+
+func (suite *ProviderSuite) TestExchange_EmailVerifiedString() {
+	suite.idp.tamper = func(claims jwt.MapClaims) {
+		claims["email_verified"] = "true"
+		claims["preferred_username"] = "jane.doe"
+		claims["name"] = "Jane Doe"
+	}
+
+	identity, err := suite.signIn(verifier, nonce)
+	suite.NoError(err)
+	suite.True(identity.EmailVerified)
+	suite.Equal("jane.doe", identity.PreferredUsername)
+	suite.Equal("Jane Doe", identity.Name)
+}
+
+func (suite *ProviderSuite) TestExchange_WrongVerifier() {
+	_, err := suite.signIn("another-verifier-of-the-required-length-0123456789", nonce)
+	suite.ErrorContains(err, "invalid_grant")
+}
+
+func (suite *ProviderSuite) TestExchange_WrongClientSecret() {
+	suite.provider.config.ClientSecret = "guess"
+	_, err := suite.signIn(verifier, nonce)
+	suite.ErrorContains(err, "invalid_client")
+}
+
+func (suite *ProviderSuite) TestExchange_WrongNonce() {
+	_, err := suite.signIn(verifier, "another-nonce")
+	suite.ErrorContains(err, "nonce")
+}
+
+func (suite *ProviderSuite) TestExchange_InvalidIDToken() {
+	tests := map[string]func(claims jwt.MapClaims){
+		"audience": func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+		"issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		"expiry":   func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"subject":  func(claims jwt.MapClaims) { delete(claims, "sub") },
+		"azp": func(claims jwt.MapClaims) {
+			claims["aud"] = []string{clientID, "another-client"}
+			claims["azp"] = "another-client"
+		},
+	}
+	for name, tamper := range tests {
+		suite.Run(name, func() {
+			suite.idp.tamper = tamper
+			_, err := suite.signIn(verifier, nonce)
+			suite.Error(err)
+		})
+	}
+}
+
+func (suite *ProviderSuite) TestExchange_SymmetricSignature() {
+	suite.idp.signSecret = true
+	_, err := suite.signIn(verifier, nonce)
+	suite.ErrorIs(err, jwt.ErrTokenSignatureInvalid)
+}
+
+func (suite *ProviderSuite) TestExchange_KeyRotation() {
+	now := time.Now()
+	suite.provider.now = func() time.Time { return now }
+
+	_, err := suite.signIn(verifier, nonce)
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.idp.jwksHits)
+
+	// Known keys are not fetched again.
+	_, err = suite.signIn(verifier, nonce)
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.idp.jwksHits)
+
+	// A key the JWKS did not have a moment ago is not looked for again yet.
+	suite.idp.rotateKey()
+	_, err = suite.signIn(verifier, nonce)
+	suite.ErrorContains(err, "unknown signing key")
+	suite.Equal(1, suite.idp.jwksHits)
+
+	now = now.Add(keyRefreshInterval)
+	_, err = suite.signIn(verifier, nonce)
+	suite.NoError(err)
+	suite.Equal(2, suite.idp.jwksHits)
+}
+
+func (suite *ProviderSuite) TestDiscovery_IssuerMismatch() {
+	provider := New(Config{Issuer: suite.idp.issuer() + "/", ClientID: clientID, RedirectURL: redirectURL})
+
+	_, err := provider.AuthCodeURL("state-1", nonce, s256(verifier))
+	suite.ErrorContains(err, "does not match")
+}
+
+func TestProviderSuite(t *testing.T) {
+	suite.Run(t, new(ProviderSuite))
+}
@@ -0,0 +1,139 @@
+/*
+Package apikeyrepo provides methods for managing API keys in a MongoDB collection.
+
+It supports saving keys, retrieving them by ID or by owner, recording when they were
+last used, and deleting them one at a time or all keys of a user. Expired keys are
+removed by the TTL index created in db.Migrate.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/google/uuid: UUID generation for key IDs.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+- github.com/beka-birhanu/domain/models/api_key: API key model definitions.
+*/
+package apikeyrepo
+
+import (
+	"context"
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repo represents a repository for managing API keys.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irepo.APIKey
+var _ irepo.APIKey = &Repo{}
+
+// New creates a new Repo for managing API keys with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// Save adds a new API key to the collection.
+func (r *Repo) Save(key *apikeymodel.APIKey) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, key.ToBSON()); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// ByID returns an API key by ID. Returns an error if the key is not found.
+func (r *Repo) ByID(id uuid.UUID) (*apikeymodel.APIKey, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	var keyBSON apikeymodel.APIKeyBSON
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&keyBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errdmn.InvalidAPIKey
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return apikeymodel.FromBSON(&keyBSON), nil
+}
+
+// ByUser returns the API keys of the given user, newest first.
+func (r *Repo) ByUser(userID uuid.UUID) ([]*apikeymodel.APIKey, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	results, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	keys := []*apikeymodel.APIKey{}
+	for results.Next(ctx) {
+		var keyBSON apikeymodel.APIKeyBSON
+		if err := results.Decode(&keyBSON); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		keys = append(keys, apikeymodel.FromBSON(&keyBSON))
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return keys, nil
+}
+
+// MarkUsed records the time the key was last used.
+func (r *Repo) MarkUsed(id uuid.UUID, at time.Time) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"lastUsedAt": at}}
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// Delete removes the API key with the given ID owned by the given user.
+// Returns an error if the user has no such key.
+func (r *Repo) Delete(id, userID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return errdmn.APIKeyNotFound
+	}
+	return nil
+}
+
+// DeleteByUser removes every API key of the given user.
+func (r *Repo) DeleteByUser(userID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
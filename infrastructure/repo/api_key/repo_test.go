@@ -0,0 +1,139 @@
+package apikeyrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	apikeymodel "github.com/beka-birhanu/task_manager_final/domain/models/api_key"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
+	apikeyrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/api_key"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *apikeyrepo.Repo
+	collection *mongo.Collection
+	key        *apikeymodel.APIKey
+}
+
+func (suite *APIKeyRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("apiKeys")
+	suite.repo = apikeyrepo.New(client, "test_db", "apiKeys")
+}
+
+func (suite *APIKeyRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *APIKeyRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.key = newKey(uuid.New(), time.Now().UTC().Add(-time.Hour))
+	err = suite.repo.Save(suite.key)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+// newKey returns an API key of the given user created at the given time.
+func newKey(userID uuid.UUID, createdAt time.Time) *apikeymodel.APIKey {
+	return apikeymodel.FromBSON(&apikeymodel.APIKeyBSON{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       "ci",
+		Scopes:     []rolemodel.Permission{rolemodel.TaskRead},
+		SecretHash: "hashed_secret",
+		CreatedAt:  createdAt.Truncate(time.Millisecond),
+	})
+}
+
+func (suite *APIKeyRepositorySuite) TestByID() {
+	key, err := suite.repo.ByID(suite.key.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.key.Name(), key.Name())
+	assert.Equal(suite.T(), suite.key.Scopes(), key.Scopes())
+	assert.Equal(suite.T(), suite.key.SecretHash(), key.SecretHash())
+	assert.True(suite.T(), key.ExpiresAt().IsZero())
+
+	_, err = suite.repo.ByID(uuid.New())
+	assert.Equal(suite.T(), errdmn.InvalidAPIKey, err)
+}
+
+func (suite *APIKeyRepositorySuite) TestByUser() {
+	newer := newKey(suite.key.UserID(), time.Now().UTC())
+	assert.NoError(suite.T(), suite.repo.Save(newer))
+	assert.NoError(suite.T(), suite.repo.Save(newKey(uuid.New(), time.Now().UTC())))
+
+	keys, err := suite.repo.ByUser(suite.key.UserID())
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 2)
+	assert.Equal(suite.T(), newer.ID(), keys[0].ID())
+	assert.Equal(suite.T(), suite.key.ID(), keys[1].ID())
+
+	keys, err = suite.repo.ByUser(uuid.New())
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), keys)
+}
+
+func (suite *APIKeyRepositorySuite) TestMarkUsed() {
+	at := time.Now().UTC().Truncate(time.Millisecond)
+	err := suite.repo.MarkUsed(suite.key.ID(), at)
+	assert.NoError(suite.T(), err)
+
+	key, err := suite.repo.ByID(suite.key.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), at, key.LastUsedAt())
+}
+
+func (suite *APIKeyRepositorySuite) TestDelete() {
+	err := suite.repo.Delete(suite.key.ID(), uuid.New())
+	assert.Equal(suite.T(), errdmn.APIKeyNotFound, err)
+
+	err = suite.repo.Delete(suite.key.ID(), suite.key.UserID())
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.ByID(suite.key.ID())
+	assert.Equal(suite.T(), errdmn.InvalidAPIKey, err)
+
+	err = suite.repo.Delete(suite.key.ID(), suite.key.UserID())
+	assert.Equal(suite.T(), errdmn.APIKeyNotFound, err)
+}
+
+func (suite *APIKeyRepositorySuite) TestDeleteByUser() {
+	other := newKey(uuid.New(), time.Now().UTC())
+	assert.NoError(suite.T(), suite.repo.Save(other))
+
+	err := suite.repo.DeleteByUser(suite.key.UserID())
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.ByID(suite.key.ID())
+	assert.Equal(suite.T(), errdmn.InvalidAPIKey, err)
+	_, err = suite.repo.ByID(other.ID())
+	assert.NoError(suite.T(), err)
+}
+
+func TestAPIKeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositorySuite))
+}
@@ -0,0 +1,41 @@
+// Package repocursor provides the opaque pagination tokens handed out by the
+// repositories of paginated listings. A token encodes how many items were already
+// returned.
+package repocursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+)
+
+// cursor is the decoded form of a pagination token.
+type cursor struct {
+	Offset int64 `json:"o"`
+}
+
+// Encode returns the pagination token for the given offset.
+func Encode(offset int64) string {
+	raw, _ := json.Marshal(cursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode returns the offset encoded in the pagination token.
+// An empty token starts from the beginning.
+func Decode(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errdmn.InvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Offset < 0 {
+		return 0, errdmn.InvalidCursor
+	}
+	return c.Offset, nil
+}
@@ -0,0 +1,112 @@
+/*
+Package loginattemptrepo provides methods for managing failed login attempts in a MongoDB collection.
+
+Failures are counted with a single update so concurrent attempts are all recorded.
+Records are removed by the TTL index created in db.Migrate once they expire.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+- github.com/beka-birhanu/domain/models/login_attempt: Login attempt model definitions.
+*/
+package loginattemptrepo
+
+import (
+	"context"
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repo represents a repository for managing failed login attempts.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irepo.LoginAttempt
+var _ irepo.LoginAttempt = &Repo{}
+
+// New creates a new Repo for managing failed login attempts with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// ByKey returns the failed attempts recorded under the key.
+// Returns an error if there are none.
+func (r *Repo) ByKey(key string) (*loginattemptmodel.LoginAttempt, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	var attemptBSON loginattemptmodel.LoginAttemptBSON
+	if err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&attemptBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errdmn.LoginAttemptNotFound
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return loginattemptmodel.FromBSON(&attemptBSON), nil
+}
+
+// AddFailure counts a failed attempt under the key. The update is a pipeline so the
+// count is restarted, incremented, and the expiry extended in one atomic operation.
+func (r *Repo) AddFailure(key string, now time.Time, window time.Duration) (*loginattemptmodel.LoginAttempt, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	now = now.UTC()
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$expiresAt", now}},
+				bson.M{"$add": bson.A{"$failures", 1}},
+				1,
+			}},
+			"expiresAt": bson.M{"$max": bson.A{"$expiresAt", now.Add(window)}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attemptBSON loginattemptmodel.LoginAttemptBSON
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attemptBSON); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return loginattemptmodel.FromBSON(&attemptBSON), nil
+}
+
+// Lock refuses signing in for the key until the given time, keeping the record at least as long.
+func (r *Repo) Lock(key string, until time.Time) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	until = until.UTC()
+	update := bson.M{"$max": bson.M{"lockedUntil": until, "expiresAt": until}}
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": key}, update, opts); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// Delete removes the record of the key.
+func (r *Repo) Delete(key string) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
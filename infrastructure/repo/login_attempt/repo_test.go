@@ -0,0 +1,97 @@
+package loginattemptrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	loginattemptrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/login_attempt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginAttemptRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *loginattemptrepo.Repo
+	collection *mongo.Collection
+	now        time.Time
+}
+
+func (suite *LoginAttemptRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("loginAttempts")
+	suite.repo = loginattemptrepo.New(client, "test_db", "loginAttempts")
+}
+
+func (suite *LoginAttemptRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *LoginAttemptRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	suite.now = time.Now().UTC().Truncate(time.Millisecond)
+}
+
+func (suite *LoginAttemptRepositorySuite) TestAddFailure() {
+	_, err := suite.repo.ByKey("user:testuser")
+	assert.Equal(suite.T(), errdmn.LoginAttemptNotFound, err)
+
+	attempt, err := suite.repo.AddFailure("user:testuser", suite.now, time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, attempt.Failures(suite.now))
+	assert.True(suite.T(), suite.now.Add(time.Minute).Equal(attempt.ExpiresAt()))
+
+	attempt, err = suite.repo.AddFailure("user:testuser", suite.now.Add(time.Second), time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, attempt.Failures(suite.now))
+
+	// Failures of an expired record are forgotten.
+	later := suite.now.Add(2 * time.Minute)
+	attempt, err = suite.repo.AddFailure("user:testuser", later, time.Minute)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, attempt.Failures(later))
+}
+
+func (suite *LoginAttemptRepositorySuite) TestLock() {
+	_, err := suite.repo.AddFailure("ip:127.0.0.1", suite.now, time.Minute)
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.repo.Lock("ip:127.0.0.1", suite.now.Add(time.Hour)))
+	assert.NoError(suite.T(), suite.repo.Lock("ip:127.0.0.1", suite.now.Add(time.Minute)))
+
+	attempt, err := suite.repo.ByKey("ip:127.0.0.1")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), suite.now.Add(time.Hour).Equal(attempt.LockedUntil()))
+	assert.True(suite.T(), suite.now.Add(time.Hour).Equal(attempt.ExpiresAt()))
+	assert.Equal(suite.T(), 1, attempt.Failures(suite.now))
+}
+
+func (suite *LoginAttemptRepositorySuite) TestDelete() {
+	_, err := suite.repo.AddFailure("user:testuser", suite.now, time.Minute)
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.repo.Delete("user:testuser"))
+	_, err = suite.repo.ByKey("user:testuser")
+	assert.Equal(suite.T(), errdmn.LoginAttemptNotFound, err)
+}
+
+func TestLoginAttemptRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptRepositorySuite))
+}
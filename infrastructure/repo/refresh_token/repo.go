@@ -0,0 +1,134 @@
+/*
+Package refreshtokenrepo provides methods for managing refresh tokens in a MongoDB collection.
+
+It supports saving and retrieving tokens, marking them as used when they are rotated,
+listing the token families of a user, and revoking whole token families. Expired tokens are removed by the TTL index
+created in db.Migrate.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/google/uuid: UUID generation for token IDs.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+- github.com/beka-birhanu/domain/models/refresh_token: Refresh token model definitions.
+*/
+package refreshtokenrepo
+
+import (
+	"context"
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repo represents a repository for managing refresh tokens.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irepo.RefreshToken
+var _ irepo.RefreshToken = &Repo{}
+
+// New creates a new Repo for managing refresh tokens with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// Save adds a new refresh token to the collection.
+func (r *Repo) Save(token *refreshtokenmodel.RefreshToken) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, token.ToBSON()); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// ByID returns a refresh token by ID. Returns an error if the token is not found.
+func (r *Repo) ByID(id uuid.UUID) (*refreshtokenmodel.RefreshToken, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"_id": id}
+	var tokenBSON refreshtokenmodel.RefreshTokenBSON
+	if err := r.collection.FindOne(ctx, filter).Decode(&tokenBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errdmn.InvalidRefreshToken
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return refreshtokenmodel.FromBSON(&tokenBSON), nil
+}
+
+// MarkUsed records that the token was exchanged. The check and the update happen in a
+// single operation so two concurrent refreshes cannot both use the same token.
+func (r *Repo) MarkUsed(id uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"_id": id, "usedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"usedAt": time.Now().UTC()}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return errdmn.RefreshTokenReused
+	}
+	return nil
+}
+
+// RevokeFamily removes every token of the given family.
+func (r *Repo) RevokeFamily(familyID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"familyId": familyID}); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// FamiliesByUser returns the IDs of the token families issued to the given user.
+func (r *Repo) FamiliesByUser(userID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"familyId": 1})
+	results, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	seen := make(map[uuid.UUID]bool)
+	var families []uuid.UUID
+	for results.Next(ctx) {
+		var tokenBSON refreshtokenmodel.RefreshTokenBSON
+		if err := results.Decode(&tokenBSON); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		if !seen[tokenBSON.FamilyID] {
+			seen[tokenBSON.FamilyID] = true
+			families = append(families, tokenBSON.FamilyID)
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return families, nil
+}
@@ -0,0 +1,137 @@
+package refreshtokenrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	refreshtokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/refresh_token"
+	refreshtokenrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/refresh_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefreshTokenRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *refreshtokenrepo.Repo
+	collection *mongo.Collection
+	token      *refreshtokenmodel.RefreshToken
+}
+
+func (suite *RefreshTokenRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("refreshTokens")
+	suite.repo = refreshtokenrepo.New(client, "test_db", "refreshTokens")
+}
+
+func (suite *RefreshTokenRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *RefreshTokenRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.token = refreshtokenmodel.FromBSON(&refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   uuid.New(),
+		UserID:     uuid.New(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond),
+	})
+
+	err = suite.repo.Save(suite.token)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *RefreshTokenRepositorySuite) TestByID() {
+	token, err := suite.repo.ByID(suite.token.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.token.FamilyID(), token.FamilyID())
+	assert.Equal(suite.T(), suite.token.SecretHash(), token.SecretHash())
+	assert.False(suite.T(), token.IsUsed())
+
+	_, err = suite.repo.ByID(uuid.New())
+	assert.Equal(suite.T(), errdmn.InvalidRefreshToken, err)
+}
+
+func (suite *RefreshTokenRepositorySuite) TestMarkUsed() {
+	err := suite.repo.MarkUsed(suite.token.ID())
+	assert.NoError(suite.T(), err)
+
+	token, err := suite.repo.ByID(suite.token.ID())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), token.IsUsed())
+
+	err = suite.repo.MarkUsed(suite.token.ID())
+	assert.Equal(suite.T(), errdmn.RefreshTokenReused, err)
+}
+
+func (suite *RefreshTokenRepositorySuite) TestRevokeFamily() {
+	sibling := refreshtokenmodel.FromBSON(&refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   suite.token.FamilyID(),
+		UserID:     suite.token.UserID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	})
+	assert.NoError(suite.T(), suite.repo.Save(sibling))
+
+	err := suite.repo.RevokeFamily(suite.token.FamilyID())
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.ByID(suite.token.ID())
+	assert.Equal(suite.T(), errdmn.InvalidRefreshToken, err)
+	_, err = suite.repo.ByID(sibling.ID())
+	assert.Equal(suite.T(), errdmn.InvalidRefreshToken, err)
+}
+
+func (suite *RefreshTokenRepositorySuite) TestFamiliesByUser() {
+	sibling := refreshtokenmodel.FromBSON(&refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   suite.token.FamilyID(),
+		UserID:     suite.token.UserID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	})
+	otherSession := refreshtokenmodel.FromBSON(&refreshtokenmodel.RefreshTokenBSON{
+		ID:         uuid.New(),
+		FamilyID:   uuid.New(),
+		UserID:     suite.token.UserID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	})
+	assert.NoError(suite.T(), suite.repo.Save(sibling))
+	assert.NoError(suite.T(), suite.repo.Save(otherSession))
+
+	families, err := suite.repo.FamiliesByUser(suite.token.UserID())
+	assert.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []uuid.UUID{suite.token.FamilyID(), otherSession.FamilyID()}, families)
+
+	families, err = suite.repo.FamiliesByUser(uuid.New())
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), families)
+}
+
+func TestRefreshTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositorySuite))
+}
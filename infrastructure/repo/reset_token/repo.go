@@ -0,0 +1,102 @@
+/*
+Package resettokenrepo provides methods for managing password reset tokens in a MongoDB collection.
+
+It supports saving and retrieving tokens, marking them as used, and removing the tokens of a user.
+Expired tokens are removed by the TTL index created in db.Migrate.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/google/uuid: UUID generation for token IDs.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+- github.com/beka-birhanu/domain/models/reset_token: Password reset token model definitions.
+*/
+package resettokenrepo
+
+import (
+	"context"
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repo represents a repository for managing password reset tokens.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irepo.ResetToken
+var _ irepo.ResetToken = &Repo{}
+
+// New creates a new Repo for managing password reset tokens with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// Save adds a new password reset token to the collection.
+func (r *Repo) Save(token *resettokenmodel.ResetToken) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, token.ToBSON()); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// ByID returns a password reset token by ID. Returns an error if the token is not found.
+func (r *Repo) ByID(id uuid.UUID) (*resettokenmodel.ResetToken, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"_id": id}
+	var tokenBSON resettokenmodel.ResetTokenBSON
+	if err := r.collection.FindOne(ctx, filter).Decode(&tokenBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errdmn.InvalidResetToken
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return resettokenmodel.FromBSON(&tokenBSON), nil
+}
+
+// MarkUsed records that the token was used. The check and the update happen in a
+// single operation so two concurrent resets cannot both use the same token.
+func (r *Repo) MarkUsed(id uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"_id": id, "usedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"usedAt": time.Now().UTC()}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return errdmn.ResetTokenUsed
+	}
+	return nil
+}
+
+// DeleteByUser removes every password reset token of the given user.
+func (r *Repo) DeleteByUser(userID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package resettokenrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	resettokenmodel "github.com/beka-birhanu/task_manager_final/domain/models/reset_token"
+	resettokenrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/reset_token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ResetTokenRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *resettokenrepo.Repo
+	collection *mongo.Collection
+	token      *resettokenmodel.ResetToken
+}
+
+func (suite *ResetTokenRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("resetTokens")
+	suite.repo = resettokenrepo.New(client, "test_db", "resetTokens")
+}
+
+func (suite *ResetTokenRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *ResetTokenRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.token = resettokenmodel.FromBSON(&resettokenmodel.ResetTokenBSON{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond),
+	})
+
+	err = suite.repo.Save(suite.token)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *ResetTokenRepositorySuite) TestByID() {
+	token, err := suite.repo.ByID(suite.token.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.token.UserID(), token.UserID())
+	assert.Equal(suite.T(), suite.token.SecretHash(), token.SecretHash())
+	assert.False(suite.T(), token.IsUsed())
+
+	_, err = suite.repo.ByID(uuid.New())
+	assert.Equal(suite.T(), errdmn.InvalidResetToken, err)
+}
+
+func (suite *ResetTokenRepositorySuite) TestMarkUsed() {
+	err := suite.repo.MarkUsed(suite.token.ID())
+	assert.NoError(suite.T(), err)
+
+	token, err := suite.repo.ByID(suite.token.ID())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), token.IsUsed())
+
+	err = suite.repo.MarkUsed(suite.token.ID())
+	assert.Equal(suite.T(), errdmn.ResetTokenUsed, err)
+}
+
+func (suite *ResetTokenRepositorySuite) TestDeleteByUser() {
+	sibling := resettokenmodel.FromBSON(&resettokenmodel.ResetTokenBSON{
+		ID:         uuid.New(),
+		UserID:     suite.token.UserID(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	})
+	other := resettokenmodel.FromBSON(&resettokenmodel.ResetTokenBSON{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		SecretHash: "hashed_secret",
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+	})
+	assert.NoError(suite.T(), suite.repo.Save(sibling))
+	assert.NoError(suite.T(), suite.repo.Save(other))
+
+	err := suite.repo.DeleteByUser(suite.token.UserID())
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.ByID(suite.token.ID())
+	assert.Equal(suite.T(), errdmn.InvalidResetToken, err)
+	_, err = suite.repo.ByID(sibling.ID())
+	assert.Equal(suite.T(), errdmn.InvalidResetToken, err)
+	_, err = suite.repo.ByID(other.ID())
+	assert.NoError(suite.T(), err)
+}
+
+func TestResetTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ResetTokenRepositorySuite))
+}
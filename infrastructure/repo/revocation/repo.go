@@ -0,0 +1,75 @@
+/*
+Package revocationrepo provides a token revocation store backed by a MongoDB collection.
+
+Each revoked identifier is kept with the time until which it stays revoked. The TTL index
+created in db.Migrate removes entries once that time has passed.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+*/
+package revocationrepo
+
+import (
+	"context"
+	"time"
+
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repo is a revocation store persisted in MongoDB.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irevocation.Store
+var _ irevocation.Store = &Repo{}
+
+// New creates a new Repo with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// Revoke marks the identifier as revoked until the given time.
+// Revoking an identifier again keeps the later of the two times.
+func (r *Repo) Revoke(id string, until time.Time) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"_id": id}
+	update := bson.M{"$max": bson.M{"expiresAt": until.UTC()}}
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// IsRevoked reports whether any of the given identifiers is revoked.
+// Expiry is checked explicitly since the TTL monitor only runs periodically.
+func (r *Repo) IsRevoked(ids ...string) (bool, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       bson.M{"$in": ids},
+		"expiresAt": bson.M{"$gt": time.Now().UTC()},
+	}
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, errdmn.NewUnexpected(err.Error())
+	}
+	return count > 0, nil
+}
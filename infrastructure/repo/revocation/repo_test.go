@@ -0,0 +1,82 @@
+package revocationrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	revocationrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/revocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RevocationRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *revocationrepo.Repo
+	collection *mongo.Collection
+}
+
+func (suite *RevocationRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("revokedTokens")
+	suite.repo = revocationrepo.New(client, "test_db", "revokedTokens")
+}
+
+func (suite *RevocationRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *RevocationRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *RevocationRepositorySuite) TestRevoke() {
+	err := suite.repo.Revoke("token", time.Now().Add(time.Minute))
+	assert.NoError(suite.T(), err)
+
+	revoked, err := suite.repo.IsRevoked("other", "token")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), revoked)
+
+	revoked, err = suite.repo.IsRevoked("other")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), revoked)
+}
+
+func (suite *RevocationRepositorySuite) TestRevoke_Expired() {
+	err := suite.repo.Revoke("token", time.Now().Add(-time.Minute))
+	assert.NoError(suite.T(), err)
+
+	revoked, err := suite.repo.IsRevoked("token")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), revoked)
+}
+
+func (suite *RevocationRepositorySuite) TestRevoke_KeepsLatest() {
+	assert.NoError(suite.T(), suite.repo.Revoke("session", time.Now().Add(time.Hour)))
+	assert.NoError(suite.T(), suite.repo.Revoke("session", time.Now().Add(-time.Minute)))
+
+	revoked, err := suite.repo.IsRevoked("session")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), revoked)
+}
+
+func TestRevocationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RevocationRepositorySuite))
+}
@@ -1,7 +1,8 @@
 /*
 Package taskrepo provides methods for managing tasks in a MongoDB collection.
 
-It supports adding, updating, deleting, and retrieving tasks. Errors related to task
+It supports adding, updating, deleting, and retrieving tasks, including filtered,
+sorted, and paginated listings and full-text search. Errors related to task
 operations are handled using custom domain-specific errors.
 
 Dependencies:
@@ -14,13 +15,17 @@ package taskrepo
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	repocursor "github.com/beka-birhanu/task_manager_final/infrastructure/repo/cursor"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -28,16 +33,19 @@ import (
 // Repo represents a repository for managing tasks.
 type Repo struct {
 	collection *mongo.Collection
+	workflows  string // Name of the collection, in the same database, the workflows of projects are stored in.
 }
 
 // Ensure Repo implements irepo.Task
 var _ irepo.Task = &Repo{}
 
 // New creates a new Repo for managing tasks with the given MongoDB client, database name, and collection name.
-func New(client *mongo.Client, dbName, collectionName string) *Repo {
+// The workflows of projects, which decide when tasks are done, are read from the workflow collection.
+func New(client *mongo.Client, dbName, collectionName, workflowCollectionName string) *Repo {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &Repo{
 		collection: collection,
+		workflows:  workflowCollectionName,
 	}
 }
 
@@ -58,6 +66,13 @@ func (r *Repo) Save(task *taskmodel.Task) error {
 			"description": task.Description(),
 			"dueDate":     task.DueDate(),
 			"status":      task.Status(),
+			"priority":    task.Priority(),
+			"tags":        task.Tags(),
+			"project":     task.Project(),
+			"parentId":    task.ParentID(),
+			"ownerId":     task.OwnerID(),
+			"creatorId":   task.CreatorID(),
+			"assigneeIds": task.AssigneeIDs(),
 			"updatedAt":   time.Now(),
 		},
 	}
@@ -88,26 +103,376 @@ func (r *Repo) Delete(id uuid.UUID) error {
 	return nil
 }
 
-// GetAll returns a list of all tasks.
-func (r *Repo) GetAll() ([]*taskmodel.Task, error) {
+// ReassignOwner gives every task owned by one user to another.
+func (r *Repo) ReassignOwner(from, to uuid.UUID) error {
 	ctx, cancel := createScopedContext()
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	filter := bson.M{"ownerId": from}
+	update := bson.M{"$set": bson.M{"ownerId": to, "updatedAt": time.Now()}}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// DeleteByOwner removes every task owned by the user. Subtasks of those tasks that
+// other users own are detached first, so none is left under a missing parent.
+func (r *Repo) DeleteByOwner(ownerID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	owned, err := r.collection.Distinct(ctx, "_id", bson.M{"ownerId": ownerID})
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if len(owned) > 0 {
+		filter := bson.M{"parentId": bson.M{"$in": owned}, "ownerId": bson.M{"$ne": ownerID}}
+		update := bson.M{"$set": bson.M{"parentId": uuid.Nil, "updatedAt": time.Now()}}
+		if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+			return errdmn.NewUnexpected(err.Error())
+		}
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"ownerId": ownerID}); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// UnassignUser removes the user from the assignees of every task.
+func (r *Repo) UnassignUser(userID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"assigneeIds": userID}
+	update := bson.M{
+		"$pull": bson.M{"assigneeIds": userID},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// List returns a page of the tasks matching the query.
+// The cursor is an opaque token encoding how many tasks were already returned.
+func (r *Repo) List(query irepo.ListTasks) (*irepo.TaskPage, error) {
+	offset, err := repocursor.Decode(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := 1
+	if query.SortDesc {
+		direction = -1
+	}
+
+	// Tasks of the same priority are ordered by due date.
+	sort := bson.D{{Key: query.SortBy, Value: direction}}
+	if query.SortBy == irepo.TaskSortByPriority {
+		sort = append(sort, bson.E{Key: irepo.TaskSortByDueDate, Value: direction})
+	}
+	sort = append(sort, bson.E{Key: "_id", Value: direction})
+
+	// Fetch one extra task to find out whether another page follows.
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(offset).
+		SetLimit(int64(query.Limit) + 1)
+
+	tasks, err := r.find(listFilter(query), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &irepo.TaskPage{Tasks: tasks}
+	if len(tasks) > query.Limit {
+		page.Tasks = tasks[:query.Limit]
+		page.NextCursor = repocursor.Encode(offset + int64(query.Limit))
+	}
+	return page, nil
+}
+
+// Search returns the tasks matching the search terms, ordered by text score.
+// It relies on the text index over title and description created by db.Migrate.
+func (r *Repo) Search(query irepo.SearchTasks) ([]*irepo.TaskSearchResult, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"$text": bson.M{"$search": query.Text}}
+	if query.VisibleTo != uuid.Nil {
+		filter["$or"] = visibleTo(query.VisibleTo)
+	}
+
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	opts := options.Find().
+		SetProjection(score).
+		SetSort(score).
+		SetLimit(int64(query.Limit))
+
+	matches, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer matches.Close(ctx)
+
+	var results []*irepo.TaskSearchResult
+	for matches.Next(ctx) {
+		var match struct {
+			taskmodel.TaskBSON `bson:",inline"`
+			Score              float64 `bson:"score"`
+		}
+		if err := matches.Decode(&match); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		results = append(results, &irepo.TaskSearchResult{
+			Task:  taskmodel.FromBSON(&match.TaskBSON),
+			Score: match.Score,
+		})
+	}
+	if err := matches.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return results, nil
+}
+
+// ListTags returns the tags of the tasks owned by or assigned to the user, or of every
+// task for uuid.Nil, most used first and then in alphabetical order.
+func (r *Repo) ListTags(userID uuid.UUID) ([]*irepo.TagUsage, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	match := bson.M{"tags.0": bson.M{"$exists": true}}
+	if userID != uuid.Nil {
+		match["$or"] = visibleTo(userID)
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	results, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	tags := []*irepo.TagUsage{}
+	for results.Next(ctx) {
+		var usage struct {
+			Tag   string `bson:"_id"`
+			Count int    `bson:"count"`
+		}
+		if err := results.Decode(&usage); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		tags = append(tags, &irepo.TagUsage{Tag: usage.Tag, Count: usage.Count})
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return tags, nil
+}
+
+// CountTagged returns the number of tasks with the tag.
+func (r *Repo) CountTagged(tag string) (int, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"tags": tag})
+	if err != nil {
+		return 0, errdmn.NewUnexpected(err.Error())
+	}
+	return int(count), nil
+}
+
+// RenameTag replaces a tag with another on every task that has it. Tasks that already
+// have the new tag keep a single copy of it.
+func (r *Repo) RenameTag(from, to string) (int, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"tags": from}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tags": bson.M{"$setUnion": bson.A{
+				bson.M{"$setDifference": bson.A{"$tags", bson.A{from}}},
+				bson.A{to},
+			}},
+			"updatedAt": time.Now(),
+		}}},
+	}
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, errdmn.NewUnexpected(err.Error())
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// Subtasks returns the subtasks of a task, ordered by priority, then due date.
+func (r *Repo) Subtasks(parentID uuid.UUID) ([]*taskmodel.Task, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: irepo.TaskSortByPriority, Value: 1},
+		{Key: irepo.TaskSortByDueDate, Value: 1},
+		{Key: "_id", Value: 1},
+	})
+	return r.find(bson.M{"parentId": parentID}, opts)
+}
+
+// SubtaskProgress counts the subtasks of each of the tasks, and those of them that are done,
+// that is in a final state of the workflow of their project.
+func (r *Repo) SubtaskProgress(parentIDs ...uuid.UUID) (map[uuid.UUID]taskmodel.Progress, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	progress := make(map[uuid.UUID]taskmodel.Progress)
+	if len(parentIDs) == 0 {
+		return progress, nil
+	}
+
+	done := bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$status", finalStates}}, 1, 0}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"parentId": bson.M{"$in": parentIDs}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.workflows,
+			"localField":   "project",
+			"foreignField": "_id",
+			"as":           "workflow",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"workflow": bson.M{"$arrayElemAt": bson.A{"$workflow", 0}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$parentId",
+			"total": bson.M{"$sum": 1},
+			"done":  bson.M{"$sum": done},
+		}}},
+	}
+
+	results, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	for results.Next(ctx) {
+		var counts struct {
+			ParentID uuid.UUID `bson:"_id"`
+			Total    int       `bson:"total"`
+			Done     int       `bson:"done"`
+		}
+		if err := results.Decode(&counts); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		progress[counts.ParentID] = taskmodel.Progress{Done: counts.Done, Total: counts.Total}
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return progress, nil
+}
+
+// finalStates is the expression for the final states of the workflow looked up for a
+// task. Tasks without a workflow follow the default one, and workflows stored before
+// final states were recorded have the default ones, as in workflowmodel.FromBSON.
+var finalStates = bson.M{"$ifNull": bson.A{
+	"$workflow.final",
+	bson.M{"$cond": bson.A{
+		bson.M{"$in": bson.A{workflowmodel.StateDone, bson.M{"$ifNull": bson.A{"$workflow.states", bson.A{workflowmodel.StateDone}}}}},
+		bson.A{workflowmodel.StateDone},
+		bson.A{},
+	}},
+}}
+
+// DetachSubtasks leaves the subtasks of a task without a parent.
+func (r *Repo) DetachSubtasks(parentID uuid.UUID) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	filter := bson.M{"parentId": parentID}
+	update := bson.M{"$set": bson.M{"parentId": uuid.Nil, "updatedAt": time.Now()}}
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// listFilter builds the MongoDB filter for a task listing.
+func listFilter(query irepo.ListTasks) bson.M {
+	filter := bson.M{}
+	// Tasks must match one of the alternatives of every entry.
+	var anyOf bson.A
+	if query.VisibleTo != uuid.Nil {
+		anyOf = append(anyOf, bson.M{"$or": visibleTo(query.VisibleTo)})
+	}
+	if query.AssigneeID != uuid.Nil {
+		filter["assigneeIds"] = query.AssigneeID
+	}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if len(query.Tags) > 0 {
+		operator := "$in"
+		if query.AllTags {
+			operator = "$all"
+		}
+		filter["tags"] = bson.M{operator: query.Tags}
+	}
+
+	dueDate := bson.M{}
+	if !query.DueAfter.IsZero() {
+		dueDate["$gte"] = query.DueAfter
+	}
+	if !query.DueBefore.IsZero() {
+		dueDate["$lte"] = query.DueBefore
+	}
+	if len(dueDate) > 0 {
+		filter["dueDate"] = dueDate
+	}
+
+	if query.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"}
+		anyOf = append(anyOf, bson.M{"$or": bson.A{
+			bson.M{"title": pattern},
+			bson.M{"description": pattern},
+		}})
+	}
+	if len(anyOf) > 0 {
+		filter["$and"] = anyOf
+	}
+	return filter
+}
+
+// visibleTo lists the alternatives matching the tasks a user owns or is assigned to.
+func visibleTo(userID uuid.UUID) bson.A {
+	return bson.A{
+		bson.M{"ownerId": userID},
+		bson.M{"assigneeIds": userID},
+	}
+}
+
+// find returns the tasks matching the given filter.
+func (r *Repo) find(filter bson.M, opts ...*options.FindOptions) ([]*taskmodel.Task, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	results, err := r.collection.Find(ctx, filter, opts...)
 	if err != nil {
 		return nil, errdmn.NewUnexpected(err.Error())
 	}
-	defer cursor.Close(ctx)
+	defer results.Close(ctx)
 
 	var tasks []*taskmodel.Task
-	for cursor.Next(ctx) {
+	for results.Next(ctx) {
 		var taskBSON taskmodel.TaskBSON
-		if err := cursor.Decode(&taskBSON); err != nil {
+		if err := results.Decode(&taskBSON); err != nil {
 			return nil, errdmn.NewUnexpected(err.Error())
 		}
 		tasks = append(tasks, taskmodel.FromBSON(&taskBSON))
 	}
-	if err := cursor.Err(); err != nil {
+	if err := results.Err(); err != nil {
 		return nil, errdmn.NewUnexpected(err.Error())
 	}
 	return tasks, nil
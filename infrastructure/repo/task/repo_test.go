@@ -5,8 +5,11 @@ import (
 	"testing"
 	"time"
 
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	taskmodel "github.com/beka-birhanu/task_manager_final/domain/models/task"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
 	taskrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/task"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
@@ -31,7 +34,17 @@ func (suite *TaskRepositorySuite) SetupSuite() {
 
 	suite.client = client
 	suite.collection = client.Database("test_db").Collection("tasks")
-	suite.repo = taskrepo.New(client, "test_db", "tasks")
+	suite.repo = taskrepo.New(client, "test_db", "tasks", "workflows")
+}
+
+// createTextIndex creates the text index that db.Migrate sets up for task search.
+func (suite *TaskRepositorySuite) createTextIndex() {
+	_, err := suite.collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+	})
+	if err != nil {
+		suite.T().Fatal(err)
+	}
 }
 
 func (suite *TaskRepositorySuite) TearDownSuite() {
@@ -54,6 +67,7 @@ func (suite *TaskRepositorySuite) SetupTest() {
 		Description: "Test Description",
 		DueDate:     time.Now(),
 		Status:      "pending",
+		OwnerID:     uuid.New(),
 	})
 	if err != nil {
 		suite.T().Fatal(err)
@@ -91,10 +105,330 @@ func (suite *TaskRepositorySuite) TestDeleteTask() {
 	assert.Equal(suite.T(), "task Not Found", err.Error())
 }
 
-func (suite *TaskRepositorySuite) TestGetAllTasks() {
-	tasks, err := suite.repo.GetAll()
+func (suite *TaskRepositorySuite) TestReassignOwner() {
+	newOwner := uuid.New()
+	err := suite.repo.ReassignOwner(suite.task.OwnerID(), newOwner)
+	assert.NoError(suite.T(), err)
+
+	task, err := suite.repo.GetSingle(suite.task.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), newOwner, task.OwnerID())
+}
+
+func (suite *TaskRepositorySuite) TestDeleteByOwner() {
+	err := suite.repo.DeleteByOwner(suite.task.OwnerID())
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.GetSingle(suite.task.ID())
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TaskRepositorySuite) TestUnassignUser() {
+	assignee, other := uuid.New(), uuid.New()
+	suite.Require().NoError(suite.task.Assign(assignee))
+	suite.Require().NoError(suite.task.Assign(other))
+	suite.Require().NoError(suite.repo.Save(suite.task))
+
+	err := suite.repo.UnassignUser(assignee)
+	assert.NoError(suite.T(), err)
+
+	task, err := suite.repo.GetSingle(suite.task.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uuid.UUID{other}, task.AssigneeIDs())
+	assert.Equal(suite.T(), suite.task.CreatorID(), task.CreatorID())
+}
+
+func (suite *TaskRepositorySuite) TestListTasks() {
+	page, err := suite.repo.List(irepo.ListTasks{SortBy: irepo.TaskSortByDueDate, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 1) // Adjusted to match the number of tasks saved in SetupTest
+	assert.Empty(suite.T(), page.NextCursor)
+}
+
+func (suite *TaskRepositorySuite) TestListTasks_Filters() {
+	page, err := suite.repo.List(irepo.ListTasks{VisibleTo: suite.task.OwnerID(), Text: "test desc", SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 1)
+
+	page, err = suite.repo.List(irepo.ListTasks{VisibleTo: uuid.New(), SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 0)
+
+	page, err = suite.repo.List(irepo.ListTasks{Status: "done", SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 0)
+
+	assignee := uuid.New()
+	suite.Require().NoError(suite.task.Assign(assignee))
+	suite.Require().NoError(suite.repo.Save(suite.task))
+
+	page, err = suite.repo.List(irepo.ListTasks{AssigneeID: assignee, SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 1)
+
+	page, err = suite.repo.List(irepo.ListTasks{AssigneeID: uuid.New(), SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 0)
+
+	// Tasks assigned to a user are visible to them, also alongside a text match.
+	page, err = suite.repo.List(irepo.ListTasks{VisibleTo: assignee, Text: "test desc", SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 1)
+}
+
+func (suite *TaskRepositorySuite) TestSearchTasks() {
+	suite.createTextIndex()
+
+	other, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Quarterly report",
+		Description: "Write the report for the board",
+		DueDate:     time.Now(),
+		Status:      "pending",
+		OwnerID:     uuid.New(),
+	})
+	assert.NoError(suite.T(), suite.repo.Save(other))
+
+	results, err := suite.repo.Search(irepo.SearchTasks{Text: "reports", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), other.ID(), results[0].Task.ID())
+	assert.Greater(suite.T(), results[0].Score, 0.0)
+
+	results, err = suite.repo.Search(irepo.SearchTasks{VisibleTo: suite.task.OwnerID(), Text: "report", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 0)
+
+	assignee := uuid.New()
+	suite.Require().NoError(other.Assign(assignee))
+	suite.Require().NoError(suite.repo.Save(other))
+
+	results, err = suite.repo.Search(irepo.SearchTasks{VisibleTo: assignee, Text: "report", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+}
+
+func (suite *TaskRepositorySuite) TestListTasks_ByPriority() {
+	urgent, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Urgent Task",
+		Description: "Due last but most urgent",
+		DueDate:     time.Now().Add(48 * time.Hour),
+		Status:      "pending",
+		Priority:    "urgent",
+		OwnerID:     suite.task.OwnerID(),
+	})
+	low, _ := taskmodel.New(taskmodel.Config{
+		Title:       "Low Task",
+		Description: "Due first but least urgent",
+		DueDate:     time.Now().Add(-48 * time.Hour),
+		Status:      "pending",
+		Priority:    "low",
+		OwnerID:     suite.task.OwnerID(),
+	})
+	assert.NoError(suite.T(), suite.repo.Save(urgent))
+	assert.NoError(suite.T(), suite.repo.Save(low))
+
+	page, err := suite.repo.List(irepo.ListTasks{SortBy: irepo.TaskSortByPriority, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Tasks, 3)
+	assert.Equal(suite.T(), urgent.ID(), page.Tasks[0].ID())
+	assert.Equal(suite.T(), suite.task.ID(), page.Tasks[1].ID())
+	assert.Equal(suite.T(), low.ID(), page.Tasks[2].ID())
+}
+
+// saveTagged saves a task of the suite's owner with the given tags.
+func (suite *TaskRepositorySuite) saveTagged(tags ...string) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Tagged Task",
+		Description: "A task with tags",
+		DueDate:     time.Now(),
+		Status:      "pending",
+		Tags:        tags,
+		OwnerID:     suite.task.OwnerID(),
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Save(task))
+	return task
+}
+
+func (suite *TaskRepositorySuite) TestListTasks_ByTags() {
+	backend := suite.saveTagged("backend")
+	both := suite.saveTagged("backend", "bug")
+	bug := suite.saveTagged("bug")
+
+	page, err := suite.repo.List(irepo.ListTasks{Tags: []string{"backend", "bug"}, SortBy: irepo.TaskSortByTitle, Limit: 10})
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), tasks, 1) // Adjusted to match the number of tasks saved in SetupTest
+	assert.ElementsMatch(suite.T(), []uuid.UUID{backend.ID(), both.ID(), bug.ID()}, taskIDs(page.Tasks))
+
+	page, err = suite.repo.List(irepo.ListTasks{Tags: []string{"backend", "bug"}, AllTags: true, SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uuid.UUID{both.ID()}, taskIDs(page.Tasks))
+}
+
+func (suite *TaskRepositorySuite) TestListTags() {
+	suite.saveTagged("backend", "bug")
+	suite.saveTagged("bug")
+
+	tags, err := suite.repo.ListTags(uuid.Nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*irepo.TagUsage{{Tag: "bug", Count: 2}, {Tag: "backend", Count: 1}}, tags)
+
+	tags, err = suite.repo.ListTags(uuid.New())
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), tags)
+
+	// Tasks assigned to a user count for them as well as for their owner.
+	assigneeID := uuid.New()
+	assigned := suite.saveTagged("frontend")
+	suite.Require().NoError(assigned.Assign(assigneeID))
+	suite.Require().NoError(suite.repo.Save(assigned))
+	tags, err = suite.repo.ListTags(assigneeID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*irepo.TagUsage{{Tag: "frontend", Count: 1}}, tags)
+
+	// The tasks counted for a tag are the ones listed with it.
+	page, err := suite.repo.List(irepo.ListTasks{VisibleTo: assigneeID, Tags: []string{"frontend"}, SortBy: irepo.TaskSortByTitle, Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uuid.UUID{assigned.ID()}, taskIDs(page.Tasks))
+
+	count, err := suite.repo.CountTagged("bug")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, count)
+}
+
+func (suite *TaskRepositorySuite) TestRenameTag() {
+	renamed := suite.saveTagged("bugs")
+	merged := suite.saveTagged("bug", "bugs", "infra")
+
+	changed, err := suite.repo.RenameTag("bugs", "bug")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, changed)
+
+	task, err := suite.repo.GetSingle(renamed.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"bug"}, task.Tags())
+
+	task, err = suite.repo.GetSingle(merged.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"bug", "infra"}, task.Tags())
+}
+
+// saveSubtask saves a task of the given owner under the suite's task with the given status.
+func (suite *TaskRepositorySuite) saveSubtask(ownerID uuid.UUID, status string) *taskmodel.Task {
+	task, err := taskmodel.New(taskmodel.Config{
+		Title:       "Subtask",
+		Description: "A part of the test task",
+		DueDate:     time.Now(),
+		Status:      status,
+		ParentID:    suite.task.ID(),
+		OwnerID:     ownerID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.Save(task))
+	return task
+}
+
+func (suite *TaskRepositorySuite) TestSubtasks() {
+	open := suite.saveSubtask(suite.task.OwnerID(), "pending")
+	done := suite.saveSubtask(suite.task.OwnerID(), taskmodel.StatusDone)
+
+	subtasks, err := suite.repo.Subtasks(suite.task.ID())
+	assert.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []uuid.UUID{open.ID(), done.ID()}, taskIDs(subtasks))
+
+	progress, err := suite.repo.SubtaskProgress(suite.task.ID(), open.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[uuid.UUID]taskmodel.Progress{suite.task.ID(): {Done: 1, Total: 2}}, progress)
+}
+
+func (suite *TaskRepositorySuite) TestSubtaskProgress_ProjectWorkflow() {
+	workflows := suite.client.Database("test_db").Collection("workflows")
+	suite.Require().NoError(workflows.Drop(context.Background()))
+	workflow, err := workflowmodel.New(workflowmodel.Config{
+		Project: "website",
+		States:  []string{"todo", "shipped", workflowmodel.StateDone},
+		Final:   []string{"shipped"},
+	})
+	suite.Require().NoError(err)
+	_, err = workflows.InsertOne(context.Background(), workflow.ToBSON())
+	suite.Require().NoError(err)
+
+	for _, status := range []string{"shipped", workflowmodel.StateDone} {
+		task, err := taskmodel.New(taskmodel.Config{
+			Title:       "Subtask",
+			Description: "A part of the test task",
+			DueDate:     time.Now(),
+			Status:      status,
+			Project:     "website",
+			ParentID:    suite.task.ID(),
+			OwnerID:     suite.task.OwnerID(),
+			Workflow:    workflow,
+		})
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.repo.Save(task))
+	}
+	suite.saveSubtask(suite.task.OwnerID(), taskmodel.StatusDone)
+
+	// Only shipped finishes the tasks of the project, while done still finishes the others.
+	progress, err := suite.repo.SubtaskProgress(suite.task.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[uuid.UUID]taskmodel.Progress{suite.task.ID(): {Done: 2, Total: 3}}, progress)
+}
+
+func (suite *TaskRepositorySuite) TestDetachSubtasks() {
+	subtask := suite.saveSubtask(suite.task.OwnerID(), "pending")
+
+	err := suite.repo.DetachSubtasks(suite.task.ID())
+	assert.NoError(suite.T(), err)
+
+	task, err := suite.repo.GetSingle(subtask.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uuid.Nil, task.ParentID())
+}
+
+func (suite *TaskRepositorySuite) TestDeleteByOwner_DetachesSubtasks() {
+	subtask := suite.saveSubtask(uuid.New(), "pending")
+
+	err := suite.repo.DeleteByOwner(suite.task.OwnerID())
+	assert.NoError(suite.T(), err)
+
+	task, err := suite.repo.GetSingle(subtask.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uuid.Nil, task.ParentID())
+}
+
+// taskIDs returns the IDs of the tasks.
+func taskIDs(tasks []*taskmodel.Task) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID())
+	}
+	return ids
+}
+
+func (suite *TaskRepositorySuite) TestListTasks_Pagination() {
+	for i := 0; i < 2; i++ {
+		task, _ := taskmodel.New(taskmodel.Config{
+			Title:       "Another Task",
+			Description: "Another Description",
+			DueDate:     time.Now().Add(time.Duration(i+1) * time.Hour),
+			Status:      "pending",
+			OwnerID:     suite.task.OwnerID(),
+		})
+		assert.NoError(suite.T(), suite.repo.Save(task))
+	}
+
+	first, err := suite.repo.List(irepo.ListTasks{SortBy: irepo.TaskSortByDueDate, Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), first.Tasks, 2)
+	assert.NotEmpty(suite.T(), first.NextCursor)
+
+	second, err := suite.repo.List(irepo.ListTasks{SortBy: irepo.TaskSortByDueDate, Limit: 2, Cursor: first.NextCursor})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), second.Tasks, 1)
+	assert.Empty(suite.T(), second.NextCursor)
+
+	_, err = suite.repo.List(irepo.ListTasks{SortBy: irepo.TaskSortByDueDate, Limit: 2, Cursor: "not a cursor"})
+	assert.Error(suite.T(), err)
 }
 
 func (suite *TaskRepositorySuite) TestGetSingleTask() {
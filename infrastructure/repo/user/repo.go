@@ -1,7 +1,8 @@
 /*
 Package userrepo provides methods for managing user models in a MongoDB collection.
 
-It supports saving, retrieving by ID or username, and counting users. Errors related to
+It supports saving, retrieving by ID, username, email or external identity, paginated
+listing, deleting, and counting users and admins. Errors related to
 user operations are handled using custom domain-specific errors.
 
 Dependencies:
@@ -14,17 +15,33 @@ package userrepo
 
 import (
 	"context"
+	"errors"
+	"regexp"
 	"time"
 
 	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
 	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
+	repocursor "github.com/beka-birhanu/task_manager_final/infrastructure/repo/cursor"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Names of the unique indexes created in db.Migrate.
+const (
+	usernameIndex         = "username_1"
+	emailIndex            = "email_1"
+	externalIdentityIndex = "externalIdentities_1"
+)
+
+// dupKeyPattern extracts the name of the index from the message of a duplicate key error,
+// such as "E11000 duplicate key error collection: db.users index: email_1 dup key: { ... }".
+var dupKeyPattern = regexp.MustCompile(`index: (\S+) dup key`)
+
 // Repo handles the persistence of user models.
 type Repo struct {
 	collection *mongo.Collection
@@ -42,32 +59,217 @@ func NewRepo(client *mongo.Client, dbName, collectionName string) *Repo {
 }
 
 // Save inserts or updates a user in the repository.
-// If the user already exists, it updates the existing record.
+// If the user already exists, it updates the existing record, except for the role and
+// whether the user is deactivated, which SaveRole and SaveDeactivation write.
 // If the user does not exist, it adds a new record.
 func (u *Repo) Save(user *usermodel.User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	return saveError(u.save(ctx, user))
+}
+
+// SaveRole writes the role of the user alone, so a save of an older copy of the user
+// made meanwhile cannot undo it. Fails with errdmn.UserNotFound if the user is not stored.
+func (u *Repo) SaveRole(user *usermodel.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return fieldError(u.saveField(ctx, user.ID(), "role", user.Role()))
+}
+
+// SaveDeactivation writes whether the user is deactivated alone, as SaveRole does the role.
+func (u *Repo) SaveDeactivation(user *usermodel.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return fieldError(u.saveField(ctx, user.ID(), "deactivated", user.IsDeactivated()))
+}
+
+// SaveRoleKeepingAdmin writes the role of a user who may have lost the admin role, as
+// SaveRole does, unless no active admin is left afterwards, in which case it fails with
+// errdmn.LastAdmin.
+func (u *Repo) SaveRoleKeepingAdmin(user *usermodel.User) error {
+	return u.keepingAdmin(func(ctx context.Context) error {
+		return u.saveField(ctx, user.ID(), "role", user.Role())
+	})
+}
+
+// SaveDeactivationKeepingAdmin writes whether a user who may have been an active admin is
+// deactivated, as SaveDeactivation does, unless no active admin is left afterwards, in
+// which case it fails with errdmn.LastAdmin.
+func (u *Repo) SaveDeactivationKeepingAdmin(user *usermodel.User) error {
+	return u.keepingAdmin(func(ctx context.Context) error {
+		return u.saveField(ctx, user.ID(), "deactivated", user.IsDeactivated())
+	})
+}
+
+// keepingAdmin runs the write unless no active admin is left afterwards, in which case
+// it fails with errdmn.LastAdmin.
+//
+// The write and the count of the remaining admins run in one transaction, which
+// requires MongoDB to run as a replica set. Counting the admins touches each of them,
+// so two transactions taking the role from the last two admins conflict instead of
+// both committing; the one retried finds no admin left.
+func (u *Repo) keepingAdmin(write func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := u.collection.Database().Client().StartSession()
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(txCtx mongo.SessionContext) (interface{}, error) {
+		if err := write(txCtx); err != nil {
+			return nil, err
+		}
+
+		update := bson.M{"$set": bson.M{"adminCheckedAt": time.Now()}}
+		result, err := u.collection.UpdateMany(txCtx, activeAdmins(), update)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, errdmn.LastAdmin
+		}
+		return nil, nil
+	})
+	if err == errdmn.LastAdmin {
+		return err
+	}
+	return fieldError(err)
+}
+
+// saveField sets a single field of the stored user, returning the driver's errors as is
+// so transactions can tell which of them are worth a retry.
+func (u *Repo) saveField(ctx context.Context, id uuid.UUID, field string, value interface{}) error {
+	update := bson.M{"$set": bson.M{field: value, "updatedAt": time.Now()}}
+	result, err := u.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errdmn.UserNotFound
+	}
+	return nil
+}
+
+// fieldError converts an error from saving a single field of a user to a domain error.
+func fieldError(err error) error {
+	if err == nil || err == errdmn.UserNotFound {
+		return err
+	}
+	return errdmn.NewUnexpected(err.Error())
+}
+
+// save writes the user with the given context, returning the driver's errors as is.
+func (u *Repo) save(ctx context.Context, user *usermodel.User) error {
 	filter := bson.M{"_id": user.ID()}
-	update := bson.M{
-		"$set": bson.M{
-			"username":     user.Username(),
-			"passwordHash": user.PasswordHash(),
-			"isAdmin":      user.IsAdmin(),
-			"updatedAt":    time.Now(),
-		},
+	set := bson.M{
+		"username":      user.Username(),
+		"emailVerified": user.IsEmailVerified(),
+		"passwordHash":  user.PasswordHash(),
+		"updatedAt":     time.Now(),
+	}
+	unset := bson.M{}
+
+	// The role and deactivation are written on their own once the user exists, so a
+	// save of an older copy of the user cannot undo a change to them.
+	setOnInsert := bson.M{
+		"role":        user.Role(),
+		"deactivated": user.IsDeactivated(),
+	}
+
+	// Absent fields are removed rather than stored empty, so the unique email index,
+	// which only covers string emails, ignores users without one.
+	if user.Email() != "" {
+		set["email"] = user.Email()
+	} else {
+		unset["email"] = ""
+	}
+	if user.EmailVerificationHash() != "" {
+		set["emailVerificationHash"] = user.EmailVerificationHash()
+		set["emailVerificationExpiresAt"] = user.EmailVerificationExpiresAt()
+	} else {
+		unset["emailVerificationHash"] = ""
+		unset["emailVerificationExpiresAt"] = ""
+	}
+	if user.TOTPSecret() != "" {
+		set["totpSecret"] = user.TOTPSecret()
+		set["totpLastStep"] = user.TOTPLastStep()
+		set["recoveryCodeHashes"] = user.RecoveryCodeHashes()
+	} else {
+		unset["totpSecret"] = ""
+		unset["totpLastStep"] = ""
+		unset["recoveryCodeHashes"] = ""
+	}
+	if identities := user.ExternalIdentities(); len(identities) > 0 {
+		set["externalIdentities"] = identities
+	} else {
+		unset["externalIdentities"] = ""
+	}
+	if user.PendingTOTPSecret() != "" {
+		set["pendingTotpSecret"] = user.PendingTOTPSecret()
+	} else {
+		unset["pendingTotpSecret"] = ""
+	}
+
+	profile := map[string]string{
+		"displayName": user.DisplayName(),
+		"timeZone":    user.TimeZone(),
+		"locale":      user.Locale(),
+		"avatarUrl":   user.AvatarURL(),
 	}
+	for field, value := range profile {
+		if value != "" {
+			set[field] = value
+		} else {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$set": set, "$unset": unset, "$setOnInsert": setOnInsert}
 
 	opts := options.Update().SetUpsert(true)
 	_, err := u.collection.UpdateOne(ctx, filter, update, opts)
-	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return errdmn.UsernameConflict
-		}
-		return errdmn.NewUnexpected(err.Error())
+	return err
+}
+
+// saveError converts an error from saving a user to a domain error.
+func saveError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch duplicateIndex(err) {
+	case usernameIndex:
+		return errdmn.UsernameConflict
+	case emailIndex:
+		return errdmn.EmailConflict
+	case externalIdentityIndex:
+		return errdmn.ExternalIdentityConflict
 	}
+	return errdmn.NewUnexpected(err.Error())
+}
 
-	return nil
+// duplicateIndex returns the name of the unique index a write failed on, or an empty
+// string when the error is not a duplicate key error. Only the index named in each
+// write error is looked at, not the duplicate value, which users choose.
+func duplicateIndex(err error) string {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return ""
+	}
+	for _, we := range writeErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we) {
+			continue
+		}
+		if match := dupKeyPattern.FindStringSubmatch(we.Message); match != nil {
+			return match[1]
+		}
+	}
+	return ""
 }
 
 // ById retrieves a user by their ID.
@@ -104,6 +306,128 @@ func (u *Repo) ByUsername(username string) (*usermodel.User, error) {
 	return usermodel.FromBSON(&userBSON), nil
 }
 
+// ByEmail retrieves a user by their email address, which must be normalized.
+// Returns an error if the user is not found or if an unexpected error occurs.
+func (u *Repo) ByEmail(email string) (*usermodel.User, error) {
+	return u.findOne(bson.M{"email": email})
+}
+
+// ByExternalIdentity retrieves the user linked to an account at an identity provider.
+// Returns an error if the user is not found or if an unexpected error occurs.
+func (u *Repo) ByExternalIdentity(identity usermodel.ExternalIdentity) (*usermodel.User, error) {
+	return u.findOne(bson.M{"externalIdentities": bson.M{"$elemMatch": bson.M{
+		"issuer":  identity.Issuer,
+		"subject": identity.Subject,
+	}}})
+}
+
+// findOne retrieves the user matching the filter.
+func (u *Repo) findOne(filter bson.M) (*usermodel.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var userBSON usermodel.UserBSON
+	if err := u.collection.FindOne(ctx, filter).Decode(&userBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errdmn.UserNotFound
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return usermodel.FromBSON(&userBSON), nil
+}
+
+// List returns a page of the users matching the query, sorted by username.
+// The cursor is an opaque token encoding how many users were already returned.
+func (u *Repo) List(query irepo.ListUsers) (*irepo.UserPage, error) {
+	offset, err := repocursor.Decode(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra user to find out whether another page follows.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "username", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(int64(query.Limit) + 1)
+
+	users, err := u.find(listFilter(query), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &irepo.UserPage{Users: users}
+	if len(users) > query.Limit {
+		page.Users = users[:query.Limit]
+		page.NextCursor = repocursor.Encode(offset + int64(query.Limit))
+	}
+	return page, nil
+}
+
+// ByIDs retrieves the users with the given IDs. IDs of unknown users are skipped,
+// and the users are returned in no particular order.
+func (u *Repo) ByIDs(ids []uuid.UUID) ([]*usermodel.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return u.find(bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// find returns the users matching the given filter.
+func (u *Repo) find(filter bson.M, opts ...*options.FindOptions) ([]*usermodel.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	results, err := u.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	var users []*usermodel.User
+	for results.Next(ctx) {
+		var userBSON usermodel.UserBSON
+		if err := results.Decode(&userBSON); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		users = append(users, usermodel.FromBSON(&userBSON))
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return users, nil
+}
+
+// listFilter builds the MongoDB filter for a user listing.
+func listFilter(query irepo.ListUsers) bson.M {
+	filter := bson.M{}
+	if query.Role != "" {
+		filter["role"] = query.Role
+	}
+	if query.Text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"username": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+	return filter
+}
+
+// Delete removes a user by ID. Returns an error if the user is not found.
+func (u *Repo) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := u.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return errdmn.UserNotFound
+	}
+	return nil
+}
+
 // Count returns the total number of users in the repository.
 func (u *Repo) Count() (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -115,3 +439,21 @@ func (u *Repo) Count() (int64, error) {
 	}
 	return count, nil
 }
+
+// CountAdmins returns the number of active users with the admin role.
+// Deactivated admins are left out, as they cannot manage users.
+func (u *Repo) CountAdmins() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := u.collection.CountDocuments(ctx, activeAdmins())
+	if err != nil {
+		return 0, errdmn.NewUnexpected(err.Error())
+	}
+	return count, nil
+}
+
+// activeAdmins is the filter matching the active users with the admin role.
+func activeAdmins() bson.M {
+	return bson.M{"role": rolemodel.Admin, "deactivated": bson.M{"$ne": true}}
+}
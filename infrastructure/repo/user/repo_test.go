@@ -4,8 +4,12 @@ import (
 	"context"
 	"testing"
 
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	rolemodel "github.com/beka-birhanu/task_manager_final/domain/models/role"
 	usermodel "github.com/beka-birhanu/task_manager_final/domain/models/user"
 	userrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/user"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,7 +26,8 @@ type UserRepositorySuite struct {
 }
 
 func (suite *UserRepositorySuite) SetupSuite() {
-	// Connect to the MongoDB instance
+	// Connect to the MongoDB instance. The keeping-admin saves run in transactions,
+	// so their tests are skipped unless it runs as a replica set.
 	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
 	client, err := mongo.Connect(context.Background(), clientOptions)
 	if err != nil {
@@ -56,7 +61,6 @@ func (suite *UserRepositorySuite) SetupTest() {
 	user, err := usermodel.New(usermodel.Config{
 		Username:      "testuser",
 		PlainPassword: "testpassword",
-		IsAdmin:       false,
 	})
 	if err != nil {
 		suite.T().Fatal(err)
@@ -75,6 +79,261 @@ func (suite *UserRepositorySuite) TestSave_Success() {
 	assert.Equal(suite.T(), suite.user.PasswordHash(), retrievedUser.PasswordHash())
 }
 
+func (suite *UserRepositorySuite) TestSave_EmailConflict() {
+	assert.NoError(suite.T(), suite.user.UpdateEmail("testuser@example.com"))
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	other, err := usermodel.New(usermodel.Config{
+		Username:      "otheruser",
+		Email:         "TestUser@example.com",
+		PlainPassword: "testpassword",
+	})
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	assert.Equal(suite.T(), errdmn.EmailConflict, suite.repo.Save(other))
+}
+
+func (suite *UserRepositorySuite) TestSave_UsernameConflict() {
+	// A username that reads like the name of another index is still a username conflict.
+	for _, username := range []string{"email_1", "externalIdentities_1"} {
+		first, err := usermodel.New(usermodel.Config{Username: username, PlainPassword: "testpassword"})
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		assert.NoError(suite.T(), suite.repo.Save(first))
+
+		second, err := usermodel.New(usermodel.Config{Username: username, PlainPassword: "testpassword"})
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		assert.Equal(suite.T(), errdmn.UsernameConflict, suite.repo.Save(second))
+	}
+}
+
+func (suite *UserRepositorySuite) TestByEmail() {
+	assert.NoError(suite.T(), suite.user.UpdateEmail("testuser@example.com"))
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	retrievedUser, err := suite.repo.ByEmail("testuser@example.com")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.user.ID(), retrievedUser.ID())
+
+	_, err = suite.repo.ByEmail("nobody@example.com")
+	assert.Equal(suite.T(), errdmn.UserNotFound, err)
+}
+
+func (suite *UserRepositorySuite) TestByIDs() {
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	users, err := suite.repo.ByIDs([]uuid.UUID{suite.user.ID(), uuid.New()})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), suite.user.Username(), users[0].Username())
+
+	users, err = suite.repo.ByIDs(nil)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), users)
+}
+
+func (suite *UserRepositorySuite) TestByExternalIdentity() {
+	identity := usermodel.ExternalIdentity{Issuer: "https://sso.example.com", Subject: "248289761001"}
+	user, err := usermodel.NewExternal(usermodel.ExternalConfig{Username: "ssouser", Identity: identity})
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	assert.NoError(suite.T(), suite.repo.Save(user))
+
+	retrievedUser, err := suite.repo.ByExternalIdentity(identity)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.ID(), retrievedUser.ID())
+	assert.Equal(suite.T(), []usermodel.ExternalIdentity{identity}, retrievedUser.ExternalIdentities())
+	assert.False(suite.T(), retrievedUser.HasPassword())
+
+	// The subject is unique within its issuer only.
+	_, err = suite.repo.ByExternalIdentity(usermodel.ExternalIdentity{Issuer: "https://other.example.com", Subject: identity.Subject})
+	assert.Equal(suite.T(), errdmn.UserNotFound, err)
+}
+
+func (suite *UserRepositorySuite) TestSave_MFA() {
+	user := usermodel.FromBSON(&usermodel.UserBSON{
+		ID:                 suite.user.ID(),
+		Username:           suite.user.Username(),
+		PasswordHash:       suite.user.PasswordHash(),
+		TOTPSecret:         "SECRET",
+		TOTPLastStep:       100,
+		RecoveryCodeHashes: []string{"hashedCode1", "hashedCode2"},
+	})
+	assert.NoError(suite.T(), suite.repo.Save(user))
+
+	retrievedUser, err := suite.repo.ById(user.ID())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), retrievedUser.IsMFAEnabled())
+	assert.Equal(suite.T(), int64(100), retrievedUser.TOTPLastStep())
+	assert.Equal(suite.T(), user.RecoveryCodeHashes(), retrievedUser.RecoveryCodeHashes())
+
+	assert.NoError(suite.T(), user.DisableMFA())
+	assert.NoError(suite.T(), suite.repo.Save(user))
+	retrievedUser, err = suite.repo.ById(user.ID())
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), retrievedUser.IsMFAEnabled())
+	assert.Empty(suite.T(), retrievedUser.RecoveryCodeHashes())
+}
+
+func (suite *UserRepositorySuite) TestSave_Profile() {
+	assert.NoError(suite.T(), suite.user.UpdateDisplayName("Test User"))
+	assert.NoError(suite.T(), suite.user.UpdateLocale("en-US"))
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	retrievedUser, err := suite.repo.ById(suite.user.ID())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Test User", retrievedUser.DisplayName())
+	assert.Equal(suite.T(), "en-US", retrievedUser.Locale())
+
+	// Cleared details are removed.
+	assert.NoError(suite.T(), suite.user.UpdateDisplayName(""))
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	retrievedUser, err = suite.repo.ById(suite.user.ID())
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), retrievedUser.DisplayName())
+}
+
+func (suite *UserRepositorySuite) TestCountAdmins() {
+	admin, err := usermodel.New(usermodel.Config{
+		Username:      "adminuser",
+		PlainPassword: "testpassword",
+		Role:          rolemodel.Admin,
+	})
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+	assert.NoError(suite.T(), suite.repo.Save(admin))
+
+	count, err := suite.repo.CountAdmins()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	// Deactivated admins are not counted.
+	admin.Deactivate()
+	assert.NoError(suite.T(), suite.repo.SaveDeactivation(admin))
+
+	count, err = suite.repo.CountAdmins()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), count)
+}
+
+func (suite *UserRepositorySuite) TestSaveRole_StaleCopy() {
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+	stale, err := suite.repo.ById(suite.user.ID())
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.user.UpdateRole(rolemodel.Admin))
+	assert.NoError(suite.T(), suite.repo.SaveRole(suite.user))
+	suite.user.Deactivate()
+	assert.NoError(suite.T(), suite.repo.SaveDeactivation(suite.user))
+
+	// Saving an older copy of the user keeps the new role and the deactivation.
+	assert.NoError(suite.T(), stale.UpdateDisplayName("Test User"))
+	assert.NoError(suite.T(), suite.repo.Save(stale))
+
+	stored, err := suite.repo.ById(suite.user.ID())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), stored.IsAdmin())
+	assert.True(suite.T(), stored.IsDeactivated())
+	assert.Equal(suite.T(), "Test User", stored.DisplayName())
+}
+
+func (suite *UserRepositorySuite) TestSaveRole_NotFound() {
+	assert.Equal(suite.T(), errdmn.UserNotFound, suite.repo.SaveRole(suite.user))
+	assert.Equal(suite.T(), errdmn.UserNotFound, suite.repo.SaveDeactivation(suite.user))
+}
+
+// requireReplicaSet skips the test unless MongoDB runs as a replica set, which
+// transactions require.
+func (suite *UserRepositorySuite) requireReplicaSet() {
+	var hello bson.M
+	command := bson.D{{Key: "hello", Value: 1}}
+	if err := suite.client.Database("admin").RunCommand(context.Background(), command).Decode(&hello); err != nil {
+		suite.T().Fatal(err)
+	}
+	if _, ok := hello["setName"]; !ok {
+		suite.T().Skip("MongoDB is not running as a replica set, which transactions require")
+	}
+}
+
+func (suite *UserRepositorySuite) TestSaveRoleKeepingAdmin() {
+	suite.requireReplicaSet()
+
+	var admins []*usermodel.User
+	for _, username := range []string{"adminone", "admintwo"} {
+		admin, err := usermodel.New(usermodel.Config{
+			Username:      username,
+			PlainPassword: "testpassword",
+			Role:          rolemodel.Admin,
+		})
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		assert.NoError(suite.T(), suite.repo.Save(admin))
+		admins = append(admins, admin)
+	}
+
+	// The first admin can lose the role while the second one is left.
+	assert.NoError(suite.T(), admins[0].UpdateRole(rolemodel.Member))
+	assert.NoError(suite.T(), suite.repo.SaveRoleKeepingAdmin(admins[0]))
+
+	// The second one cannot, and keeps it, nor be deactivated.
+	assert.NoError(suite.T(), admins[1].UpdateRole(rolemodel.Member))
+	assert.Equal(suite.T(), errdmn.LastAdmin, suite.repo.SaveRoleKeepingAdmin(admins[1]))
+	assert.NoError(suite.T(), admins[1].UpdateRole(rolemodel.Admin))
+	admins[1].Deactivate()
+	assert.Equal(suite.T(), errdmn.LastAdmin, suite.repo.SaveDeactivationKeepingAdmin(admins[1]))
+
+	stored, err := suite.repo.ById(admins[1].ID())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), stored.IsAdmin())
+	assert.False(suite.T(), stored.IsDeactivated())
+}
+
+func (suite *UserRepositorySuite) TestList_Pagination() {
+	for _, username := range []string{"carol", "alice", "bob"} {
+		user, err := usermodel.New(usermodel.Config{Username: username, PlainPassword: "testpassword"})
+		if err != nil {
+			suite.T().Fatal(err)
+		}
+		assert.NoError(suite.T(), suite.repo.Save(user))
+	}
+
+	first, err := suite.repo.List(irepo.ListUsers{Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), first.Users, 2)
+	assert.Equal(suite.T(), "alice", first.Users[0].Username())
+	assert.NotEmpty(suite.T(), first.NextCursor)
+
+	second, err := suite.repo.List(irepo.ListUsers{Limit: 2, Cursor: first.NextCursor})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), second.Users, 1)
+	assert.Equal(suite.T(), "carol", second.Users[0].Username())
+	assert.Empty(suite.T(), second.NextCursor)
+
+	search, err := suite.repo.List(irepo.ListUsers{Text: "BO", Limit: 10})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), search.Users, 1)
+	assert.Equal(suite.T(), "bob", search.Users[0].Username())
+}
+
+func (suite *UserRepositorySuite) TestDelete() {
+	assert.NoError(suite.T(), suite.repo.Save(suite.user))
+
+	assert.NoError(suite.T(), suite.repo.Delete(suite.user.ID()))
+	_, err := suite.repo.ById(suite.user.ID())
+	assert.Equal(suite.T(), errdmn.UserNotFound, err)
+
+	assert.Equal(suite.T(), errdmn.UserNotFound, suite.repo.Delete(suite.user.ID()))
+}
+
 // Test Suite Execution
 func TestUserRepositorySuite(t *testing.T) {
 	suite.Run(t, new(UserRepositorySuite))
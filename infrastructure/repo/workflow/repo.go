@@ -0,0 +1,120 @@
+/*
+Package workflowrepo provides methods for managing the workflows of projects in a
+MongoDB collection.
+
+Each project has at most one workflow, stored under the name of the project. Projects
+without one follow the default workflow, which is never stored.
+
+Dependencies:
+- go.mongodb.org/mongo-driver/mongo: MongoDB driver for Go.
+- github.com/beka-birhanu/domain/errors: Custom domain errors.
+- github.com/beka-birhanu/domain/models/workflow: Workflow model definitions.
+*/
+package workflowrepo
+
+import (
+	"context"
+	"time"
+
+	irepo "github.com/beka-birhanu/task_manager_final/app/common/i_repo"
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repo represents a repository for managing workflows.
+type Repo struct {
+	collection *mongo.Collection
+}
+
+// Ensure Repo implements irepo.Workflow
+var _ irepo.Workflow = &Repo{}
+
+// New creates a new Repo for managing workflows with the given MongoDB client, database name, and collection name.
+func New(client *mongo.Client, dbName, collectionName string) *Repo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &Repo{
+		collection: collection,
+	}
+}
+
+// createScopedContext creates a new context with a timeout for scoped operations.
+func createScopedContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+// Save adds the workflow of a project, replacing the one it had.
+func (r *Repo) Save(workflow *workflowmodel.Workflow) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": workflow.Project()}, workflow.ToBSON(), opts); err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	return nil
+}
+
+// ForProject returns the workflow of the given project, or the default workflow
+// when the project has none of its own.
+func (r *Repo) ForProject(project string) (*workflowmodel.Workflow, error) {
+	if project == "" {
+		return workflowmodel.Default(), nil
+	}
+
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	var workflowBSON workflowmodel.WorkflowBSON
+	if err := r.collection.FindOne(ctx, bson.M{"_id": project}).Decode(&workflowBSON); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return workflowmodel.Default(), nil
+		}
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return workflowmodel.FromBSON(&workflowBSON), nil
+}
+
+// List returns the workflows defined for projects, sorted by project.
+func (r *Repo) List() ([]*workflowmodel.Workflow, error) {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	results, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	defer results.Close(ctx)
+
+	workflows := []*workflowmodel.Workflow{}
+	for results.Next(ctx) {
+		var workflowBSON workflowmodel.WorkflowBSON
+		if err := results.Decode(&workflowBSON); err != nil {
+			return nil, errdmn.NewUnexpected(err.Error())
+		}
+		workflows = append(workflows, workflowmodel.FromBSON(&workflowBSON))
+	}
+	if err := results.Err(); err != nil {
+		return nil, errdmn.NewUnexpected(err.Error())
+	}
+	return workflows, nil
+}
+
+// Delete removes the workflow of the given project, whose tasks then follow the default workflow.
+// Returns an error if the project has none.
+func (r *Repo) Delete(project string) error {
+	ctx, cancel := createScopedContext()
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": project})
+	if err != nil {
+		return errdmn.NewUnexpected(err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return errdmn.WorkflowNotFound
+	}
+	return nil
+}
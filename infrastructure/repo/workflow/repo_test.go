@@ -0,0 +1,118 @@
+package workflowrepo_test
+
+import (
+	"context"
+	"testing"
+
+	errdmn "github.com/beka-birhanu/task_manager_final/domain/errors"
+	workflowmodel "github.com/beka-birhanu/task_manager_final/domain/models/workflow"
+	workflowrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WorkflowRepositorySuite struct {
+	suite.Suite
+	client     *mongo.Client
+	repo       *workflowrepo.Repo
+	collection *mongo.Collection
+	workflow   *workflowmodel.Workflow
+}
+
+func (suite *WorkflowRepositorySuite) SetupSuite() {
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.client = client
+	suite.collection = client.Database("test_db").Collection("workflows")
+	suite.repo = workflowrepo.New(client, "test_db", "workflows")
+}
+
+func (suite *WorkflowRepositorySuite) TearDownSuite() {
+	err := suite.client.Disconnect(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+func (suite *WorkflowRepositorySuite) SetupTest() {
+	// Clear the collection before each test
+	err := suite.collection.Drop(context.Background())
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+
+	suite.workflow = newWorkflow("website", "todo", "shipped")
+	err = suite.repo.Save(suite.workflow)
+	if err != nil {
+		suite.T().Fatal(err)
+	}
+}
+
+// newWorkflow returns a workflow of the given project moving tasks through the given states in order.
+func newWorkflow(project string, states ...string) *workflowmodel.Workflow {
+	transitions := []workflowmodel.Transition{}
+	for i := 1; i < len(states); i++ {
+		transitions = append(transitions, workflowmodel.Transition{From: states[i-1], To: states[i]})
+	}
+	workflow, err := workflowmodel.New(workflowmodel.Config{Project: project, States: states, Transitions: transitions})
+	if err != nil {
+		panic(err)
+	}
+	return workflow
+}
+
+func (suite *WorkflowRepositorySuite) TestForProject() {
+	workflow, err := suite.repo.ForProject("website")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.workflow, workflow)
+
+	workflow, err = suite.repo.ForProject("mobile")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), workflowmodel.Default(), workflow)
+
+	workflow, err = suite.repo.ForProject("")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), workflowmodel.Default(), workflow)
+}
+
+func (suite *WorkflowRepositorySuite) TestSave_Replaces() {
+	replacement := newWorkflow("website", "backlog", "todo", "shipped")
+	assert.NoError(suite.T(), suite.repo.Save(replacement))
+
+	workflow, err := suite.repo.ForProject("website")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), replacement.States(), workflow.States())
+	assert.Equal(suite.T(), "backlog", workflow.Initial())
+}
+
+func (suite *WorkflowRepositorySuite) TestList() {
+	assert.NoError(suite.T(), suite.repo.Save(newWorkflow("api", "open", "closed")))
+
+	workflows, err := suite.repo.List()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), workflows, 2)
+	assert.Equal(suite.T(), "api", workflows[0].Project())
+	assert.Equal(suite.T(), "website", workflows[1].Project())
+}
+
+func (suite *WorkflowRepositorySuite) TestDelete() {
+	err := suite.repo.Delete("website")
+	assert.NoError(suite.T(), err)
+
+	workflow, err := suite.repo.ForProject("website")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), workflowmodel.Default(), workflow)
+
+	err = suite.repo.Delete("website")
+	assert.Equal(suite.T(), errdmn.WorkflowNotFound, err)
+}
+
+func TestWorkflowRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WorkflowRepositorySuite))
+}
@@ -0,0 +1,67 @@
+// Package revocation provides an in-memory token revocation store for local development
+// and tests. Revocations are lost on restart and are not shared between instances;
+// use the MongoDB store from revocationrepo in production.
+package revocation
+
+import (
+	"sync"
+	"time"
+
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+)
+
+// MemoryStore keeps revoked identifiers in memory.
+// Implements irevocation.Store.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+	now     func() time.Time
+}
+
+// Ensure MemoryStore implements irevocation.Store.
+var _ irevocation.Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		revoked: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Revoke marks the identifier as revoked until the given time.
+// Revoking an identifier again keeps the later of the two times.
+func (s *MemoryStore) Revoke(id string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	if current, ok := s.revoked[id]; !ok || until.After(current) {
+		s.revoked[id] = until
+	}
+	return nil
+}
+
+// IsRevoked reports whether any of the given identifiers is revoked.
+func (s *MemoryStore) IsRevoked(ids ...string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	for _, id := range ids {
+		if until, ok := s.revoked[id]; ok && now.Before(until) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// prune drops the revocations that ran out. The caller must hold the write lock.
+func (s *MemoryStore) prune() {
+	now := s.now()
+	for id, until := range s.revoked {
+		if !now.Before(until) {
+			delete(s.revoked, id)
+		}
+	}
+}
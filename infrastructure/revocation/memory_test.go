@@ -0,0 +1,59 @@
+package revocation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MemoryStoreSuite struct {
+	suite.Suite
+	store *MemoryStore
+	now   time.Time
+}
+
+func (suite *MemoryStoreSuite) SetupTest() {
+	suite.now = time.Now()
+	suite.store = NewMemoryStore()
+	suite.store.now = func() time.Time { return suite.now }
+}
+
+func (suite *MemoryStoreSuite) TestRevoke() {
+	suite.NoError(suite.store.Revoke("token", suite.now.Add(time.Minute)))
+
+	revoked, err := suite.store.IsRevoked("other", "token")
+	suite.NoError(err)
+	suite.True(revoked)
+
+	revoked, err = suite.store.IsRevoked("other")
+	suite.NoError(err)
+	suite.False(revoked)
+}
+
+func (suite *MemoryStoreSuite) TestRevoke_Expires() {
+	suite.NoError(suite.store.Revoke("token", suite.now.Add(time.Minute)))
+
+	suite.now = suite.now.Add(time.Minute)
+	revoked, err := suite.store.IsRevoked("token")
+	suite.NoError(err)
+	suite.False(revoked)
+
+	// Expired revocations are dropped on the next write.
+	suite.NoError(suite.store.Revoke("other", suite.now.Add(time.Minute)))
+	suite.NotContains(suite.store.revoked, "token")
+}
+
+func (suite *MemoryStoreSuite) TestRevoke_KeepsLatest() {
+	suite.NoError(suite.store.Revoke("session", suite.now.Add(time.Hour)))
+	suite.NoError(suite.store.Revoke("session", suite.now.Add(time.Minute)))
+
+	suite.now = suite.now.Add(30 * time.Minute)
+	revoked, err := suite.store.IsRevoked("session")
+	suite.NoError(err)
+	suite.True(revoked)
+}
+
+func TestMemoryStoreSuite(t *testing.T) {
+	suite.Run(t, new(MemoryStoreSuite))
+}
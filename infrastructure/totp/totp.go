@@ -0,0 +1,123 @@
+/*
+Package totp implements time-based one-time passwords as specified by RFC 6238, the
+codes shown by authenticator apps.
+
+Codes have 6 digits and are derived from the secret with HMAC-SHA1 every 30 seconds,
+the defaults every authenticator app supports. A code is accepted during the step
+before and the step after its own, to allow for clock skew.
+
+Key Components:
+  - Service: Implements itotp.Service.
+  - Config: Holds the configuration for creating a new Service.
+  - New: Creates a new Service with the given configuration.
+
+Dependencies:
+- crypto/hmac, crypto/sha1: For deriving the codes.
+*/
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+)
+
+const (
+	secretSize = 20 // Bytes, the size of an HMAC-SHA1 key recommended by RFC 4226.
+	digits     = 6
+	period     = 30 * time.Second
+	skew       = 1 // Steps accepted before and after the current one.
+)
+
+// encoding is the base32 alphabet without padding authenticator apps expect.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Service generates TOTP secrets and checks codes.
+// Implements itotp.Service.
+type Service struct {
+	issuer string
+	now    func() time.Time
+}
+
+// Ensure Service implements itotp.Service.
+var _ itotp.Service = &Service{}
+
+// Config holds TOTP service configuration.
+type Config struct {
+	Issuer string // Name authenticator apps show the accounts under.
+}
+
+// New creates a new TOTP Service with the provided configuration.
+func New(config Config) *Service {
+	return &Service{
+		issuer: config.Issuer,
+		now:    time.Now,
+	}
+}
+
+// NewSecret returns a new random secret, base32 encoded.
+func (s *Service) NewSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// URI returns the otpauth:// URI of the secret, labelled with the issuer and account.
+func (s *Service) URI(account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(int(period.Seconds())))
+
+	label := url.PathEscape(s.issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Validate checks the code against the secret at the current time and returns the
+// time step it was generated for.
+func (s *Service) Validate(secret, code string) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.ReplaceAll(secret, " ", "")))
+	if err != nil || len(code) != digits {
+		return 0, false
+	}
+
+	current := s.now().Unix() / int64(period.Seconds())
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generate(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// generate derives the code of the given time step (RFC 4226, section 5.3).
+func generate(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
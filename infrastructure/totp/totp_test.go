@@ -0,0 +1,97 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+type TOTPSuite struct {
+	suite.Suite
+	service *Service
+	now     time.Time
+}
+
+func (suite *TOTPSuite) SetupTest() {
+	suite.service = New(Config{Issuer: "Task Manager"})
+	suite.service.now = func() time.Time { return suite.now }
+}
+
+func (suite *TOTPSuite) TestValidate_RFCVectors() {
+	// The last 6 digits of the 8-digit codes in RFC 6238, appendix B.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, code := range vectors {
+		suite.now = time.Unix(unix, 0)
+		step, ok := suite.service.Validate(rfcSecret, code)
+		suite.True(ok, unix)
+		suite.Equal(unix/30, step)
+	}
+}
+
+func (suite *TOTPSuite) TestValidate_Skew() {
+	// The code of step 1 is still accepted during step 2, but not during step 3.
+	suite.now = time.Unix(89, 0)
+	step, ok := suite.service.Validate(rfcSecret, "287082")
+	suite.True(ok)
+	suite.Equal(int64(1), step)
+
+	suite.now = suite.now.Add(30 * time.Second)
+	_, ok = suite.service.Validate(rfcSecret, "287082")
+	suite.False(ok)
+}
+
+func (suite *TOTPSuite) TestValidate_Rejects() {
+	suite.now = time.Unix(59, 0)
+	for _, code := range []string{"", "287083", "28708", "0287082"} {
+		_, ok := suite.service.Validate(rfcSecret, code)
+		suite.False(ok, code)
+	}
+	_, ok := suite.service.Validate("not base32!", "287082")
+	suite.False(ok)
+}
+
+func (suite *TOTPSuite) TestNewSecret() {
+	secret, err := suite.service.NewSecret()
+	suite.NoError(err)
+	suite.Len(secret, 32)
+
+	other, err := suite.service.NewSecret()
+	suite.NoError(err)
+	suite.NotEqual(secret, other)
+
+	suite.now = time.Now()
+	key, err := encoding.DecodeString(secret)
+	suite.Require().NoError(err)
+	step := suite.now.Unix() / 30
+	_, ok := suite.service.Validate(secret, generate(key, step))
+	suite.True(ok)
+}
+
+func (suite *TOTPSuite) TestURI() {
+	uri, err := url.Parse(suite.service.URI("beka", "SECRET"))
+	suite.Require().NoError(err)
+	suite.Equal("otpauth", uri.Scheme)
+	suite.Equal("totp", uri.Host)
+	suite.Equal("/Task Manager:beka", uri.Path)
+	suite.Equal("SECRET", uri.Query().Get("secret"))
+	suite.Equal("Task Manager", uri.Query().Get("issuer"))
+	suite.Equal("6", uri.Query().Get("digits"))
+	suite.Equal("30", uri.Query().Get("period"))
+}
+
+func TestTOTPSuite(t *testing.T) {
+	suite.Run(t, new(TOTPSuite))
+}
@@ -3,26 +3,93 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
+
+	// Embeds the time zone database, so time zones of user profiles are validated
+	// even where the system has none installed.
+	_ "time/tzdata"
 
 	"github.com/beka-birhanu/task_manager_final/api"
 	authcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/auth"
+	jwkscontroller "github.com/beka-birhanu/task_manager_final/api/controllers/jwks"
 	taskcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/task"
 	usercontroller "github.com/beka-birhanu/task_manager_final/api/controllers/user"
+	workflowcontroller "github.com/beka-birhanu/task_manager_final/api/controllers/workflow"
 	"github.com/beka-birhanu/task_manager_final/api/router"
+	inotifier "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+	irevocation "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+	itotp "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+	mergetagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/merge"
+	renametagcmd "github.com/beka-birhanu/task_manager_final/app/tag/command/rename"
+	listtagsqry "github.com/beka-birhanu/task_manager_final/app/tag/query/list"
 	addcmd "github.com/beka-birhanu/task_manager_final/app/task/command/add"
+	assigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/assign"
 	deletecmd "github.com/beka-birhanu/task_manager_final/app/task/command/delete"
+	parentcmd "github.com/beka-birhanu/task_manager_final/app/task/command/parent"
+	transitioncmd "github.com/beka-birhanu/task_manager_final/app/task/command/transition"
+	unassigncmd "github.com/beka-birhanu/task_manager_final/app/task/command/unassign"
 	updatecmd "github.com/beka-birhanu/task_manager_final/app/task/command/update"
+	taskpolicy "github.com/beka-birhanu/task_manager_final/app/task/policy"
 	getqry "github.com/beka-birhanu/task_manager_final/app/task/query/get"
 	getallqry "github.com/beka-birhanu/task_manager_final/app/task/query/get_all"
+	progressqry "github.com/beka-birhanu/task_manager_final/app/task/query/progress"
+	searchqry "github.com/beka-birhanu/task_manager_final/app/task/query/search"
+	subtasksqry "github.com/beka-birhanu/task_manager_final/app/task/query/subtasks"
+	usernamesqry "github.com/beka-birhanu/task_manager_final/app/task/query/usernames"
 	promotcmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/command"
+	demotecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/demote"
+	rolecmd "github.com/beka-birhanu/task_manager_final/app/user/admin_status/role"
+	apikeyauth "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth"
+	createapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/create"
+	listapikeysqry "github.com/beka-birhanu/task_manager_final/app/user/apikey/list"
+	revokeapikeycmd "github.com/beka-birhanu/task_manager_final/app/user/apikey/revoke"
 	registercmd "github.com/beka-birhanu/task_manager_final/app/user/auth/command"
+	"github.com/beka-birhanu/task_manager_final/app/user/auth/lockout"
+	logoutcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/logout"
+	mfacmd "github.com/beka-birhanu/task_manager_final/app/user/auth/mfa"
+	oidccallbackcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/callback"
+	oidcstartcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/oidc/start"
 	loginqry "github.com/beka-birhanu/task_manager_final/app/user/auth/query"
+	refreshcmd "github.com/beka-birhanu/task_manager_final/app/user/auth/refresh"
+	authtoken "github.com/beka-birhanu/task_manager_final/app/user/auth/token"
+	resendemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/resend"
+	emailverification "github.com/beka-birhanu/task_manager_final/app/user/email/verification"
+	verifyemailcmd "github.com/beka-birhanu/task_manager_final/app/user/email/verify"
+	lockstatusqry "github.com/beka-birhanu/task_manager_final/app/user/lock/status"
+	unlockcmd "github.com/beka-birhanu/task_manager_final/app/user/lock/unlock"
+	deactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/deactivate"
+	deleteusercmd "github.com/beka-birhanu/task_manager_final/app/user/manage/delete"
+	getuserqry "github.com/beka-birhanu/task_manager_final/app/user/manage/get"
+	listusersqry "github.com/beka-birhanu/task_manager_final/app/user/manage/list"
+	reactivatecmd "github.com/beka-birhanu/task_manager_final/app/user/manage/reactivate"
+	confirmmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/confirm"
+	disablemfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/disable"
+	enrollmfacmd "github.com/beka-birhanu/task_manager_final/app/user/mfa/enroll"
+	passwordcmd "github.com/beka-birhanu/task_manager_final/app/user/password/command"
+	forgotcmd "github.com/beka-birhanu/task_manager_final/app/user/password/forgot"
+	resetcmd "github.com/beka-birhanu/task_manager_final/app/user/password/reset"
+	profileqry "github.com/beka-birhanu/task_manager_final/app/user/profile/get"
+	profilecmd "github.com/beka-birhanu/task_manager_final/app/user/profile/update"
+	defineworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/define"
+	deleteworkflowcmd "github.com/beka-birhanu/task_manager_final/app/workflow/command/delete"
+	getworkflowqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/get"
+	listworkflowsqry "github.com/beka-birhanu/task_manager_final/app/workflow/query/list"
 	"github.com/beka-birhanu/task_manager_final/config"
+	loginattemptmodel "github.com/beka-birhanu/task_manager_final/domain/models/login_attempt"
 	"github.com/beka-birhanu/task_manager_final/infrastructure/db"
 	"github.com/beka-birhanu/task_manager_final/infrastructure/hash"
 	"github.com/beka-birhanu/task_manager_final/infrastructure/jwt"
+	"github.com/beka-birhanu/task_manager_final/infrastructure/notifier"
+	"github.com/beka-birhanu/task_manager_final/infrastructure/oidc"
+	apikeyrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/api_key"
+	loginattemptrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/login_attempt"
+	refreshtokenrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/refresh_token"
+	resettokenrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/reset_token"
+	revocationrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/revocation"
 	taskrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/task"
 	userrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/user"
+	workflowrepo "github.com/beka-birhanu/task_manager_final/infrastructure/repo/workflow"
+	"github.com/beka-birhanu/task_manager_final/infrastructure/totp"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -36,18 +103,60 @@ func main() {
 
 	// Initialize services
 	userRepo, taskRepo, jwtService, hashService := initServices(cfg, mongoClient)
+	refreshTokenRepo := refreshtokenrepo.New(mongoClient, cfg.DBName, "refreshTokens")
+	tokenIssuer := authtoken.NewIssuer(authtoken.Config{
+		JwtSvc:      jwtService,
+		RefreshRepo: refreshTokenRepo,
+		HashSvc:     hashService,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+		RefreshTTL:  cfg.RefreshTokenExpirationInSeconds,
+	})
+	revocationRepo := revocationrepo.New(mongoClient, cfg.DBName, "revokedTokens")
+	resetTokenRepo := resettokenrepo.New(mongoClient, cfg.DBName, "resetTokens")
+	userNotifier := initNotifier(cfg)
+	forgotPasswordHandler := forgotcmd.NewHandler(forgotcmd.Config{
+		UserRepo:  userRepo,
+		ResetRepo: resetTokenRepo,
+		Notifier:  userNotifier,
+		HashSvc:   hashService,
+		TTL:       cfg.PasswordResetExpirationInSeconds,
+		ResetURL:  cfg.PasswordResetURL,
+	})
+	emailVerification := emailverification.NewSender(emailverification.Config{
+		HashSvc:   hashService,
+		Notifier:  userNotifier,
+		TTL:       cfg.EmailVerificationExpirationInSeconds,
+		VerifyURL: cfg.EmailVerificationURL,
+	})
+	totpService := totp.New(totp.Config{Issuer: cfg.MFAIssuer})
+	loginAttemptRepo := loginattemptrepo.New(mongoClient, cfg.DBName, "loginAttempts")
+	loginGuard := initLoginGuard(cfg, loginAttemptRepo)
+	apiKeyRepo := apikeyrepo.New(mongoClient, cfg.DBName, "apiKeys")
+	workflowRepo := workflowrepo.New(mongoClient, cfg.DBName, "workflows")
+	apiKeys := apikeyauth.NewAuthenticator(apikeyauth.Config{
+		APIKeyRepo: apiKeyRepo,
+		UserRepo:   userRepo,
+		HashSvc:    hashService,
+	})
 
 	// Initialize controllers
-	userController := initUserController(userRepo)
-	authController := initAuthController(userRepo, jwtService, hashService)
-	taskController := initTaskController(taskRepo)
+	userController := initUserController(cfg, userRepo, taskRepo, refreshTokenRepo, revocationRepo, loginAttemptRepo, apiKeyRepo, tokenIssuer, hashService, forgotPasswordHandler, totpService)
+	authController := initAuthController(cfg, userRepo, refreshTokenRepo, resetTokenRepo, revocationRepo, tokenIssuer, jwtService, hashService, forgotPasswordHandler, emailVerification, totpService, loginGuard)
+	taskController := initTaskController(taskRepo, userRepo, workflowRepo)
+	workflowController := initWorkflowController(workflowRepo)
+	jwksController := jwkscontroller.New(jwtService)
 
 	// Router configuration
 	routerConfig := router.Config{
 		Addr:        fmt.Sprintf(":%s", cfg.ServerPort),
 		BaseURL:     "/api",
-		Controllers: []api.IController{userController, taskController, authController},
+		Controllers: []api.IController{userController, taskController, authController, workflowController},
+		Root:        []api.IController{jwksController},
 		JwtService:  jwtService,
+		Revocations: revocationRepo,
+		AllowCookie: cfg.AuthCookieEnabled,
+		APIKeys:     apiKeys,
+		Proxies:     cfg.TrustedProxies,
 	}
 	r := router.NewRouter(routerConfig)
 
@@ -73,12 +182,26 @@ func initDB(cfg config.Config) *mongo.Client {
 // It returns the user repository, task repository, JWT service, and hash service.
 func initServices(cfg config.Config, mongoClient *mongo.Client) (*userrepo.Repo, *taskrepo.Repo, *jwt.Service, *hash.Service) {
 	userRepo := userrepo.NewRepo(mongoClient, cfg.DBName, "users")
-	taskRepo := taskrepo.New(mongoClient, cfg.DBName, "tasks")
+	taskRepo := taskrepo.New(mongoClient, cfg.DBName, "tasks", "workflows")
+
+	var signingKeys []*jwt.Key
+	if cfg.JWTKeysFile != "" {
+		keys, err := jwt.LoadKeys(cfg.JWTKeysFile)
+		if err != nil {
+			log.Fatalf("Error loading JWT signing keys: %v", err)
+		}
+		signingKeys = keys
+	}
 
 	jwtService := jwt.New(jwt.Config{
-		SecretKey: cfg.JWTSecret,
-		Issuer:    cfg.ServerHost,
-		ExpTime:   cfg.JWTExpirationInSeconds,
+		SecretKey:         cfg.JWTSecret,
+		Keys:              signingKeys,
+		GracePeriod:       cfg.JWTKeyGracePeriodInSeconds,
+		Issuer:            cfg.ServerHost,
+		Audience:          cfg.JWTAudience,
+		ExpTime:           cfg.JWTExpirationInSeconds,
+		ChallengeExpTime:  cfg.MFAChallengeExpirationInSeconds,
+		LoginStateExpTime: cfg.OIDCLoginExpirationInSeconds,
 	})
 
 	hashService := hash.SingletonService()
@@ -86,45 +209,273 @@ func initServices(cfg config.Config, mongoClient *mongo.Client) (*userrepo.Repo,
 	return userRepo, taskRepo, jwtService, hashService
 }
 
+// initNotifier initializes the notifier delivering messages to users, as selected in the configuration.
+func initNotifier(cfg config.Config) inotifier.Notifier {
+	switch cfg.Notifier {
+	case "smtp":
+		return notifier.NewSMTPNotifier(notifier.SMTPConfig{
+			Host:            cfg.SMTPHost,
+			Port:            cfg.SMTPPort,
+			Username:        cfg.SMTPUsername,
+			Password:        cfg.SMTPPassword,
+			From:            cfg.SMTPFrom,
+			RecipientDomain: cfg.SMTPRecipientDomain,
+		})
+	case "":
+		log.Println("NOTIFIER is not set, password reset and verification messages will not be sent")
+		return notifier.DisabledNotifier{}
+	case "log":
+		log.Println("NOTIFIER is log, messages and the tokens they carry are written out; use it for development only")
+		if cfg.NotifierLogFile == "" {
+			return notifier.NewLogNotifier(os.Stdout)
+		}
+		fileNotifier, err := notifier.NewFileNotifier(cfg.NotifierLogFile)
+		if err != nil {
+			log.Fatalf("Error opening notifier log file: %v", err)
+		}
+		return fileNotifier
+	default:
+		log.Fatalf("Unknown notifier %q", cfg.Notifier)
+		return nil
+	}
+}
+
+// initLoginGuard initializes the guard counting failed sign ins per username and per client address.
+func initLoginGuard(cfg config.Config, loginAttemptRepo *loginattemptrepo.Repo) *lockout.Guard {
+	policy := loginattemptmodel.Policy{
+		Window:      cfg.LoginFailureWindowInSeconds,
+		BaseLockout: cfg.LoginLockoutInSeconds,
+		MaxLockout:  cfg.LoginMaxLockoutInSeconds,
+	}
+	userPolicy, ipPolicy := policy, policy
+	userPolicy.MaxFailures = cfg.LoginUserMaxFailures
+	ipPolicy.MaxFailures = cfg.LoginIPMaxFailures
+
+	return lockout.NewGuard(lockout.Config{
+		AttemptRepo: loginAttemptRepo,
+		UserPolicy:  userPolicy,
+		IPPolicy:    ipPolicy,
+	})
+}
+
 // initUserController initializes the user controller with the necessary handlers.
 // It returns the user controller instance.
-func initUserController(userRepo *userrepo.Repo) *usercontroller.Controller {
+func initUserController(cfg config.Config, userRepo *userrepo.Repo, taskRepo *taskrepo.Repo, refreshTokenRepo *refreshtokenrepo.Repo, revocations irevocation.Store, loginAttemptRepo *loginattemptrepo.Repo, apiKeyRepo *apikeyrepo.Repo, tokenIssuer *authtoken.Issuer, hashService *hash.Service, forgotPasswordHandler *forgotcmd.Handler, totpService itotp.Service) *usercontroller.Controller {
 	promotHandler := promotcmd.New(userRepo)
 
+	demoteHandler := demotecmd.New(demotecmd.Config{
+		UserRepo:    userRepo,
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	roleHandler := rolecmd.New(rolecmd.Config{
+		UserRepo:    userRepo,
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	changePasswordHandler := passwordcmd.NewHandler(passwordcmd.Config{
+		UserRepo:    userRepo,
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		Tokens:      tokenIssuer,
+		HashSvc:     hashService,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	enrollMFAHandler := enrollmfacmd.NewHandler(enrollmfacmd.Config{
+		UserRepo: userRepo,
+		TOTP:     totpService,
+	})
+
+	confirmMFAHandler := confirmmfacmd.NewHandler(confirmmfacmd.Config{
+		UserRepo: userRepo,
+		TOTP:     totpService,
+		HashSvc:  hashService,
+	})
+
+	disableMFAHandler := disablemfacmd.NewHandler(disablemfacmd.Config{
+		UserRepo: userRepo,
+		TOTP:     totpService,
+		HashSvc:  hashService,
+	})
+
+	lockStatusHandler := lockstatusqry.New(lockstatusqry.Config{
+		UserRepo:    userRepo,
+		AttemptRepo: loginAttemptRepo,
+	})
+
+	unlockHandler := unlockcmd.New(unlockcmd.Config{
+		UserRepo:    userRepo,
+		AttemptRepo: loginAttemptRepo,
+	})
+
+	deactivateHandler := deactivatecmd.New(deactivatecmd.Config{
+		UserRepo:    userRepo,
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	deleteHandler := deleteusercmd.New(deleteusercmd.Config{
+		UserRepo:    userRepo,
+		TaskRepo:    taskRepo,
+		RefreshRepo: refreshTokenRepo,
+		APIKeyRepo:  apiKeyRepo,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	createAPIKeyHandler := createapikeycmd.NewHandler(createapikeycmd.Config{
+		UserRepo:   userRepo,
+		APIKeyRepo: apiKeyRepo,
+		HashSvc:    hashService,
+	})
+
 	return usercontroller.New(usercontroller.Config{
-		PromotHandler: promotHandler,
+		PromotHandler:         promotHandler,
+		DemoteHandler:         demoteHandler,
+		RoleHandler:           roleHandler,
+		ChangePasswordHandler: changePasswordHandler,
+		ResetPasswordHandler:  forgotPasswordHandler,
+		EnrollMFAHandler:      enrollMFAHandler,
+		ConfirmMFAHandler:     confirmMFAHandler,
+		DisableMFAHandler:     disableMFAHandler,
+		LockStatusHandler:     lockStatusHandler,
+		UnlockHandler:         unlockHandler,
+		ListHandler:           listusersqry.New(userRepo),
+		GetHandler:            getuserqry.New(userRepo),
+		DeactivateHandler:     deactivateHandler,
+		ReactivateHandler:     reactivatecmd.New(userRepo),
+		DeleteHandler:         deleteHandler,
+		ProfileHandler:        profileqry.New(userRepo),
+		UpdateProfileHandler:  profilecmd.New(userRepo),
+		CreateAPIKeyHandler:   createAPIKeyHandler,
+		ListAPIKeysHandler:    listapikeysqry.New(apiKeyRepo),
+		RevokeAPIKeyHandler:   revokeapikeycmd.New(apiKeyRepo),
 	})
 }
 
 // initAuthController initializes the authentication controller with the necessary handlers.
 // It returns the authentication controller instance.
-func initAuthController(userRepo *userrepo.Repo, jwtService *jwt.Service, hashService *hash.Service) *authcontroller.Controller {
+func initAuthController(cfg config.Config, userRepo *userrepo.Repo, refreshTokenRepo *refreshtokenrepo.Repo, resetTokenRepo *resettokenrepo.Repo, revocations irevocation.Store, tokenIssuer *authtoken.Issuer, jwtService *jwt.Service, hashService *hash.Service, forgotPasswordHandler *forgotcmd.Handler, emailVerification *emailverification.Sender, totpService itotp.Service, loginGuard lockout.IGuard) *authcontroller.Controller {
 	signupHandler := registercmd.NewHandler(registercmd.Config{
-		UserRepo: userRepo,
-		JwtSvc:   jwtService,
-		HashSvc:  hashService,
+		UserRepo:             userRepo,
+		Tokens:               tokenIssuer,
+		HashSvc:              hashService,
+		Verification:         emailVerification,
+		RequireVerifiedEmail: cfg.RequireVerifiedEmail,
 	})
 
 	loginHandler := loginqry.NewHandler(loginqry.Config{
+		UserRepo:             userRepo,
+		Tokens:               tokenIssuer,
+		HashSvc:              hashService,
+		Challenges:           jwtService,
+		Guard:                loginGuard,
+		RequireVerifiedEmail: cfg.RequireVerifiedEmail,
+	})
+
+	mfaHandler := mfacmd.NewHandler(mfacmd.Config{
+		UserRepo:   userRepo,
+		Challenges: jwtService,
+		TOTP:       totpService,
+		HashSvc:    hashService,
+		Tokens:     tokenIssuer,
+		Guard:      loginGuard,
+	})
+
+	verifyEmailHandler := verifyemailcmd.NewHandler(verifyemailcmd.Config{
 		UserRepo: userRepo,
-		JwtSvc:   jwtService,
 		HashSvc:  hashService,
 	})
 
-	return authcontroller.New(authcontroller.Config{
+	resendVerificationHandler := resendemailcmd.NewHandler(resendemailcmd.Config{
+		UserRepo:     userRepo,
+		Verification: emailVerification,
+	})
+
+	refreshHandler := refreshcmd.NewHandler(refreshcmd.Config{
+		UserRepo:    userRepo,
+		RefreshRepo: refreshTokenRepo,
+		Tokens:      tokenIssuer,
+		HashSvc:     hashService,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	logoutHandler := logoutcmd.NewHandler(logoutcmd.Config{
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	resetPasswordHandler := resetcmd.NewHandler(resetcmd.Config{
+		UserRepo:    userRepo,
+		ResetRepo:   resetTokenRepo,
+		RefreshRepo: refreshTokenRepo,
+		Revocations: revocations,
+		HashSvc:     hashService,
+		AccessTTL:   cfg.JWTExpirationInSeconds,
+	})
+
+	controllerConfig := authcontroller.Config{
 		RegisterHandler: signupHandler,
 		LoginHandler:    loginHandler,
-	})
+		MFAHandler:      mfaHandler,
+		RefreshHandler:  refreshHandler,
+		LogoutHandler:   logoutHandler,
+		ForgotHandler:   forgotPasswordHandler,
+		ResetHandler:    resetPasswordHandler,
+		VerifyHandler:   verifyEmailHandler,
+		ResendHandler:   resendVerificationHandler,
+	}
+
+	// Single sign-on is only offered when an identity provider is configured.
+	if cfg.OIDCIssuer != "" {
+		provider := oidc.New(oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		})
+
+		controllerConfig.OIDCStartHandler = oidcstartcmd.NewHandler(oidcstartcmd.Config{
+			Provider: provider,
+			States:   jwtService,
+		})
+		controllerConfig.OIDCCallbackHandler = oidccallbackcmd.NewHandler(oidccallbackcmd.Config{
+			UserRepo:             userRepo,
+			Provider:             provider,
+			States:               jwtService,
+			Tokens:               tokenIssuer,
+			Challenges:           jwtService,
+			RequireVerifiedEmail: cfg.RequireVerifiedEmail,
+		})
+	}
+
+	return authcontroller.New(controllerConfig)
 }
 
 // initTaskController initializes the task controller with the necessary handlers.
 // It returns the task controller instance.
-func initTaskController(taskRepo *taskrepo.Repo) *taskcontroller.Controller {
-	addHandler := addcmd.NewHandler(taskRepo)
-	updateHandler := updatecmd.NewHandler(taskRepo)
-	deleteHandler := deletecmd.New(taskRepo)
-	getAllHandler := getallqry.New(taskRepo)
-	getHandler := getqry.New(taskRepo)
+func initTaskController(taskRepo *taskrepo.Repo, userRepo *userrepo.Repo, workflowRepo *workflowrepo.Repo) *taskcontroller.Controller {
+	policy := taskpolicy.RoleBased{}
+
+	addHandler := addcmd.NewHandler(addcmd.Config{Repo: taskRepo, Workflows: workflowRepo, UserRepo: userRepo, Policy: policy})
+	updateHandler := updatecmd.NewHandler(updatecmd.Config{Repo: taskRepo, Workflows: workflowRepo, Policy: policy})
+	deleteHandler := deletecmd.New(deletecmd.Config{Repo: taskRepo, Policy: policy})
+	getAllHandler := getallqry.New(getallqry.Config{Repo: taskRepo, Workflows: workflowRepo})
+	getHandler := getqry.New(getqry.Config{Repo: taskRepo, Policy: policy})
+	searchHandler := searchqry.New(taskRepo)
+	assignHandler := assigncmd.NewHandler(assigncmd.Config{TaskRepo: taskRepo, UserRepo: userRepo, Policy: policy})
+	unassignHandler := unassigncmd.NewHandler(unassigncmd.Config{TaskRepo: taskRepo, UserRepo: userRepo, Policy: policy})
+	transitionHandler := transitioncmd.NewHandler(transitioncmd.Config{TaskRepo: taskRepo, Workflows: workflowRepo, Policy: policy})
+	usernamesHandler := usernamesqry.New(userRepo)
 
 	return taskcontroller.New(taskcontroller.Config{
 		AddHandler:    addHandler,
@@ -132,5 +483,29 @@ func initTaskController(taskRepo *taskrepo.Repo) *taskcontroller.Controller {
 		DeleteHandler: deleteHandler,
 		GetAllHandler: getAllHandler,
 		GetHandler:    getHandler,
+		SearchHandler: searchHandler,
+
+		AssignHandler:     assignHandler,
+		UnassignHandler:   unassignHandler,
+		TransitionHandler: transitionHandler,
+		UsernamesHandler:  usernamesHandler,
+
+		ListTagsHandler:  listtagsqry.New(taskRepo),
+		RenameTagHandler: renametagcmd.New(taskRepo),
+		MergeTagHandler:  mergetagcmd.New(taskRepo),
+
+		ParentHandler:   parentcmd.NewHandler(parentcmd.Config{Repo: taskRepo, Workflows: workflowRepo, Policy: policy}),
+		SubtasksHandler: subtasksqry.New(subtasksqry.Config{Repo: taskRepo, Policy: policy}),
+		ProgressHandler: progressqry.New(taskRepo),
+	})
+}
+
+// initWorkflowController initializes the controller letting admins define the workflows of projects.
+func initWorkflowController(workflowRepo *workflowrepo.Repo) *workflowcontroller.Controller {
+	return workflowcontroller.New(workflowcontroller.Config{
+		DefineHandler: defineworkflowcmd.New(workflowRepo),
+		DeleteHandler: deleteworkflowcmd.New(workflowRepo),
+		GetHandler:    getworkflowqry.New(workflowRepo),
+		ListHandler:   listworkflowsqry.New(workflowRepo),
 	})
 }
@@ -4,7 +4,14 @@
 exclude_packages=(
   "github.com/beka-birhanu/task_manager_final/infrastructure/repo/task" 
   "github.com/beka-birhanu/task_manager_final/infrastructure/repo/user"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/refresh_token"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/revocation"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/reset_token"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/login_attempt"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/api_key"
+  "github.com/beka-birhanu/task_manager_final/infrastructure/repo/workflow"
   "github.com/beka-birhanu/task_manager_final/api/errors"
+  "github.com/beka-birhanu/task_manager_final/api/mocks"
   "github.com/beka-birhanu/task_manager_final/api/router"
   "github.com/beka-birhanu/task_manager_final/api/controllers/base"
   "github.com/beka-birhanu/task_manager_final/app/task/command/add/command"
@@ -28,6 +35,18 @@ exclude_packages=(
   "github.com/beka-birhanu/task_manager_final/app/common/cqrs/command/mocks"
   "github.com/beka-birhanu/task_manager_final/app/common/cqrs/query"
   "github.com/beka-birhanu/task_manager_final/app/common/i_repo/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/task/policy/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/user/auth/token/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/user/auth/lockout/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/user/apikey/auth/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_revocation"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_revocation/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_notifier"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_notifier/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_totp"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_totp/mocks"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_oidc"
+  "github.com/beka-birhanu/task_manager_final/app/common/i_oidc/mocks"
 )
 
 # Find all packages with .go files